@@ -0,0 +1,167 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// ACIA emulates a 6551 ACIA (serial interface) Device bridged to Go streams,
+// e.g. os.Stdin/os.Stdout or a net.Conn. It occupies four registers,
+// mirrored across the range it is attached to:
+//
+//	0  Data, read: received byte, write: byte to transmit
+//	1  Status (read), programmed reset (write)
+//	2  Command
+//	3  Control, stored only, the baud rate is not emulated
+//
+// Received bytes are handed over one at a time: the next byte is read from
+// the stream only after the previous one has been read from the data
+// register, as with hardware flow control, so no overrun occurs. Written
+// bytes are transmitted immediately, the transmitter is always empty.
+//
+// The IRQ line, see SetIRQ(), is asserted while a received byte is pending
+// and the receiver interrupt is enabled, or while the transmitter interrupt
+// is enabled. The ACIA drives the line exclusively.
+type ACIA struct {
+	mu      sync.Mutex
+	w       io.Writer
+	err     error
+	lines   *Lines
+	data    byte // Received byte
+	rdrf    bool // Receiver data register full
+	command byte
+	control byte
+	ready   chan struct{} // Data register has been read
+}
+
+// Status register bits of the ACIA.
+const (
+	ACIAReceiverFull     = 1 << 3 // RDRF, a received byte is pending
+	ACIATransmitterEmpty = 1 << 4 // TDRE, always set
+	ACIAInterrupt        = 1 << 7 // IRQ line asserted
+)
+
+// NewACIA creates an ACIA receiving from r and transmitting to w. Both may
+// be nil. The ACIA reads from r in a goroutine until r returns an error.
+func NewACIA(r io.Reader, w io.Writer) *ACIA {
+	a := &ACIA{w: w, ready: make(chan struct{}, 1)}
+	if r != nil {
+		go a.receive(r)
+	}
+	return a
+}
+
+// SetIRQ connects the ACIA to the interrupt lines of a CPU.
+func (a *ACIA) SetIRQ(l *Lines) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lines = l
+	a.update()
+}
+
+// Err returns the first error of the streams, io.EOF excluded.
+func (a *ACIA) Err() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.err
+}
+
+// Read reads an ACIA register.
+func (a *ACIA) Read(addr uint16) byte {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch addr & 3 {
+	case 0:
+		if a.rdrf {
+			a.rdrf = false
+			a.update()
+			select {
+			case a.ready <- struct{}{}:
+			default:
+			}
+		}
+		return a.data
+	case 1:
+		st := byte(ACIATransmitterEmpty)
+		if a.rdrf {
+			st |= ACIAReceiverFull
+		}
+		if a.irq() {
+			st |= ACIAInterrupt
+		}
+		return st
+	case 2:
+		return a.command
+	}
+	return a.control
+}
+
+// Write writes an ACIA register.
+func (a *ACIA) Write(addr uint16, db byte) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch addr & 3 {
+	case 0:
+		if a.w == nil {
+			return
+		}
+		if _, err := a.w.Write([]byte{db}); err != nil && a.err == nil {
+			a.err = err
+		}
+	case 1:
+		a.command &^= 0x1F
+	case 2:
+		a.command = db
+	case 3:
+		a.control = db
+	}
+	a.update()
+}
+
+func (a *ACIA) receive(r io.Reader) {
+	buf := make([]byte, 1)
+	for {
+		n, err := r.Read(buf)
+		if n == 1 {
+			a.mu.Lock()
+			a.data, a.rdrf = buf[0], true
+			a.update()
+			a.mu.Unlock()
+			<-a.ready
+		}
+		if err != nil {
+			a.mu.Lock()
+			if !errors.Is(err, io.EOF) && a.err == nil {
+				a.err = err
+			}
+			a.mu.Unlock()
+			return
+		}
+	}
+}
+
+// irq reports the state of the IRQ line. Bit 0 (DTR) of the command register
+// enables the interrupts, bit 1 disables the receiver interrupt, bits 2-3
+// set to 01 enable the transmitter interrupt.
+func (a *ACIA) irq() bool {
+	if a.command&0x01 == 0 {
+		return false
+	}
+	return a.rdrf && a.command&0x02 == 0 || a.command&0x0C == 0x04
+}
+
+func (a *ACIA) update() {
+	if a.lines == nil {
+		return
+	}
+	if a.irq() {
+		a.lines.AssertIRQ()
+	} else {
+		a.lines.ReleaseIRQ()
+	}
+}
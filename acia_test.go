@@ -0,0 +1,94 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitStatus polls the ACIA status until the bits are set.
+func waitStatus(t *testing.T, a *ACIA, bits byte) {
+	t.Helper()
+	for i := 0; a.Read(1)&bits != bits; i++ {
+		if i == 1000 {
+			t.Fatalf("unexpected, got status %02X", a.Read(1))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestACIAEcho(t *testing.T) {
+	out := &bytes.Buffer{}
+	acia := NewACIA(strings.NewReader("hello"), out)
+
+	bus := NewDeviceBus()
+	bus.Attach(0x0000, 0xFFFF, 0, NewRAM(0x10000))
+	bus.Attach(0x8000, 0x8003, 1, acia)
+
+	cpu := New(bus)
+	cpu.PC(0x00, 0x02)
+	for i, b := range []byte{
+		0xAD, 0x01, 0x80, // loop: LDA $8001
+		0x29, 0x08, //       AND #$08
+		0xF0, 0xF9, //       BEQ loop
+		0xAD, 0x00, 0x80, // LDA $8000
+		0x8D, 0x00, 0x80, // STA $8000
+		0x4C, 0x00, 0x02, // JMP loop
+	} {
+		bus.Write(byte(0x00+i), 0x02, b)
+	}
+	deadline := time.Now().Add(time.Second)
+	for out.Len() < 5 && time.Now().Before(deadline) {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if out.String() != "hello" || acia.Err() != nil {
+		t.Fatalf("unexpected, got %q %v", out, acia.Err())
+	}
+}
+
+func TestACIAInterrupt(t *testing.T) {
+	r, w := io.Pipe()
+	acia := NewACIA(r, nil)
+	lines := &Lines{}
+	acia.SetIRQ(lines)
+
+	acia.Write(2, 0x01) // DTR, receiver IRQ enabled
+	go w.Write([]byte{0x42})
+	waitStatus(t, acia, ACIAReceiverFull|ACIAInterrupt)
+
+	if !lines.irq.Load() {
+		t.Fatal("unexpected, IRQ released")
+	}
+	if d := acia.Read(0); d != 0x42 || lines.irq.Load() {
+		t.Fatalf("unexpected, got %02X", d)
+	}
+
+	acia.Write(2, 0x05) // Transmitter IRQ enabled
+	if !lines.irq.Load() || acia.Read(2) != 0x05 {
+		t.Fatal("unexpected, IRQ released")
+	}
+	acia.Write(1, 0x00) // Programmed reset
+	if lines.irq.Load() || acia.Read(2) != 0x00 {
+		t.Fatal("unexpected, IRQ asserted")
+	}
+	acia.Write(3, 0x1F)
+	if acia.Read(7) != 0x1F {
+		t.Fatal("unexpected, control lost")
+	}
+
+	failed := errors.New("failed")
+	w.CloseWithError(failed)
+	for i := 0; acia.Err() == nil && i < 1000; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if !errors.Is(acia.Err(), failed) {
+		t.Fatalf("unexpected, got %v", acia.Err())
+	}
+}
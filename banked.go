@@ -0,0 +1,88 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "fmt"
+
+type (
+	// Banked is a bank-switched memory region: its address range is divided
+	// into windows of equal size, e.g. 4K or 8K, each showing a selectable
+	// bank of a larger backing store. Banked implements Bus16, use it as
+	// region of a Mapper. Addresses beyond the windows are mirrored.
+	Banked struct {
+		store    []byte
+		size     int   // Window and bank size
+		selected []int // Bank selected per window
+	}
+
+	// control is a register selecting the bank of a window on write.
+	control struct {
+		b      *Banked
+		window int
+	}
+)
+
+// NewBanked creates a Banked region of windows, each of size bytes, backed
+// by the store. The store is divided into banks of size bytes, its length
+// must be a multiple of size. Initially, window n shows bank n, modulo the
+// number of banks.
+func NewBanked(store []byte, size, windows int) *Banked {
+	if size < 1 || windows < 1 || size*windows > 0x10000 {
+		panic(fmt.Sprintf("m6502: invalid bank windows %d x %d", windows, size))
+	}
+	if len(store) == 0 || len(store)%size != 0 {
+		panic(fmt.Sprintf("m6502: invalid bank store size %d", len(store)))
+	}
+	b := &Banked{store: store, size: size, selected: make([]int, windows)}
+	for w := range b.selected {
+		b.selected[w] = w % b.Banks()
+	}
+	return b
+}
+
+// Banks returns the number of banks of the backing store.
+func (b *Banked) Banks() int {
+	return len(b.store) / b.size
+}
+
+// SelectBank shows the bank in the window. The bank number is taken
+// modulo the number of banks, like on hardware with fewer banks than
+// the latch is able to address.
+func (b *Banked) SelectBank(window, bank int) {
+	b.selected[window] = bank % b.Banks()
+}
+
+// Bank returns the bank shown in the window.
+func (b *Banked) Bank(window int) int {
+	return b.selected[window]
+}
+
+// Control returns a register selecting the bank of the window. Map it with a
+// Mapper to trigger bank switching from 6502 code: writing a value selects
+// the bank, reading returns the selected bank.
+func (b *Banked) Control(window int) Bus16 {
+	return control{b, window}
+}
+
+// Read reads a byte from the bank shown at the address.
+func (b *Banked) Read(addr uint16) byte {
+	return b.store[b.offset(addr)]
+}
+
+// Write writes a byte to the bank shown at the address.
+func (b *Banked) Write(addr uint16, db byte) {
+	b.store[b.offset(addr)] = db
+}
+
+func (b *Banked) offset(addr uint16) int {
+	a := int(addr) % (b.size * len(b.selected))
+	return b.selected[a/b.size]*b.size + a%b.size
+}
+
+func (c control) Read(uint16) byte {
+	return byte(c.b.Bank(c.window))
+}
+
+func (c control) Write(_ uint16, db byte) {
+	c.b.SelectBank(c.window, int(db))
+}
@@ -0,0 +1,57 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+func TestBanked(t *testing.T) {
+	store := make([]byte, 4*0x1000)
+	for bank := 0; bank < 4; bank++ {
+		store[bank*0x1000] = byte(bank)
+	}
+	b := NewBanked(store, 0x1000, 2)
+
+	if b.Banks() != 4 || b.Read(0x0000) != 0 || b.Read(0x1000) != 1 || b.Read(0x2000) != 0 {
+		t.Fatal("unexpected")
+	}
+	b.SelectBank(1, 3)
+	if b.Bank(1) != 3 || b.Read(0x1000) != 3 {
+		t.Fatal("unexpected")
+	}
+	b.Write(0x1001, 0x42)
+	if store[3*0x1000+1] != 0x42 {
+		t.Fatal("unexpected")
+	}
+	b.SelectBank(0, 6)
+	if b.Bank(0) != 2 {
+		t.Fatal("unexpected")
+	}
+}
+
+func TestBankedControl(t *testing.T) {
+	store := make([]byte, 4*0x2000)
+	for bank := 0; bank < 4; bank++ {
+		store[bank*0x2000] = byte(0x10 + bank)
+	}
+	b := NewBanked(store, 0x2000, 1)
+
+	// LDA #$02, STA $DF00, LDA $8000
+	ram := NewRAM(0x10000)
+	ram.Load(0x0200, []byte{0xA9, 0x02, 0x8D, 0x00, 0xDF, 0xAD, 0x00, 0x80})
+	ram.Load(0xFFFC, []byte{0x00, 0x02})
+
+	m := NewMapper().
+		Map(0x0000, 0xFFFF, ram).
+		Map(0x8000, 0x9FFF, b).
+		Map(0xDF00, 0xDF00, b.Control(0))
+
+	cpu := New(m)
+	for i := 0; i < 3; i++ {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cpu.a != 0x12 || m.Read(0x00, 0xDF) != 0x02 {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+}
@@ -0,0 +1,83 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+// BenchmarkMode measures each addressing mode and instruction class in
+// isolation. The instruction is repeated to fill a page, followed by a
+// JMP back, so the loop overhead is negligible. Results are per Step().
+func BenchmarkMode(b *testing.B) {
+	for _, tt := range []struct {
+		name string
+		code []byte
+		x    byte
+		p    flag
+	}{
+		{"implied", []byte{0xE8}, 0x00, 0},                       // INX
+		{"accumulator", []byte{0x0A}, 0x00, 0},                   // ASL A
+		{"immediate", []byte{0xA9, 0x12}, 0x00, 0},               // LDA #$12
+		{"zeropage", []byte{0xA5, 0x10}, 0x00, 0},                // LDA $10
+		{"zeropage,X", []byte{0xB5, 0x10}, 0x04, 0},              // LDA $10,X
+		{"absolute", []byte{0xAD, 0x00, 0x30}, 0x00, 0},          // LDA $3000
+		{"absolute,X", []byte{0xBD, 0x00, 0x30}, 0x04, 0},        // LDA $3000,X
+		{"absolute,X/cross", []byte{0xBD, 0xF0, 0x30}, 0x20, 0},  // LDA $30F0,X
+		{"absolute,Y", []byte{0xB9, 0x00, 0x30}, 0x00, 0},        // LDA $3000,Y
+		{"(indirect,X)", []byte{0xA1, 0x1C}, 0x04, 0},            // LDA ($1C,X)
+		{"(indirect),Y", []byte{0xB1, 0x20}, 0x00, 0},            // LDA ($20),Y
+		{"store", []byte{0x8D, 0x00, 0x30}, 0x00, 0},             // STA $3000
+		{"rmw/zeropage", []byte{0xE6, 0x10}, 0x00, 0},            // INC $10
+		{"rmw/absolute,X", []byte{0xFE, 0x00, 0x30}, 0x04, 0},    // INC $3000,X
+		{"branch/taken", []byte{0xD0, 0x00}, 0x00, 0},            // BNE *+2
+		{"branch/not-taken", []byte{0xF0, 0x00}, 0x00, 0},        // BEQ *+2
+		{"stack", []byte{0x48, 0x68}, 0x00, 0},                   // PHA, PLA
+		{"subroutine", []byte{0x20, 0x00, 0x30}, 0x00, 0},        // JSR $3000
+		{"adc/binary", []byte{0x69, 0x01}, 0x00, 0},              // ADC #$01
+		{"adc/decimal", []byte{0x69, 0x01}, 0x00, flagD},         // ADC #$01
+		{"sbc/decimal", []byte{0xE9, 0x01}, 0x00, flagD | flagC}, // SBC #$01
+		{"bit", []byte{0x24, 0x10}, 0x00, 0},                     // BIT $10
+		{"compare", []byte{0xC9, 0x12}, 0x00, 0},                 // CMP #$12
+	} {
+		b.Run(tt.name, func(b *testing.B) {
+			bus := &memoryBus{}
+			a := 0x0200
+			for ; a+len(tt.code) <= 0x02FD; a += len(tt.code) {
+				copy(bus.mem[a:], tt.code)
+			}
+			copy(bus.mem[a:], []byte{0x4C, 0x00, 0x02}) // JMP $0200
+			bus.mem[0x3000] = 0x60                      // RTS
+			bus.mem[0x20], bus.mem[0x21] = 0x00, 0x30   // Pointer to $3000
+
+			cpu := New(bus)
+			cpu.PC(0x00, 0x02)
+			cpu.x, cpu.y, cpu.a = tt.x, 0x04, 0x01
+			cpu.p = tt.p
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				if _, err := cpu.Step(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkStep measures the per-Step() overhead on a short loop of
+// cheap instructions, without any of the optional features enabled.
+func BenchmarkStep(b *testing.B) {
+	bus := &memoryBus{}
+	copy(bus.mem[0x0200:], []byte{0xEA, 0xE8, 0xA9, 0x01, 0x4C, 0x00, 0x02}) // NOP, INX, LDA #$01, JMP $0200
+
+	cpu := New(bus)
+	cpu.PC(0x00, 0x02)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if _, err := cpu.Step(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
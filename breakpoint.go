@@ -0,0 +1,84 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"fmt"
+	"sort"
+)
+
+type (
+	// Breakpoint stops Step() before the instruction at Addr is performed,
+	// when Cond is nil or returns true, see SetBreakpoint(). Hits counts
+	// the instructions reached at Addr, Breaks the stops. Cond is invoked
+	// after Hits has been incremented and must not step the CPU, e.g.
+	//
+	//	bp := cpu.SetBreakpoint(0x1234, nil)
+	//	bp.Cond = func(cpu *m6502.CPU) bool { return bp.Hits == 100 }
+	Breakpoint struct {
+		Addr   uint16
+		Cond   func(cpu *CPU) bool
+		Hits   uint64
+		Breaks uint64
+	}
+
+	// BreakpointError is returned by Step() when a Breakpoint stops it.
+	BreakpointError struct {
+		*Breakpoint
+	}
+
+	breakpoints struct {
+		list   map[uint16]*Breakpoint
+		resume *Breakpoint // Stopped, passed by the next instruction
+	}
+)
+
+// SetBreakpoint sets a Breakpoint at the address, replacing the previous one.
+// On a stop, Step() returns a *BreakpointError and leaves the CPU untouched.
+// The next Step() performs the instruction, so calling Step() again resumes
+// the execution. A nil cond stops every time the address is reached.
+func (cpu *CPU) SetBreakpoint(addr uint16, cond func(cpu *CPU) bool) *Breakpoint {
+	if cpu.breakpoints.list == nil {
+		cpu.breakpoints.list = make(map[uint16]*Breakpoint)
+	}
+	bp := &Breakpoint{Addr: addr, Cond: cond}
+	cpu.breakpoints.list[addr] = bp
+	return bp
+}
+
+// ClearBreakpoint removes the Breakpoint at the address, if any.
+func (cpu *CPU) ClearBreakpoint(addr uint16) {
+	delete(cpu.breakpoints.list, addr)
+}
+
+// Breakpoints returns the breakpoints set, ordered by address.
+func (cpu *CPU) Breakpoints() []*Breakpoint {
+	list := make([]*Breakpoint, 0, len(cpu.breakpoints.list))
+	for _, bp := range cpu.breakpoints.list {
+		list = append(list, bp)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Addr < list[j].Addr })
+	return list
+}
+
+// breakpoint returns a *BreakpointError, when a Breakpoint at pc stops
+// the execution.
+func (cpu *CPU) breakpoint(pc uint16) error {
+	resume := cpu.breakpoints.resume
+	cpu.breakpoints.resume = nil
+
+	bp, ok := cpu.breakpoints.list[pc]
+	if !ok || bp == resume {
+		return nil
+	}
+	if bp.Hits++; bp.Cond != nil && !bp.Cond(cpu) {
+		return nil
+	}
+	bp.Breaks++
+	cpu.breakpoints.resume = bp
+	return &BreakpointError{bp}
+}
+
+func (e *BreakpointError) Error() string {
+	return fmt.Sprintf("m6502: %04X: breakpoint reached", e.Addr)
+}
@@ -0,0 +1,67 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBreakpoint(t *testing.T) {
+	cpu, _ := newTestCPU(NMOS6502, nopJmp...)
+
+	cpu.SetBreakpoint(0x0201, nil)
+	if _, err := cpu.Step(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		cycles, err := cpu.Step()
+		e := &BreakpointError{}
+		if !errors.As(err, &e) || e.Addr != 0x0201 || cycles != 0 || cpu.pc() != 0x0201 {
+			t.Fatalf("unexpected, got %d %v", cycles, err)
+		}
+		if cycles, err = cpu.Step(); err != nil || cycles != 3 {
+			t.Fatalf("unexpected, got %d %v", cycles, err)
+		}
+		if _, err = cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := (&BreakpointError{&Breakpoint{Addr: 0x0201}}); err.Error() != "m6502: 0201: breakpoint reached" {
+		t.Fatalf("unexpected, got %s", err)
+	}
+
+	cpu.ClearBreakpoint(0x0201)
+	if _, err := cpu.Step(); err != nil || len(cpu.Breakpoints()) != 0 {
+		t.Fatal(err)
+	}
+}
+
+func TestBreakpointCond(t *testing.T) {
+	cpu, _ := newTestCPU(NMOS6502, nopJmp...)
+
+	bp := cpu.SetBreakpoint(0x0200, nil)
+	bp.Cond = func(cpu *CPU) bool { return bp.Hits == 100 }
+	cpu.SetBreakpoint(0x0201, func(cpu *CPU) bool { return cpu.a == 0x42 })
+
+	_, reason, err := cpu.RunUntil(0xFFFF, 0)
+	e := &BreakpointError{}
+	if reason != StopError || !errors.As(err, &e) || e.Breakpoint != bp {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	if bp.Hits != 100 || bp.Breaks != 1 || cpu.total != 7+99*5 {
+		t.Fatalf("unexpected, got %d %d %d", bp.Hits, bp.Breaks, cpu.total)
+	}
+
+	cpu.a = 0x42
+	if _, err = cpu.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cpu.Step(); !errors.As(err, &e) || e.Addr != 0x0201 {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	list := cpu.Breakpoints()
+	if len(list) != 2 || list[0] != bp || list[1].Hits != 100 || list[1].Breaks != 1 {
+		t.Fatalf("unexpected, got %v", list)
+	}
+}
@@ -0,0 +1,33 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+type (
+	// Bus16 is an alternative to Bus for integrators modeling the address
+	// space as a flat 16-bit range, connected by Adapt16():
+	//
+	//	cpu := m6502.New(m6502.Adapt16(bus), m6502.WithVariant(m6502.CMOS65C02))
+	Bus16 interface {
+
+		// Read reads a byte from address space. See Bus.Read().
+		Read(addr uint16) byte
+
+		// Write writes a byte to address space. See Bus.Write().
+		Write(addr uint16, db byte)
+	}
+
+	bus16 struct{ Bus16 }
+)
+
+// Adapt16 returns a Bus forwarding the accesses to the Bus16.
+func Adapt16(bus Bus16) Bus {
+	return bus16{bus}
+}
+
+func (b bus16) Read(lo, hi byte) byte {
+	return b.Bus16.Read(uint16(hi)<<8 | uint16(lo))
+}
+
+func (b bus16) Write(lo, hi, db byte) {
+	b.Bus16.Write(uint16(hi)<<8|uint16(lo), db)
+}
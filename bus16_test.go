@@ -0,0 +1,35 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+type flatBus []byte
+
+func (b flatBus) Read(addr uint16) byte     { return b[addr] }
+func (b flatBus) Write(addr uint16, d byte) { b[addr] = d }
+
+func TestAdapt16(t *testing.T) {
+	bus := make(flatBus, 0x10000)
+	bus[0xFFFC], bus[0xFFFD] = 0x00, 0x02
+	copy(bus[0x0200:], []byte{0xA9, 0x42, 0x8D, 0x34, 0x12}) // LDA #$42, STA $1234
+
+	cpu := New(Adapt16(bus))
+	if cpu.pc() != 0x0200 {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if bus[0x1234] != 0x42 {
+		t.Fatalf("unexpected, got %02X", bus[0x1234])
+	}
+
+	b := Adapt16(bus)
+	b.Write(0xCD, 0xAB, 0x11)
+	if bus[0xABCD] != 0x11 || b.Read(0xCD, 0xAB) != 0x11 {
+		t.Fatal("unexpected")
+	}
+}
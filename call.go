@@ -0,0 +1,67 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "errors"
+
+// RegisterSet holds the registers passed to and returned by Call(). The
+// B and unused bits of P are ignored when passed.
+type RegisterSet struct {
+	A, X, Y, S, P byte
+}
+
+// Registers returns the current registers, e.g. as base for Call().
+func (cpu *CPU) Registers() RegisterSet {
+	return RegisterSet{cpu.a, cpu.x, cpu.y, cpu.s, byte(cpu.p | flagU)}
+}
+
+// Call calls the subroutine at addr like a function: the registers are
+// loaded from regs, the sentinel address minus one is pushed as return
+// address, and instructions are performed until the matching RTS returns
+// to the sentinel, i.e. with the stack pointer back at regs.S. The result
+// registers are returned; the PC is restored to its original value.
+// Interrupts are serviced while the subroutine runs. The sentinel of the
+// CPU is used when set, DefaultSentinel otherwise, see SetSentinel().
+//
+// A maxCycles > 0 limits the call, an exceeded limit is returned as a
+// *LimitError. Errors of Step() are returned as is, together with the
+// registers at the time of the error.
+//
+//	res, err := cpu.Call(0xB867, RegisterSet{A: 0x12, S: 0xFF}, 100_000)
+func (cpu *CPU) Call(addr uint16, regs RegisterSet, maxCycles uint64) (RegisterSet, error) {
+	pc, saved := cpu.pc(), cpu.sentinel
+	defer func() { cpu.sentinel = saved }()
+	if !cpu.sentinel.on {
+		cpu.sentinel = sentinel{DefaultSentinel, true}
+	}
+	ret := cpu.sentinel.addr - 1
+
+	cpu.a, cpu.x, cpu.y, cpu.s = regs.A, regs.X, regs.Y, regs.S
+	cpu.p = flag(regs.P) &^ (flagU | flagB)
+
+	for _, b := range [...]byte{byte(ret >> 8), byte(ret)} {
+		cpu.bus.Write(cpu.s, 0x01, b)
+		cpu.s--
+	}
+	cpu.PC(byte(addr), byte(addr>>8))
+
+	cycles, steps := uint64(0), uint64(0)
+	for {
+		n, err := cpu.Step()
+		if e := (*SentinelError)(nil); errors.As(err, &e) && e.S == regs.S {
+			cpu.PC(byte(pc), byte(pc>>8))
+			return cpu.Registers(), nil
+		}
+		if err != nil {
+			return cpu.Registers(), err
+		}
+		cycles += uint64(n)
+		steps++
+
+		if maxCycles > 0 && cycles >= maxCycles {
+			return cpu.Registers(), cpu.named(&LimitError{
+				Kind: LimitCycles, PC: cpu.pc(), Cycles: cycles, Instructions: steps,
+			})
+		}
+	}
+}
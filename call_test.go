@@ -0,0 +1,74 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCall(t *testing.T) {
+	bus := &memoryBus{}
+	copy(bus.mem[0x1000:], []byte{
+		0x18,       // add: CLC
+		0x65, 0x10, //      ADC $10
+		0xC8,             //      INY
+		0x20, 0x10, 0x10, // JSR ret
+		0x60, //            RTS
+	})
+	bus.mem[0x1010] = 0x60 // ret: RTS
+	bus.mem[0x10] = 0x30
+
+	cpu := New(bus)
+	cpu.PC(0x00, 0x02)
+
+	regs := RegisterSet{A: 0x12, Y: 0x01, S: 0xF0, P: 0x01}
+	res, err := cpu.Call(0x1000, regs, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != (RegisterSet{A: 0x42, Y: 0x02, S: 0xF0, P: 0x20}) {
+		t.Fatalf("unexpected, got %+v", res)
+	}
+	if cpu.pc() != 0x0200 {
+		t.Fatalf("unexpected, got %04X", cpu.pc())
+	}
+	if r := cpu.Registers(); r != res {
+		t.Fatalf("unexpected, got %+v", r)
+	}
+}
+
+func TestCallLimit(t *testing.T) {
+	bus := &memoryBus{}
+	bus.mem[0x1000], bus.mem[0x1001], bus.mem[0x1002] = 0x4C, 0x00, 0x10 // JMP *
+	bus.mem[0x2000] = 0x02                                               // JAM
+
+	cpu := New(bus)
+	_, err := cpu.Call(0x1000, cpu.Registers(), 30)
+
+	lim := &LimitError{}
+	if !errors.As(err, &lim) || lim.Cycles != 30 || lim.PC != 0x1000 {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	if _, err = cpu.Call(0x2000, cpu.Registers(), 0); err == nil {
+		t.Fatal("unexpected")
+	}
+}
+
+func TestCallSentinel(t *testing.T) {
+	bus := &memoryBus{}
+	bus.mem[0x1000] = 0x60 // RTS
+
+	cpu := New(bus)
+	cpu.SetSentinel(0x3000, true)
+	if _, err := cpu.Call(0x1000, RegisterSet{S: 0xFF}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if bus.mem[0x01FF] != 0x2F || bus.mem[0x01FE] != 0xFF {
+		t.Fatalf("unexpected, got %02X%02X", bus.mem[0x01FF], bus.mem[0x01FE])
+	}
+	cpu.PC(0x00, 0x30)
+	if _, err := cpu.Step(); !errors.As(err, new(*SentinelError)) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
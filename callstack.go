@@ -0,0 +1,75 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+type (
+	// Frame is an entry of the call stack tracked alongside the 6502 stack.
+	Frame struct {
+		Interrupt bool   // Entered by interrupt or BRK, left by RTI
+		From      uint16 // Address of the JSR/BRK, or the interrupted address
+		To        uint16 // Address of the subroutine or handler
+		S         byte   // Stack pointer after the return address was pushed
+	}
+
+	callStack struct {
+		on     bool
+		frames []Frame
+	}
+)
+
+// SetStrictReturns enables the call stack tracking. Each RTS and RTI is checked
+// against the tracked calls: returning with an empty call stack, from a frame of
+// the wrong kind or with bytes left on the stack is reported as a Diagnostic. The
+// tracking tolerates stack unwinding, e.g. by TXS or pulling a return address.
+// Defaults to false.
+func (cpu *CPU) SetStrictReturns(on bool) {
+	cpu.calls = callStack{on: on}
+}
+
+// CallStack returns the tracked call stack, innermost frame last.
+// The call stack is only tracked with SetStrictReturns() enabled.
+func (cpu *CPU) CallStack() []Frame {
+	return append([]Frame(nil), cpu.calls.frames...)
+}
+
+func (cs *callStack) call(f Frame) {
+	cs.unwind(f.S + 1)
+	cs.frames = append(cs.frames, f)
+}
+
+// ret checks a return with the current stack pointer s against the call
+// stack and reports a Diagnostic on mismatch. The address of the returning
+// instruction is pc.
+func (cpu *CPU) ret(interrupt bool, pc uint16, s byte) {
+	cs := &cpu.calls
+	cs.unwind(s)
+
+	op, n := "RTS", len(cs.frames)
+	if interrupt {
+		op = "RTI"
+	}
+
+	if n == 0 {
+		cpu.diagnose(DiagReturnWithoutCall, pc, "%s without matching call", op)
+		return
+	}
+	f := cs.frames[n-1]
+	cs.frames = cs.frames[:n-1]
+
+	switch {
+	case f.Interrupt != interrupt:
+		cpu.diagnose(DiagReturnImbalanced, pc, "%s returns from frame entered at %04X", op, f.From)
+	case f.S != s:
+		cpu.diagnose(DiagReturnImbalanced, pc, "%s with %d byte(s) left on stack", op, f.S-s)
+	}
+}
+
+// unwind drops the frames below the stack pointer s, they are not
+// reachable anymore, e.g. after TXS or a pulled return address.
+func (cs *callStack) unwind(s byte) {
+	n := len(cs.frames)
+	for n > 0 && cs.frames[n-1].S < s {
+		n--
+	}
+	cs.frames = cs.frames[:n]
+}
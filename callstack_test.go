@@ -0,0 +1,115 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+// strict enables the return checks and collects the diagnostics.
+func strict(cpu *CPU) *[]Diagnostic {
+	diags := &[]Diagnostic{}
+	cpu.SetStrictReturns(true)
+	cpu.SetDiagnostics(func(d Diagnostic) { *diags = append(*diags, d) })
+	return diags
+}
+
+func steps(t *testing.T, cpu *CPU, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStrictReturnsBalanced(t *testing.T) {
+	cpu, bus := newTestCPU(NMOS6502,
+		0x20, 0x10, 0x02, // 0200 JSR $0210
+		0x00, 0xEA, //       0203 BRK
+		0xEA, //             0205 NOP
+	)
+	bus.mem[0x9000] = 0x40 // RTI
+	diags := strict(cpu)
+	cpu.bus.Write(0x10, 0x02, 0x20) // 0210 JSR $0220
+	cpu.bus.Write(0x11, 0x02, 0x20)
+	cpu.bus.Write(0x12, 0x02, 0x02)
+	cpu.bus.Write(0x13, 0x02, 0x60) // 0213 RTS
+	cpu.bus.Write(0x20, 0x02, 0x60) // 0220 RTS
+
+	steps(t, cpu, 2)
+	if cs := cpu.CallStack(); len(cs) != 2 || cs[0].To != 0x0210 || cs[1].From != 0x0210 || cs[1].S != 0xFB {
+		t.Fatalf("unexpected, got %+v", cs)
+	}
+	steps(t, cpu, 4)
+	if len(*diags) != 0 || len(cpu.CallStack()) != 0 || cpu.pc() != 0x0205 {
+		t.Fatalf("unexpected, got %v %s", *diags, cpu)
+	}
+
+	cpu.AssertIRQ()
+	steps(t, cpu, 1)
+	if cs := cpu.CallStack(); len(cs) != 1 || !cs[0].Interrupt || cs[0].From != 0x0205 {
+		t.Fatalf("unexpected, got %+v", cs)
+	}
+	cpu.ReleaseIRQ()
+	steps(t, cpu, 1)
+	if len(*diags) != 0 || len(cpu.CallStack()) != 0 {
+		t.Fatalf("unexpected, got %v", *diags)
+	}
+}
+
+func TestStrictReturnsViolations(t *testing.T) {
+	// RTS without call.
+	cpu, _ := newTestCPU(NMOS6502, 0x60)
+	diags := strict(cpu)
+	steps(t, cpu, 1)
+	if len(*diags) != 1 || (*diags)[0].Kind != DiagReturnWithoutCall || (*diags)[0].PC != 0x0200 {
+		t.Fatalf("unexpected, got %v", *diags)
+	}
+	if (*diags)[0].Error() != "m6502: 0200: RTS without matching call" {
+		t.Fatalf("unexpected, got %s", (*diags)[0].Error())
+	}
+
+	// RTS with byte left on stack.
+	cpu, _ = newTestCPU(NMOS6502, 0x20, 0x10, 0x02)
+	diags = strict(cpu)
+	cpu.bus.Write(0x10, 0x02, 0x48) // PHA
+	cpu.bus.Write(0x11, 0x02, 0x60) // RTS
+	steps(t, cpu, 3)
+	if len(*diags) != 1 || (*diags)[0].Kind != DiagReturnImbalanced {
+		t.Fatalf("unexpected, got %v", *diags)
+	}
+
+	// RTS from interrupt handler.
+	cpu, _ = newTestCPU(NMOS6502, 0x00, 0xEA)
+	diags = strict(cpu)
+	cpu.bus.Write(0x00, 0x90, 0x60) // RTS
+	steps(t, cpu, 2)
+	if len(*diags) != 1 || (*diags)[0].Kind != DiagReturnImbalanced {
+		t.Fatalf("unexpected, got %v", *diags)
+	}
+}
+
+func TestStrictReturnsUnwind(t *testing.T) {
+	// Subroutine drops its return address and returns to the outer caller.
+	cpu, _ := newTestCPU(NMOS6502, 0x20, 0x10, 0x02)
+	diags := strict(cpu)
+	cpu.bus.Write(0x10, 0x02, 0x20) // JSR $0220
+	cpu.bus.Write(0x11, 0x02, 0x20)
+	cpu.bus.Write(0x12, 0x02, 0x02)
+	cpu.bus.Write(0x20, 0x02, 0x68) // PLA
+	cpu.bus.Write(0x21, 0x02, 0x68) // PLA
+	cpu.bus.Write(0x22, 0x02, 0x60) // RTS
+
+	steps(t, cpu, 5)
+	if len(*diags) != 0 || len(cpu.CallStack()) != 0 || cpu.pc() != 0x0203 {
+		t.Fatalf("unexpected, got %v %s", *diags, cpu)
+	}
+
+	// Not tracked when disabled.
+	cpu, _ = newTestCPU(NMOS6502, 0x60)
+	diags = strict(cpu)
+	cpu.SetStrictReturns(false)
+	steps(t, cpu, 1)
+	if len(*diags) != 0 {
+		t.Fatalf("unexpected, got %v", *diags)
+	}
+}
@@ -0,0 +1,51 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "fmt"
+
+// BusCycle is a bus access captured by SetBusCapture().
+type BusCycle struct {
+	Cycle uint64    // Index of the cycle, counted like TotalCycles()
+	Kind  MicroKind // Kind of the access
+	Addr  uint16    // Address bus
+	Data  byte      // Data bus
+	Write bool      // R/W line low
+	Sync  bool      // SYNC line high, i.e. op code fetch
+}
+
+// SetBusCapture registers a function receiving each bus access of the
+// instructions and interrupt sequences as it happens, e.g. to feed a
+// logic-analyzer view or to validate against per-cycle test vectors.
+// Cycles without emulated bus access are skipped, thus gaps in the cycle
+// index. The capture relies on the micro-operations and costs accordingly.
+// Passing nil removes the function.
+func (cpu *CPU) SetBusCapture(fn func(BusCycle)) {
+	cpu.capture = fn
+	cpu.micro.on = cpu.micro.user || cpu.events != nil || fn != nil
+	cpu.track()
+}
+
+// String returns the bus cycle in a logic-analyzer like notation,
+// e.g. "42 R 0200 A9 SYNC".
+func (c BusCycle) String() string {
+	rw, sync := "R", ""
+	if c.Write {
+		rw = "W"
+	}
+	if c.Sync {
+		sync = " SYNC"
+	}
+	return fmt.Sprintf("%d %s %04X %02X%s", c.Cycle, rw, c.Addr, c.Data, sync)
+}
+
+func (cpu *CPU) captured(m MicroOp) {
+	if m.Kind == MicroInternal {
+		return
+	}
+	write := m.Kind == MicroWrite || m.Kind == MicroDummyWrite || m.Kind == MicroPush
+	cpu.capture(BusCycle{
+		Cycle: cpu.total + uint64(m.Cycle) - 1, Kind: m.Kind, Addr: m.Addr, Data: m.Data,
+		Write: write, Sync: m.Kind == MicroOpcode,
+	})
+}
@@ -0,0 +1,48 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBusCapture(t *testing.T) {
+	bus := &memoryBus{}
+	// LDA $10, INC $10, PHA
+	copy(bus.mem[0x0200:], []byte{0xA5, 0x10, 0xE6, 0x10, 0x48})
+	bus.mem[0x10] = 0x41
+
+	cpu := New(bus)
+	cpu.PC(0x00, 0x02)
+
+	var cycles []string
+	cpu.SetBusCapture(func(c BusCycle) { cycles = append(cycles, c.String()) })
+	for i := 0; i < 3; i++ {
+		_, _ = cpu.Step()
+	}
+	want := []string{
+		"7 R 0200 A5 SYNC", "8 R 0201 10", "9 R 0010 41",
+		"10 R 0202 E6 SYNC", "11 R 0203 10", "12 R 0010 41", "13 W 0010 41", "14 W 0010 42",
+		"15 R 0204 48 SYNC", "16 W 01FF 41", // PHA: internal cycle skipped
+	}
+	if strings.Join(cycles, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected, got\n%s", strings.Join(cycles, "\n"))
+	}
+	if cpu.TotalCycles() != 18 || len(cpu.MicroOps()) != 3 {
+		t.Fatalf("unexpected, got %d %d", cpu.TotalCycles(), len(cpu.MicroOps()))
+	}
+
+	cycles = nil
+	_, _ = cpu.NMI()
+	if len(cycles) != 5 || cycles[0] != "18 W 01FE 02" {
+		t.Fatalf("unexpected, got %v", cycles)
+	}
+
+	cpu.SetBusCapture(nil)
+	cycles = nil
+	_, _ = cpu.Step()
+	if len(cycles) != 0 || cpu.micro.on {
+		t.Fatalf("unexpected, got %v", cycles)
+	}
+}
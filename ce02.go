@@ -0,0 +1,438 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+type (
+	// CE02 holds the registers the CSG 65CE02 adds to the 65C02.
+	CE02 struct {
+		Z   byte // Z register, index of ($LL),Z, stored by STZ
+		B   byte // Base page, high byte of the zero page addresses
+		SPH byte // Stack pointer high byte
+		E   bool // E flag: 8 bit stack in page SPH when set, 16 bit when clear
+	}
+
+	ce02 struct {
+		z, b byte
+		wide bool // E flag clear, 16 bit stack pointer
+	}
+)
+
+// CE02 returns the registers of the 65CE02. Reset() clears Z and B, sets
+// SPH to 0x01 and sets the E flag, i.e. the stack of the NMOS 6502.
+func (cpu *CPU) CE02() CE02 {
+	return CE02{Z: cpu.ce.z, B: cpu.ce.b, SPH: cpu.sph, E: !cpu.ce.wide}
+}
+
+// SetCE02 sets the registers of the 65CE02, e.g. when loading a snapshot.
+func (cpu *CPU) SetCE02(r CE02) {
+	cpu.ce.z, cpu.ce.b, cpu.sph, cpu.ce.wide = r.Z, r.B, r.SPH, !r.E
+}
+
+// Addressing of the 65CE02: the zero page is located at the base page B,
+// indexing neither reads dummies nor costs a cycle on page cross.
+func (cpu *CPU) bp() (byte, byte)        { return cpu.fetch(), cpu.ce.b }
+func (cpu *CPU) bpN(n byte) (byte, byte) { return cpu.fetch() + n, cpu.ce.b }
+func (cpu *CPU) absI(n byte) (byte, byte) {
+	l, h, _ := cpu.absN(n)
+	return l, h
+}
+func (cpu *CPU) bpIndX() (byte, byte) {
+	b := cpu.fetch() + cpu.x
+	return cpu.read(b, cpu.ce.b), cpu.read(b+1, cpu.ce.b)
+}
+func (cpu *CPU) bpInd(n byte) (byte, byte) {
+	b := cpu.fetch()
+	l, c := uadd(cpu.read(b, cpu.ce.b), n)
+	return l, cpu.read(b+1, cpu.ce.b) + c
+}
+func (cpu *CPU) spIndY() (byte, byte) {
+	a := (uint16(cpu.sph)<<8 | uint16(cpu.s)) + uint16(cpu.fetch())
+	l, c := uadd(cpu.read(byte(a), byte(a>>8)), cpu.y)
+	a++
+	return l, cpu.read(byte(a), byte(a>>8)) + c
+}
+
+// modify is the read-modify-write of the 65CE02, without dummy access.
+func (cpu *CPU) modify(l, h byte, f func(*CPU, byte) byte) {
+	cpu.write(l, h, f(cpu, cpu.read(l, h)))
+}
+
+// modifyW is the read-modify-write of a little endian word located at l, h
+// and l2, h2. N and Z are set from the word.
+func (cpu *CPU) modifyW(l, h, l2, h2 byte, f func(*CPU, uint16) uint16) {
+	w := f(cpu, uint16(cpu.read(l2, h2))<<8|uint16(cpu.read(l, h)))
+	cpu.write(l, h, byte(w))
+	cpu.write(l2, h2, byte(w>>8))
+	cpu.setN(byte(w >> 8))
+	cpu.setF(w == 0, flagZ)
+}
+
+func (cpu *CPU) asr(b byte) byte { cpu.setC(b&0x01 != 0); return cpu.setNZ(b>>1 | b&0x80) }
+func (cpu *CPU) tsb(b byte) byte { cpu.setF(b&cpu.a == 0, flagZ); return b | cpu.a }
+func (cpu *CPU) trb(b byte) byte { cpu.setF(b&cpu.a == 0, flagZ); return b &^ cpu.a }
+
+// bra branches relative to the next instruction, see taken() for the cost.
+func (cpu *CPU) bra(c bool) {
+	if b := cpu.fetch(); c {
+		cpu.jump(cpu.pc() + uint16(int8(b)))
+		cpu.taken()
+	}
+}
+
+// braW branches relative to the last byte of the instruction by a word.
+func (cpu *CPU) braW(c bool) {
+	if l, h := cpu.fetch(), cpu.fetch(); c {
+		cpu.jump(cpu.pc() - 1 + (uint16(h)<<8 | uint16(l)))
+		cpu.cost(1)
+	}
+}
+
+// bbx tests a bit of a base page address and branches, when the bit
+// equals set, relative to the next instruction.
+func (cpu *CPU) bbx(bit byte, set bool) {
+	m := cpu.read(cpu.bp())
+	if b := cpu.fetch(); (m&(1<<bit) != 0) == set {
+		cpu.jump(cpu.pc() + uint16(int8(b)))
+		cpu.taken()
+	}
+}
+
+func (cpu *CPU) jsr(l, h byte) {
+	cpu.setPC(l, h)
+	if cpu.calls.on {
+		cpu.calls.call(Frame{From: cpu.at, To: cpu.pc(), S: cpu.s})
+	}
+}
+
+// pushPCW pushes the address of the last byte of an instruction.
+func (cpu *CPU) pushPCW() {
+	pc := cpu.pc() - 1
+	cpu.push(byte(pc >> 8))
+	cpu.push(byte(pc))
+}
+
+func (cpu *CPU) jump(pc uint16) { cpu.setPC(byte(pc), byte(pc>>8)) }
+
+// ce02ops performs the instructions of the 65CE02 by op code. The cycles
+// follow the bus accesses, see the csg op code table.
+var ce02ops = func() (t [0x100]func(cpu *CPU)) {
+	t = [0x100]func(cpu *CPU){
+		0x00:/* BRK */ func(cpu *CPU) { dispatch[0x00](cpu) },
+		0x01:/* ORA (oper,X) */ func(cpu *CPU) { cpu.setA(cpu.a | cpu.read(cpu.bpIndX())) },
+		0x02:/* CLE */ func(cpu *CPU) { cpu.ce.wide = true },
+		0x03:/* SEE */ func(cpu *CPU) { cpu.ce.wide = false },
+		0x04:/* TSB oper */ func(cpu *CPU) { l, h := cpu.bp(); cpu.modify(l, h, (*CPU).tsb) },
+		0x05:/* ORA oper */ func(cpu *CPU) { cpu.setA(cpu.a | cpu.read(cpu.bp())) },
+		0x06:/* ASL oper */ func(cpu *CPU) { l, h := cpu.bp(); cpu.modify(l, h, (*CPU).asl) },
+		0x08:/* PHP */ func(cpu *CPU) { cpu.push(byte(cpu.p|flagB) | when(cpu.ce.wide, 0, byte(flagU))) },
+		0x09:/* ORA #oper */ func(cpu *CPU) { cpu.setA(cpu.a | cpu.fetch()) },
+		0x0A:/* ASL A */ func(cpu *CPU) { cpu.a = cpu.asl(cpu.a) },
+		0x0B:/* TSY */ func(cpu *CPU) { cpu.setY(cpu.sph) },
+		0x0C:/* TSB oper */ func(cpu *CPU) { l, h := cpu.abs(); cpu.modify(l, h, (*CPU).tsb) },
+		0x0D:/* ORA oper */ func(cpu *CPU) { cpu.setA(cpu.a | cpu.read(cpu.abs())) },
+		0x0E:/* ASL oper */ func(cpu *CPU) { l, h := cpu.abs(); cpu.modify(l, h, (*CPU).asl) },
+
+		0x10:/* BPL oper */ func(cpu *CPU) { cpu.bra(!cpu.hasF(flagN)) },
+		0x11:/* ORA (oper),Y */ func(cpu *CPU) { cpu.setA(cpu.a | cpu.read(cpu.bpInd(cpu.y))) },
+		0x12:/* ORA (oper),Z */ func(cpu *CPU) { cpu.setA(cpu.a | cpu.read(cpu.bpInd(cpu.ce.z))) },
+		0x13:/* BPL oper */ func(cpu *CPU) { cpu.braW(!cpu.hasF(flagN)) },
+		0x14:/* TRB oper */ func(cpu *CPU) { l, h := cpu.bp(); cpu.modify(l, h, (*CPU).trb) },
+		0x15:/* ORA oper,X */ func(cpu *CPU) { cpu.setA(cpu.a | cpu.read(cpu.bpN(cpu.x))) },
+		0x16:/* ASL oper,X */ func(cpu *CPU) { l, h := cpu.bpN(cpu.x); cpu.modify(l, h, (*CPU).asl) },
+		0x18:/* CLC */ func(cpu *CPU) { cpu.setC(false) },
+		0x19:/* ORA oper,Y */ func(cpu *CPU) { cpu.setA(cpu.a | cpu.read(cpu.absI(cpu.y))) },
+		0x1A:/* INC A */ func(cpu *CPU) { cpu.setA(cpu.a + 1) },
+		0x1B:/* INZ */ func(cpu *CPU) { cpu.ce.z = cpu.setNZ(cpu.ce.z + 1) },
+		0x1C:/* TRB oper */ func(cpu *CPU) { l, h := cpu.abs(); cpu.modify(l, h, (*CPU).trb) },
+		0x1D:/* ORA oper,X */ func(cpu *CPU) { cpu.setA(cpu.a | cpu.read(cpu.absI(cpu.x))) },
+		0x1E:/* ASL oper,X */ func(cpu *CPU) { l, h := cpu.absI(cpu.x); cpu.modify(l, h, (*CPU).asl) },
+
+		0x20: /* JSR oper */ func(cpu *CPU) {
+			l := cpu.fetch()
+			cpu.pushPC()
+			cpu.jsr(l, cpu.fetch())
+		},
+		0x21:/* AND (oper,X) */ func(cpu *CPU) { cpu.setA(cpu.a & cpu.read(cpu.bpIndX())) },
+		0x22: /* JSR (oper) */ func(cpu *CPU) {
+			l, h := cpu.abs()
+			cpu.pushPCW()
+			cpu.jsr(cpu.read(l, h), cpu.read(inc(l, h)))
+		},
+		0x23: /* JSR (oper,X) */ func(cpu *CPU) {
+			l, h := cpu.absI(cpu.x)
+			cpu.pushPCW()
+			cpu.jsr(cpu.read(l, h), cpu.read(inc(l, h)))
+		},
+		0x24:/* BIT oper */ func(cpu *CPU) { cpu.bit(cpu.read(cpu.bp())) },
+		0x25:/* AND oper */ func(cpu *CPU) { cpu.setA(cpu.a & cpu.read(cpu.bp())) },
+		0x26:/* ROL oper */ func(cpu *CPU) { l, h := cpu.bp(); cpu.modify(l, h, (*CPU).rol) },
+		0x28:/* PLP */ func(cpu *CPU) { cpu.plp() },
+		0x29:/* AND #oper */ func(cpu *CPU) { cpu.setA(cpu.a & cpu.fetch()) },
+		0x2A:/* ROL A */ func(cpu *CPU) { cpu.a = cpu.rol(cpu.a) },
+		0x2B:/* TYS */ func(cpu *CPU) { cpu.sph = cpu.y },
+		0x2C:/* BIT oper */ func(cpu *CPU) { cpu.bit(cpu.read(cpu.abs())) },
+		0x2D:/* AND oper */ func(cpu *CPU) { cpu.setA(cpu.a & cpu.read(cpu.abs())) },
+		0x2E:/* ROL oper */ func(cpu *CPU) { l, h := cpu.abs(); cpu.modify(l, h, (*CPU).rol) },
+
+		0x30:/* BMI oper */ func(cpu *CPU) { cpu.bra(cpu.hasF(flagN)) },
+		0x31:/* AND (oper),Y */ func(cpu *CPU) { cpu.setA(cpu.a & cpu.read(cpu.bpInd(cpu.y))) },
+		0x32:/* AND (oper),Z */ func(cpu *CPU) { cpu.setA(cpu.a & cpu.read(cpu.bpInd(cpu.ce.z))) },
+		0x33:/* BMI oper */ func(cpu *CPU) { cpu.braW(cpu.hasF(flagN)) },
+		0x34:/* BIT oper,X */ func(cpu *CPU) { cpu.bit(cpu.read(cpu.bpN(cpu.x))) },
+		0x35:/* AND oper,X */ func(cpu *CPU) { cpu.setA(cpu.a & cpu.read(cpu.bpN(cpu.x))) },
+		0x36:/* ROL oper,X */ func(cpu *CPU) { l, h := cpu.bpN(cpu.x); cpu.modify(l, h, (*CPU).rol) },
+		0x38:/* SEC */ func(cpu *CPU) { cpu.setC(true) },
+		0x39:/* AND oper,Y */ func(cpu *CPU) { cpu.setA(cpu.a & cpu.read(cpu.absI(cpu.y))) },
+		0x3A:/* DEC A */ func(cpu *CPU) { cpu.setA(cpu.a - 1) },
+		0x3B:/* DEZ */ func(cpu *CPU) { cpu.ce.z = cpu.setNZ(cpu.ce.z - 1) },
+		0x3C:/* BIT oper,X */ func(cpu *CPU) { cpu.bit(cpu.read(cpu.absI(cpu.x))) },
+		0x3D:/* AND oper,X */ func(cpu *CPU) { cpu.setA(cpu.a & cpu.read(cpu.absI(cpu.x))) },
+		0x3E:/* ROL oper,X */ func(cpu *CPU) { l, h := cpu.absI(cpu.x); cpu.modify(l, h, (*CPU).rol) },
+
+		0x40: /* RTI */ func(cpu *CPU) {
+			if cpu.calls.on {
+				cpu.ret(true, cpu.at, cpu.s)
+			}
+			cpu.plp()
+			cpu.setPC(cpu.popPC())
+		},
+		0x41:/* EOR (oper,X) */ func(cpu *CPU) { cpu.setA(cpu.a ^ cpu.read(cpu.bpIndX())) },
+		0x42:/* NEG */ func(cpu *CPU) { cpu.setA(-cpu.a) },
+		0x43:/* ASR A */ func(cpu *CPU) { cpu.a = cpu.asr(cpu.a) },
+		0x44:/* ASR oper */ func(cpu *CPU) { l, h := cpu.bp(); cpu.modify(l, h, (*CPU).asr) },
+		0x45:/* EOR oper */ func(cpu *CPU) { cpu.setA(cpu.a ^ cpu.read(cpu.bp())) },
+		0x46:/* LSR oper */ func(cpu *CPU) { l, h := cpu.bp(); cpu.modify(l, h, (*CPU).lsr) },
+		0x48:/* PHA */ func(cpu *CPU) { cpu.push(cpu.a) },
+		0x49:/* EOR #oper */ func(cpu *CPU) { cpu.setA(cpu.a ^ cpu.fetch()) },
+		0x4A:/* LSR A */ func(cpu *CPU) { cpu.a = cpu.lsr(cpu.a) },
+		0x4B:/* TAZ */ func(cpu *CPU) { cpu.ce.z = cpu.setNZ(cpu.a) },
+		0x4C:/* JMP oper */ func(cpu *CPU) { cpu.setPC(cpu.abs()) },
+		0x4D:/* EOR oper */ func(cpu *CPU) { cpu.setA(cpu.a ^ cpu.read(cpu.abs())) },
+		0x4E:/* LSR oper */ func(cpu *CPU) { l, h := cpu.abs(); cpu.modify(l, h, (*CPU).lsr) },
+
+		0x50:/* BVC oper */ func(cpu *CPU) { cpu.bra(!cpu.hasF(flagV)) },
+		0x51:/* EOR (oper),Y */ func(cpu *CPU) { cpu.setA(cpu.a ^ cpu.read(cpu.bpInd(cpu.y))) },
+		0x52:/* EOR (oper),Z */ func(cpu *CPU) { cpu.setA(cpu.a ^ cpu.read(cpu.bpInd(cpu.ce.z))) },
+		0x53:/* BVC oper */ func(cpu *CPU) { cpu.braW(!cpu.hasF(flagV)) },
+		0x54:/* ASR oper,X */ func(cpu *CPU) { l, h := cpu.bpN(cpu.x); cpu.modify(l, h, (*CPU).asr) },
+		0x55:/* EOR oper,X */ func(cpu *CPU) { cpu.setA(cpu.a ^ cpu.read(cpu.bpN(cpu.x))) },
+		0x56:/* LSR oper,X */ func(cpu *CPU) { l, h := cpu.bpN(cpu.x); cpu.modify(l, h, (*CPU).lsr) },
+		0x58:/* CLI */ func(cpu *CPU) { cpu.setI(false) },
+		0x59:/* EOR oper,Y */ func(cpu *CPU) { cpu.setA(cpu.a ^ cpu.read(cpu.absI(cpu.y))) },
+		0x5A:/* PHY */ func(cpu *CPU) { cpu.push(cpu.y) },
+		0x5B:/* TAB */ func(cpu *CPU) { cpu.ce.b = cpu.a },
+		0x5C:/* AUG */ func(cpu *CPU) { cpu.fetch(); cpu.fetch(); cpu.fetch() },
+		0x5D:/* EOR oper,X */ func(cpu *CPU) { cpu.setA(cpu.a ^ cpu.read(cpu.absI(cpu.x))) },
+		0x5E:/* LSR oper,X */ func(cpu *CPU) { l, h := cpu.absI(cpu.x); cpu.modify(l, h, (*CPU).lsr) },
+
+		0x60: /* RTS */ func(cpu *CPU) {
+			if cpu.calls.on {
+				cpu.ret(false, cpu.at, cpu.s)
+			}
+			cpu.setPC(inc(cpu.popPC()))
+		},
+		0x61:/* ADC (oper,X) */ func(cpu *CPU) { cpu.adc(cpu.read(cpu.bpIndX())) },
+		0x62: /* RTS #oper */ func(cpu *CPU) {
+			n := cpu.fetch()
+			if cpu.calls.on {
+				cpu.ret(false, cpu.at, cpu.s)
+			}
+			cpu.setPC(inc(cpu.popPC()))
+			for ; n > 0; n-- {
+				cpu.up()
+			}
+		},
+		0x63: /* BSR oper */ func(cpu *CPU) {
+			l, h := cpu.fetch(), cpu.fetch()
+			cpu.pushPCW()
+			pc := cpu.pc() - 1 + (uint16(h)<<8 | uint16(l))
+			cpu.jsr(byte(pc), byte(pc>>8))
+		},
+		0x64:/* STZ oper */ func(cpu *CPU) { l, h := cpu.bp(); cpu.write(l, h, cpu.ce.z) },
+		0x65:/* ADC oper */ func(cpu *CPU) { cpu.adc(cpu.read(cpu.bp())) },
+		0x66:/* ROR oper */ func(cpu *CPU) { l, h := cpu.bp(); cpu.modify(l, h, (*CPU).ror) },
+		0x68:/* PLA */ func(cpu *CPU) { cpu.setA(cpu.pop()) },
+		0x69:/* ADC #oper */ func(cpu *CPU) { cpu.adc(cpu.fetch()) },
+		0x6A:/* ROR A */ func(cpu *CPU) { cpu.a = cpu.ror(cpu.a) },
+		0x6B:/* TZA */ func(cpu *CPU) { cpu.setA(cpu.ce.z) },
+		0x6C:/* JMP (oper) */ func(cpu *CPU) { l, h := cpu.abs(); cpu.setPC(cpu.read(l, h), cpu.read(inc(l, h))) },
+		0x6D:/* ADC oper */ func(cpu *CPU) { cpu.adc(cpu.read(cpu.abs())) },
+		0x6E:/* ROR oper */ func(cpu *CPU) { l, h := cpu.abs(); cpu.modify(l, h, (*CPU).ror) },
+
+		0x70:/* BVS oper */ func(cpu *CPU) { cpu.bra(cpu.hasF(flagV)) },
+		0x71:/* ADC (oper),Y */ func(cpu *CPU) { cpu.adc(cpu.read(cpu.bpInd(cpu.y))) },
+		0x72:/* ADC (oper),Z */ func(cpu *CPU) { cpu.adc(cpu.read(cpu.bpInd(cpu.ce.z))) },
+		0x73:/* BVS oper */ func(cpu *CPU) { cpu.braW(cpu.hasF(flagV)) },
+		0x74:/* STZ oper,X */ func(cpu *CPU) { l, h := cpu.bpN(cpu.x); cpu.write(l, h, cpu.ce.z) },
+		0x75:/* ADC oper,X */ func(cpu *CPU) { cpu.adc(cpu.read(cpu.bpN(cpu.x))) },
+		0x76:/* ROR oper,X */ func(cpu *CPU) { l, h := cpu.bpN(cpu.x); cpu.modify(l, h, (*CPU).ror) },
+		0x78:/* SEI */ func(cpu *CPU) { cpu.setI(true) },
+		0x79:/* ADC oper,Y */ func(cpu *CPU) { cpu.adc(cpu.read(cpu.absI(cpu.y))) },
+		0x7A:/* PLY */ func(cpu *CPU) { cpu.setY(cpu.pop()) },
+		0x7B:/* TBA */ func(cpu *CPU) { cpu.setA(cpu.ce.b) },
+		0x7C:/* JMP (oper,X) */ func(cpu *CPU) { l, h := cpu.absI(cpu.x); cpu.setPC(cpu.read(l, h), cpu.read(inc(l, h))) },
+		0x7D:/* ADC oper,X */ func(cpu *CPU) { cpu.adc(cpu.read(cpu.absI(cpu.x))) },
+		0x7E:/* ROR oper,X */ func(cpu *CPU) { l, h := cpu.absI(cpu.x); cpu.modify(l, h, (*CPU).ror) },
+
+		0x80:/* BRA oper */ func(cpu *CPU) { cpu.bra(true) },
+		0x81:/* STA (oper,X) */ func(cpu *CPU) { l, h := cpu.bpIndX(); cpu.write(l, h, cpu.a) },
+		0x82:/* STA (oper,SP),Y */ func(cpu *CPU) { l, h := cpu.spIndY(); cpu.write(l, h, cpu.a) },
+		0x83:/* BRA oper */ func(cpu *CPU) { cpu.braW(true) },
+		0x84:/* STY oper */ func(cpu *CPU) { l, h := cpu.bp(); cpu.write(l, h, cpu.y) },
+		0x85:/* STA oper */ func(cpu *CPU) { l, h := cpu.bp(); cpu.write(l, h, cpu.a) },
+		0x86:/* STX oper */ func(cpu *CPU) { l, h := cpu.bp(); cpu.write(l, h, cpu.x) },
+		0x88:/* DEY */ func(cpu *CPU) { cpu.setY(cpu.y - 1) },
+		0x89:/* BIT #oper */ func(cpu *CPU) { cpu.setF(cpu.fetch()&cpu.a == 0, flagZ) },
+		0x8A:/* TXA */ func(cpu *CPU) { cpu.setA(cpu.x) },
+		0x8B:/* STY oper,X */ func(cpu *CPU) { l, h := cpu.absI(cpu.x); cpu.write(l, h, cpu.y) },
+		0x8C:/* STY oper */ func(cpu *CPU) { l, h := cpu.abs(); cpu.write(l, h, cpu.y) },
+		0x8D:/* STA oper */ func(cpu *CPU) { l, h := cpu.abs(); cpu.write(l, h, cpu.a) },
+		0x8E:/* STX oper */ func(cpu *CPU) { l, h := cpu.abs(); cpu.write(l, h, cpu.x) },
+
+		0x90:/* BCC oper */ func(cpu *CPU) { cpu.bra(!cpu.hasF(flagC)) },
+		0x91:/* STA (oper),Y */ func(cpu *CPU) { l, h := cpu.bpInd(cpu.y); cpu.write(l, h, cpu.a) },
+		0x92:/* STA (oper),Z */ func(cpu *CPU) { l, h := cpu.bpInd(cpu.ce.z); cpu.write(l, h, cpu.a) },
+		0x93:/* BCC oper */ func(cpu *CPU) { cpu.braW(!cpu.hasF(flagC)) },
+		0x94:/* STY oper,X */ func(cpu *CPU) { l, h := cpu.bpN(cpu.x); cpu.write(l, h, cpu.y) },
+		0x95:/* STA oper,X */ func(cpu *CPU) { l, h := cpu.bpN(cpu.x); cpu.write(l, h, cpu.a) },
+		0x96:/* STX oper,Y */ func(cpu *CPU) { l, h := cpu.bpN(cpu.y); cpu.write(l, h, cpu.x) },
+		0x98:/* TYA */ func(cpu *CPU) { cpu.setA(cpu.y) },
+		0x99:/* STA oper,Y */ func(cpu *CPU) { l, h := cpu.absI(cpu.y); cpu.write(l, h, cpu.a) },
+		0x9A:/* TXS */ func(cpu *CPU) { cpu.s = cpu.x },
+		0x9B:/* STX oper,Y */ func(cpu *CPU) { l, h := cpu.absI(cpu.y); cpu.write(l, h, cpu.x) },
+		0x9C:/* STZ oper */ func(cpu *CPU) { l, h := cpu.abs(); cpu.write(l, h, cpu.ce.z) },
+		0x9D:/* STA oper,X */ func(cpu *CPU) { l, h := cpu.absI(cpu.x); cpu.write(l, h, cpu.a) },
+		0x9E:/* STZ oper,X */ func(cpu *CPU) { l, h := cpu.absI(cpu.x); cpu.write(l, h, cpu.ce.z) },
+
+		0xA0:/* LDY #oper */ func(cpu *CPU) { cpu.setY(cpu.fetch()) },
+		0xA1:/* LDA (oper,X) */ func(cpu *CPU) { cpu.setA(cpu.read(cpu.bpIndX())) },
+		0xA2:/* LDX #oper */ func(cpu *CPU) { cpu.setX(cpu.fetch()) },
+		0xA3:/* LDZ #oper */ func(cpu *CPU) { cpu.ce.z = cpu.setNZ(cpu.fetch()) },
+		0xA4:/* LDY oper */ func(cpu *CPU) { cpu.setY(cpu.read(cpu.bp())) },
+		0xA5:/* LDA oper */ func(cpu *CPU) { cpu.setA(cpu.read(cpu.bp())) },
+		0xA6:/* LDX oper */ func(cpu *CPU) { cpu.setX(cpu.read(cpu.bp())) },
+		0xA8:/* TAY */ func(cpu *CPU) { cpu.setY(cpu.a) },
+		0xA9:/* LDA #oper */ func(cpu *CPU) { cpu.setA(cpu.fetch()) },
+		0xAA:/* TAX */ func(cpu *CPU) { cpu.setX(cpu.a) },
+		0xAB:/* LDZ oper */ func(cpu *CPU) { cpu.ce.z = cpu.setNZ(cpu.read(cpu.abs())) },
+		0xAC:/* LDY oper */ func(cpu *CPU) { cpu.setY(cpu.read(cpu.abs())) },
+		0xAD:/* LDA oper */ func(cpu *CPU) { cpu.setA(cpu.read(cpu.abs())) },
+		0xAE:/* LDX oper */ func(cpu *CPU) { cpu.setX(cpu.read(cpu.abs())) },
+
+		0xB0:/* BCS oper */ func(cpu *CPU) { cpu.bra(cpu.hasF(flagC)) },
+		0xB1:/* LDA (oper),Y */ func(cpu *CPU) { cpu.setA(cpu.read(cpu.bpInd(cpu.y))) },
+		0xB2:/* LDA (oper),Z */ func(cpu *CPU) { cpu.setA(cpu.read(cpu.bpInd(cpu.ce.z))) },
+		0xB3:/* BCS oper */ func(cpu *CPU) { cpu.braW(cpu.hasF(flagC)) },
+		0xB4:/* LDY oper,X */ func(cpu *CPU) { cpu.setY(cpu.read(cpu.bpN(cpu.x))) },
+		0xB5:/* LDA oper,X */ func(cpu *CPU) { cpu.setA(cpu.read(cpu.bpN(cpu.x))) },
+		0xB6:/* LDX oper,Y */ func(cpu *CPU) { cpu.setX(cpu.read(cpu.bpN(cpu.y))) },
+		0xB8:/* CLV */ func(cpu *CPU) { cpu.setF(false, flagV) },
+		0xB9:/* LDA oper,Y */ func(cpu *CPU) { cpu.setA(cpu.read(cpu.absI(cpu.y))) },
+		0xBA:/* TSX */ func(cpu *CPU) { cpu.setX(cpu.s) },
+		0xBB:/* LDZ oper,X */ func(cpu *CPU) { cpu.ce.z = cpu.setNZ(cpu.read(cpu.absI(cpu.x))) },
+		0xBC:/* LDY oper,X */ func(cpu *CPU) { cpu.setY(cpu.read(cpu.absI(cpu.x))) },
+		0xBD:/* LDA oper,X */ func(cpu *CPU) { cpu.setA(cpu.read(cpu.absI(cpu.x))) },
+		0xBE:/* LDX oper,Y */ func(cpu *CPU) { cpu.setX(cpu.read(cpu.absI(cpu.y))) },
+
+		0xC0:/* CPY #oper */ func(cpu *CPU) { cpu.cmp(cpu.fetch(), cpu.y) },
+		0xC1:/* CMP (oper,X) */ func(cpu *CPU) { cpu.cmp(cpu.read(cpu.bpIndX()), cpu.a) },
+		0xC2:/* CPZ #oper */ func(cpu *CPU) { cpu.cmp(cpu.fetch(), cpu.ce.z) },
+		0xC3: /* DEW oper */ func(cpu *CPU) {
+			l, h := cpu.bp()
+			cpu.modifyW(l, h, l+1, h, func(_ *CPU, w uint16) uint16 { return w - 1 })
+		},
+		0xC4:/* CPY oper */ func(cpu *CPU) { cpu.cmp(cpu.read(cpu.bp()), cpu.y) },
+		0xC5:/* CMP oper */ func(cpu *CPU) { cpu.cmp(cpu.read(cpu.bp()), cpu.a) },
+		0xC6:/* DEC oper */ func(cpu *CPU) { l, h := cpu.bp(); cpu.modify(l, h, (*CPU).dec) },
+		0xC8:/* INY */ func(cpu *CPU) { cpu.setY(cpu.y + 1) },
+		0xC9:/* CMP #oper */ func(cpu *CPU) { cpu.cmp(cpu.fetch(), cpu.a) },
+		0xCA:/* DEX */ func(cpu *CPU) { cpu.setX(cpu.x - 1) },
+		0xCB: /* ASW oper */ func(cpu *CPU) {
+			l, h := cpu.abs()
+			l2, h2 := inc(l, h)
+			cpu.modifyW(l, h, l2, h2, func(cpu *CPU, w uint16) uint16 { cpu.setC(w&0x8000 != 0); return w << 1 })
+		},
+		0xCC:/* CPY oper */ func(cpu *CPU) { cpu.cmp(cpu.read(cpu.abs()), cpu.y) },
+		0xCD:/* CMP oper */ func(cpu *CPU) { cpu.cmp(cpu.read(cpu.abs()), cpu.a) },
+		0xCE:/* DEC oper */ func(cpu *CPU) { l, h := cpu.abs(); cpu.modify(l, h, (*CPU).dec) },
+
+		0xD0:/* BNE oper */ func(cpu *CPU) { cpu.bra(!cpu.hasF(flagZ)) },
+		0xD1:/* CMP (oper),Y */ func(cpu *CPU) { cpu.cmp(cpu.read(cpu.bpInd(cpu.y)), cpu.a) },
+		0xD2:/* CMP (oper),Z */ func(cpu *CPU) { cpu.cmp(cpu.read(cpu.bpInd(cpu.ce.z)), cpu.a) },
+		0xD3:/* BNE oper */ func(cpu *CPU) { cpu.braW(!cpu.hasF(flagZ)) },
+		0xD4:/* CPZ oper */ func(cpu *CPU) { cpu.cmp(cpu.read(cpu.bp()), cpu.ce.z) },
+		0xD5:/* CMP oper,X */ func(cpu *CPU) { cpu.cmp(cpu.read(cpu.bpN(cpu.x)), cpu.a) },
+		0xD6:/* DEC oper,X */ func(cpu *CPU) { l, h := cpu.bpN(cpu.x); cpu.modify(l, h, (*CPU).dec) },
+		0xD8:/* CLD */ func(cpu *CPU) { cpu.setF(false, flagD) },
+		0xD9:/* CMP oper,Y */ func(cpu *CPU) { cpu.cmp(cpu.read(cpu.absI(cpu.y)), cpu.a) },
+		0xDA:/* PHX */ func(cpu *CPU) { cpu.push(cpu.x) },
+		0xDB:/* PHZ */ func(cpu *CPU) { cpu.push(cpu.ce.z) },
+		0xDC:/* CPZ oper */ func(cpu *CPU) { cpu.cmp(cpu.read(cpu.abs()), cpu.ce.z) },
+		0xDD:/* CMP oper,X */ func(cpu *CPU) { cpu.cmp(cpu.read(cpu.absI(cpu.x)), cpu.a) },
+		0xDE:/* DEC oper,X */ func(cpu *CPU) { l, h := cpu.absI(cpu.x); cpu.modify(l, h, (*CPU).dec) },
+
+		0xE0:/* CPX #oper */ func(cpu *CPU) { cpu.cmp(cpu.fetch(), cpu.x) },
+		0xE1:/* SBC (oper,X) */ func(cpu *CPU) { cpu.sbc(cpu.read(cpu.bpIndX())) },
+		0xE2:/* LDA (oper,SP),Y */ func(cpu *CPU) { cpu.setA(cpu.read(cpu.spIndY())) },
+		0xE3: /* INW oper */ func(cpu *CPU) {
+			l, h := cpu.bp()
+			cpu.modifyW(l, h, l+1, h, func(_ *CPU, w uint16) uint16 { return w + 1 })
+		},
+		0xE4:/* CPX oper */ func(cpu *CPU) { cpu.cmp(cpu.read(cpu.bp()), cpu.x) },
+		0xE5:/* SBC oper */ func(cpu *CPU) { cpu.sbc(cpu.read(cpu.bp())) },
+		0xE6:/* INC oper */ func(cpu *CPU) { l, h := cpu.bp(); cpu.modify(l, h, (*CPU).incr) },
+		0xE8:/* INX */ func(cpu *CPU) { cpu.setX(cpu.x + 1) },
+		0xE9:/* SBC #oper */ func(cpu *CPU) { cpu.sbc(cpu.fetch()) },
+		0xEA:/* NOP */ func(cpu *CPU) {},
+		0xEB: /* ROW oper */ func(cpu *CPU) {
+			l, h := cpu.abs()
+			l2, h2 := inc(l, h)
+			cpu.modifyW(l, h, l2, h2, func(cpu *CPU, w uint16) uint16 {
+				c := uint16(cpu.p & flagC)
+				cpu.setC(w&0x8000 != 0)
+				return w<<1 | c
+			})
+		},
+		0xEC:/* CPX oper */ func(cpu *CPU) { cpu.cmp(cpu.read(cpu.abs()), cpu.x) },
+		0xED:/* SBC oper */ func(cpu *CPU) { cpu.sbc(cpu.read(cpu.abs())) },
+		0xEE:/* INC oper */ func(cpu *CPU) { l, h := cpu.abs(); cpu.modify(l, h, (*CPU).incr) },
+
+		0xF0:/* BEQ oper */ func(cpu *CPU) { cpu.bra(cpu.hasF(flagZ)) },
+		0xF1:/* SBC (oper),Y */ func(cpu *CPU) { cpu.sbc(cpu.read(cpu.bpInd(cpu.y))) },
+		0xF2:/* SBC (oper),Z */ func(cpu *CPU) { cpu.sbc(cpu.read(cpu.bpInd(cpu.ce.z))) },
+		0xF3:/* BEQ oper */ func(cpu *CPU) { cpu.braW(cpu.hasF(flagZ)) },
+		0xF4: /* PHW #oper */ func(cpu *CPU) {
+			l, h := cpu.fetch(), cpu.fetch()
+			cpu.push(h)
+			cpu.push(l)
+		},
+		0xF5:/* SBC oper,X */ func(cpu *CPU) { cpu.sbc(cpu.read(cpu.bpN(cpu.x))) },
+		0xF6:/* INC oper,X */ func(cpu *CPU) { l, h := cpu.bpN(cpu.x); cpu.modify(l, h, (*CPU).incr) },
+		0xF8:/* SED */ func(cpu *CPU) { cpu.setF(true, flagD) },
+		0xF9:/* SBC oper,Y */ func(cpu *CPU) { cpu.sbc(cpu.read(cpu.absI(cpu.y))) },
+		0xFA:/* PLX */ func(cpu *CPU) { cpu.setX(cpu.pop()) },
+		0xFB:/* PLZ */ func(cpu *CPU) { cpu.ce.z = cpu.setNZ(cpu.pop()) },
+		0xFC: /* PHW oper */ func(cpu *CPU) {
+			l, h := cpu.abs()
+			lo, hi := cpu.read(l, h), cpu.read(inc(l, h))
+			cpu.push(hi)
+			cpu.push(lo)
+		},
+		0xFD:/* SBC oper,X */ func(cpu *CPU) { cpu.sbc(cpu.read(cpu.absI(cpu.x))) },
+		0xFE:/* INC oper,X */ func(cpu *CPU) { l, h := cpu.absI(cpu.x); cpu.modify(l, h, (*CPU).incr) },
+	}
+	for i := byte(0); i < 8; i++ {
+		bit := i
+		t[i<<4|0x07] = /* RMB */ func(cpu *CPU) {
+			l, h := cpu.bp()
+			cpu.write(l, h, cpu.read(l, h)&^(1<<bit))
+		}
+		t[i<<4|0x87] = /* SMB */ func(cpu *CPU) {
+			l, h := cpu.bp()
+			cpu.write(l, h, cpu.read(l, h)|1<<bit)
+		}
+		t[i<<4|0x0F] = /* BBR */ func(cpu *CPU) { cpu.bbx(bit, false) }
+		t[i<<4|0x8F] = /* BBS */ func(cpu *CPU) { cpu.bbx(bit, true) }
+	}
+	return t
+}()
@@ -0,0 +1,244 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+func TestCE02Cycles(t *testing.T) {
+	for op := 0; op < 0x100; op++ {
+		i := DecodeVariant(CSG65CE02, byte(op))
+		switch i.Mode {
+		case Relative, RelativeWord, ZeroPageRelative:
+			if i.Op != OpBRA && i.Op != OpBSR {
+				continue
+			}
+		}
+		cpu, _ := newTestCPU(CSG65CE02, byte(op))
+		cycles, err := cpu.Step()
+		if err != nil || cycles != uint(i.Cycles) || i.Op == OpInvalid || i.Illegal {
+			t.Errorf("unexpected, got %d %v for %02X %s", cycles, err, op, i.Mnemonic())
+		}
+	}
+	if CSG65CE02.String() != "65CE02" {
+		t.Error("unexpected")
+	}
+}
+
+func TestCE02Registers(t *testing.T) {
+	// LDA #$30, TAB, LDZ #$80, INZ, PHZ, PLA, STZ $10, TBA
+	cpu, bus := newTestCPU(CSG65CE02, 0xA9, 0x30, 0x5B, 0xA3, 0x80, 0x1B, 0xDB, 0x68, 0x64, 0x10, 0x7B)
+	for i := 0; i < 6; i++ {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if r := cpu.CE02(); r.Z != 0x81 || r.B != 0x30 || !r.E || r.SPH != 0x01 || cpu.a != 0x81 {
+		t.Fatalf("unexpected, got %+v %s", r, cpu)
+	}
+	for i := 0; i < 2; i++ {
+		_, _ = cpu.Step()
+	}
+	if bus.mem[0x3010] != 0x81 || cpu.a != 0x30 {
+		t.Fatalf("unexpected, got %02X %s", bus.mem[0x3010], cpu)
+	}
+
+	cpu.SetCE02(CE02{Z: 1, B: 2, SPH: 3, E: false})
+	if r := cpu.CE02(); r != (CE02{Z: 1, B: 2, SPH: 3, E: false}) {
+		t.Fatalf("unexpected, got %+v", r)
+	}
+	if cpu.Reset(); cpu.CE02() != (CE02{SPH: 0x01, E: true}) {
+		t.Fatalf("unexpected, got %+v", cpu.CE02())
+	}
+}
+
+func TestCE02BasePage(t *testing.T) {
+	// LDA #$30, TAB, LDA $10, LDZ #$02, LDA ($12),Z, STA $11
+	cpu, bus := newTestCPU(CSG65CE02, 0xA9, 0x30, 0x5B, 0xA5, 0x10, 0xA3, 0x02, 0xB2, 0x12, 0x85, 0x11)
+	bus.mem[0x0010], bus.mem[0x3010] = 0x11, 0x22
+	bus.mem[0x3012], bus.mem[0x3013] = 0x00, 0x40
+	bus.mem[0x4002] = 0x33
+
+	for i := 0; i < 3; i++ {
+		_, _ = cpu.Step()
+	}
+	if cpu.a != 0x22 {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+	for i := 0; i < 3; i++ {
+		_, _ = cpu.Step()
+	}
+	if cpu.a != 0x33 || bus.mem[0x3011] != 0x33 || bus.mem[0x0011] != 0x00 {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+}
+
+func TestCE02Stack(t *testing.T) {
+	// LDY #$05, TYS, PHA, CLE, LDX #$00, TXS, PHA, PLA, PLA, SEE, TSY
+	cpu, bus := newTestCPU(CSG65CE02, 0xA0, 0x05, 0x2B, 0x48, 0x02, 0xA2, 0x00, 0x9A, 0x48, 0x68, 0x68, 0x03, 0x0B)
+	cpu.a = 0xAA
+
+	for i := 0; i < 3; i++ {
+		_, _ = cpu.Step()
+	}
+	if bus.mem[0x05FF] != 0xAA || cpu.s != 0xFE || cpu.sph != 0x05 {
+		t.Fatalf("unexpected, got %02X %02X %02X", bus.mem[0x05FF], cpu.s, cpu.sph)
+	}
+	for i := 0; i < 4; i++ {
+		_, _ = cpu.Step()
+	}
+	if bus.mem[0x0500] != 0xAA || cpu.s != 0xFF || cpu.sph != 0x04 {
+		t.Fatalf("unexpected, got %02X %02X %02X", bus.mem[0x0500], cpu.s, cpu.sph)
+	}
+	for i := 0; i < 2; i++ {
+		_, _ = cpu.Step()
+	}
+	if cpu.s != 0x01 || cpu.sph != 0x05 {
+		t.Fatalf("unexpected, got %02X %02X", cpu.s, cpu.sph)
+	}
+	for i := 0; i < 2; i++ {
+		_, _ = cpu.Step()
+	}
+	if r := cpu.CE02(); !r.E || cpu.y != 0x05 {
+		t.Fatalf("unexpected, got %+v %s", r, cpu)
+	}
+}
+
+func TestCE02StackIndirect(t *testing.T) {
+	// PHW #$4000, LDY #$01, LDA ($01,SP),Y, STA ($01,SP),Y
+	cpu, bus := newTestCPU(CSG65CE02, 0xF4, 0x00, 0x40, 0xA0, 0x01, 0xE2, 0x01, 0x82, 0x01)
+	bus.mem[0x4001] = 0x55
+
+	for i := 0; i < 3; i++ {
+		_, _ = cpu.Step()
+	}
+	if cpu.s != 0xFD || bus.mem[0x01FE] != 0x00 || bus.mem[0x01FF] != 0x40 || cpu.a != 0x55 {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+	cpu.y, cpu.a = 0x02, 0x66
+	if _, _ = cpu.Step(); bus.mem[0x4002] != 0x66 {
+		t.Fatalf("unexpected, got %02X", bus.mem[0x4002])
+	}
+}
+
+func TestCE02Branches(t *testing.T) {
+	cpu, bus := newTestCPU(CSG65CE02, 0x83, 0xFE, 0x0F) // BRA $1200
+	copy(bus.mem[0x1200:], []byte{0x63, 0xFE, 0xF0})    // BSR $0300
+	copy(bus.mem[0x0300:], []byte{0x62, 0x02})          // RTS #$02
+
+	if cycles, _ := cpu.Step(); cpu.pc() != 0x1200 || cycles != 4 {
+		t.Fatalf("unexpected, got %04X %d", cpu.pc(), cycles)
+	}
+	if cycles, _ := cpu.Step(); cpu.pc() != 0x0300 || cycles != 5 || cpu.s != 0xFD {
+		t.Fatalf("unexpected, got %04X %d", cpu.pc(), cycles)
+	}
+	if bus.mem[0x01FF] != 0x12 || bus.mem[0x01FE] != 0x02 {
+		t.Fatalf("unexpected, got %02X %02X", bus.mem[0x01FF], bus.mem[0x01FE])
+	}
+	if cycles, _ := cpu.Step(); cpu.pc() != 0x1203 || cycles != 4 || cpu.s != 0x01 {
+		t.Fatalf("unexpected, got %04X %d %02X", cpu.pc(), cycles, cpu.s)
+	}
+
+	// BEQ $0200 not taken, BNE $0200 taken
+	cpu, _ = newTestCPU(CSG65CE02, 0xF3, 0xFE, 0xFF, 0xD3, 0xFB, 0xFF)
+	if cycles, _ := cpu.Step(); cpu.pc() != 0x0203 || cycles != 3 {
+		t.Fatalf("unexpected, got %04X %d", cpu.pc(), cycles)
+	}
+	if cycles, _ := cpu.Step(); cpu.pc() != 0x0200 || cycles != 4 {
+		t.Fatalf("unexpected, got %04X %d", cpu.pc(), cycles)
+	}
+}
+
+func TestCE02Bits(t *testing.T) {
+	// SMB3 $10, BBS3 $10,+2, RMB3 $10, BBR3 $10,-7
+	cpu, bus := newTestCPU(CSG65CE02, 0xB7, 0x10, 0xBF, 0x10, 0x02, 0x00, 0x00, 0x37, 0x10, 0x3F, 0x10, 0xF6)
+
+	steps := []struct {
+		pc     uint16
+		mem    byte
+		cycles uint
+	}{
+		{0x0202, 0x08, 4},
+		{0x0207, 0x08, 5},
+		{0x0209, 0x00, 4},
+		{0x0202, 0x00, 5},
+		{0x0205, 0x00, 4},
+	}
+	for i, s := range steps {
+		if cycles, _ := cpu.Step(); cpu.pc() != s.pc || bus.mem[0x10] != s.mem || cycles != s.cycles {
+			t.Fatalf("unexpected, got %04X %02X %d in step %d", cpu.pc(), bus.mem[0x10], cycles, i)
+		}
+	}
+}
+
+func TestCE02Words(t *testing.T) {
+	// INW $10, DEW $12, ASW $3000, ROW $3002
+	cpu, bus := newTestCPU(CSG65CE02, 0xE3, 0x10, 0xC3, 0x12, 0xCB, 0x00, 0x30, 0xEB, 0x02, 0x30)
+	bus.mem[0x10], bus.mem[0x11] = 0xFF, 0x00
+	bus.mem[0x12], bus.mem[0x13] = 0x01, 0x00
+	bus.mem[0x3000], bus.mem[0x3001] = 0x81, 0x80
+	bus.mem[0x3002], bus.mem[0x3003] = 0x00, 0x40
+
+	tests := []struct {
+		addr  uint16
+		want  uint16
+		flags flag
+	}{
+		{0x10, 0x0100, 0},
+		{0x12, 0x0000, flagZ},
+		{0x3000, 0x0102, flagC},
+		{0x3002, 0x8001, flagN},
+	}
+	for i, tt := range tests {
+		_, _ = cpu.Step()
+		w := uint16(bus.mem[tt.addr+1])<<8 | uint16(bus.mem[tt.addr])
+		if w != tt.want || cpu.p&(flagN|flagZ|flagC) != tt.flags {
+			t.Fatalf("unexpected, got %04X %s in test %d", w, cpu, i)
+		}
+	}
+}
+
+func TestCE02Arithmetic(t *testing.T) {
+	// LDA #$81, NEG, ASR A, LDA #$81, ASR A, CPZ #$00
+	cpu, _ := newTestCPU(CSG65CE02, 0xA9, 0x81, 0x42, 0x43, 0xA9, 0x81, 0x43, 0xC2, 0x00)
+	tests := []struct {
+		a     byte
+		flags flag
+	}{
+		{0x81, flagN},
+		{0x7F, 0},
+		{0x3F, flagC},
+		{0x81, flagN | flagC},
+		{0xC0, flagN | flagC},
+		{0xC0, flagZ | flagC},
+	}
+	for i, tt := range tests {
+		_, _ = cpu.Step()
+		if cpu.a != tt.a || cpu.p&(flagN|flagZ|flagC) != tt.flags {
+			t.Fatalf("unexpected, got %s in test %d", cpu, i)
+		}
+	}
+}
+
+func TestCE02Disassembler(t *testing.T) {
+	d := Disassembler{Variant: CSG65CE02}
+	tests := []struct {
+		code []byte
+		want string
+	}{
+		{[]byte{0xB2, 0x12}, "LDA ($12),Z"},
+		{[]byte{0xE2, 0x03}, "LDA ($03,SP),Y"},
+		{[]byte{0x13, 0xFE, 0xFF}, "BPL $0200"},
+		{[]byte{0x63, 0x00, 0x10}, "BSR $1202"},
+		{[]byte{0xF4, 0x34, 0x12}, "PHW #$1234"},
+		{[]byte{0x9F, 0x10, 0xFD}, "BBS1 $10,$0200"},
+		{[]byte{0x57, 0x20}, "RMB5 $20"},
+		{[]byte{0x5C, 0x00, 0x00, 0x00}, "AUG"},
+		{[]byte{0x62, 0x02}, "RTS #$02"},
+		{[]byte{0x02}, "CLE"},
+	}
+	for _, tt := range tests {
+		if asm, n := d.Decode(0x0200, tt.code); asm != tt.want || n != len(tt.code) {
+			t.Errorf("unexpected, got %q %d", asm, n)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Clock paces the execution of a CPU to a target frequency, based on the
+// cycle counts returned by Step(). It runs the CPU in slices of about one
+// millisecond and sleeps when ahead of the wall clock. When behind by more
+// than MaxDrift, e.g. after the host has been suspended, the Clock resyncs
+// instead of catching up with a burst.
+type Clock struct {
+	// MaxDrift is the lag tolerated before resyncing, 100ms by default.
+	MaxDrift time.Duration
+
+	cpu    *CPU
+	hz     float64
+	warp   atomic.Bool
+	cycles uint64
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// Common clock frequencies in Hz.
+const (
+	ClockC64PAL  = 985_248   // Commodore 64, PAL
+	ClockC64NTSC = 1_022_727 // Commodore 64, NTSC
+	ClockNESNTSC = 1_789_773 // NES 2A03, NTSC
+)
+
+// NewClock creates a Clock running the CPU at hz cycles per second.
+func NewClock(cpu *CPU, hz uint) *Clock {
+	if hz == 0 {
+		panic("m6502: invalid clock frequency 0")
+	}
+	return &Clock{
+		MaxDrift: 100 * time.Millisecond,
+		cpu:      cpu,
+		hz:       float64(hz),
+		now:      time.Now,
+		sleep:    time.Sleep,
+	}
+}
+
+// SetWarp disables (true) or enables (false) the pacing, e.g. for a turbo
+// key or fast loading. It may be called concurrently to Run().
+func (c *Clock) SetWarp(on bool) {
+	c.warp.Store(on)
+}
+
+// Cycles returns the number of cycles run by the Clock.
+func (c *Clock) Cycles() uint64 {
+	return c.cycles
+}
+
+// Run runs the CPU paced to the frequency until Step() returns an
+// error or the context is done, and returns the respective error.
+func (c *Clock) Run(ctx context.Context) error {
+	slice := uint64(c.hz/1000) + 1
+	start, base := c.now(), c.cycles
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for n := uint64(0); n < slice; {
+			cycles, err := c.cpu.Step()
+			if err != nil {
+				return err
+			}
+			n += uint64(cycles)
+			c.cycles += uint64(cycles)
+		}
+		if c.warp.Load() {
+			start, base = c.now(), c.cycles
+			continue
+		}
+		due := start.Add(time.Duration(float64(c.cycles-base) / c.hz * float64(time.Second)))
+		switch ahead := due.Sub(c.now()); {
+		case ahead > 0:
+			c.sleep(ahead)
+		case -ahead > c.MaxDrift:
+			start, base = c.now(), c.cycles
+		}
+	}
+}
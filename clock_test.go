@@ -0,0 +1,90 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeTime is a virtual wall clock advanced by sleeping.
+type fakeTime struct {
+	t     time.Time
+	slept time.Duration
+	stop  time.Duration
+	fn    context.CancelFunc
+}
+
+func (f *fakeTime) now() time.Time { return f.t }
+
+func (f *fakeTime) sleep(d time.Duration) {
+	f.t, f.slept = f.t.Add(d), f.slept+d
+	if f.slept >= f.stop {
+		f.fn()
+	}
+}
+
+func newFakeClock(hz uint, stop time.Duration) (*Clock, *fakeTime, context.Context) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ft := &fakeTime{t: time.Unix(0, 0), stop: stop, fn: cancel}
+
+	cpu, _ := newTestCPU(NMOS6502, nopJmp...)
+	c := NewClock(cpu, hz)
+	c.now, c.sleep = ft.now, ft.sleep
+	return c, ft, ctx
+}
+
+func TestClockPacing(t *testing.T) {
+	c, ft, ctx := newFakeClock(1_000_000, 10*time.Millisecond)
+
+	if err := c.Run(ctx); err != context.Canceled {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	// 10ms at 1 MHz, within a slice.
+	if c.Cycles() < 10_000 || c.Cycles() > 11_010 || ft.slept < 10*time.Millisecond {
+		t.Fatalf("unexpected, got %d cycles, %s", c.Cycles(), ft.slept)
+	}
+}
+
+func TestClockWarp(t *testing.T) {
+	c, ft, ctx := newFakeClock(1_000_000, 0)
+	c.SetWarp(true)
+
+	cctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+
+	if err := c.Run(cctx); err != context.DeadlineExceeded || ft.slept != 0 || c.Cycles() == 0 {
+		t.Fatalf("unexpected, got %v %s", err, ft.slept)
+	}
+}
+
+func TestClockDrift(t *testing.T) {
+	c, ft, ctx := newFakeClock(1_000_000, 5*time.Millisecond)
+
+	// Host suspended for a second: resync instead of a catch-up burst.
+	first := true
+	c.now = func() time.Time {
+		if first {
+			first = false
+			return ft.t
+		}
+		return ft.now().Add(time.Second)
+	}
+	if err := c.Run(ctx); err != context.Canceled {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	if c.Cycles() > 10_000 {
+		t.Fatalf("unexpected, got %d cycles", c.Cycles())
+	}
+}
+
+func TestClockError(t *testing.T) {
+	c, _, ctx := newFakeClock(1_000_000, time.Second)
+	c.cpu.bus.(*triggerBus).mem[0x0200] = 0x02 // HLT
+
+	if err := c.Run(ctx); !errors.Is(err, ErrHalted) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dtgorski/m6502"
+)
+
+type (
+	debugger struct {
+		cpu    *m6502.CPU
+		mem    *memory
+		breaks map[uint16]bool
+		recent []uint16 // PCs of the recently performed instructions
+		view   uint16   // Start of the memory view
+		last   string   // Previous command, repeated on empty input
+		status string
+	}
+
+	memory [0x10000]byte
+)
+
+const (
+	maxContinue = 10_000_000 // Steps of "c" before giving up
+	maxRecent   = 4          // Instructions shown before the PC
+	maxAhead    = 10         // Instructions shown from the PC on
+	clearScreen = "\x1b[H\x1b[2J"
+)
+
+func newDebugger(v m6502.Variant) *debugger {
+	mem := &memory{}
+	cpu := m6502.New(mem, m6502.WithVariant(v))
+	return &debugger{cpu: cpu, mem: mem, breaks: map[uint16]bool{}}
+}
+
+// loop renders the screen and performs commands until "q" or EOF.
+func (d *debugger) loop(in io.Reader, out io.Writer) error {
+	sc := bufio.NewScanner(in)
+	for {
+		io.WriteString(out, clearScreen)
+		d.render(out)
+		fmt.Fprint(out, "> ")
+
+		if !sc.Scan() {
+			fmt.Fprintln(out)
+			return sc.Err()
+		}
+		if !d.command(sc.Text()) {
+			return nil
+		}
+	}
+}
+
+// command performs a command line, false when the debugger shall quit.
+func (d *debugger) command(line string) bool {
+	if line = strings.TrimSpace(line); line == "" {
+		line = d.last
+	}
+	d.last, d.status = line, ""
+
+	f := strings.Fields(line)
+	if len(f) == 0 {
+		return true
+	}
+	arg := func(def uint64, bits int) (uint64, bool) {
+		if len(f) < 2 {
+			return def, true
+		}
+		n, err := strconv.ParseUint(strings.TrimPrefix(f[1], "$"), 16, bits)
+		if err != nil {
+			d.status = fmt.Sprintf("invalid argument %q", f[1])
+			return 0, false
+		}
+		return n, true
+	}
+
+	switch f[0] {
+	case "q":
+		return false
+	case "s":
+		if n, ok := arg(1, 32); ok {
+			d.step(n, false)
+		}
+	case "c":
+		d.step(maxContinue, true)
+	case "b":
+		if len(f) < 2 {
+			d.status = "missing address"
+		} else if a, ok := arg(0, 16); ok {
+			d.breaks[uint16(a)] = !d.breaks[uint16(a)]
+			if !d.breaks[uint16(a)] {
+				delete(d.breaks, uint16(a))
+			}
+		}
+	case "m":
+		if a, ok := arg(uint64(d.view), 16); ok {
+			d.view = uint16(a)
+		}
+	case "g":
+		if a, ok := arg(uint64(d.pc()), 16); ok {
+			d.cpu.PC(byte(a), byte(a>>8))
+		}
+	case "r":
+		d.cpu.Reset()
+		d.recent = d.recent[:0]
+	default:
+		d.status = fmt.Sprintf("unknown command %q", f[0])
+	}
+	return true
+}
+
+// step performs up to n instructions. With breaks, it stops in front of
+// a breakpoint, the instruction at the current PC is always performed.
+func (d *debugger) step(n uint64, breaks bool) {
+	for i := uint64(0); i < n; i++ {
+		pc := d.pc()
+		if breaks && i > 0 && d.breaks[pc] {
+			d.status = fmt.Sprintf("breakpoint at %04X", pc)
+			return
+		}
+		if _, err := d.cpu.Step(); err != nil {
+			d.status = err.Error()
+			return
+		}
+		if d.recent = append(d.recent, pc); len(d.recent) > maxRecent {
+			d.recent = d.recent[1:]
+		}
+	}
+	if breaks {
+		d.status = "no breakpoint reached"
+	}
+}
+
+func (d *debugger) render(w io.Writer) {
+	fmt.Fprintf(w, "%s\n\n", d.cpu)
+
+	for _, pc := range d.recent {
+		d.disasm(w, pc, "  ")
+	}
+	pc := d.pc()
+	for i := 0; i < maxAhead; i++ {
+		mark := "  "
+		if i == 0 {
+			mark = "> "
+		}
+		pc += uint16(d.disasm(w, pc, mark))
+	}
+
+	fmt.Fprintf(w, "\nmemory\n")
+	for row := uint16(0); row < 8; row++ {
+		a := d.view + row*0x10
+		var hex, txt strings.Builder
+		for i := uint16(0); i < 0x10; i++ {
+			b := d.mem[a+i]
+			fmt.Fprintf(&hex, " %02X", b)
+			if b < 0x20 || b > 0x7E {
+				b = '.'
+			}
+			txt.WriteByte(b)
+		}
+		fmt.Fprintf(w, "  %04X %s  %s\n", a, hex.String(), txt.String())
+	}
+
+	s := d.cpu.Registers().S
+	fmt.Fprintf(w, "\nstack\n ")
+	for a := int(s) + 1; a <= 0xFF && a <= int(s)+0x10; a++ {
+		fmt.Fprintf(w, " %02X", d.mem[0x0100|a])
+	}
+	fmt.Fprintf(w, "\n\nbreakpoints\n ")
+	for _, a := range d.breakpoints() {
+		fmt.Fprintf(w, " %04X", a)
+	}
+	fmt.Fprintf(w, "\n\n%s\n", d.status)
+}
+
+func (d *debugger) disasm(w io.Writer, pc uint16, mark string) int {
+	code := []byte{d.mem[pc], d.mem[pc+1], d.mem[pc+2]}
+	asm, size := d.cpu.Disassembler().Decode(pc, code)
+
+	brk := " "
+	if d.breaks[pc] {
+		brk = "*"
+	}
+	fmt.Fprintf(w, "%s%s%04X  % -8X  %s\n", mark, brk, pc, code[:size], asm)
+	return size
+}
+
+func (d *debugger) breakpoints() []uint16 {
+	list := make([]uint16, 0, len(d.breaks))
+	for a := range d.breaks {
+		list = append(list, a)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	return list
+}
+
+func (d *debugger) pc() uint16 {
+	return uint16(d.cpu.PCH())<<8 | uint16(d.cpu.PCL())
+}
+
+func (m *memory) Read(l, h byte) byte { return m[uint16(h)<<8|uint16(l)] }
+func (m *memory) Write(l, h, db byte) { m[uint16(h)<<8|uint16(l)] = db }
+func (m *memory) MemSlice() []byte    { return m[:] }
@@ -0,0 +1,94 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dtgorski/m6502"
+)
+
+func newTestDebugger() *debugger {
+	d := newDebugger(m6502.NMOS6502)
+	// LDX #$00, INX, STX $10, JMP $0202
+	copy(d.mem[0x0200:], []byte{0xA2, 0x00, 0xE8, 0x86, 0x10, 0x4C, 0x02, 0x02})
+	d.cpu.PC(0x00, 0x02)
+	return d
+}
+
+func TestDebuggerStep(t *testing.T) {
+	d := newTestDebugger()
+	d.command("s 3")
+	if d.pc() != 0x0205 || d.mem[0x10] != 0x01 {
+		t.Fatalf("unexpected, got %04X %02X", d.pc(), d.mem[0x10])
+	}
+	d.command("")
+	if d.pc() != 0x0205 || d.last != "s 3" || len(d.recent) != maxRecent {
+		t.Fatalf("unexpected, got %04X %q %v", d.pc(), d.last, d.recent)
+	}
+}
+
+func TestDebuggerBreakpoint(t *testing.T) {
+	d := newTestDebugger()
+	d.command("b 0205")
+	d.command("c")
+	if d.pc() != 0x0205 || d.status != "breakpoint at 0205" {
+		t.Fatalf("unexpected, got %04X %q", d.pc(), d.status)
+	}
+	d.command("c")
+	if d.pc() != 0x0205 || d.mem[0x10] != 0x02 {
+		t.Fatalf("unexpected, got %04X %02X", d.pc(), d.mem[0x10])
+	}
+	d.command("b $0205")
+	if len(d.breaks) != 0 {
+		t.Fatalf("unexpected, got %v", d.breaks)
+	}
+}
+
+func TestDebuggerCommands(t *testing.T) {
+	d := newTestDebugger()
+	for _, tt := range []struct {
+		line   string
+		status string
+	}{
+		{"g 0202", ""},
+		{"m 0010", ""},
+		{"m xyz", `invalid argument "xyz"`},
+		{"b", "missing address"},
+		{"x", `unknown command "x"`},
+	} {
+		if d.command(tt.line); d.status != tt.status {
+			t.Fatalf("unexpected, got %q for %q", d.status, tt.line)
+		}
+	}
+	if d.pc() != 0x0202 || d.view != 0x0010 {
+		t.Fatalf("unexpected, got %04X %04X", d.pc(), d.view)
+	}
+	if d.command("q") {
+		t.Fatalf("unexpected, got true")
+	}
+}
+
+func TestDebuggerLoop(t *testing.T) {
+	d := newTestDebugger()
+	d.view = 0x0200
+
+	out := &strings.Builder{}
+	if err := d.loop(strings.NewReader("b 0205\ns 2\n"), out); err != nil {
+		t.Fatal(err)
+	}
+	screen := out.String()[strings.LastIndex(out.String(), clearScreen):]
+
+	for _, want := range []string{
+		"   0200  A2 00     LDX #$00",
+		">  0203  86 10     STX $10",
+		"  *0205  4C 02 02  JMP $0202",
+		"  0200  A2 00 E8 86 10 4C 02 02",
+		"breakpoints\n  0205",
+	} {
+		if !strings.Contains(screen, want) {
+			t.Fatalf("unexpected, missing %q in\n%s", want, screen)
+		}
+	}
+}
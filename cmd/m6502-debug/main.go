@@ -0,0 +1,83 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+// Command m6502-debug is a terminal debugger for 6502 binaries. The image
+// is loaded into 64K RAM, the screen shows the registers, the disassembly
+// around the PC, a memory view and the stack. Commands are read line by
+// line, an empty line repeats the previous command:
+//
+//	s [n]     Steps n instructions, default 1
+//	c         Continues until a breakpoint or an error
+//	b <addr>  Toggles a breakpoint at addr
+//	m <addr>  Shows the memory at addr
+//	g <addr>  Sets the PC to addr
+//	r         Resets the CPU
+//	q         Quits
+//
+// Usage:
+//
+//	m6502-debug [-load 0200] [-pc 0200] [-variant 6502|65C02|2A03|65CE02|HuC6280] <image>
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/dtgorski/m6502"
+)
+
+func main() {
+	load := flag.String("load", "0200", "load address of the image (hex)")
+	pc := flag.String("pc", "", "entry point (hex), defaults to the load address")
+	variant := flag.String("variant", "6502", "processor model: 6502, 65C02, 2A03, 65CE02 or HuC6280")
+	flag.Parse()
+
+	if err := run(flag.Arg(0), *load, *pc, *variant); err != nil {
+		fmt.Fprintln(os.Stderr, "m6502-debug:", err)
+		os.Exit(1)
+	}
+}
+
+func run(file, load, pc, variant string) error {
+	if file == "" {
+		return fmt.Errorf("missing image, see -h")
+	}
+	img, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	origin, err := strconv.ParseUint(load, 16, 16)
+	if err != nil {
+		return fmt.Errorf("invalid load address %q", load)
+	}
+	entry := origin
+	if pc != "" {
+		if entry, err = strconv.ParseUint(pc, 16, 16); err != nil {
+			return fmt.Errorf("invalid entry point %q", pc)
+		}
+	}
+	v, ok := parseVariant(variant)
+	if !ok {
+		return fmt.Errorf("unknown variant %q", variant)
+	}
+	if int(origin)+len(img) > 0x10000 {
+		return fmt.Errorf("image exceeds address space")
+	}
+
+	d := newDebugger(v)
+	copy(d.mem[origin:], img)
+	d.cpu.PC(byte(entry), byte(entry>>8))
+	d.view = uint16(origin)
+
+	return d.loop(os.Stdin, os.Stdout)
+}
+
+func parseVariant(s string) (m6502.Variant, bool) {
+	for _, v := range []m6502.Variant{m6502.NMOS6502, m6502.CMOS65C02, m6502.Ricoh2A03, m6502.CSG65CE02, m6502.HuC6280} {
+		if v.String() == s {
+			return v, true
+		}
+	}
+	return 0, false
+}
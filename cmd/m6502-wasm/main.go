@@ -0,0 +1,19 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+//go:build js && wasm
+
+// Command m6502-wasm exports a 6502 machine with 64K RAM as global
+// JavaScript object "m6502", see the wasm package. Build with "make wasm"
+// and load it by the wasm_exec.js glue of the Go distribution.
+package main
+
+import (
+	"syscall/js"
+
+	"github.com/dtgorski/m6502/wasm"
+)
+
+func main() {
+	js.Global().Set("m6502", wasm.Export(wasm.New()))
+	select {}
+}
@@ -0,0 +1,88 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+// Zero page indirect addressing of the 65C02, ($LL).
+func (cpu *CPU) zpInd() (byte, byte) {
+	b := cpu.fetch()
+	return cpu.zread(b), cpu.zread(b + 1)
+}
+
+// reserved performs a reserved op code of the 65C02 as NOP of the size
+// and the cycles of the cmos op code table, reading its operand address.
+func reserved(i Instruction) func(cpu *CPU) {
+	return func(cpu *CPU) {
+		switch i.Mode {
+		case Immediate:
+			cpu.fetch()
+		case ZeroPage:
+			cpu.zread(cpu.fetch())
+		case ZeroPageX:
+			cpu.zread(cpu.fetch() + cpu.x)
+		case Absolute, AbsoluteX:
+			cpu.read(cpu.abs())
+		}
+		if n := uint(i.Cycles); cpu.cycles < n {
+			cpu.cost(byte(n - cpu.cycles))
+		}
+	}
+}
+
+// cmosops performs the instructions of the 65C02 by op code. The op codes
+// shared with the NMOS 6502 use its handlers, the Quirks of the 65C02 apply
+// the differences, e.g. JMP (indirect) and the read-modify-write accesses.
+// The reserved op codes are NOPs, see the cmos op code table.
+var cmosops = func() (t [0x100]func(cpu *CPU)) {
+	own := [0x100]func(cpu *CPU){
+		0x04:/* TSB oper */ func(cpu *CPU) { cpu.rmw(cpu.fetch(), 0x00, (*CPU).tsb) },
+		0x0C:/* TSB oper */ func(cpu *CPU) { l, h := cpu.abs(); cpu.rmw(l, h, (*CPU).tsb) },
+		0x12:/* ORA (oper) */ func(cpu *CPU) { cpu.setA(cpu.a | cpu.read(cpu.zpInd())) },
+		0x14:/* TRB oper */ func(cpu *CPU) { cpu.rmw(cpu.fetch(), 0x00, (*CPU).trb) },
+		0x1A:/* INC A */ func(cpu *CPU) { cpu.setA(cpu.a + 1); cpu.cost(1) },
+		0x1C:/* TRB oper */ func(cpu *CPU) { l, h := cpu.abs(); cpu.rmw(l, h, (*CPU).trb) },
+		0x32:/* AND (oper) */ func(cpu *CPU) { cpu.setA(cpu.a & cpu.read(cpu.zpInd())) },
+		0x34:/* BIT oper,X */ func(cpu *CPU) { cpu.bit(cpu.zread(cpu.fetch() + cpu.x)); cpu.cost(1) },
+		0x3A:/* DEC A */ func(cpu *CPU) { cpu.setA(cpu.a - 1); cpu.cost(1) },
+		0x3C: /* BIT oper,X */ func(cpu *CPU) {
+			l, h, c := cpu.absN(cpu.x)
+			cpu.cross(l, h, c)
+			cpu.bit(cpu.read(l, h))
+		},
+		0x52:/* EOR (oper) */ func(cpu *CPU) { cpu.setA(cpu.a ^ cpu.read(cpu.zpInd())) },
+		0x5A:/* PHY */ func(cpu *CPU) { cpu.push(cpu.y); cpu.cost(1) },
+		0x64:/* STZ oper */ func(cpu *CPU) { cpu.zwrite(cpu.fetch(), 0x00) },
+		0x72:/* ADC (oper) */ func(cpu *CPU) { cpu.adc(cpu.read(cpu.zpInd())) },
+		0x74:/* STZ oper,X */ func(cpu *CPU) { cpu.zwrite(cpu.fetch()+cpu.x, 0x00); cpu.cost(1) },
+		0x7A:/* PLY */ func(cpu *CPU) { cpu.setY(cpu.pop()); cpu.cost(2) },
+		0x7C: /* JMP (oper,X) */ func(cpu *CPU) {
+			l, h, c := cpu.absN(cpu.x)
+			cpu.dummy(l, h-c)
+			cpu.setPC(cpu.read(l, h), cpu.read(inc(l, h)))
+		},
+		0x80:/* BRA oper */ func(cpu *CPU) { cpu.branch(true) },
+		0x89:/* BIT #oper */ func(cpu *CPU) { cpu.setF(cpu.fetch()&cpu.a == 0, flagZ) },
+		0x92:/* STA (oper) */ func(cpu *CPU) { l, h := cpu.zpInd(); cpu.write(l, h, cpu.a) },
+		0x9C:/* STZ oper */ func(cpu *CPU) { l, h := cpu.abs(); cpu.write(l, h, 0x00) },
+		0x9E: /* STZ oper,X */ func(cpu *CPU) {
+			l, h, c := cpu.absN(cpu.x)
+			cpu.dummy(l, h-c)
+			cpu.write(l, h, 0x00)
+		},
+		0xB2:/* LDA (oper) */ func(cpu *CPU) { cpu.setA(cpu.read(cpu.zpInd())) },
+		0xD2:/* CMP (oper) */ func(cpu *CPU) { cpu.cmp(cpu.read(cpu.zpInd()), cpu.a) },
+		0xDA:/* PHX */ func(cpu *CPU) { cpu.push(cpu.x); cpu.cost(1) },
+		0xF2:/* SBC (oper) */ func(cpu *CPU) { cpu.sbc(cpu.read(cpu.zpInd())) },
+		0xFA:/* PLX */ func(cpu *CPU) { cpu.setX(cpu.pop()); cpu.cost(2) },
+	}
+	for op := range t {
+		switch i := cmos[op]; {
+		case own[op] != nil:
+			t[op] = own[op]
+		case i.Op == OpInvalid:
+			t[op] = reserved(i)
+		default:
+			t[op] = dispatch[op]
+		}
+	}
+	return t
+}()
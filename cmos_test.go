@@ -0,0 +1,132 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+func TestCMOSCycles(t *testing.T) {
+	for op := 0; op < 0x100; op++ {
+		i := DecodeVariant(CMOS65C02, byte(op))
+		switch i.Op {
+		case OpBRK, OpJMP, OpJSR, OpRTS, OpRTI:
+			continue
+		}
+		switch i.Mode {
+		case Relative, ZeroPageRelative:
+			if i.Op != OpBRA {
+				continue
+			}
+		}
+		cpu, _ := newTestCPU(CMOS65C02, byte(op))
+		cycles, err := cpu.Step()
+		if err != nil {
+			continue
+		}
+		if cycles != uint(i.Cycles) {
+			t.Errorf("unexpected, got %d for %02X %s", cycles, op, i.Mnemonic())
+		}
+	}
+}
+
+func TestCMOSStack(t *testing.T) {
+	// PHX, PHY, PLX, PLY
+	cpu, bus := newTestCPU(CMOS65C02, 0xDA, 0x5A, 0xFA, 0x7A)
+	cpu.x, cpu.y, cpu.s = 0x11, 0x80, 0xFF
+	for i := 0; i < 2; i++ {
+		_, _ = cpu.Step()
+	}
+	if bus.mem[0x01FF] != 0x11 || bus.mem[0x01FE] != 0x80 {
+		t.Fatalf("unexpected, got %02X %02X", bus.mem[0x01FF], bus.mem[0x01FE])
+	}
+	for i := 0; i < 2; i++ {
+		_, _ = cpu.Step()
+	}
+	if cpu.x != 0x80 || cpu.y != 0x11 || cpu.p&flagN != 0 || cpu.s != 0xFF {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+}
+
+func TestCMOSStore(t *testing.T) {
+	// STZ $10, STZ $10,X, STZ $3000, STZ $3000,X, TSB $20, TRB $21
+	cpu, bus := newTestCPU(CMOS65C02, 0x64, 0x10, 0x74, 0x10, 0x9C, 0x00, 0x30, 0x9E, 0x00, 0x30, 0x04, 0x20, 0x14, 0x21)
+	bus.mem[0x0010], bus.mem[0x0011], bus.mem[0x3000], bus.mem[0x3001] = 1, 1, 1, 1
+	bus.mem[0x0020], bus.mem[0x0021] = 0x0C, 0x0C
+	cpu.a, cpu.x = 0x03, 0x01
+	for i := 0; i < 6; i++ {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if bus.mem[0x0010]|bus.mem[0x0011]|bus.mem[0x3000]|bus.mem[0x3001] != 0 {
+		t.Fatal("unexpected")
+	}
+	if bus.mem[0x0020] != 0x0F || bus.mem[0x0021] != 0x0C || cpu.p&flagZ == 0 {
+		t.Fatalf("unexpected, got %02X %02X %s", bus.mem[0x0020], bus.mem[0x0021], cpu)
+	}
+}
+
+func TestCMOSAddressing(t *testing.T) {
+	// LDA ($10), INC A, STA ($12), DEC A, BIT #$00
+	cpu, bus := newTestCPU(CMOS65C02, 0xB2, 0x10, 0x1A, 0x92, 0x12, 0x3A, 0x89, 0x00)
+	copy(bus.mem[0x0010:], []byte{0x00, 0x30, 0x00, 0x31})
+	bus.mem[0x3000] = 0x7F
+	for i := 0; i < 4; i++ {
+		_, _ = cpu.Step()
+	}
+	if bus.mem[0x3100] != 0x80 || cpu.a != 0x7F {
+		t.Fatalf("unexpected, got %02X %s", bus.mem[0x3100], cpu)
+	}
+	cpu.p |= flagN | flagV
+	if _, _ = cpu.Step(); cpu.p&flagZ == 0 || cpu.p&(flagN|flagV) != flagN|flagV {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+}
+
+func TestCMOSFlow(t *testing.T) {
+	// BRA +2, JMP ($3000,X)
+	cpu, bus := newTestCPU(CMOS65C02, 0x80, 0x02, 0x00, 0x00, 0x7C, 0x00, 0x30)
+	copy(bus.mem[0x3002:], []byte{0x34, 0x12})
+	cpu.x = 0x02
+	if _, _ = cpu.Step(); cpu.pc() != 0x0204 {
+		t.Fatalf("unexpected, got %04X", cpu.pc())
+	}
+	if _, _ = cpu.Step(); cpu.pc() != 0x1234 {
+		t.Fatalf("unexpected, got %04X", cpu.pc())
+	}
+
+	// Reserved, two bytes on the 65C02.
+	cpu, _ = newTestCPU(CMOS65C02, 0x02, 0xFF)
+	if _, err := cpu.Step(); err != nil || cpu.pc() != 0x0202 {
+		t.Fatalf("unexpected, got %v %04X", err, cpu.pc())
+	}
+}
+
+func TestCMOSReserved(t *testing.T) {
+	sizes, cycles := map[uint16]bool{}, map[uint]bool{}
+	for op := 0; op < 0x100; op++ {
+		i := DecodeVariant(CMOS65C02, byte(op))
+		if i.Op != OpInvalid {
+			continue
+		}
+		cpu, _ := newTestCPU(CMOS65C02, byte(op), 0x10, 0x30)
+		cpu.a, cpu.x, cpu.y = 0x11, 0x22, 0x33
+		p := cpu.p
+
+		n, err := cpu.Step()
+		if err != nil || n != uint(i.Cycles) || cpu.pc() != 0x0200+uint16(i.Size()) {
+			t.Fatalf("unexpected, got %v %d %04X for %02X", err, n, cpu.pc(), op)
+		}
+		if cpu.a != 0x11 || cpu.x != 0x22 || cpu.y != 0x33 || cpu.p != p || cpu.s != 0xFF {
+			t.Fatalf("unexpected, got %s for %02X", cpu, op)
+		}
+		sizes[uint16(i.Size())], cycles[n] = true, true
+	}
+	if len(sizes) != 3 || !sizes[1] || !sizes[2] || !sizes[3] {
+		t.Fatalf("unexpected, got %v", sizes)
+	}
+	for _, n := range []uint{1, 2, 3, 4, 8} {
+		if !cycles[n] {
+			t.Fatalf("unexpected, got %v", cycles)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+type (
+	// Coverage collects which addresses have been executed, read and
+	// written, e.g. to find code paths of a ROM never reached. Executed
+	// are the op code and operand bytes of the performed instructions.
+	Coverage struct {
+		sets [3][0x10000 / 64]uint64
+	}
+
+	// CoverKind selects the accesses of a Coverage.
+	CoverKind byte
+
+	// AddrRange is a range of addresses, inclusive.
+	AddrRange struct {
+		Start, End uint16
+	}
+
+	coveredBus struct {
+		bus Bus
+		c   *Coverage
+	}
+)
+
+// Kinds of coverage.
+const (
+	CoverExec  CoverKind = iota // Instruction bytes performed
+	CoverRead                   // Bus reads, incl. op code fetches
+	CoverWrite                  // Bus writes
+)
+
+var coverKinds = [...]string{"exec", "read", "write"}
+
+// NewCoverage creates an empty Coverage.
+func NewCoverage() *Coverage {
+	return &Coverage{}
+}
+
+// Attach collects the executed addresses of the CPU by a Hook with
+// PriorityProfiler. The returned function detaches the Coverage.
+func (c *Coverage) Attach(cpu *CPU) (detach func()) {
+	return cpu.AddHook(PriorityProfiler, func(cpu *CPU, t Trace) bool {
+		n := max(DecodeVariant(cpu.variant, t.Opcode).Size(), 1)
+		for i := byte(0); i < n; i++ {
+			c.Mark(CoverExec, t.PC+uint16(i))
+		}
+		return false
+	})
+}
+
+// Bus returns a decorator of bus collecting the read and written
+// addresses. Pass it to New() instead of bus.
+func (c *Coverage) Bus(bus Bus) Bus {
+	return coveredBus{bus, c}
+}
+
+// Mark marks the address as covered.
+func (c *Coverage) Mark(k CoverKind, addr uint16) {
+	c.sets[k][addr>>6] |= 1 << (addr & 63)
+}
+
+// Has reports whether the address has been covered.
+func (c *Coverage) Has(k CoverKind, addr uint16) bool {
+	return c.sets[k][addr>>6]&(1<<(addr&63)) != 0
+}
+
+// Count returns the number of covered addresses.
+func (c *Coverage) Count(k CoverKind) (n int) {
+	for a := 0; a <= 0xFFFF; a++ {
+		if c.Has(k, uint16(a)) {
+			n++
+		}
+	}
+	return n
+}
+
+// Bitmap returns the covered addresses as 8K bitmap: bit n (LSB first)
+// of byte n/8 is set when address n has been covered.
+func (c *Coverage) Bitmap(k CoverKind) []byte {
+	bm := make([]byte, 0x10000/8)
+	for i, w := range c.sets[k] {
+		for j := 0; j < 8; j++ {
+			bm[i*8+j] = byte(w >> (j * 8))
+		}
+	}
+	return bm
+}
+
+// Ranges returns the covered addresses as ranges in ascending order.
+func (c *Coverage) Ranges(k CoverKind) []AddrRange {
+	var list []AddrRange
+	for a := 0; a <= 0xFFFF; a++ {
+		if !c.Has(k, uint16(a)) {
+			continue
+		}
+		if n := len(list); n > 0 && int(list[n-1].End) == a-1 {
+			list[n-1].End = uint16(a)
+			continue
+		}
+		list = append(list, AddrRange{uint16(a), uint16(a)})
+	}
+	return list
+}
+
+// Report writes the covered ranges of all kinds, one per line:
+//
+//	exec  0200-0214
+//	read  0200-0214
+//	write 01FE-01FF
+func (c *Coverage) Report(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for k := range c.sets {
+		for _, r := range c.Ranges(CoverKind(k)) {
+			fmt.Fprintf(bw, "%-5s %04X-%04X\n", CoverKind(k), r.Start, r.End)
+		}
+	}
+	return bw.Flush()
+}
+
+// String returns the name of the coverage kind.
+func (k CoverKind) String() string {
+	if int(k) < len(coverKinds) {
+		return coverKinds[k]
+	}
+	return "unknown"
+}
+
+func (b coveredBus) Read(l, h byte) byte {
+	b.c.Mark(CoverRead, uint16(h)<<8|uint16(l))
+	return b.bus.Read(l, h)
+}
+
+func (b coveredBus) Write(l, h, db byte) {
+	b.c.Mark(CoverWrite, uint16(h)<<8|uint16(l))
+	b.bus.Write(l, h, db)
+}
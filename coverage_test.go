@@ -0,0 +1,54 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCoverage(t *testing.T) {
+	bus := &memoryBus{}
+	copy(bus.mem[0x0200:], []byte{
+		0xA9, 0x01, //       LDA #$01
+		0xD0, 0x01, //       BNE skip
+		0xEA,       //       NOP (not reached)
+		0x85, 0x10, // skip: STA $10
+	})
+	cov := NewCoverage()
+	cpu := New(cov.Bus(bus))
+	cpu.PC(0x00, 0x02)
+	detach := cov.Attach(cpu)
+
+	for i := 0; i < 3; i++ {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []AddrRange{{0x0200, 0x0203}, {0x0205, 0x0206}}
+	if r := cov.Ranges(CoverExec); len(r) != 2 || r[0] != want[0] || r[1] != want[1] {
+		t.Fatalf("unexpected, got %v", r)
+	}
+	if !cov.Has(CoverWrite, 0x0010) || cov.Has(CoverExec, 0x0204) || cov.Count(CoverExec) != 6 {
+		t.Fatal("unexpected")
+	}
+	if bm := cov.Bitmap(CoverExec); bm[0x0200/8] != 0b01101111 {
+		t.Fatalf("unexpected, got %08b", bm[0x0200/8])
+	}
+
+	sb := &strings.Builder{}
+	if err := cov.Report(sb); err != nil {
+		t.Fatal(err)
+	}
+	report := sb.String()
+	if !strings.HasPrefix(report, "exec  0200-0203\nexec  0205-0206\nread  ") ||
+		!strings.HasSuffix(report, "write 0010-0010\n") {
+		t.Fatalf("unexpected, got\n%s", sb)
+	}
+
+	detach()
+	cpu.PC(0x04, 0x02)
+	if _, err := cpu.Step(); err != nil || cov.Has(CoverExec, 0x0204) {
+		t.Fatal("unexpected, still attached")
+	}
+}
@@ -4,8 +4,8 @@
 package m6502
 
 import (
-	"errors"
 	"fmt"
+	"math/rand"
 )
 
 type (
@@ -26,20 +26,98 @@ type (
 	// CPU represents the 6502 emulator.
 	CPU struct {
 		bus Bus
+		mem *[0x10000]byte // Backing memory of a MemSlicer bus
 
-		a byte  // Accumulator
-		x byte  // X register
-		y byte  // Y register
-		s byte  // Stack pointer
-		p *flag // Processor flags
+		a byte // Accumulator
+		x byte // X register
+		y byte // Y register
+		s byte // Stack pointer
+		p flag // Processor flags
 
 		pcl byte // Program counter low
 		pch byte // Program counter high
+		sph byte // Stack page, 0x01 unless on the 65CE02 or HuC6280
+		ce  ce02 // Registers of the 65CE02
+		hu  huc  // Registers of the HuC6280
+
+		decimal DecimalMode // Instructions honoring the D flag
+		irq     bool        // IRQ line asserted
+		nmi     bool        // NMI line asserted
+		nmiEdge bool        // NMI falling edge latched
+		drive   drive       // Sources driving the IRQ and NMI line
+		so      bool        // SO pin asserted
+		lines   lines       // Interrupt line timing, see AccuracyCycle
+		busy    bool        // Instruction in progress
+		acc     Accuracy    // Accuracy of the emulation
+		hijack  bool        // NMI may hijack the BRK sequence
+		calls   callStack   // Tracked calls, see SetStrictReturns()
+		variant Variant     // Processor model
+		quirks  Quirks      // Behavior of the model, see SetQuirks()
+		vecs    Vectors     // Vector addresses, see SetVectors()
+		magic   byte        // Constant of ANE and LXA
+		stable  bool        // SHA, SHX, SHY and TAS ignore page crossing
+
+		diagnostics func(Diagnostic)
+		stackWrap   StackWrap
+		reset       ResetMode // Behavior of Reset()
+		stack       StackReset
+		stackInit   byte   // S loaded by StackLoad
+		hooks       hooks  // Per-instruction hooks
+		untrace     func() // Removes the hook of SetTracer()
+		micro       micro  // Micro-operations of the last Step()
+		events      func(Event)
+		resolve     func(Resolved)
+		capture     func(BusCycle)
+		traps       *[0x100]TrapHandler
+		ext         *[0x100]func(cpu *CPU)
+		addrTraps   map[uint16]TrapHandler
+		observers   observers
+		phased      PhaseBus  // Two-phase bus, see NewPhased()
+		forbidden   OpcodeSet // Op codes rejected by the policy
+		waiting     bool      // WAI performed, waiting for interrupt
+		async       Lines     // Lines driven by other goroutines
+		name        string    // Name of the CPU, see SetName()
+		layout      Layout    // Layout of String()
+		requests    requests  // Request port and op code
+		sentinel    sentinel  // Address returning control to the host
+		breakpoints breakpoints
+		vectors     VectorWatch
+		powered     bool       // Reset() performed at least once
+		power       PowerOn    // State of the first Reset()
+		rnd         *rand.Rand // Source of PowerOnRandom
+		fallible    bool       // Bus reports failures, see NewFallible()
+
+		// State of the instruction in progress, see tick()
+		at     uint16    // Address of the instruction
+		op     byte      // Op code of the instruction
+		addr   uint16    // Address of the current Bus access, see fault()
+		wr     bool      // Current Bus access is a write, see fault()
+		kind   MicroKind // Kind of the next bus access, when not plain
+		watch  bool      // Bus accesses are observed, see track()
+		pollAt uint      // Cycle of the interrupt polling, when not default
+		stop   error     // Breaks execution after the instruction
+		fail   error     // Ends the instruction immediately
+		eff    Access    // Effective access, see SetResolvedTracer()
+		effOK  bool      // Effective access performed
 
 		cycles uint
-		error  error
+		total  uint64 // Cycles since Reset(), incl. the reset sequence
+		error  error  // Halt state, see Halted()
+		irqs   uint64 // IRQ sequences since Reset()
+		nmis   uint64 // NMI sequences since Reset()
+		last   error  // Returned by the last Step()
 	}
 
+	// ResetMode selects the behavior of Reset().
+	ResetMode byte
+
+	// StackReset selects how Reset() initializes the stack pointer S.
+	StackReset byte
+
+	// DecimalMode selects the instructions that honor the D flag. Some
+	// clone chips and FPGA cores implement decimal mode only partially.
+	DecimalMode byte
+
 	flag byte
 )
 
@@ -54,16 +132,65 @@ const (
 	flagC flag = 1 << 0 // C | Set if overflow in bit 7
 )
 
+const (
+	DecimalNone DecimalMode = 0                       // ADC and SBC ignore the D flag
+	DecimalADC  DecimalMode = 1 << 0                  // ADC honors the D flag
+	DecimalSBC  DecimalMode = 1 << 1                  // SBC honors the D flag
+	DecimalAll              = DecimalADC | DecimalSBC // Default, both honor the D flag
+)
+
+const (
+	// ResetLegacy clears A, X, Y and the processor flags and sets S to 0xFF.
+	ResetLegacy ResetMode = iota
+
+	// ResetAccurate behaves like real silicon: A, X and Y are left unchanged,
+	// S is decremented by 3 (the suppressed pushes), the I flag is set.
+	ResetAccurate
+)
+
+const (
+	// StackByMode follows the ResetMode, i.e. ResetLegacy sets S to 0xFF
+	// and ResetAccurate decrements S.
+	StackByMode StackReset = iota
+
+	// StackLoad sets S to the initial value given to SetStackReset().
+	StackLoad
+
+	// StackDecrement decrements S by 3 like real silicon, regardless of
+	// the ResetMode. Software may fingerprint the environment this way.
+	StackDecrement
+)
+
 var (
-	// ErrHalted will be returned from Step() when CPU was halted.
+	// ErrHalted will be returned from Step(), wrapped in a *HaltError,
+	// when CPU was halted.
 	ErrHalted = fmt.Errorf("CPU halted")
+
+	// ErrStopped will be returned from Step(), wrapped in a *HaltError,
+	// when CPU was stopped by STP.
+	ErrStopped = fmt.Errorf("CPU stopped")
 )
 
 // New creates a new 6502 CPU. This method will panic when the Bus does not have access
 // to the Reset Vector memory (0xFFFC/FD): When the CPU is created, the program counter
-// will be set to the Reset Vector values found at 0xFFFC and 0xFFFD.
-func New(bus Bus) *CPU {
-	cpu := &CPU{bus: bus}
+// will be set to the Reset Vector values found at 0xFFFC and 0xFFFD. The options are
+// applied before, e.g. New(bus, WithVariant(CMOS65C02)) or WithVectors() to read
+// the Reset Vector elsewhere.
+func New(bus Bus, opts ...Option) *CPU {
+	cpu := &CPU{
+		bus: bus, mem: flat(bus),
+		decimal: DecimalAll, magic: DefaultMagic,
+		quirks: NMOS6502.Quirks(), vecs: NMOS6502.Vectors(),
+	}
+	if m, ok := bus.(*Mapper); ok {
+		m.cpu = cpu
+	}
+	if m, ok := bus.(*MMU); ok {
+		m.cpu = cpu
+	}
+	for _, opt := range opts {
+		opt(cpu)
+	}
 	cpu.Reset()
 	return cpu
 }
@@ -83,753 +210,387 @@ func (cpu *CPU) PCH() byte {
 	return cpu.pch
 }
 
-// NMI processes a non-maskable interrupt.
-func (cpu *CPU) NMI() {
-	cpu.interrupt(
-		cpu.bus.Read(0xFA, 0xFF),
-		cpu.bus.Read(0xFB, 0xFF),
-	)
-}
-
-// IRQ processes an interrupt request.
-func (cpu *CPU) IRQ() {
-	if !cpu.p.has(flagI) {
-		cpu.interrupt(
-			cpu.bus.Read(0xFE, 0xFF),
-			cpu.bus.Read(0xFF, 0xFF),
-		)
-	}
+// SetDecimalMode controls whether ADC, SBC or both honor the D flag.
+// Instructions excluded from the mode operate in binary while the
+// D flag still can be set and cleared. Defaults to DecimalAll. With the
+// NoDecimal quirk, e.g. of the Ricoh2A03, both operate in binary regardless.
+func (cpu *CPU) SetDecimalMode(m DecimalMode) {
+	cpu.decimal = m
 }
 
-func (cpu *CPU) interrupt(l, h byte) {
-	cpu.bus.Write(cpu.s, 0x01, cpu.pch)
-	cpu.s--
-	cpu.bus.Write(cpu.s, 0x01, cpu.pcl)
-	cpu.s--
-	cpu.bus.Write(cpu.s, 0x01, byte(*cpu.p|flagU))
-	cpu.s--
-	cpu.pcl, cpu.pch = l, h
-	*cpu.p |= flagI
+// NMI processes a non-maskable interrupt. It returns the number of cycles,
+// that the interrupt sequence takes on the original processor. A panic on
+// the underlying bus is recovered and returned as error, like by Step(),
+// a failure of a FallibleBus is returned as *BusError. A halted CPU does
+// not service it, the *HaltError is returned instead.
+// Consider AssertNMI() instead, where Step() services the interrupt.
+func (cpu *CPU) NMI() (cycles uint, err error) {
+	return cpu.service(0xFA)
 }
 
-// Reset resets the CPU to initial state. The program counter
-// is set to value of the default Reset Vector (0xFFFC/FD).
-func (cpu *CPU) Reset() {
-	cpu.s, cpu.a, cpu.x, cpu.y = 0xFF, 0x00, 0x00, 0x00
-	cpu.pcl = cpu.bus.Read(0xFC, 0xFF)
-	cpu.pch = cpu.bus.Read(0xFD, 0xFF)
-	flg := flag(0)
-	cpu.p = &flg
-	cpu.cycles = 0
-	cpu.error = nil
+// IRQ processes an interrupt request. It returns the number of cycles, that
+// the interrupt sequence takes on the original processor, or 0 when the
+// interrupt is masked by the I flag. Errors are handled like by NMI().
+// Consider AssertIRQ() instead, where Step() services the interrupt.
+func (cpu *CPU) IRQ() (cycles uint, err error) {
+	if cpu.p.has(flagI) {
+		return 0, nil
+	}
+	return cpu.service(0xFE)
 }
 
-// Step performs *one* instruction and returns the number of cycles, that the original
-// processor would have needed. Use this value to control the time penalty regime.
-// A panic on the underlying bus read/write will be recovered and converted to an error.
-// When the CPU is halted by an instruction, this function will immediately return
-// an ErrHalted error until a Reset().
-func (cpu *CPU) Step() (cycles uint, err error) {
+// service performs the interrupt sequence outside of Step().
+func (cpu *CPU) service(v byte) (cycles uint, err error) {
 	if cpu.error != nil {
-		return 0, cpu.error
+		return 0, cpu.named(cpu.error)
 	}
+	pc := cpu.pc()
 	defer func() {
 		if r := recover(); r != nil {
-			err = errors.New(r.(string))
+			cycles, err = 0, cpu.named(cpu.fault(pc, r))
 		}
 	}()
-	if err = cpu.tick(); err != nil {
-		return 0, err
+	cpu.op, cpu.stop = 0x00, nil
+	cpu.micro.ops = cpu.micro.ops[:0]
+	if err = cpu.enter(v); err != nil {
+		return 0, cpu.named(err)
 	}
-	return cpu.cycles, err
+	cpu.total += 7
+	if cpu.stop != nil {
+		return 0, cpu.named(cpu.stop)
+	}
+	return 7, nil
 }
 
-func (cpu *CPU) String() string {
-	return fmt.Sprintf(
-		"m6502: PC=%02X%02X A=%02X X=%02X Y=%02X [%s] S=%02X",
-		cpu.PCH(), cpu.PCL(), cpu.a, cpu.x, cpu.y, cpu.p, cpu.s,
-	)
+// fault converts a recovered bus panic into an error, pc
+// is the address of the instruction or interrupted address.
+func (cpu *CPU) fault(pc uint16, r any) error {
+	return &PanicError{PC: pc, Opcode: cpu.op, Addr: cpu.addr, Write: cpu.wr, Value: r}
 }
 
-func (cpu *CPU) tick() error {
-	cpu.cycles = 0
-	pcl, pch := cpu.pcl, cpu.pch
+// interrupt pushes the return address and the status, then continues
+// at the vector 0xFF<v>, as relocated by the variant or SetVectors().
+// enter performs the interrupt sequence of the vector 0xFF<v>. A failed
+// access of a FallibleBus aborts the sequence, see abort().
+func (cpu *CPU) enter(v byte) error {
+	if !cpu.fallible {
+		cpu.interrupt(v)
+		return nil
+	}
+	r := cpu.regs()
+	cpu.at, cpu.fail = cpu.pc(), nil
+	if cpu.interrupt(v); cpu.fail != nil {
+		cpu.abort(r)
+		return cpu.fail
+	}
+	return nil
+}
 
-	type B = byte
-	type C = bool // Read: "condition"
-	type F = flag
+// abort restores the registers after a failed bus access.
+func (cpu *CPU) abort(r regs) {
+	cpu.a, cpu.x, cpu.y, cpu.s = r.a, r.x, r.y, r.s
+	cpu.p = flag(r.p) &^ flagU
+	cpu.setPC(byte(r.pc), byte(r.pc>>8))
+}
 
-	when := func(d C, t, g B) B {
-		if d {
-			return t
+func (cpu *CPU) interrupt(v byte) {
+	for _, b := range [...]byte{cpu.pch, cpu.pcl, byte(cpu.p | flagU)} {
+		if cpu.s == 0x00 && !cpu.ce.wide && cpu.stackWrap != StackWrapIgnore {
+			cpu.wrapped(cpu.pc(), true)
 		}
-		return g
+		addr := uint16(cpu.sph)<<8 | uint16(cpu.s)
+		cpu.addr, cpu.wr = addr, true
+		cpu.bus.Write(cpu.s, cpu.sph, b)
+		cpu.record(MicroPush, addr, b)
+		cpu.observe(addr, b, true)
+		cpu.down()
+	}
+	w := cpu.vector(v)
+	cpu.micro.nmi = v == 0xFA
+	cpu.addr, cpu.wr = w, false
+	l := cpu.bus.Read(byte(w), byte(w>>8))
+	cpu.record(MicroVector, w, l)
+	cpu.observe(w, l, false)
+	cpu.addr = w + 1
+	h := cpu.bus.Read(byte(w+1), byte((w+1)>>8))
+	cpu.record(MicroVector, w+1, h)
+	cpu.observe(w+1, h, false)
+
+	if cpu.calls.on {
+		cpu.calls.call(Frame{Interrupt: true, From: cpu.pc(), To: uint16(h)<<8 | uint16(l), S: cpu.s})
+	}
+	cpu.pcl, cpu.pch = l, h
+	cpu.p |= flagI
+	if cpu.quirks.ClearD {
+		cpu.p &^= flagD
 	}
-	cost := func(n B) { cpu.cycles += uint(n) }
-
-	uadd := func(a, b B) (B, B) { s := a + b; return s, when(s < b, 0x01, 0x00) }
-	ovfl := func(s int16) B { return when(s>>8 > 0x00, 0x01, when(s < 0, 0xFF, 0x00)) }
-	sadd := func(a B, b int8) (B, B) { s := int16(a) + int16(b); return B(s), ovfl(s) }
-	inc := func(l, h B) (B, B) { l, c := uadd(l, 0x01); return l, h + c }
 
-	setPC := func(l, h B) { cpu.pcl, cpu.pch = l, h }
-	incPC := func() { setPC(inc(cpu.pcl, cpu.pch)) }
+	if v == 0xFA {
+		cpu.nmis++
+	} else {
+		cpu.irqs++
+	}
+}
 
-	read := func(l, h B) B { cost(1); return cpu.bus.Read(l, h) }
-	zread := func(l B) B { return read(l, 0x00) }
-	vread := func(l B) (B, B) { return read(l, 0xFF), read(l+1, 0xFF) }
-	write := func(l, h, b B) { cost(1); cpu.bus.Write(l, h, b) }
-	zwrite := func(l, b B) { write(l, 0x00, b) }
-	fetch := func() B { b := read(cpu.pcl, cpu.pch); incPC(); return b }
+// SetResetMode selects the behavior of subsequent Reset() calls.
+// Defaults to ResetLegacy.
+func (cpu *CPU) SetResetMode(m ResetMode) {
+	cpu.reset = m
+}
 
-	setF := func(c C, f F) { cpu.p.set(c, f) }
-	hasF := func(f F) C { return cpu.p.has(f) }
+// SetStackReset selects how subsequent Reset() calls initialize S and
+// sets S to init, e.g. to 0x00 to emulate the power-on state, where the
+// first reset leaves S at 0xFD. Defaults to StackByMode.
+func (cpu *CPU) SetStackReset(r StackReset, init byte) {
+	cpu.stack, cpu.stackInit, cpu.s = r, init, init
+}
 
-	setC := func(c C) { setF(c, flagC) }
-	setI := func(c C) { setF(c, flagI) }
-	setN := func(b B) { setF(b&0x80 != 0x00, flagN) }
-	setNZ := func(b B) B { setN(b); setF(b == 0x00, flagZ); return b }
+// Reset resets the CPU to initial state. The program counter is set to value of
+// the Reset Vector (0xFFFC/FD by default, see SetVectors()). The register state depends on the ResetMode,
+// on the first Reset() after New() or PowerCycle() on the PowerOn state.
+// Reset returns the number of cycles the reset sequence takes on the original processor.
+// On a PhaseBus, the cycles before the vector fetch are signaled as Internal().
+func (cpu *CPU) Reset() (cycles uint) {
+	s := cpu.s
+	switch {
+	case !cpu.powered && cpu.power != PowerOnZero:
+		s = cpu.powerOn()
+	case cpu.reset == ResetAccurate && cpu.powered:
+		s -= 3
+		cpu.p |= flagI
+	default:
+		s, cpu.a, cpu.x, cpu.y = 0xFF, 0x00, 0x00, 0x00
+		cpu.p = 0
+	}
+	cpu.powered = true
+	switch cpu.stack {
+	case StackLoad:
+		s = cpu.stackInit
+	case StackDecrement:
+		s = cpu.s - 3
+	}
+	cpu.s = s
+	cpu.pages()
+	cpu.hu.mpr[7], cpu.hu.t, cpu.hu.fast = 0x00, false, false
+	cpu.fail = nil
+	if cpu.phased != nil {
+		for i := 0; i < 5; i++ { // Cycles before the vector fetch
+			cpu.phased.Internal()
+		}
+	}
+	v := cpu.vecs.Reset
+	cpu.pcl = cpu.bus.Read(byte(v), byte(v>>8))
+	cpu.pch = cpu.bus.Read(byte(v+1), byte((v+1)>>8))
+	cpu.cycles = 0
+	cpu.total = 7
+	cpu.irqs, cpu.nmis = 0, 0
+	cpu.error, cpu.last = nil, nil
+	cpu.drive.irq, cpu.drive.nmi, cpu.nmiEdge = false, false, false
+	cpu.irq, cpu.nmi = cpu.drive.irqAsync, cpu.drive.nmiAsync
+	cpu.waiting = false
+	cpu.lines = lines{}
+	cpu.calls.frames = nil
+	return 7
+}
 
-	setA := func(b B) { cpu.a = setNZ(b) }
-	setX := func(b B) { cpu.x = setNZ(b) }
-	setY := func(b B) { cpu.y = setNZ(b) }
+// Resume clears the halted state caused by HLT or STP, leaving registers and
+// the program counter unchanged, unlike Reset(). The program counter points
+// past the halting op code, a debugger may patch it before stepping on.
+func (cpu *CPU) Resume() {
+	cpu.error = nil
+}
 
-	push := func(b B) { write(cpu.s, 0x01, b); cpu.s-- }
-	pop := func() B { cpu.s++; return read(cpu.s, 0x01) }
+// Halted reports whether the CPU has been halted by HLT or STP. Step()
+// returns a *HaltError until Reset() or Resume().
+func (cpu *CPU) Halted() bool {
+	return cpu.error != nil
+}
 
-	pushPC := func() { push(cpu.pch); push(cpu.pcl) }
-	popPC := func() (B, B) { return pop(), pop() }
+// LastError returns the error returned by the last Step(), nil when it
+// succeeded or Step() has not been called since Reset().
+func (cpu *CPU) LastError() error {
+	return cpu.last
+}
 
-	php := func() { push(B(*cpu.p | flagU | flagB)) }
-	plp := func() { *cpu.p = F(pop()) & ^(flagU | flagB) }
+// TotalCycles returns the cycles performed since Reset(), incl. its own 7
+// and the interrupts serviced, a monotonic timebase for peripherals.
+func (cpu *CPU) TotalCycles() uint64 {
+	return cpu.total
+}
 
-	cmp := func(a, b B) { setNZ(b - a); setC(b >= a) }
-	bit := func(b B) { setN(b); setF(b&cpu.a == 0, flagZ); setF(b&0x40 != 0, flagV) }
+// SetTotalCycles sets the counter of TotalCycles(), e.g. 0 to start over.
+func (cpu *CPU) SetTotalCycles(n uint64) {
+	cpu.total = n
+}
 
-	asl := func(b B) B { setC(b&0x80 != 0); return setNZ(b << 1) }
-	lsr := func(b B) B { setC(b&0x01 != 0); return setNZ(b >> 1) }
-	rol := func(b B) B { c := B(*cpu.p & flagC); setC(b&0x80 != 0); return setNZ(b<<1 | c) }
-	ror := func(b B) B { c := B(*cpu.p & flagC); setC(b&0x01 != 0); return setNZ(b>>1 | c<<7) }
+// Step performs *one* instruction and returns the number of cycles, that the original
+// processor would have needed. Use this value to control the time penalty regime.
+// A panic on the underlying bus read/write will be recovered and returned as *PanicError,
+// a failed access to a FallibleBus is returned as *BusError.
+// When the CPU is halted by an instruction, this function will immediately return
+// an ErrHalted error until a Reset() or Resume(), respectively ErrStopped after STP. After WAI,
+// Step idles for one cycle per call until an interrupt line is asserted, see Waiting().
+// When an NMI edge has been latched, or when the IRQ line is asserted and the I flag
+// is clear, Step services the interrupt instead of performing an instruction. NMI
+// takes precedence over IRQ. See also Accuracy. At the sentinel address, Step
+// returns a *SentinelError, see SetSentinel(), at a breakpoint a *BreakpointError,
+// see SetBreakpoint().
+func (cpu *CPU) Step() (cycles uint, err error) {
+	pc := cpu.pc()
+	defer func() {
+		cpu.busy = false
+		if r := recover(); r != nil {
+			cycles, err = 0, cpu.fault(pc, r)
+		}
+		err = cpu.named(err)
+		cpu.total += uint64(cycles)
+		cpu.last = err
+	}()
+	if cpu.error != nil {
+		return 0, cpu.error
+	}
+	if cpu.micro.on {
+		cpu.micro.ops = cpu.micro.ops[:0]
+	}
+	before := regs{}
+	if cpu.events != nil {
+		before = cpu.regs()
+	}
 
-	abs := func() (B, B) { return fetch(), fetch() }
-	absN := func(n B) (B, B, B) { l, c := uadd(fetch(), n); return l, fetch() + c, c }
-	relN := func(n B) (B, B, B) { l, o := sadd(cpu.pcl, int8(n)); return l, cpu.pch + o, o }
+	if cpu.async.dirty.Load() {
+		cpu.sample()
+	}
+	if cpu.sentinel.on && pc == cpu.sentinel.addr {
+		return 0, &SentinelError{PC: pc, S: cpu.s}
+	}
 
-	indY := func() (B, B, B) { b := fetch(); l, c := uadd(zread(b), cpu.y); return l, zread(b+1) + c, c }
-	indX := func() (B, B) { b := fetch() + cpu.x; return zread(b), zread(b + 1) }
+	if cpu.waiting && !cpu.wake() {
+		if cpu.phased != nil {
+			cpu.phased.Internal()
+		}
+		cpu.cycles = 1
+		return cpu.cycles, nil
+	}
 
-	adc := func(b B) B {
-		if cpu.p.has(flagD) {
-			l := cpu.a&0x0F + b&0x0F + when(hasF(flagC), 0x01, 0x00)
-			l += when(l&0xFF > 9, 6, 0)
-			h := cpu.a>>4 + b>>4 + when(l > 0x0F, 1, 0)
-			h += when(h&0xFF > 9, 6, 0)
-			setC(h > 0x0F)
-			return l&0x0F | (h<<4)&0xF0
+	nmi, irq := cpu.nmiEdge, cpu.irq && !cpu.p.has(flagI)
+	if cpu.acc == AccuracyCycle {
+		nmi, irq = cpu.lines.nmiPoll, cpu.lines.irqPoll
+		cpu.lines = lines{}
+	}
+	if nmi || irq {
+		v := byte(0xFE)
+		if nmi {
+			cpu.nmiEdge, v = false, 0xFA
+		}
+		cpu.idle()
+		cpu.idle()
+		cpu.stop = nil
+		if err = cpu.enter(v); err != nil {
+			return 0, err
 		}
-		w := uint16(cpu.a) + uint16(b) + uint16(when(hasF(flagC), 0x01, 0x00))
-		r := B(w)
-		setC(w > 0xFF)
-		setF((cpu.a^r)&(b^r)&0x80 != 0x00, flagV)
-		return r
-	}
-	sbc := func(b B) B {
-		if cpu.p.has(flagD) {
-			l := (cpu.a & 0x0F) - (b & 0x0F) - when(hasF(flagC), 0x00, 0x01)
-			l -= when(l&0x10 != 0, 6, 0)
-			h := (cpu.a >> 4) - (b >> 4) - when((l&0x10) != 0, 1, 0)
-			h -= when(h&0x10 != 0, 6, 0)
-			setC(h&0xFF < 0x0F)
-			return l&0x0F | h<<4
+		cpu.cycles = 7
+		if cpu.events != nil {
+			cpu.emit(before)
 		}
-		return adc(^b)
+		if cpu.stop != nil {
+			return 0, cpu.stop
+		}
+		return cpu.cycles, nil
 	}
-	branch := func(c C) {
-		if b := fetch(); c {
-			l, h, o := relN(b)
-			cost(1 + when(o == 0, 0, 1))
-			setPC(l, h)
+	if len(cpu.breakpoints.list) > 0 {
+		if err = cpu.breakpoint(pc); err != nil {
+			return 0, err
 		}
 	}
+	cpu.busy = true
+	if err = cpu.tick(); err != nil {
+		return 0, err
+	}
+	if cpu.events != nil {
+		cpu.emit(before)
+	}
+	return cpu.cycles, err
+}
 
-	// ---
-
-	//  * add 1 to cycles if page boundary is crossed
-	// ** add 1 to cycles if branch occurs on same page
-	// ** add 2 to cycles if branch occurs to different page
-	//
-	//   Op     | Mnemonic     |  Addressing  |  Processor Flags  | Cycles
-	//
-	switch fetch() /* cost 1 */ {
-	case 0x00: /* BRK          |   implied    | N- Z- C- I+ D- V- | 7 */
-		fetch()
-		pushPC()
-		php()
-		setPC(vread(0xFE))
-		setI(true)
-	case 0x20: /* JSR oper     |   absolute   | N- Z- C- I- D- V- | 6  */
-		l := fetch()
-		pushPC()
-		setPC(l, fetch())
-		cost(1)
-	case 0x40: /* RTI          |   implied    |    from stack     | 7 */
-		plp()
-		setPC(popPC())
-		cost(3)
-	case 0x60: /* RTS          |   implied    | N- Z- C- I- D- V- | 6 */
-		setPC(inc(popPC()))
-		cost(3)
-	case 0x80: /* NOP          |  immediate   | N- Z- C- I- D- V- | 2 */
-		cost(1)
-	case 0xA0: /* LDY #oper    |  immediate   | N+ Z+ C- I- D- V- | 2 */
-		setY(fetch())
-	case 0xC0: /* CPY #oper    |  immediate   | N+ Z+ C+ I- D- V- | 2 */
-		cmp(fetch(), cpu.y)
-	case 0xE0: /* CPX #oper    |  immediate   | N+ Z+ C+ I- D- V- | 2 */
-		cmp(fetch(), cpu.x)
-
-	case 0x01: /* ORA (oper,X) | (indirect,X) | N+ Z+ C- I- D- V- | 6 */
-		setA(cpu.a | read(indX()))
-		cost(1)
-	case 0x21: /* AND (oper,X) | (indirect,X) | N+ Z+ C- I- D- V- | 6 */
-		setA(cpu.a & read(indX()))
-		cost(1)
-	case 0x41: /* EOR (oper,X) | (indirect,X) | N+ Z+ C- I- D- V- | 6 */
-		setA(cpu.a ^ read(indX()))
-		cost(1)
-	case 0x61: /* ADC (oper,X) | (indirect,X) | N+ Z+ C+ I- D- V+ | 6 */
-		setA(adc(read(indX())))
-		cost(1)
-	case 0x81: /* STA (oper,X) | (indirect,X) | N- Z- C- I- D- V- | 6 */
-		l, h := indX()
-		write(l, h, cpu.a)
-		cost(1)
-	case 0xA1: /* LDA (oper,X) | (indirect,X) | N+ Z+ C- I- D- V- | 6 */
-		setA(read(indX()))
-		cost(1)
-	case 0xC1: /* CMP (oper,X) | (indirect,X) | N+ Z+ C+ I- D- V- | 6 */
-		cmp(read(indX()), cpu.a)
-		cost(1)
-	case 0xE1: /* SBC (oper,X) | (indirect,X) | N+ Z+ C+ I- D- V+ | 6 */
-		setA(sbc(read(indX())))
-		cost(1)
-
-	case 0x02: /* HLT          |              |                   | 1 */
-		cpu.error = ErrHalted
-	case 0x22: /* HLT          |              |                   | 1 */
-		cpu.error = ErrHalted
-	case 0x42: /* HLT          |              |                   | 1 */
-		cpu.error = ErrHalted
-	case 0x62: /* HLT          |              |                   | 1 */
-		cpu.error = ErrHalted
-	case 0x82: /* NOP          |  immediate   | N- Z- C- I- D- V- | 2 */
-		cost(1)
-	case 0xA2: /* LDX #oper    |  immediate   | N+ Z+ C- I- D- V- | 2 */
-		setX(fetch())
-	case 0xC2: /* NOP          |  immediate   | N- Z- C- I- D- V- | 2 */
-		cost(1)
-	case 0xE2: /* NOP          |  immediate   | N- Z- C- I- D- V- | 2 */
-		cost(1)
-
-	case 0x04: /* NOP          |   zeropage   | N- Z- C- I- D- V- | 3 */
-		cost(2)
-	case 0x24: /* BIT oper     |   zeropage   | N+ Z+ C- I- D- V+ | 3 */
-		bit(zread(fetch()))
-	case 0x44: /* NOP          |   zeropage   | N- Z- C- I- D- V- | 3 */
-		cost(2)
-	case 0x64: /* NOP          |   zeropage   | N- Z- C- I- D- V- | 3 */
-		cost(2)
-	case 0x84: /* STY oper     |   zeropage   | N- Z- C- I- D- V- | 3 */
-		zwrite(fetch(), cpu.y)
-	case 0xA4: /* LDY oper     |   zeropage   | N+ Z+ C- I- D- V- | 3 */
-		setY(zread(fetch()))
-	case 0xC4: /* CPY oper     |   zeropage   | N+ Z+ C+ I- D- V- | 3 */
-		cmp(zread(fetch()), cpu.y)
-	case 0xE4: /* CPX oper     |   zeropage   | N+ Z+ C+ I- D- V- | 3 */
-		cmp(zread(fetch()), cpu.x)
-
-	case 0x05: /* ORA oper     |   zeropage   | N+ Z+ C- I- D- V- | 3 */
-		setA(cpu.a | zread(fetch()))
-	case 0x25: /* AND oper     |   zeropage   | N+ Z+ C- I- D- V- | 3 */
-		setA(cpu.a & zread(fetch()))
-	case 0x45: /* EOR oper     |   zeropage   | N+ Z+ C- I- D- V- | 3 */
-		setA(cpu.a ^ zread(fetch()))
-	case 0x65: /* ADC oper     |   zeropage   | N+ Z+ C+ I- D- V+ | 3 */
-		setA(adc(zread(fetch())))
-	case 0x85: /* STA oper     |   zeropage   | N- Z- C- I- D- V- | 3 */
-		zwrite(fetch(), cpu.a)
-	case 0xA5: /* LDA oper     |   zeropage   | N+ Z+ C- I- D- V- | 3 */
-		setA(zread(fetch()))
-	case 0xC5: /* CMP oper     |   zeropage   | N+ Z+ C+ I- D- V- | 3 */
-		cmp(zread(fetch()), cpu.a)
-	case 0xE5: /* SBC oper     |   zeropage   | N+ Z+ C+ I- D- V+ | 3 */
-		setA(sbc(zread(fetch())))
-
-	case 0x06: /* ASL oper     |   zeropage   | N+ Z+ C+ I- D- V- | 5 */
-		b := fetch()
-		zwrite(b, asl(zread(b)))
-		cost(1)
-	case 0x26: /* ROL oper     |   zeropage   | N+ Z+ C+ I- D- V- | 5 */
-		b := fetch()
-		zwrite(b, rol(zread(b)))
-		cost(1)
-	case 0x46: /* LSR oper     |   zeropage   | N0 Z+ C+ I- D- V- | 5 */
-		b := fetch()
-		zwrite(b, lsr(zread(b)))
-		cost(1)
-	case 0x66: /* ROR oper     |   zeropage   | N+ Z+ C+ I- D- V- | 5 */
-		b := fetch()
-		zwrite(b, ror(zread(b)))
-		cost(1)
-	case 0x86: /* STX oper     |   zeropage   | N- Z- C- I- D- V- | 3 */
-		zwrite(fetch(), cpu.x)
-	case 0xA6: /* LDX oper     |   zeropage   | N+ Z+ C- I- D- V- | 3 */
-		setX(zread(fetch()))
-	case 0xC6: /* DEC oper     |   zeropage   | N+ Z+ C- I- D- V- | 5 */
-		b := fetch()
-		zwrite(b, setNZ(zread(b)-1))
-		cost(1)
-	case 0xE6: /* INC oper     |   zeropage   | N+ Z+ C- I- D- V- | 5 */
-		b := fetch()
-		zwrite(b, setNZ(zread(b)+1))
-		cost(1)
-
-	case 0x08: /* PHP          |   implied    | N- Z- C- I- D- V- | 3 */
-		php()
-		cost(1)
-	case 0x28: /* PLP          |   implied    |    from stack     | 4 */
-		plp()
-		cost(2)
-	case 0x48: /* PHA          |   implied    | N- Z- C- I- D- V- | 3 */
-		push(cpu.a)
-		cost(1)
-	case 0x68: /* PLA          |   implied    | N+ Z+ C- I- D- V- | 4 */
-		setA(pop())
-		cost(2)
-	case 0x88: /* DEY          |   implied    | N+ Z+ C- I- D- V- | 2 */
-		setY(cpu.y - 1)
-		cost(1)
-	case 0xA8: /* TAY          |   implied    | N+ Z+ C- I- D- V- | 2 */
-		setY(cpu.a)
-		cost(1)
-	case 0xC8: /* INY          |   implied    | N+ Z+ C- I- D- V- | 2 */
-		setY(cpu.y + 1)
-		cost(1)
-	case 0xE8: /* INX          |   implied    | N+ Z+ C- I- D- V- | 2 */
-		setX(cpu.x + 1)
-		cost(1)
-
-	case 0x09: /* ORA #oper    |  immediate   | N+ Z+ C- I- D- V- | 2 */
-		setA(cpu.a | fetch())
-	case 0x29: /* AND #oper    |  immediate   | N+ Z+ C- I- D- V- | 2 */
-		setA(cpu.a & fetch())
-	case 0x49: /* EOR #oper    |  immediate   | N+ Z+ C- I- D- V- | 2 */
-		setA(cpu.a ^ fetch())
-	case 0x69: /* ADC #oper    |  immediate   | N+ Z+ C+ I- D- V+ | 2 */
-		setA(adc(fetch()))
-	case 0x89: /* NOP          |  immediate   | N- Z- C- I- D- V- | 2 */
-		cost(1)
-	case 0xA9: /* LDA #oper    |  immediate   | N+ Z+ C- I- D- V- | 2 */
-		setA(fetch())
-	case 0xC9: /* CMP #oper    |  immediate   | N+ Z+ C+ I- D- V- | 2 */
-		cmp(fetch(), cpu.a)
-	case 0xE9: /* SBC #oper    |  immediate   | N+ Z+ C+ I- D- V+ | 2 */
-		setA(sbc(fetch()))
-
-	case 0x0A: /* ASL A        | accumulator  | N+ Z+ C+ I- D- V- | 2 */
-		setA(asl(cpu.a))
-		cost(1)
-	case 0x2A: /* ROL A        | accumulator  | N+ Z+ C+ I- D- V- | 2 */
-		setA(rol(cpu.a))
-		cost(1)
-	case 0x4A: /* LSR A        | accumulator  | N0 Z+ C+ I- D- V- | 2 */
-		setA(lsr(cpu.a))
-		cost(1)
-	case 0x6A: /* ROR A        | accumulator  | N+ Z+ C+ I- D- V- | 2 */
-		setA(ror(cpu.a))
-		cost(1)
-	case 0x8A: /* TXA          |   implied    | N+ Z+ C- I- D- V- | 2 */
-		setA(cpu.x)
-		cost(1)
-	case 0xAA: /* TAX          |   implied    | N+ Z+ C- I- D- V- | 2 */
-		setX(cpu.a)
-		cost(1)
-	case 0xCA: /* DEX          |   implied    | N+ Z+ C- I- D- V- | 2 */
-		setX(cpu.x - 1)
-		cost(1)
-	case 0xEA: /* NOP          |   implied    | N- Z- C- I- D- V- | 2 */
-		cost(1)
-
-	case 0x0C: /* NOP          |   absolute   | N- Z- C- I- D- V- | 4 */
-		cost(3)
-	case 0x2C: /* BIT oper     |   absolute   | N+ Z+ C- I- D- V+ | 4 */
-		bit(read(abs()))
-	case 0x4C: /* JMP oper     |   absolute   | N- Z- C- I- D- V- | 3 */
-		setPC(abs())
-	case 0x6C: /* JMP (oper)   |   indirect   | N- Z- C- I- D- V- | 5 */
-		l, h := abs()
-		lo := read(l, h)
-		setPC(lo, read(l+1, h))
-	case 0x8C: /* STY oper     |   absolute   | N- Z- C- I- D- V- | 4 */
-		write(fetch(), fetch(), cpu.y)
-	case 0xAC: /* LDY oper     |   absolute   | N+ Z+ C- I- D- V- | 4 */
-		setY(read(abs()))
-	case 0xCC: /* CPY oper     |   absolute   | N+ Z+ C+ I- D- V- | 4 */
-		cmp(read(abs()), cpu.y)
-	case 0xEC: /* CPX oper     |   absolute   | N+ Z+ C+ I- D- V- | 4 */
-		cmp(read(abs()), cpu.x)
-
-	case 0x0D: /* ORA oper     |   absolute   | N+ Z+ C- I- D- V- | 4 */
-		setA(cpu.a | read(abs()))
-	case 0x2D: /* AND oper     |   absolute   | N+ Z+ C- I- D- V- | 4 */
-		setA(cpu.a & read(abs()))
-	case 0x4D: /* EOR oper     |   absolute   | N+ Z+ C- I- D- V- | 4 */
-		setA(cpu.a ^ read(abs()))
-	case 0x6D: /* ADC oper     |   absolute   | N+ Z+ C+ I- D- V+ | 4 */
-		setA(adc(read(abs())))
-	case 0x8D: /* STA oper     |   absolute   | N- Z- C- I- D- V- | 4 */
-		write(fetch(), fetch(), cpu.a)
-	case 0xAD: /* LDA oper     |   absolute   | N+ Z+ C- I- D- V- | 4 */
-		setA(read(abs()))
-	case 0xCD: /* CMP oper     |   absolute   | N+ Z+ C+ I- D- V- | 4 */
-		cmp(read(abs()), cpu.a)
-	case 0xED: /* SBC oper     |   absolute   | N+ Z+ C+ I- D- V+ | 4 */
-		setA(sbc(read(abs())))
-
-	case 0x0E: /* ASL oper     |   absolute   | N+ Z+ C+ I- D- V- | 6 */
-		l, h := abs()
-		b := read(l, h)
-		write(l, h, asl(b))
-		cost(1)
-	case 0x2E: /* ROL oper     |   absolute   | N+ Z+ C+ I- D- V- | 6 */
-		l, h := abs()
-		b := read(l, h)
-		write(l, h, rol(b))
-		cost(1)
-	case 0x4E: /* LSR oper     |   absolute   | N0 Z+ C+ I- D- V- | 6 */
-		l, h := abs()
-		b := read(l, h)
-		write(l, h, lsr(b))
-		cost(1)
-	case 0x6E: /* ROR oper     |   absolute   | N+ Z+ C+ I- D- V- | 6 */
-		l, h := abs()
-		b := read(l, h)
-		write(l, h, ror(b))
-		cost(1)
-	case 0x8E: /* STX oper     |   absolute   | N- Z- C- I- D- V- | 4 */
-		write(fetch(), fetch(), cpu.x)
-	case 0xAE: /* LDX oper     |   absolute   | N+ Z+ C- I- D- V- | 4 */
-		setX(read(abs()))
-	case 0xCE: /* DEC oper     |   absolute   | N+ Z+ C- I- D- V- | 6 */
-		l, h := abs()
-		b := read(l, h)
-		write(l, h, setNZ(b-1))
-		cost(1)
-	case 0xEE: /* INC oper     |   absolute   | N+ Z+ C- I- D- V- | 6 */
-		l, h := abs()
-		b := read(l, h)
-		write(l, h, setNZ(b+1))
-		cost(1)
-
-	case 0x10: /* BPL oper     |   relative   | N- Z- C- I- D- V- | 2** */
-		branch(!hasF(flagN))
-	case 0x30: /* BMI oper     |   relative   | N- Z- C- I- D- V- | 2** */
-		branch(hasF(flagN))
-	case 0x50: /* BVC oper     |   relative   | N- Z- C- I- D- V- | 2** */
-		branch(!hasF(flagV))
-	case 0x70: /* BVS oper     |   relative   | N- Z- C- I- D- V- | 2** */
-		branch(hasF(flagV))
-	case 0x90: /* BCC oper     |   relative   | N- Z- C- I- D- V- | 2** */
-		branch(!hasF(flagC))
-	case 0xB0: /* BCS oper     |   relative   | N- Z- C- I- D- V- | 2** */
-		branch(hasF(flagC))
-	case 0xD0: /* BNE oper     |   relative   | N- Z- C- I- D- V- | 2** */
-		branch(!hasF(flagZ))
-	case 0xF0: /* BEQ oper     |   relative   | N- Z- C- I- D- V- | 2** */
-		branch(hasF(flagZ))
-
-	case 0x11: /* ORA (oper),Y | (indirect),Y | N+ Z+ C- I- D- V- | 5* */
-		l, h, c := indY()
-		setA(cpu.a | read(l, h))
-		cost(c)
-	case 0x31: /* AND (oper),Y | (indirect),Y | N+ Z+ C- I- D- V- | 5* */
-		l, h, c := indY()
-		setA(cpu.a & read(l, h))
-		cost(c)
-	case 0x51: /* EOR (oper),Y | (indirect),Y | N+ Z+ C- I- D- V- | 5* */
-		l, h, c := indY()
-		setA(cpu.a ^ read(l, h))
-		cost(c)
-	case 0x71: /* ADC (oper),Y | (indirect),Y | N+ Z+ C+ I- D- V+ | 5* */
-		l, h, c := indY()
-		setA(adc(read(l, h)))
-		cost(c)
-	case 0x91: /* STA (oper),Y | (indirect),Y | N- Z- C- I- D- V- | 6 */
-		l, h, _ := indY()
-		write(l, h, cpu.a)
-		cost(1)
-	case 0xB1: /* LDA (oper),Y | (indirect),Y | N+ Z+ C- I- D- V- | 5* */
-		l, h, c := indY()
-		setA(read(l, h))
-		cost(c)
-	case 0xD1: /* CMP (oper),Y | (indirect),Y | N+ Z+ C+ I- D- V- | 5* */
-		l, h, c := indY()
-		cmp(read(l, h), cpu.a)
-		cost(c)
-	case 0xF1: /* SBC (oper),Y | (indirect),Y | N+ Z+ C+ I- D- V+ | 5* */
-		l, h, c := indY()
-		setA(sbc(read(l, h)))
-		cost(c)
-
-	case 0x12: /* HLT          |              |                   | 1 */
-		cpu.error = ErrHalted
-	case 0x32: /* HLT          |              |                   | 1 */
-		cpu.error = ErrHalted
-	case 0x52: /* HLT          |              |                   | 1 */
-		cpu.error = ErrHalted
-	case 0x72: /* HLT          |              |                   | 1 */
-		cpu.error = ErrHalted
-	case 0x92: /* HLT          |              |                   | 1 */
-		cpu.error = ErrHalted
-	case 0xB2: /* HLT          |              |                   | 1 */
-		cpu.error = ErrHalted
-	case 0xD2: /* HLT          |              |                   | 1 */
-		cpu.error = ErrHalted
-	case 0xF2: /* HLT          |              |                   | 1 */
-		cpu.error = ErrHalted
-
-	case 0x14: /* NOP          |  zeropage,X  | N- Z- C- I- D- V- | 4 */
-		cost(3)
-	case 0x34: /* NOP          |  zeropage,X  | N- Z- C- I- D- V- | 4 */
-		cost(3)
-	case 0x54: /* NOP          |  zeropage,X  | N- Z- C- I- D- V- | 4 */
-		cost(3)
-	case 0x74: /* NOP          |  zeropage,X  | N- Z- C- I- D- V- | 4 */
-		cost(3)
-	case 0x94: /* STY oper,X   |  zeropage,X  | N- Z- C- I- D- V- | 4 */
-		zwrite(fetch()+cpu.x, cpu.y)
-		cost(1)
-	case 0xB4: /* LDY oper,X   |  zeropage,X  | N+ Z+ C- I- D- V- | 4 */
-		setY(zread(fetch() + cpu.x))
-		cost(1)
-	case 0xD4: /* NOP          |  zeropage,X  | N- Z- C- I- D- V- | 4 */
-		cost(3)
-	case 0xF4: /* NOP          |  zeropage,X  | N- Z- C- I- D- V- | 4 */
-		cost(3)
-
-	case 0x15: /* ORA oper,X   |  zeropage,X  | N+ Z+ C- I- D- V- | 4 */
-		setA(cpu.a | zread(fetch()+cpu.x))
-		cost(1)
-	case 0x35: /* AND oper,X   |  zeropage,X  | N+ Z+ C- I- D- V- | 4 */
-		setA(cpu.a & zread(fetch()+cpu.x))
-		cost(1)
-	case 0x55: /* EOR oper,X   |  zeropage,X  | N+ Z+ C- I- D- V- | 4 */
-		setA(cpu.a ^ zread(fetch()+cpu.x))
-		cost(1)
-	case 0x75: /* ADC oper,X   |  zeropage,X  | N+ Z+ C+ I- D- V+ | 4 */
-		setA(adc(zread(fetch() + cpu.x)))
-		cost(1)
-	case 0x95: /* STA oper,X   |  zeropage,X  | N- Z- C- I- D- V- | 4 */
-		zwrite(fetch()+cpu.x, cpu.a)
-		cost(1)
-	case 0xB5: /* LDA oper,X   |  zeropage,X  | N+ Z+ C- I- D- V- | 4 */
-		setA(zread(fetch() + cpu.x))
-		cost(1)
-	case 0xD5: /* CMP oper,X   |  zeropage,X  | N+ Z+ C+ I- D- V- | 4 */
-		cmp(zread(fetch()+cpu.x), cpu.a)
-		cost(1)
-	case 0xF5: /* SBC oper,X   |  zeropage,X  | N+ Z+ C+ I- D- V+ | 4 */
-		setA(sbc(zread(fetch() + cpu.x)))
-		cost(1)
-
-	case 0x16: /* ASL oper,X   |  zeropage,X  | N+ Z+ C+ I- D- V- | 6 */
-		l := fetch() + cpu.x
-		zwrite(l, asl(zread(l)))
-		cost(2)
-	case 0x36: /* ROL oper,X   |  zeropage,X  | N+ Z+ C+ I- D- V- | 6 */
-		l := fetch() + cpu.x
-		zwrite(l, rol(zread(l)))
-		cost(2)
-	case 0x56: /* LSR oper,X   |  zeropage,X  | N0 Z+ C+ I- D- V- | 6 */
-		l := fetch() + cpu.x
-		zwrite(l, lsr(zread(l)))
-		cost(2)
-	case 0x76: /* ROR oper,X   |  zeropage,X  | N+ Z+ C+ I- D- V- | 6 */
-		l := fetch() + cpu.x
-		zwrite(l, ror(zread(l)))
-		cost(2)
-	case 0x96: /* STX oper,Y   |  zeropage,Y  | N- Z- C- I- D- V- | 4 */
-		zwrite(fetch()+cpu.y, cpu.x)
-		cost(1)
-	case 0xB6: /* LDX oper,Y   |  zeropage,Y  | N+ Z+ C- I- D- V- | 4 */
-		setX(zread(fetch() + cpu.y))
-		cost(1)
-	case 0xD6: /* DEC oper,X   |  zeropage,X  | N+ Z+ C- I- D- V- | 6 */
-		l := fetch() + cpu.x
-		zwrite(l, setNZ(zread(l)-1))
-		cost(2)
-	case 0xF6: /* INC oper,X   |  zeropage,X  | N+ Z+ C- I- D- V- | 6 */
-		l := fetch() + cpu.x
-		zwrite(l, setNZ(zread(l)+1))
-		cost(2)
-
-	case 0x18: /* CLC          |   implied    | N- Z- C0 I- D- V- | 2 */
-		setC(false)
-		cost(1)
-	case 0x38: /* SEC          |   implied    | N- Z- C1 I- D- V- | 2 */
-		setC(true)
-		cost(1)
-	case 0x58: /* CLI          |   implied    | N- Z- C- I0 D- V- | 2 */
-		setI(false)
-		cost(1)
-	case 0x78: /* SEI          |   implied    | N- Z- C- I1 D- V- | 2 */
-		setI(true)
-		cost(1)
-	case 0x98: /* TYA          |   implied    | N+ Z+ C- I- D- V- | 2 */
-		setA(cpu.y)
-		cost(1)
-	case 0xB8: /* CLV          |   implied    | N- Z- C- I- D- V0 | 2 */
-		setF(false, flagV)
-		cost(1)
-	case 0xD8: /* CLD          |   implied    | N- Z- C- I- D0 V- | 2 */
-		setF(false, flagD)
-		cost(1)
-	case 0xF8: /* SED          |   implied    | N- Z- C- I- D1 V- | 2 */
-		setF(true, flagD)
-		cost(1)
-
-	case 0x19: /* ORA oper,Y   |  absolute,Y  | N+ Z+ C- I- D- V- | 4* */
-		l, h, c := absN(cpu.y)
-		setA(cpu.a | read(l, h))
-		cost(c)
-	case 0x39: /* AND oper,Y   |  absolute,Y  | N+ Z+ C- I- D- V- | 4* */
-		l, h, c := absN(cpu.y)
-		setA(cpu.a & read(l, h))
-		cost(c)
-	case 0x59: /* EOR oper,Y   |  absolute,Y  | N+ Z+ C- I- D- V- | 4* */
-		l, h, c := absN(cpu.y)
-		setA(cpu.a ^ read(l, h))
-		cost(c)
-	case 0x79: /* ADC oper,Y   |  absolute,Y  | N+ Z+ C+ I- D- V+ | 4* */
-		l, h, c := absN(cpu.y)
-		setA(adc(read(l, h)))
-		cost(c)
-	case 0x99: /* STA oper,Y   |  absolute,Y  | N- Z- C- I- D- V- | 5 */
-		l, h, _ := absN(cpu.y)
-		write(l, h, cpu.a)
-		cost(1)
-	case 0xB9: /* LDA oper,Y   |  absolute,Y  | N+ Z+ C- I- D- V- | 4* */
-		l, h, c := absN(cpu.y)
-		setA(read(l, h))
-		cost(c)
-	case 0xD9: /* CMP oper,Y   |  absolute,Y  | N+ Z+ C+ I- D- V- | 4* */
-		l, h, c := absN(cpu.y)
-		cmp(read(l, h), cpu.a)
-		cost(c)
-	case 0xF9: /* SBC oper,Y   |  absolute,Y  | N+ Z+ C+ I- D- V+ | 4* */
-		l, h, c := absN(cpu.y)
-		setA(sbc(read(l, h)))
-		cost(c)
-
-	case 0x1A: /* NOP          |   implied    | N- Z- C- I- D- V- | 2 */
-		cost(1)
-	case 0x3A: /* NOP          |   implied    | N- Z- C- I- D- V- | 2 */
-		cost(1)
-	case 0x5A: /* NOP          |   implied    | N- Z- C- I- D- V- | 2 */
-		cost(1)
-	case 0x7A: /* NOP          |   implied    | N- Z- C- I- D- V- | 2 */
-		cost(1)
-	case 0x9A: /* TXS          |   implied    | N- Z- C- I- D- V- | 2 */
-		cpu.s = cpu.x
-		cost(1)
-	case 0xBA: /* TSX          |   implied    | N+ Z+ C- I- D- V- | 2 */
-		setX(cpu.s)
-		cost(1)
-	case 0xDA: /* NOP          |   implied    | N- Z- C- I- D- V- | 2 */
-		cost(1)
-	case 0xFA: /* NOP          |   implied    | N- Z- C- I- D- V- | 2 */
-		cost(1)
-
-	case 0x1C: /* NOP          |  absolute,X  | N- Z- C- I- D- V- | 4* */
-		cost(3)
-	case 0x3C: /* NOP          |  absolute,X  | N- Z- C- I- D- V- | 4* */
-		cost(3)
-	case 0x5C: /* NOP          |  absolute,X  | N- Z- C- I- D- V- | 4* */
-		cost(3)
-	case 0x7C: /* NOP          |  absolute,X  | N- Z- C- I- D- V- | 4* */
-		cost(3)
-	case 0xBC: /* LDY oper,X   |  absolute,X  | N+ Z+ C- I- D- V- | 4* */
-		l, h, c := absN(cpu.x)
-		setY(read(l, h))
-		cost(c)
-	case 0xDC: /* NOP          |  absolute,X  | N- Z- C- I- D- V- | 4* */
-		cost(3)
-	case 0xFC: /* NOP          |  absolute,X  | N- Z- C- I- D- V- | 4* */
-		cost(3)
-
-	case 0x1D: /* ORA oper,X   |  absolute,X  | N+ Z+ C- I- D- V- | 4* */
-		l, h, c := absN(cpu.x)
-		setA(cpu.a | read(l, h))
-		cost(c)
-	case 0x3D: /* AND oper,X   |  absolute,X  | N+ Z+ C- I- D- V- | 4* */
-		l, h, c := absN(cpu.x)
-		setA(cpu.a & read(l, h))
-		cost(c)
-	case 0x5D: /* EOR oper,X   |  absolute,X  | N+ Z+ C- I- D- V- | 4* */
-		l, h, c := absN(cpu.x)
-		setA(cpu.a ^ read(l, h))
-		cost(c)
-	case 0x7D: /* ADC oper,X   |  absolute,X  | N+ Z+ C+ I- D- V+ | 4* */
-		l, h, c := absN(cpu.x)
-		setA(adc(read(l, h)))
-		cost(c)
-	case 0x9D: /* STA oper,X   |  absolute,X  | N- Z- C- I- D- V- | 5 */
-		l, h, _ := absN(cpu.x)
-		write(l, h, cpu.a)
-		cost(1)
-	case 0xBD: /* LDA oper,X   |  absolute,X  | N+ Z+ C- I- D- V- | 4* */
-		l, h, c := absN(cpu.x)
-		setA(read(l, h))
-		cost(c)
-	case 0xDD: /* CMP oper,X   |  absolute,X  | N+ Z+ C+ I- D- V- | 4* */
-		l, h, c := absN(cpu.x)
-		cmp(read(l, h), cpu.a)
-		cost(c)
-	case 0xFD: /* SBC oper,X   |  absolute,X  | N+ Z+ C+ I- D- V+ | 4* */
-		l, h, c := absN(cpu.x)
-		setA(sbc(read(l, h)))
-		cost(c)
-
-	case 0x1E: /* ASL oper,X   |  absolute,X  | N+ Z+ C+ I- D- V- | 7 */
-		l, h, _ := absN(cpu.x)
-		write(l, h, asl(read(l, h)))
-		cost(2)
-	case 0x3E: /* ROL oper,X   |  absolute,X  | N+ Z+ C+ I- D- V- | 7 */
-		l, h, _ := absN(cpu.x)
-		write(l, h, rol(read(l, h)))
-		cost(2)
-	case 0x5E: /* LSR oper,X   |  absolute,X  | N0 Z+ C+ I- D- V- | 7 */
-		l, h, _ := absN(cpu.x)
-		write(l, h, lsr(read(l, h)))
-		cost(2)
-	case 0x7E: /* ROR oper,X   |  absolute,X  | N+ Z+ C+ I- D- V- | 7 */
-		l, h, _ := absN(cpu.x)
-		write(l, h, ror(read(l, h)))
-		cost(2)
-	case 0xBE: /* LDX oper,Y   |  absolute,Y  | N+ Z+ C- I- D- V- | 4* */
-		l, h, c := absN(cpu.y)
-		setX(read(l, h))
-		cost(c)
-	case 0xDE: /* DEC oper,X   |  absolute,X  | N+ Z+ C- I- D- V- | 7 */
-		l, h, _ := absN(cpu.x)
-		write(l, h, setNZ(read(l, h)-1))
-		cost(2)
-	case 0xFE: /* INC oper,X   |  absolute,X  | N+ Z+ C- I- D- V- | 7 */
-		l, h, _ := absN(cpu.x)
-		write(l, h, setNZ(read(l, h)+1))
-		cost(2)
-	default:
-		return fmt.Errorf("m6502: invalid op code: %02X%02X: %02X", pch, pcl, read(pcl, pch))
+// String returns the state of the CPU in the Layout set by SetLayout().
+func (cpu *CPU) String() string {
+	return cpu.layout.Render(Trace{
+		CPU: cpu.name, PC: cpu.pc(), Cycles: cpu.total,
+		A: cpu.a, X: cpu.x, Y: cpu.y, S: cpu.s, P: byte(cpu.p | flagU),
+	})
+}
+
+func (cpu *CPU) tick() error {
+	cpu.cycles = 0
+	cpu.at = cpu.pc()
+	cpu.stop, cpu.fail, cpu.pollAt = nil, nil, 0
+	cpu.effOK = false
+	flgI := cpu.p.has(flagI)
+
+	cpu.kind, cpu.op = MicroOpcode, 0x00
+	if len(cpu.addrTraps) > 0 {
+		if h, ok := cpu.addrTraps[cpu.at]; ok {
+			return cpu.trapAddr(h)
+		}
+	}
+	r := regs{}
+	if cpu.fallible {
+		r = cpu.regs()
+	}
+	op := cpu.fetch() /* cost 1 */
+	cpu.op = op
+	if cpu.fail != nil {
+		cpu.abort(r)
+		return cpu.fail
+	}
+
+	if cpu.forbidden.Has(op) {
+		cpu.setPC(byte(cpu.at), byte(cpu.at>>8))
+		return &PolicyError{PC: cpu.at, Opcode: op}
+	}
+	t := Trace{}
+	if len(cpu.hooks.list) > 0 || cpu.resolve != nil {
+		i := DecodeVariant(cpu.variant, op)
+		t = Trace{
+			CPU: cpu.name, PC: cpu.at, Opcode: op, Op: i.Op, Cycles: cpu.total,
+			A: cpu.a, X: cpu.x, Y: cpu.y, S: cpu.s, P: byte(cpu.p | flagU),
+		}
+		cpu.hooks.run(cpu, t)
+	}
+	if cpu.requests.opOn && op == cpu.requests.op {
+		return &RequestError{Request: Request(cpu.fetch()), PC: cpu.at}
+	}
+
+	f := dispatch[op]
+	if cpu.ext != nil {
+		f = cpu.ext[op]
+	}
+	if cpu.traps != nil && cpu.traps[op] != nil {
+		cpu.trap(cpu.traps[op], false)
+	} else if f != nil {
+		f(cpu)
+	} else {
+		cpu.invalid()
+	}
+	if cpu.fail != nil {
+		if _, ok := cpu.fail.(*BusError); ok {
+			cpu.abort(r)
+		}
+		return cpu.fail
+	}
+	if cpu.resolve != nil {
+		cpu.resolve(Resolved{Trace: t, Access: cpu.eff, Memory: cpu.effOK})
+	}
+	if cpu.acc == AccuracyCycle {
+		switch op {
+		case 0x28, 0x58, 0x78: // PLP, CLI, SEI change I after polling
+		default:
+			flgI = cpu.p.has(flagI)
+		}
+		cpu.poll(cpu.pollAt, flgI)
+	}
+	if cpu.stop != nil {
+		return cpu.stop
 	}
 	return cpu.error
 }
@@ -843,11 +604,11 @@ func (f *flag) set(cond bool, bit flag) *flag {
 	return f
 }
 
-func (f *flag) has(bit flag) bool {
-	return *f&bit != 0
+func (f flag) has(bit flag) bool {
+	return f&bit != 0
 }
 
-func (f *flag) String() string {
+func (f flag) String() string {
 	isset := func(flag flag, char byte) byte {
 		if flag != 0 {
 			return char
@@ -855,12 +616,12 @@ func (f *flag) String() string {
 		return '-'
 	}
 	buf := [6]byte{}
-	buf[0] = isset(*f&flagN, 'N')
-	buf[1] = isset(*f&flagV, 'V')
-	buf[2] = isset(*f&flagD, 'D')
-	buf[3] = isset(*f&flagI, 'I')
-	buf[4] = isset(*f&flagZ, 'Z')
-	buf[5] = isset(*f&flagC, 'C')
+	buf[0] = isset(f&flagN, 'N')
+	buf[1] = isset(f&flagV, 'V')
+	buf[2] = isset(f&flagD, 'D')
+	buf[3] = isset(f&flagI, 'I')
+	buf[4] = isset(f&flagZ, 'Z')
+	buf[5] = isset(f&flagC, 'C')
 
 	return string(buf[:])
 }
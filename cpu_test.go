@@ -4,10 +4,12 @@ package m6502
 
 import (
 	"errors"
-	"io"
-	"os"
+	"fmt"
 	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/dtgorski/m6502/testrom"
 )
 
 type memoryBus struct{ mem [0x10000]byte }
@@ -24,6 +26,35 @@ func (m *memoryBus) Reset() {
 	}
 }
 
+// triggerBus invokes a callback when the CPU accesses a given address.
+type triggerBus struct {
+	memoryBus
+	addr uint16
+	fn   func()
+}
+
+func (b *triggerBus) Read(l, h byte) byte {
+	if uint16(h)<<8|uint16(l) == b.addr && b.fn != nil {
+		b.fn()
+	}
+	return b.memoryBus.Read(l, h)
+}
+
+// newTestCPU creates a CPU of the variant running the program at $0200.
+// The NMI handler is at $8000, the IRQ and BRK handler at $9000.
+func newTestCPU(variant Variant, prog ...byte) (*CPU, *triggerBus) {
+	bus := &triggerBus{addr: 0xFFFF}
+	v := variant.Vectors()
+	bus.mem[v.NMI], bus.mem[v.NMI+1] = 0x00, 0x80
+	bus.mem[v.IRQ], bus.mem[v.IRQ+1] = 0x00, 0x90
+	bus.mem[v.BRK], bus.mem[v.BRK+1] = 0x00, 0x90
+	copy(bus.mem[0x0200:], prog)
+
+	cpu := New(bus, WithVariant(variant))
+	cpu.PC(0x00, 0x02)
+	return cpu, bus
+}
+
 func TestCPU(t *testing.T) {
 
 	bus := &memoryBus{}
@@ -89,7 +120,7 @@ func TestCPU(t *testing.T) {
 		{
 			func() { W(0xFD, 0x01, 0xFF, 0x12, 0x34); cpu.s -= 3 },
 			"RTI", []byte{0x40}, 7,
-			func() { EQ(0x12, cpu.PCL()); EQ(0x34, cpu.PCH()); EQ(0xCF, byte(*cpu.p)) },
+			func() { EQ(0x12, cpu.PCL()); EQ(0x34, cpu.PCH()); EQ(0xCF, byte(cpu.p)) },
 		},
 	}
 	tests[0x60 /* RTS | implied | N- Z- C- I- D- V- | 6 */] = []test{
@@ -248,10 +279,10 @@ func TestCPU(t *testing.T) {
 
 	// ---
 
-	tests[0x02 /* HLT */] = []test{{func() {}, "HLT", []byte{0x02}, 0, func() { EX(cpu.error == ErrHalted) }}}
-	tests[0x22 /* HLT */] = []test{{func() {}, "HLT", []byte{0x22}, 0, func() { EX(cpu.error == ErrHalted) }}}
-	tests[0x42 /* HLT */] = []test{{func() {}, "HLT", []byte{0x42}, 0, func() { EX(cpu.error == ErrHalted) }}}
-	tests[0x62 /* HLT */] = []test{{func() {}, "HLT", []byte{0x62}, 0, func() { EX(cpu.error == ErrHalted) }}}
+	tests[0x02 /* HLT */] = []test{{func() {}, "HLT", []byte{0x02}, 0, func() { EX(errors.Is(cpu.error, ErrHalted)) }}}
+	tests[0x22 /* HLT */] = []test{{func() {}, "HLT", []byte{0x22}, 0, func() { EX(errors.Is(cpu.error, ErrHalted)) }}}
+	tests[0x42 /* HLT */] = []test{{func() {}, "HLT", []byte{0x42}, 0, func() { EX(errors.Is(cpu.error, ErrHalted)) }}}
+	tests[0x62 /* HLT */] = []test{{func() {}, "HLT", []byte{0x62}, 0, func() { EX(errors.Is(cpu.error, ErrHalted)) }}}
 
 	tests[0x82 /* NOP | immediate | N- Z- C- I- D- V- | 2 */] = []test{
 		{
@@ -1305,14 +1336,14 @@ func TestCPU(t *testing.T) {
 
 	// ---
 
-	tests[0x12 /* HLT */] = []test{{func() {}, "HLT", []byte{0x12}, 0, func() { EX(cpu.error == ErrHalted) }}}
-	tests[0x32 /* HLT */] = []test{{func() {}, "HLT", []byte{0x32}, 0, func() { EX(cpu.error == ErrHalted) }}}
-	tests[0x52 /* HLT */] = []test{{func() {}, "HLT", []byte{0x52}, 0, func() { EX(cpu.error == ErrHalted) }}}
-	tests[0x72 /* HLT */] = []test{{func() {}, "HLT", []byte{0x72}, 0, func() { EX(cpu.error == ErrHalted) }}}
-	tests[0x92 /* HLT */] = []test{{func() {}, "HLT", []byte{0x92}, 0, func() { EX(cpu.error == ErrHalted) }}}
-	tests[0xB2 /* HLT */] = []test{{func() {}, "HLT", []byte{0xB2}, 0, func() { EX(cpu.error == ErrHalted) }}}
-	tests[0xD2 /* HLT */] = []test{{func() {}, "HLT", []byte{0xD2}, 0, func() { EX(cpu.error == ErrHalted) }}}
-	tests[0xF2 /* HLT */] = []test{{func() {}, "HLT", []byte{0xF2}, 0, func() { EX(cpu.error == ErrHalted) }}}
+	tests[0x12 /* HLT */] = []test{{func() {}, "HLT", []byte{0x12}, 0, func() { EX(errors.Is(cpu.error, ErrHalted)) }}}
+	tests[0x32 /* HLT */] = []test{{func() {}, "HLT", []byte{0x32}, 0, func() { EX(errors.Is(cpu.error, ErrHalted)) }}}
+	tests[0x52 /* HLT */] = []test{{func() {}, "HLT", []byte{0x52}, 0, func() { EX(errors.Is(cpu.error, ErrHalted)) }}}
+	tests[0x72 /* HLT */] = []test{{func() {}, "HLT", []byte{0x72}, 0, func() { EX(errors.Is(cpu.error, ErrHalted)) }}}
+	tests[0x92 /* HLT */] = []test{{func() {}, "HLT", []byte{0x92}, 0, func() { EX(errors.Is(cpu.error, ErrHalted)) }}}
+	tests[0xB2 /* HLT */] = []test{{func() {}, "HLT", []byte{0xB2}, 0, func() { EX(errors.Is(cpu.error, ErrHalted)) }}}
+	tests[0xD2 /* HLT */] = []test{{func() {}, "HLT", []byte{0xD2}, 0, func() { EX(errors.Is(cpu.error, ErrHalted)) }}}
+	tests[0xF2 /* HLT */] = []test{{func() {}, "HLT", []byte{0xF2}, 0, func() { EX(errors.Is(cpu.error, ErrHalted)) }}}
 
 	// ---
 
@@ -1783,7 +1814,13 @@ func TestCPU(t *testing.T) {
 			func() {}, "NOP", []byte{0x7C}, 4, func() {},
 		},
 	}
-	tests[0x9C /* invalid */] = nil
+	tests[0x9C /* SHY oper,X | absolute,X | N- Z- C- I- D- V- | 5 */] = []test{
+		{
+			func() { X(0x01); Y(0xFF) },
+			"SHY", []byte{0x9C, 0x11, 0x34}, 5,
+			func() { EQ(0x35, R(0x12, 0x34)) },
+		},
+	}
 	tests[0xBC /* LDY oper,X | absolute,X | N+ Z+ C- I- D- V- | 4* */] = []test{
 		{
 			func() { W(0x12, 0x34, 0x80); cpu.x = 0x1 },
@@ -1972,7 +2009,13 @@ func TestCPU(t *testing.T) {
 			func() { EQ(0x55, R(0x12, 0x34)); EX(!H(flagN)); EX(!H(flagC)) },
 		},
 	}
-	tests[0x9E /* invalid */] = nil
+	tests[0x9E /* SHX oper,Y | absolute,Y | N- Z- C- I- D- V- | 5 */] = []test{
+		{
+			func() { X(0xFF); Y(0x01) },
+			"SHX", []byte{0x9E, 0x11, 0x34}, 5,
+			func() { EQ(0x35, R(0x12, 0x34)) },
+		},
+	}
 	tests[0xBE /* LDX oper,Y | absolute,Y | N+ Z+ C- I- D- V- | 4* */] = []test{
 		{
 			func() { W(0x12, 0x34, 0x80); Y(0x01) },
@@ -2035,6 +2078,228 @@ func TestFlag(t *testing.T) {
 	}
 }
 
+func TestDecimalMode(t *testing.T) {
+	bus := &memoryBus{}
+	cpu := New(bus)
+
+	tests := []struct {
+		mode DecimalMode
+		op   byte
+		a, b byte
+		want byte
+	}{
+		{DecimalAll, 0x69, 0x09, 0x01, 0x10},
+		{DecimalAll, 0xE9, 0x10, 0x01, 0x09},
+		{DecimalADC, 0x69, 0x09, 0x01, 0x10},
+		{DecimalADC, 0xE9, 0x10, 0x01, 0x0F},
+		{DecimalSBC, 0x69, 0x09, 0x01, 0x0A},
+		{DecimalSBC, 0xE9, 0x10, 0x01, 0x09},
+		{DecimalNone, 0x69, 0x09, 0x01, 0x0A},
+		{DecimalNone, 0xE9, 0x10, 0x01, 0x0F},
+	}
+	for i, tt := range tests {
+		bus.Reset()
+		bus.mem[0x0000], bus.mem[0x0001] = tt.op, tt.b
+
+		cpu.Reset()
+		cpu.SetDecimalMode(tt.mode)
+		cpu.p.set(true, flagD|flagC)
+		if tt.op == 0x69 {
+			cpu.p.set(false, flagC)
+		}
+		cpu.a = tt.a
+
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+		if cpu.a != tt.want {
+			t.Errorf("unexpected, want 0x%02X, got 0x%02X in test %d", tt.want, cpu.a, i)
+		}
+	}
+}
+
+func TestDecimalFlags(t *testing.T) {
+	bus := &memoryBus{}
+	cpu := New(bus)
+
+	tests := []struct {
+		variant Variant
+		op, a   byte
+		b       byte
+		carry   bool
+		want    byte
+		flags   string
+	}{
+		{NMOS6502, 0x69, 0x50, 0x50, false, 0x00, "NVD--C"},
+		{CMOS65C02, 0x69, 0x50, 0x50, false, 0x00, "-VD-ZC"},
+		{NMOS6502, 0x69, 0x99, 0x01, false, 0x00, "N-D--C"},
+		{CMOS65C02, 0x69, 0x99, 0x01, false, 0x00, "--D-ZC"},
+		{NMOS6502, 0x69, 0x79, 0x00, true, 0x80, "NVD---"},
+		{CMOS65C02, 0x69, 0x79, 0x00, true, 0x80, "NVD---"},
+		{NMOS6502, 0x69, 0x90, 0x90, false, 0x80, "-VD--C"},
+		{CMOS65C02, 0x69, 0x90, 0x90, false, 0x80, "NVD--C"},
+		{NMOS6502, 0xE9, 0x00, 0x01, true, 0x99, "N-D---"},
+		{CMOS65C02, 0xE9, 0x00, 0x01, true, 0x99, "N-D---"},
+		{NMOS6502, 0xE9, 0x42, 0x42, true, 0x00, "--D-ZC"},
+		{NMOS6502, 0xE9, 0x80, 0x01, true, 0x79, "-VD--C"},
+	}
+	for i, tt := range tests {
+		bus.mem[0x0000], bus.mem[0x0001] = tt.op, tt.b
+
+		cpu.Reset()
+		cpu.SetVariant(tt.variant)
+		cpu.PC(0x00, 0x00)
+		cpu.p.set(true, flagD)
+		cpu.p.set(tt.carry, flagC)
+		cpu.a = tt.a
+
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+		if cpu.a != tt.want || cpu.p.String() != tt.flags {
+			t.Errorf("unexpected, got 0x%02X [%s] in test %d", cpu.a, cpu.p, i)
+		}
+	}
+}
+
+func TestResetMode(t *testing.T) {
+	bus := &memoryBus{}
+	bus.mem[0xFFFC] = 0x12
+	bus.mem[0xFFFD] = 0x34
+
+	cpu := New(bus)
+	cpu.a, cpu.x, cpu.y, cpu.s = 0x01, 0x02, 0x03, 0x80
+	cpu.p.set(true, flagC|flagD)
+	cpu.PC(0x00, 0x00)
+
+	if cycles := cpu.Reset(); cycles != 7 {
+		t.Fatalf("unexpected, got %d", cycles)
+	}
+	if cpu.String() != "m6502: PC=3412 A=00 X=00 Y=00 [------] S=FF" {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+
+	cpu.SetResetMode(ResetAccurate)
+	cpu.a, cpu.x, cpu.y, cpu.s = 0x01, 0x02, 0x03, 0x80
+	cpu.p.set(true, flagC|flagD)
+	cpu.PC(0x00, 0x00)
+
+	if cycles := cpu.Reset(); cycles != 7 {
+		t.Fatalf("unexpected, got %d", cycles)
+	}
+	if cpu.String() != "m6502: PC=3412 A=01 X=02 Y=03 [--DI-C] S=7D" {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+}
+
+func TestStackReset(t *testing.T) {
+	cpu := New(&memoryBus{})
+
+	cpu.SetStackReset(StackDecrement, 0x00)
+	if cpu.Reset(); cpu.s != 0xFD {
+		t.Fatalf("unexpected, got %02X", cpu.s)
+	}
+	if cpu.Reset(); cpu.s != 0xFA {
+		t.Fatalf("unexpected, got %02X", cpu.s)
+	}
+
+	cpu.SetStackReset(StackLoad, 0x40)
+	cpu.s = 0x10
+	if cpu.Reset(); cpu.s != 0x40 {
+		t.Fatalf("unexpected, got %02X", cpu.s)
+	}
+
+	cpu.SetStackReset(StackByMode, 0x40)
+	if cpu.Reset(); cpu.s != 0xFF {
+		t.Fatalf("unexpected, got %02X", cpu.s)
+	}
+	cpu.SetResetMode(ResetAccurate)
+	if cpu.Reset(); cpu.s != 0xFC {
+		t.Fatalf("unexpected, got %02X", cpu.s)
+	}
+}
+
+// recordBus records the bus accesses, "R" for reads and "W" for writes.
+type recordBus struct {
+	memoryBus
+	log []string
+}
+
+func (b *recordBus) Read(l, h byte) byte {
+	d := b.memoryBus.Read(l, h)
+	b.log = append(b.log, fmt.Sprintf("R%02X%02X:%02X", h, l, d))
+	return d
+}
+func (b *recordBus) Write(l, h, d byte) {
+	b.log = append(b.log, fmt.Sprintf("W%02X%02X:%02X", h, l, d))
+	b.memoryBus.Write(l, h, d)
+}
+
+func TestRMWDummyWrite(t *testing.T) {
+	tests := []struct {
+		variant Variant
+		prog    []byte
+		log     string
+	}{
+		{NMOS6502, []byte{0xEE, 0x00, 0x03}, "R0200:EE R0201:00 R0202:03 R0300:41 W0300:41 W0300:42"},
+		{NMOS6502, []byte{0x06, 0x10}, "R0200:06 R0201:10 R0010:41 W0010:41 W0010:82"},
+		{CMOS65C02, []byte{0xEE, 0x00, 0x03}, "R0200:EE R0201:00 R0202:03 R0300:41 R0300:41 W0300:42"},
+	}
+	for _, tt := range tests {
+		bus := &recordBus{}
+		copy(bus.mem[0x0200:], tt.prog)
+		bus.mem[0x0300], bus.mem[0x0010] = 0x41, 0x41
+
+		cpu := New(bus)
+		cpu.SetVariant(tt.variant)
+		cpu.PC(0x00, 0x02)
+		bus.log = nil
+
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+		if log := strings.Join(bus.log, " "); log != tt.log {
+			t.Errorf("unexpected, got %s", log)
+		}
+	}
+}
+
+func TestIndexedDummyRead(t *testing.T) {
+	tests := []struct {
+		variant Variant
+		prog    []byte
+		x, y    byte
+		log     string
+	}{
+		{NMOS6502, []byte{0xBD, 0xF0, 0x12}, 0x20, 0, "R0200:BD R0201:F0 R0202:12 R1210:00 R1310:00"},
+		{NMOS6502, []byte{0xBD, 0x00, 0x12}, 0x20, 0, "R0200:BD R0201:00 R0202:12 R1220:00"},
+		{NMOS6502, []byte{0x9D, 0x00, 0x12}, 0x20, 0, "R0200:9D R0201:00 R0202:12 R1220:00 W1220:00"},
+		{NMOS6502, []byte{0x99, 0xF0, 0x12}, 0, 0x20, "R0200:99 R0201:F0 R0202:12 R1210:00 W1310:00"},
+		{NMOS6502, []byte{0x91, 0x10}, 0, 0x20, "R0200:91 R0201:10 R0010:F0 R0011:12 R1210:00 W1310:00"},
+		{NMOS6502, []byte{0xFE, 0x00, 0x12}, 0x20, 0, "R0200:FE R0201:00 R0202:12 R1220:00 R1220:00 W1220:00 W1220:01"},
+		{CMOS65C02, []byte{0xBD, 0xF0, 0x12}, 0x20, 0, "R0200:BD R0201:F0 R0202:12 R0202:12 R1310:00"},
+		{CMOS65C02, []byte{0x1E, 0x00, 0x12}, 0x20, 0, "R0200:1E R0201:00 R0202:12 R1220:00 R1220:00 W1220:00"},
+	}
+	for _, tt := range tests {
+		bus := &recordBus{}
+		copy(bus.mem[0x0200:], tt.prog)
+		bus.mem[0x0010], bus.mem[0x0011] = 0xF0, 0x12
+
+		cpu := New(bus)
+		cpu.SetVariant(tt.variant)
+		cpu.PC(0x00, 0x02)
+		cpu.x, cpu.y = tt.x, tt.y
+		bus.log = nil
+
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+		if log := strings.Join(bus.log, " "); log != tt.log {
+			t.Errorf("unexpected, got %s", log)
+		}
+	}
+}
+
 func TestHalt(t *testing.T) {
 	bus := &memoryBus{}
 	bus.mem[0x00] = 0x02
@@ -2055,14 +2320,14 @@ func TestHalt(t *testing.T) {
 
 func TestInvalid(t *testing.T) {
 	bus := &memoryBus{}
-	bus.mem[0x00] = 0x9E
+	bus.mem[0x00] = 0x03
 	cpu := New(bus)
 
 	_, err := cpu.Step()
 	if err == nil {
 		t.Fatal("unexpected")
 	}
-	if "m6502: invalid op code: 0000: 9E" != err.Error() {
+	if "m6502: invalid op code: 0000: 03" != err.Error() {
 		t.Logf("unexpected, got '%s'", err)
 	}
 }
@@ -2073,10 +2338,10 @@ func TestNMI(t *testing.T) {
 	bus.mem[0xFFFB] = 0x34
 
 	cpu := New(bus)
-	cpu.NMI()
+	cycles, err := cpu.NMI()
 
-	if cpu.PCL() != 0x12 || cpu.PCH() != 0x34 || cpu.s != 0xFC {
-		t.Log("unexpected")
+	if cpu.PCL() != 0x12 || cpu.PCH() != 0x34 || cpu.s != 0xFC || cycles != 7 || err != nil {
+		t.Fatal("unexpected")
 	}
 }
 
@@ -2088,15 +2353,103 @@ func TestIRQ(t *testing.T) {
 	cpu := New(bus)
 
 	cpu.p.set(true, flagI)
-	cpu.IRQ()
-	if cpu.PCL() != 0x00 || cpu.PCH() != 0x00 || cpu.s != 0xFF {
-		t.Log("unexpected")
+	if cycles, _ := cpu.IRQ(); cpu.PCL() != 0x00 || cpu.PCH() != 0x00 || cpu.s != 0xFF || cycles != 0 {
+		t.Fatal("unexpected")
 	}
 
 	cpu.p.set(false, flagI)
-	cpu.IRQ()
-	if cpu.PCL() != 0x12 || cpu.PCH() != 0x34 || cpu.s != 0xFC {
-		t.Log("unexpected")
+	if cycles, _ := cpu.IRQ(); cpu.PCL() != 0x12 || cpu.PCH() != 0x34 || cpu.s != 0xFC || cycles != 7 {
+		t.Fatal("unexpected")
+	}
+}
+
+func TestAssertIRQ(t *testing.T) {
+	bus := &memoryBus{}
+	bus.mem[0xFFFE] = 0x12
+	bus.mem[0xFFFF] = 0x34
+	bus.mem[0x0000] = 0xEA // NOP
+	bus.mem[0x0001] = 0x58 // CLI
+
+	cpu := New(bus)
+	cpu.p.set(true, flagI)
+	cpu.AssertIRQ()
+
+	if cycles, _ := cpu.Step(); cycles != 2 || cpu.PCL() != 0x01 {
+		t.Fatalf("unexpected, got %d cycles, %s", cycles, cpu)
+	}
+	if cycles, _ := cpu.Step(); cycles != 2 || cpu.PCL() != 0x02 {
+		t.Fatalf("unexpected, got %d cycles, %s", cycles, cpu)
+	}
+	if cycles, _ := cpu.Step(); cycles != 7 || cpu.PCL() != 0x12 || cpu.PCH() != 0x34 {
+		t.Fatalf("unexpected, got %d cycles, %s", cycles, cpu)
+	}
+	if cpu.s != 0xFC || bus.mem[0x01FD]&byte(flagB) != 0 || !cpu.p.has(flagI) {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+
+	// Line still asserted, but masked by I flag.
+	bus.mem[0x3412] = 0xEA
+	if cycles, _ := cpu.Step(); cycles != 2 || cpu.PCL() != 0x13 {
+		t.Fatalf("unexpected, got %d cycles, %s", cycles, cpu)
+	}
+
+	cpu.ReleaseIRQ()
+	cpu.p.set(false, flagI)
+	bus.mem[0x3413] = 0xEA
+	if cycles, _ := cpu.Step(); cycles != 2 || cpu.PCL() != 0x14 {
+		t.Fatalf("unexpected, got %d cycles, %s", cycles, cpu)
+	}
+}
+
+func TestAssertNMI(t *testing.T) {
+	bus := &memoryBus{}
+	bus.mem[0xFFFA] = 0x12
+	bus.mem[0xFFFB] = 0x34
+	bus.mem[0xFFFE] = 0x56
+	bus.mem[0xFFFF] = 0x78
+	for i := 0x3412; i < 0x3420; i++ {
+		bus.mem[i] = 0xEA // NOP
+	}
+
+	cpu := New(bus)
+	cpu.p.set(true, flagI)
+	cpu.AssertIRQ()
+	cpu.AssertNMI()
+
+	// NMI is not masked by I and takes precedence.
+	if cycles, _ := cpu.Step(); cycles != 7 || cpu.PCL() != 0x12 || cpu.PCH() != 0x34 {
+		t.Fatalf("unexpected, got %d cycles, %s", cycles, cpu)
+	}
+	// Line still asserted, no further edge.
+	if cycles, _ := cpu.Step(); cycles != 2 || cpu.PCL() != 0x13 {
+		t.Fatalf("unexpected, got %d cycles, %s", cycles, cpu)
+	}
+	cpu.AssertNMI()
+	if cycles, _ := cpu.Step(); cycles != 2 || cpu.PCL() != 0x14 {
+		t.Fatalf("unexpected, got %d cycles, %s", cycles, cpu)
+	}
+
+	// Retrigger within the handler.
+	cpu.ReleaseNMI()
+	cpu.AssertNMI()
+	if cycles, _ := cpu.Step(); cycles != 7 || cpu.PCL() != 0x12 || cpu.s != 0xF9 {
+		t.Fatalf("unexpected, got %d cycles, %s", cycles, cpu)
+	}
+
+	// The edge stays latched even when the line is released early.
+	cpu.ReleaseNMI()
+	cpu.AssertNMI()
+	cpu.ReleaseNMI()
+	if cycles, _ := cpu.Step(); cycles != 7 || cpu.s != 0xF6 {
+		t.Fatalf("unexpected, got %d cycles, %s", cycles, cpu)
+	}
+
+	// Releasing IRQ keeps the latched edge.
+	cpu.ReleaseNMI()
+	cpu.AssertNMI()
+	cpu.ReleaseIRQ()
+	if cycles, _ := cpu.Step(); cycles != 7 || cpu.s != 0xF3 {
+		t.Fatalf("unexpected, got %d cycles, %s", cycles, cpu)
 	}
 }
 
@@ -2125,8 +2478,8 @@ func TestPanic(t *testing.T) {
 	if err == nil {
 		t.Fatal("unexpected")
 	}
-	if "foo" != err.Error() {
-		t.Logf("unexpected, got *%s*", err)
+	if "m6502: 0000: foo" != err.Error() {
+		t.Fatalf("unexpected, got *%s*", err)
 	}
 }
 
@@ -2134,28 +2487,149 @@ func BenchmarkCPU(b *testing.B) {
 	bus := &memoryBus{}
 	cpu := New(bus)
 
-	file, err := os.Open("./dev/6502_functional_test.bin")
-	if err != nil {
-		b.Fatal(err)
-	}
-	_, err = io.ReadFull(file, bus.mem[:])
+	image, err := testrom.Locate(testrom.FunctionalTest)
 	if err != nil {
-		b.Fatal(err)
+		b.Skip(err)
 	}
+	copy(bus.mem[:], image)
 	cpu.PC(0x00, 0x04)
 
 	b.ReportAllocs()
 	b.ResetTimer()
 
 	for n := 0; n < b.N; n++ {
-		for {
-			_, err = cpu.Step()
-			if err != nil {
-				b.Fatal(err)
-			}
-			if cpu.PCH() == 0x34 && cpu.PCL() == 0x69 {
-				break
-			}
+		if _, _, err = cpu.RunUntil(0x3469, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestStepAllocs(t *testing.T) {
+	bus := &memoryBus{}
+	copy(bus.mem[0x0200:], []byte{
+		0xA2, 0x00, // LDX #$00
+		0xBD, 0x00, 0x30, // LDA $3000,X
+		0x69, 0x01, // ADC #$01
+		0x9D, 0x00, 0x30, // STA $3000,X
+		0xE8,       // INX
+		0xD0, 0xF5, // BNE $0202
+		0x20, 0x13, 0x02, // JSR $0213
+		0x4C, 0x00, 0x02, // JMP $0200
+		0xE6, 0x10, // INC $10
+		0x60, // RTS
+	})
+	cpu := New(bus)
+	cpu.PC(0x00, 0x02)
+
+	allocs := testing.AllocsPerRun(10000, func() {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
 		}
+	})
+	if allocs != 0 {
+		t.Fatalf("unexpected, got %v allocs per step", allocs)
+	}
+}
+
+func TestResume(t *testing.T) {
+	bus := &memoryBus{}
+	copy(bus.mem[0x0200:], []byte{0xA9, 0x42, 0x02, 0xE8, 0xE8})
+	cpu := New(bus)
+	cpu.PC(0x00, 0x02)
+
+	if _, err := cpu.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cpu.Step(); !errors.Is(err, ErrHalted) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	if _, err := cpu.Step(); !errors.Is(err, ErrHalted) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	cpu.Resume()
+	cpu.PC(0x04, 0x02)
+
+	if _, err := cpu.Step(); err != nil {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	if cpu.a != 0x42 || cpu.x != 0x01 || cpu.pc() != 0x0205 {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+}
+
+func TestHalted(t *testing.T) {
+	bus := &memoryBus{}
+	copy(bus.mem[0x0200:], []byte{0xEA, 0x02, 0xEA, 0x03})
+	cpu := New(bus)
+	cpu.PC(0x00, 0x02)
+
+	if _, err := cpu.Step(); err != nil || cpu.Halted() || cpu.LastError() != nil {
+		t.Fatalf("unexpected, got %v", cpu.LastError())
+	}
+	if _, err := cpu.Step(); err == nil || !cpu.Halted() || !errors.Is(cpu.LastError(), ErrHalted) {
+		t.Fatalf("unexpected, got %v", cpu.LastError())
+	}
+	cpu.Resume()
+	if _, err := cpu.Step(); err != nil || cpu.Halted() || cpu.LastError() != nil {
+		t.Fatalf("unexpected, got %v", cpu.LastError())
+	}
+	e := &OpcodeError{}
+	if _, err := cpu.Step(); err == nil || cpu.Halted() || !errors.As(cpu.LastError(), &e) {
+		t.Fatalf("unexpected, got %v", cpu.LastError())
+	}
+	if cpu.Reset(); cpu.LastError() != nil {
+		t.Fatalf("unexpected, got %v", cpu.LastError())
+	}
+
+	// Pending or requested interrupts do not leave the halted state.
+	copy(bus.mem[0x0200:], []byte{0x02})
+	bus.mem[0xFFFA], bus.mem[0xFFFB] = 0x00, 0x80
+	bus.mem[0xFFFE], bus.mem[0xFFFF] = 0x00, 0x90
+	cpu.PC(0x00, 0x02)
+	cpu.p &^= flagI
+	if _, err := cpu.Step(); !errors.Is(err, ErrHalted) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	cpu.AssertNMI()
+	cpu.AssertIRQ()
+	if _, err := cpu.Step(); !errors.Is(err, ErrHalted) || !cpu.Halted() || cpu.pc() != 0x0201 {
+		t.Fatalf("unexpected, got %v %s", err, cpu)
+	}
+	for _, fn := range []func() (uint, error){cpu.NMI, cpu.IRQ} {
+		if _, err := fn(); !errors.Is(err, ErrHalted) || !cpu.Halted() || cpu.pc() != 0x0201 {
+			t.Fatalf("unexpected, got %v %s", err, cpu)
+		}
+	}
+	if cpu.Reset(); cpu.Halted() {
+		t.Fatal("unexpected")
+	}
+}
+
+func TestTotalCycles(t *testing.T) {
+	bus := &memoryBus{}
+	copy(bus.mem[0x0200:], []byte{0xEA, 0xAD, 0x00, 0x30, 0x02})
+	cpu := New(bus)
+	cpu.PC(0x00, 0x02)
+
+	if n := cpu.TotalCycles(); n != 7 {
+		t.Fatalf("unexpected, got %d", n)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := cpu.TotalCycles(); n != 13 {
+		t.Fatalf("unexpected, got %d", n)
+	}
+	cpu.SetTotalCycles(0)
+	if _, err := cpu.Step(); !errors.Is(err, ErrHalted) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	if _, err := cpu.NMI(); !errors.Is(err, ErrHalted) || cpu.TotalCycles() != 0 {
+		t.Fatalf("unexpected, got %d, %v", cpu.TotalCycles(), err)
+	}
+	if cpu.Reset(); cpu.TotalCycles() != 7 {
+		t.Fatalf("unexpected, got %d", cpu.TotalCycles())
 	}
 }
@@ -0,0 +1,99 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"fmt"
+	"sort"
+)
+
+type (
+	// Device is a peripheral attached to a DeviceBus. It sees the addresses
+	// relative to the start of the range it has been attached to.
+	Device = Bus16
+
+	// DeviceBus is a Bus routing accesses to the peripherals attached to
+	// address ranges. Where ranges overlap, the device with the highest
+	// priority receives the access. Reading an address without device
+	// returns the OpenBus value, writes are ignored.
+	DeviceBus struct {
+		OpenBus byte // Value read from unattached addresses
+
+		seq   uint64
+		list  []attachment
+		pages [0x100][]*attachment // Attachments per page, in order of precedence
+	}
+
+	attachment struct {
+		start, end uint16
+		priority   int
+		seq        uint64
+		dev        Device
+	}
+)
+
+// NewDeviceBus creates a DeviceBus without devices.
+func NewDeviceBus() *DeviceBus {
+	return &DeviceBus{OpenBus: 0xFF}
+}
+
+// Attach attaches the device to the addresses start to end (inclusive).
+// Where ranges overlap, the device of higher priority takes precedence,
+// of equal priority the device attached first. The returned function
+// detaches the device; it is safe to call more than once. Attach panics
+// when end is less than start.
+func (b *DeviceBus) Attach(start, end uint16, priority int, dev Device) (detach func()) {
+	if end < start {
+		panic(fmt.Sprintf("m6502: invalid range %04X-%04X", start, end))
+	}
+	b.seq++
+	seq := b.seq
+	b.list = append(b.list, attachment{start, end, priority, seq, dev})
+	b.update()
+
+	return func() {
+		for i := range b.list {
+			if b.list[i].seq == seq {
+				b.list = append(b.list[:i:i], b.list[i+1:]...)
+				b.update()
+				return
+			}
+		}
+	}
+}
+
+// Read reads a byte from the device attached at the address.
+func (b *DeviceBus) Read(lo, hi byte) byte {
+	addr := uint16(hi)<<8 | uint16(lo)
+	for _, a := range b.pages[hi] {
+		if addr >= a.start && addr <= a.end {
+			return a.dev.Read(addr - a.start)
+		}
+	}
+	return b.OpenBus
+}
+
+// Write writes a byte to the device attached at the address.
+func (b *DeviceBus) Write(lo, hi, db byte) {
+	addr := uint16(hi)<<8 | uint16(lo)
+	for _, a := range b.pages[hi] {
+		if addr >= a.start && addr <= a.end {
+			a.dev.Write(addr-a.start, db)
+			return
+		}
+	}
+}
+
+// update rebuilds the page table after the attachments changed.
+func (b *DeviceBus) update() {
+	sort.SliceStable(b.list, func(i, j int) bool {
+		return b.list[i].priority > b.list[j].priority
+	})
+	b.pages = [0x100][]*attachment{}
+	for i := range b.list {
+		a := &b.list[i]
+		for p := int(a.start >> 8); p <= int(a.end>>8); p++ {
+			b.pages[p] = append(b.pages[p], a)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+// register is a device recording the last access.
+type register struct {
+	addr uint16
+	data byte
+}
+
+func (r *register) Read(addr uint16) byte     { r.addr = addr; return r.data }
+func (r *register) Write(addr uint16, b byte) { r.addr, r.data = addr, b }
+
+func TestDeviceBus(t *testing.T) {
+	b := NewDeviceBus()
+	ram := NewRAM(0x10000)
+	io, overlay := &register{}, &register{}
+
+	b.Attach(0x0000, 0xFFFF, PriorityDefault, ram)
+	b.Attach(0xD000, 0xD0FF, 10, io)
+
+	b.Write(0x10, 0xD0, 0x42)
+	if io.addr != 0x0010 || io.data != 0x42 || ram.Read(0xD010) != 0x00 {
+		t.Fatal("unexpected")
+	}
+	b.Write(0x10, 0xC0, 0x43)
+	if ram.Read(0xC010) != 0x43 || b.Read(0x10, 0xC0) != 0x43 {
+		t.Fatal("unexpected")
+	}
+
+	// Equal priority, attached first takes precedence.
+	detach := b.Attach(0xD010, 0xD010, 10, overlay)
+	if b.Read(0x10, 0xD0) != 0x42 || overlay.addr != 0x0000 {
+		t.Fatal("unexpected")
+	}
+	detach()
+	detach = b.Attach(0xD010, 0xD010, 20, overlay)
+	if b.Read(0x10, 0xD0) != 0x00 || b.Read(0x11, 0xD0) != 0x42 {
+		t.Fatal("unexpected")
+	}
+	detach()
+	detach()
+	if b.Read(0x10, 0xD0) != 0x42 || len(b.list) != 2 {
+		t.Fatal("unexpected")
+	}
+}
+
+func TestDeviceBusOpen(t *testing.T) {
+	b := NewDeviceBus()
+	b.Attach(0x00FF, 0x0100, PriorityDefault, NewRAM(2))
+	b.Write(0x00, 0x01, 0x11)
+	b.Write(0x01, 0x01, 0x22)
+
+	if b.Read(0xFF, 0x00) != 0x00 || b.Read(0x00, 0x01) != 0x11 || b.Read(0x01, 0x01) != 0xFF {
+		t.Fatal("unexpected")
+	}
+	b.OpenBus = 0x00
+	if b.Read(0x00, 0x80) != 0x00 {
+		t.Fatal("unexpected")
+	}
+}
@@ -0,0 +1,51 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "fmt"
+
+type (
+	// Diagnostic is an event reporting suspicious behavior of the emulated
+	// program, e.g. a return without matching call. Diagnostics are opt-in
+	// and do not affect the execution.
+	Diagnostic struct {
+		Kind DiagnosticKind // Category of the event
+		PC   uint16         // Address of the offending instruction
+		Text string         // Human readable description
+
+		// DiagVectorWrite: address and values of the vector byte.
+		// DiagROMWrite: address and value written.
+		Addr     uint16
+		Old, New byte
+	}
+
+	// DiagnosticKind categorizes a Diagnostic.
+	DiagnosticKind byte
+)
+
+// Categories of Diagnostic events.
+const (
+	DiagReturnWithoutCall DiagnosticKind = iota + 1 // RTS/RTI with empty call stack
+	DiagReturnImbalanced                            // RTS/RTI with unbalanced stack
+	DiagVectorWrite                                 // Write to the Vectors
+	DiagROMWrite                                    // Write to a protected range of a Mapper
+	DiagStackWrap                                   // Stack pointer wrapped by a push or a pull
+)
+
+// SetDiagnostics registers a function receiving Diagnostic events. Passing
+// nil removes the function. Diagnostics are dropped when no function is set.
+func (cpu *CPU) SetDiagnostics(fn func(Diagnostic)) {
+	cpu.diagnostics = fn
+}
+
+// Error implements the error interface, so that a Diagnostic can be
+// returned as an error, e.g. to stop the execution from a handler.
+func (d Diagnostic) Error() string {
+	return fmt.Sprintf("m6502: %04X: %s", d.PC, d.Text)
+}
+
+func (cpu *CPU) diagnose(kind DiagnosticKind, pc uint16, format string, a ...any) {
+	if cpu.diagnostics != nil {
+		cpu.diagnostics(Diagnostic{Kind: kind, PC: pc, Text: fmt.Sprintf(format, a...)})
+	}
+}
@@ -0,0 +1,77 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// Diff is the structured difference between the states of two CPUs,
+	// empty when the states are equal, see Compare().
+	Diff []Delta
+
+	// Delta is a register, flag or counter differing between two CPUs.
+	Delta struct {
+		Name string // "PC", "A", "X", "Y", "S", "Cycles" or a flag "N" ... "C"
+		Flag bool   // Name denotes a flag
+		A, B uint64 // Value of the first and the second CPU, 0 or 1 for flags
+	}
+)
+
+// Compare compares the registers, the flags and the total cycles of two
+// CPUs, register by register and flag by flag, e.g. in differential tests
+// against a reference. The unused and the B bit of P are not compared.
+func Compare(a, b *CPU) Diff {
+	d := Diff{}
+	cmp := func(name string, x, y uint64) {
+		if x != y {
+			d = append(d, Delta{Name: name, A: x, B: y})
+		}
+	}
+	bit := func(p, f flag) uint64 {
+		return uint64(when(p.has(f), 1, 0))
+	}
+	cmp("PC", uint64(a.pc()), uint64(b.pc()))
+	cmp("A", uint64(a.a), uint64(b.a))
+	cmp("X", uint64(a.x), uint64(b.x))
+	cmp("Y", uint64(a.y), uint64(b.y))
+	cmp("S", uint64(a.s), uint64(b.s))
+
+	cmp("Cycles", a.total, b.total)
+
+	for i, f := range [...]flag{flagN, flagV, flagD, flagI, flagZ, flagC} {
+		if x, y := bit(a.p, f), bit(b.p, f); x != y {
+			d = append(d, Delta{Name: "NVDIZC"[i : i+1], Flag: true, A: x, B: y})
+		}
+	}
+	return d
+}
+
+// Equal reports whether the compared states are equal.
+func (d Diff) Equal() bool {
+	return len(d) == 0
+}
+
+// String returns a report of the differences, one per line.
+func (d Diff) String() string {
+	sb := strings.Builder{}
+	for _, e := range d {
+		fmt.Fprintf(&sb, "%s\n", e)
+	}
+	return sb.String()
+}
+
+// String returns the difference in human-readable form.
+func (e Delta) String() string {
+	switch {
+	case e.Flag:
+		return fmt.Sprintf("flag %s: %d != %d", e.Name, e.A, e.B)
+	case e.Name == "PC":
+		return fmt.Sprintf("PC: %04X != %04X", e.A, e.B)
+	case e.Name == "Cycles":
+		return fmt.Sprintf("Cycles: %d != %d", e.A, e.B)
+	}
+	return fmt.Sprintf("%s: %02X != %02X", e.Name, e.A, e.B)
+}
@@ -0,0 +1,37 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	a, b := New(&memoryBus{}), New(&memoryBus{})
+	if d := Compare(a, b); !d.Equal() || d.String() != "" {
+		t.Fatalf("unexpected, got %v", d)
+	}
+
+	b.PC(0x34, 0x12)
+	b.a, b.s = 0x80, 0xF0
+	b.p.set(true, flagC).set(true, flagN).set(true, flagB)
+	b.total = 100
+
+	want := Diff{
+		{Name: "PC", A: 0x0000, B: 0x1234},
+		{Name: "A", A: 0x00, B: 0x80},
+		{Name: "S", A: 0xFF, B: 0xF0},
+		{Name: "Cycles", A: 7, B: 100},
+		{Name: "N", Flag: true, A: 0, B: 1},
+		{Name: "C", Flag: true, A: 0, B: 1},
+	}
+	d := Compare(a, b)
+	if d.Equal() || !reflect.DeepEqual(d, want) {
+		t.Fatalf("unexpected, got %#v", d)
+	}
+	report := "PC: 0000 != 1234\nA: 00 != 80\nS: FF != F0\nCycles: 7 != 100\nflag N: 0 != 1\nflag C: 0 != 1\n"
+	if d.String() != report {
+		t.Fatalf("unexpected, got %q", d)
+	}
+}
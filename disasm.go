@@ -0,0 +1,153 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Disassembler renders machine code as assembly source. Op codes without
+// instruction for the Variant are rendered as ".byte $XX", one byte at a
+// time, so that data and reserved op codes never pass for NOPs.
+type Disassembler struct {
+	Variant Variant // Processor model decoding the op codes
+	Illegal bool    // Render undocumented NMOS op codes by their mnemonic
+	Labels  Labels  // Names shown instead of addresses, optional
+}
+
+// Labels provides names of addresses to a Disassembler, see the symbols
+// package for a VICE label file based implementation.
+type Labels interface {
+	Label(addr uint16) (name string, ok bool)
+}
+
+// Disassembler returns a Disassembler for the processor model of the CPU.
+func (cpu *CPU) Disassembler() Disassembler {
+	return Disassembler{Variant: cpu.variant}
+}
+
+// Decode renders the instruction at the start of code located at pc. It
+// returns the source and the number of bytes consumed, 0 when code is empty.
+func (d Disassembler) Decode(pc uint16, code []byte) (asm string, size int) {
+	if len(code) == 0 {
+		return "", 0
+	}
+	i := DecodeVariant(d.Variant, code[0])
+	if i.Op == OpInvalid || (i.Illegal && !d.Illegal) || len(code) < int(i.Size()) {
+		return fmt.Sprintf(".byte $%02X", code[0]), 1
+	}
+	b, w := byte(0), uint16(0)
+	if len(code) > 1 {
+		b = code[1]
+		w = uint16(b)
+	}
+	if len(code) > 2 {
+		w |= uint16(code[2]) << 8
+	}
+
+	operand := ""
+	switch i.Mode {
+	case Accumulator:
+		operand = "A"
+	case Immediate:
+		operand = fmt.Sprintf("#$%02X", b)
+	case ZeroPage:
+		operand = d.zp(b)
+	case ZeroPageX:
+		operand = d.zp(b) + ",X"
+	case ZeroPageY:
+		operand = d.zp(b) + ",Y"
+	case Relative:
+		operand = d.abs(pc + 2 + uint16(int8(b)))
+	case Absolute:
+		operand = d.abs(w)
+	case AbsoluteX:
+		operand = d.abs(w) + ",X"
+	case AbsoluteY:
+		operand = d.abs(w) + ",Y"
+	case Indirect:
+		operand = "(" + d.abs(w) + ")"
+	case IndirectX:
+		operand = "(" + d.zp(b) + ",X)"
+	case IndirectY:
+		operand = "(" + d.zp(b) + "),Y"
+	case ZeroPageIndirect:
+		operand = "(" + d.zp(b) + ")"
+	case AbsoluteIndirectX:
+		operand = "(" + d.abs(w) + ",X)"
+	case ZeroPageIndirectZ:
+		operand = "(" + d.zp(b) + "),Z"
+	case StackIndirectY:
+		operand = fmt.Sprintf("($%02X,SP),Y", b)
+	case RelativeWord:
+		operand = d.abs(pc + 2 + w)
+	case ImmediateWord:
+		operand = fmt.Sprintf("#$%04X", w)
+	case ZeroPageRelative:
+		operand = d.zp(b) + "," + d.abs(pc+3+uint16(int8(w>>8)))
+	case ImmediateZeroPage:
+		operand = fmt.Sprintf("#$%02X,", b) + d.zp(code[2])
+	case ImmediateZeroPageX:
+		operand = fmt.Sprintf("#$%02X,", b) + d.zp(code[2]) + ",X"
+	case ImmediateAbsolute:
+		operand = fmt.Sprintf("#$%02X,", b) + d.abs(word(code[2:]))
+	case ImmediateAbsoluteX:
+		operand = fmt.Sprintf("#$%02X,", b) + d.abs(word(code[2:])) + ",X"
+	case BlockTransfer:
+		operand = d.abs(w) + "," + d.abs(word(code[3:])) + "," + fmt.Sprintf("$%04X", word(code[5:]))
+	}
+	mnemonic := i.Mnemonic()
+	switch i.Op {
+	case OpRMB, OpSMB, OpBBR, OpBBS:
+		mnemonic += string('0' + rune(code[0]>>4&0x07))
+	}
+	if operand == "" {
+		return mnemonic, int(i.Size())
+	}
+	return mnemonic + " " + operand, int(i.Size())
+}
+
+// Listing renders code located at pc, one instruction per line
+// prefixed with the address and the bytes of the instruction. A
+// labeled address is preceded by a line with the label.
+func (d Disassembler) Listing(pc uint16, code []byte) string {
+	sb := strings.Builder{}
+	for len(code) > 0 {
+		if d.Labels != nil {
+			if name, ok := d.Labels.Label(pc); ok {
+				fmt.Fprintf(&sb, "%s:\n", name)
+			}
+		}
+		asm, n := d.Decode(pc, code)
+		fmt.Fprintf(&sb, "%04X  %-9s %s\n", pc, fmt.Sprintf("% X", code[:n]), asm)
+		pc += uint16(n)
+		code = code[n:]
+	}
+	return sb.String()
+}
+
+// abs renders an absolute address, by its label if available.
+func (d Disassembler) abs(addr uint16) string {
+	if d.Labels != nil {
+		if name, ok := d.Labels.Label(addr); ok {
+			return name
+		}
+	}
+	return fmt.Sprintf("$%04X", addr)
+}
+
+// zp renders a zero page address, by its label if available.
+func (d Disassembler) zp(addr byte) string {
+	if d.Labels != nil {
+		if name, ok := d.Labels.Label(uint16(addr)); ok {
+			return name
+		}
+	}
+	return fmt.Sprintf("$%02X", addr)
+}
+
+// word returns the little endian word at the start of code.
+func word(code []byte) uint16 {
+	return uint16(code[1])<<8 | uint16(code[0])
+}
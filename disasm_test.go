@@ -0,0 +1,75 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+func TestDisassemblerDecode(t *testing.T) {
+	nmos := Disassembler{}
+	illegal := Disassembler{Illegal: true}
+	cmos := Disassembler{Variant: CMOS65C02}
+
+	tests := []struct {
+		d    Disassembler
+		code []byte
+		asm  string
+		size int
+	}{
+		{nmos, []byte{0xA9, 0x10}, "LDA #$10", 2},
+		{nmos, []byte{0x0A}, "ASL A", 1},
+		{nmos, []byte{0xD0, 0xFE}, "BNE $0200", 2},
+		{nmos, []byte{0x6C, 0x34, 0x12}, "JMP ($1234)", 3},
+		{nmos, []byte{0xB1, 0x80}, "LDA ($80),Y", 2},
+		{nmos, []byte{0x96, 0x80}, "STX $80,Y", 2},
+		{nmos, []byte{0xA7, 0x80}, ".byte $A7", 1},
+		{nmos, []byte{0x1A}, ".byte $1A", 1},
+		{nmos, []byte{0xAD, 0x34}, ".byte $AD", 1},
+		{illegal, []byte{0xA7, 0x80}, "LAX $80", 2},
+		{illegal, []byte{0x1C, 0x34, 0x12}, "NOP $1234,X", 3},
+		{cmos, []byte{0x80, 0x02}, "BRA $0204", 2},
+		{cmos, []byte{0xB2, 0x80}, "LDA ($80)", 2},
+		{cmos, []byte{0x7C, 0x34, 0x12}, "JMP ($1234,X)", 3},
+		{cmos, []byte{0x1A}, "INC A", 1},
+		{cmos, []byte{0x9C, 0x34, 0x12}, "STZ $1234", 3},
+		{cmos, []byte{0x03}, ".byte $03", 1},
+		{cmos, []byte{0x02, 0x10}, ".byte $02", 1},
+		{cmos, []byte{0xA7, 0x80}, "SMB2 $80", 2},
+		{cmos, []byte{0x1F, 0x80, 0xFD}, "BBR1 $80,$0200", 3},
+		{Disassembler{Variant: CMOS65C02, Illegal: true}, []byte{0xA3, 0x80}, ".byte $A3", 1},
+	}
+	for _, tt := range tests {
+		asm, size := tt.d.Decode(0x0200, tt.code)
+		if asm != tt.asm || size != tt.size {
+			t.Errorf("unexpected, got %q %d for % X", asm, size, tt.code)
+		}
+	}
+	if asm, size := nmos.Decode(0x0200, nil); asm != "" || size != 0 {
+		t.Error("unexpected")
+	}
+}
+
+func TestDisassemblerListing(t *testing.T) {
+	cpu := New(&memoryBus{})
+	cpu.SetVariant(CMOS65C02)
+
+	got := cpu.Disassembler().Listing(0x0200, []byte{0xA9, 0x10, 0xDA, 0x80, 0xFB, 0xFF})
+	want := "0200  A9 10     LDA #$10\n0202  DA        PHX\n0203  80 FB     BRA $0200\n0205  FF        .byte $FF\n"
+	if got != want {
+		t.Fatalf("unexpected, got\n%s", got)
+	}
+}
+
+func TestDecodeVariant(t *testing.T) {
+	for op := 0; op < 0x100; op++ {
+		i := DecodeVariant(CMOS65C02, byte(op))
+		if i.Illegal != (i.Op == OpInvalid) || i.Cycles == 0 {
+			t.Errorf("unexpected, got %s %s for %02X", i.Mnemonic(), i.Mode, op)
+		}
+		if DecodeVariant(NMOS6502, byte(op)) != Decode(byte(op)) {
+			t.Errorf("unexpected, got %02X", op)
+		}
+	}
+	if i := DecodeVariant(CMOS65C02, 0x5C); i.Size() != 3 || i.Cycles != 8 {
+		t.Error("unexpected")
+	}
+}
@@ -0,0 +1,84 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Dump writes the bytes at the addresses start to end (inclusive) of the
+// RAM in canonical hexdump format, 16 bytes per line followed by ASCII.
+func (r *RAM) Dump(w io.Writer, start, end uint16) error {
+	return dump(w, r.Read, start, end, false)
+}
+
+// String returns the hexdump of the whole RAM, repeated lines collapsed.
+func (r *RAM) String() string {
+	return dumpString(r.Read, len(r.data))
+}
+
+// Dump writes the bytes of the ROM like RAM.Dump().
+func (r *ROM) Dump(w io.Writer, start, end uint16) error {
+	return dump(w, r.Read, start, end, false)
+}
+
+// String returns the hexdump of the whole ROM like RAM.String().
+func (r *ROM) String() string {
+	return dumpString(r.Read, len(r.data))
+}
+
+func dumpString(read func(uint16) byte, size int) string {
+	sb := &strings.Builder{}
+	_ = dump(sb, read, 0, uint16(size-1), true)
+	return sb.String()
+}
+
+// dump writes the lines aligned to 16 bytes, addresses out of range are
+// blank. With squeeze, lines repeating the previous one are shown as "*".
+func dump(w io.Writer, read func(uint16) byte, start, end uint16, squeeze bool) error {
+	if end < start {
+		return fmt.Errorf("m6502: invalid range %04X-%04X", start, end)
+	}
+	bw := bufio.NewWriter(w)
+	line, prev := make([]byte, 0, 80), []byte(nil)
+	skipped := false
+
+	for row := int(start) &^ 0xF; row <= int(end); row += 16 {
+		line = line[:0]
+		ascii := [16]byte{}
+
+		for i := 0; i < 16; i++ {
+			if i == 8 {
+				line = append(line, ' ')
+			}
+			addr := row + i
+			if addr < int(start) || addr > int(end) {
+				line, ascii[i] = append(line, "   "...), ' '
+				continue
+			}
+			b := read(uint16(addr))
+			line = append(line, fmt.Sprintf(" %02X", b)...)
+			if ascii[i] = '.'; b >= 0x20 && b < 0x7F {
+				ascii[i] = b
+			}
+		}
+		line = append(line, "  |"...)
+		line = append(line, bytes.TrimRight(ascii[:], " ")...)
+		line = append(line, '|')
+
+		if squeeze && prev != nil && bytes.Equal(line, prev) && row+16 <= int(end) {
+			if !skipped {
+				bw.WriteString("*\n")
+			}
+			skipped = true
+			continue
+		}
+		skipped, prev = false, append(prev[:0], line...)
+		fmt.Fprintf(bw, "%04X %s\n", row, line)
+	}
+	return bw.Flush()
+}
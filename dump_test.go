@@ -0,0 +1,45 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	ram := NewRAM(0x40)
+	ram.Load(0x12, []byte("Hello, 6502!\x00\xFF"))
+
+	sb := &strings.Builder{}
+	if err := ram.Dump(sb, 0x14, 0x21); err != nil {
+		t.Fatal(err)
+	}
+	want := "" +
+		"0010              6C 6C 6F 2C  20 36 35 30 32 21 00 FF  |    llo, 6502!..|\n" +
+		"0020  00 00                                             |..|\n"
+	if sb.String() != want {
+		t.Fatalf("unexpected, got\n%s", sb)
+	}
+	if err := ram.Dump(sb, 2, 1); err == nil {
+		t.Fatal("unexpected")
+	}
+}
+
+func TestDumpString(t *testing.T) {
+	want := "" +
+		"0000  00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00  |................|\n" +
+		"*\n" +
+		"0030  00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00  |................|\n"
+	if s := NewRAM(0x40).String(); s != want {
+		t.Fatalf("unexpected, got\n%s", s)
+	}
+
+	rom := NewROM([]byte{0x41, 0x42, 0x43})
+	if s := rom.String(); s != "0000  41 42 43"+strings.Repeat(" ", 42)+"|ABC|\n" {
+		t.Fatalf("unexpected, got %q", s)
+	}
+	if s := NewRAM(0x10000).String(); strings.Count(s, "\n") != 3 {
+		t.Fatalf("unexpected, got\n%s", s)
+	}
+}
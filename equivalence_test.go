@@ -0,0 +1,102 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+// FuzzAccuracyEquivalence runs random programs under AccuracyMinimal and
+// AccuracyCycle and asserts, that the architectural results are identical.
+// Only the timing may differ between the modes. Each program receives an
+// IRQ or NMI at a random cycle, see inject. Failures report the seed.
+func FuzzAccuracyEquivalence(f *testing.F) {
+	for seed := int64(0); seed < 64; seed++ {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, seed int64) {
+		equivalenceRun(t, seed, 1000)
+	})
+}
+
+// inject asserts an IRQ or NMI at a random cycle, during the opcode fetch
+// of an instruction both modes service it after: not BRK, and for IRQ
+// neither one changing the I flag nor one with the I flag set.
+type inject struct {
+	at   uint64 // Earliest cycle of the assertion
+	nmi  bool   // NMI instead of IRQ
+	done bool   // Asserted
+}
+
+func (in *inject) hook(cpu *CPU, t Trace) bool {
+	if in.done || t.Cycles < in.at || t.Opcode == 0x00 {
+		return false
+	}
+	switch {
+	case in.nmi:
+		cpu.AssertNMI()
+	case t.P&byte(flagI) != 0:
+		return false
+	case t.Opcode == 0x28, t.Opcode == 0x40, t.Opcode == 0x58, t.Opcode == 0x78:
+		return false // PLP, RTI, CLI, SEI
+	default:
+		cpu.AssertIRQ()
+	}
+	in.done = true
+	return false
+}
+
+func equivalenceRun(t *testing.T, seed int64, steps int) {
+	t.Helper()
+
+	rnd := rand.New(rand.NewSource(seed))
+	image := &memoryBus{}
+	_, _ = rnd.Read(image.mem[:])
+	for i, op := range image.mem {
+		if d := nmos[op]; d.Illegal || d.Op == OpInvalid {
+			image.mem[i] = 0xEA // NOP, performing documented op codes only
+		}
+	}
+
+	busA, busB := &memoryBus{}, &memoryBus{}
+	busA.mem, busB.mem = image.mem, image.mem
+
+	a, b := New(busA), New(busB)
+	a.SetAccuracy(AccuracyMinimal)
+	b.SetAccuracy(AccuracyCycle)
+
+	at, nmi := uint64(rnd.Intn(2000)), rnd.Intn(2) == 0
+	inA, inB := &inject{at: at, nmi: nmi}, &inject{at: at, nmi: nmi}
+	a.AddHook(PriorityDefault, inA.hook)
+	b.AddHook(PriorityDefault, inB.hook)
+	released := false
+
+	for i := 0; i < steps; i++ {
+		asserted := inA.done
+		_, errA := a.Step()
+		_, errB := b.Step()
+
+		if (errA == nil) != (errB == nil) || errA != nil && errA.Error() != errB.Error() {
+			t.Fatalf("seed %d, step %d: errors differ: %v, %v", seed, i, errA, errB)
+		}
+		if a.String() != b.String() || a.p != b.p || inA.done != inB.done {
+			t.Fatalf("seed %d, step %d: state differs:\n%s\n%s", seed, i, a, b)
+		}
+		if errA != nil {
+			break
+		}
+		// Released after the step servicing the interrupt.
+		if asserted && !released {
+			a.ReleaseIRQ()
+			b.ReleaseIRQ()
+			a.ReleaseNMI()
+			b.ReleaseNMI()
+			released = true
+		}
+	}
+	if !bytes.Equal(busA.mem[:], busB.mem[:]) {
+		t.Fatalf("seed %d: memory differs", seed)
+	}
+}
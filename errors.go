@@ -0,0 +1,62 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// OpcodeError is returned from Step() when the op code at PC is not
+	// implemented for the processor variant.
+	OpcodeError struct {
+		PC     uint16 // Address of the op code
+		Opcode byte   // Invalid op code
+	}
+
+	// HaltError is returned from Step() when the CPU has been halted by HLT,
+	// or stopped by STP. It wraps ErrHalted respectively ErrStopped, test
+	// with errors.Is().
+	HaltError struct {
+		PC  uint16 // Address of the halting instruction
+		Err error  // ErrHalted or ErrStopped
+	}
+
+	// PanicError is returned from Step() when the Bus panicked. Errors of a
+	// FallibleBus are returned as *BusError instead.
+	PanicError struct {
+		PC     uint16 // Address of the instruction or interrupted address
+		Opcode byte   // Op code, 0x00 when fetching it or servicing an interrupt
+		Addr   uint16 // Address of the access panicking
+		Write  bool   // Access panicking was a write
+		Value  any    // Value passed to panic()
+	}
+)
+
+func (e *OpcodeError) Error() string {
+	return fmt.Sprintf("m6502: invalid op code: %04X: %02X", e.PC, e.Opcode)
+}
+
+func (e *HaltError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *HaltError) Unwrap() error {
+	return e.Err
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("m6502: %04X: %s", e.PC, strings.TrimPrefix(fmt.Sprint(e.Value), "m6502: "))
+}
+
+// Unwrap returns the panic value when it is an error, e.g. a runtime.Error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// halt halts the CPU after the instruction in progress.
+func (cpu *CPU) halt(err error) {
+	cpu.error = &HaltError{PC: cpu.at, Err: err}
+}
@@ -0,0 +1,128 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+)
+
+func TestOpcodeError(t *testing.T) {
+	bus := &memoryBus{}
+	bus.mem[0x1234] = 0x03
+	cpu := New(bus)
+	cpu.PC(0x34, 0x12)
+
+	_, err := cpu.Step()
+	e := &OpcodeError{}
+	if !errors.As(err, &e) || e.PC != 0x1234 || e.Opcode != 0x03 {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	if err.Error() != "m6502: invalid op code: 1234: 03" {
+		t.Fatalf("unexpected, got %s", err)
+	}
+}
+
+func TestHaltError(t *testing.T) {
+	bus := &memoryBus{}
+	bus.mem[0x1234] = 0xEA // NOP
+	bus.mem[0x1235] = 0x02 // HLT
+	bus.mem[0x2000] = 0xDB // STP
+	cpu := New(bus)
+	cpu.PC(0x34, 0x12)
+	if _, err := cpu.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := cpu.Step()
+		e := &HaltError{}
+		if !errors.As(err, &e) || e.PC != 0x1235 || !errors.Is(err, ErrHalted) {
+			t.Fatalf("unexpected, got %v", err)
+		}
+	}
+	cpu.SetVariant(CMOS65C02)
+	cpu.Resume()
+	cpu.PC(0x00, 0x20)
+
+	_, err := cpu.Step()
+	e := &HaltError{}
+	if !errors.As(err, &e) || e.PC != 0x2000 || !errors.Is(err, ErrStopped) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
+
+// valueBus panics with the value on access of the address.
+type valueBus struct {
+	memoryBus
+	addr  uint16
+	value any
+}
+
+func (b *valueBus) Read(l, h byte) byte {
+	if uint16(h)<<8|uint16(l) == b.addr {
+		panic(b.value)
+	}
+	return b.memoryBus.Read(l, h)
+}
+
+func (b *valueBus) Write(l, h, db byte) {
+	if uint16(h)<<8|uint16(l) == b.addr {
+		panic(b.value)
+	}
+	b.memoryBus.Write(l, h, db)
+}
+
+func TestPanicError(t *testing.T) {
+	errFoo := errors.New("foo")
+
+	for i, tt := range []struct {
+		code  []byte
+		addr  uint16
+		value any
+		write bool
+		msg   string
+	}{
+		{[]byte{0xAD, 0x34, 0x12}, 0x1234, errFoo, false, "m6502: 0200: foo"},
+		{[]byte{0x8D, 0x34, 0x12}, 0x1234, 42, true, "m6502: 0200: 42"},
+		{[]byte{0xEE, 0x34, 0x12}, 0x1234, "m6502: bar", false, "m6502: 0200: bar"},
+		{[]byte{0x20, 0x34, 0x12}, 0x01FF, struct{}{}, true, "m6502: 0200: {}"},
+	} {
+		bus := &valueBus{addr: tt.addr, value: tt.value}
+		copy(bus.mem[0x0200:], tt.code)
+		cpu := New(bus)
+		cpu.PC(0x00, 0x02)
+
+		_, err := cpu.Step()
+		e := &PanicError{}
+		if !errors.As(err, &e) || e.PC != 0x0200 || e.Addr != tt.addr || e.Write != tt.write {
+			t.Fatalf("unexpected, got %v in %d", err, i)
+		}
+		if e.Opcode != tt.code[0] {
+			t.Fatalf("unexpected, got %02X in %d", e.Opcode, i)
+		}
+		if err.Error() != tt.msg {
+			t.Fatalf("unexpected, got %s in %d", err, i)
+		}
+	}
+}
+
+func TestPanicErrorUnwrap(t *testing.T) {
+	bus := &valueBus{addr: 0x1234}
+	bus.value = func() (r any) {
+		defer func() { r = recover() }()
+		var s []byte
+		_ = s[len(bus.mem)]
+		return nil
+	}()
+	copy(bus.mem[0x0200:], []byte{0xAD, 0x34, 0x12})
+	cpu := New(bus)
+	cpu.PC(0x00, 0x02)
+
+	_, err := cpu.Step()
+	var re runtime.Error
+	if !errors.As(err, &re) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "strings"
+
+type (
+	// Event is an element of the educational event stream, see SetEvents().
+	// The meaning of the fields depends on the Kind:
+	//
+	//	EventInterrupt  Text: "NMI" or "IRQ"
+	//	EventFetch      Data: op code, Instruction
+	//	EventOperand    Text: operand, Addr: effective address (when in memory)
+	//	EventRead       Addr, Data
+	//	EventWrite      Addr, Data
+	//	EventALU        Instruction, Before: input, Data: operand, After: result
+	//	EventRegister   Text: "A", "X", "Y" or "S", Before, After
+	//	EventFlags      Before, After: processor status
+	Event struct {
+		Kind        EventKind
+		PC          uint16 // Address of the instruction or the interrupted address
+		Instruction Instruction
+		Text        string
+		Addr        uint16
+		Data        byte
+		Before      byte
+		After       byte
+	}
+
+	// EventKind enumerates the kinds of Event.
+	EventKind byte
+
+	regs struct {
+		a, x, y, s, p byte
+		pc            uint16
+	}
+)
+
+// Kinds of Event.
+const (
+	EventInterrupt EventKind = iota + 1 // Interrupt sequence entered
+	EventFetch                          // Instruction fetched
+	EventOperand                        // Operand decoded
+	EventRead                           // Memory read, incl. stack and vectors
+	EventWrite                          // Memory write, incl. stack
+	EventALU                            // Arithmetic, logic, compare, shift
+	EventRegister                       // Register changed
+	EventFlags                          // Processor status changed
+)
+
+// SetEvents registers a function receiving the educational event stream:
+// after each Step(), the events describing the instruction, its memory
+// accesses, the ALU operation and the resulting state changes are emitted
+// in this order. The stream is meant for teaching tools and visualizers,
+// it is derived from the micro-operations and costs accordingly. Passing
+// nil removes the function.
+func (cpu *CPU) SetEvents(fn func(Event)) {
+	cpu.events = fn
+	cpu.micro.on = cpu.micro.user || fn != nil || cpu.capture != nil
+	cpu.track()
+}
+
+// String returns the name of the event kind.
+func (k EventKind) String() string {
+	if int(k) < len(eventKinds) && eventKinds[k] != "" {
+		return eventKinds[k]
+	}
+	return "unknown"
+}
+
+func (cpu *CPU) regs() regs {
+	return regs{cpu.a, cpu.x, cpu.y, cpu.s, byte(cpu.p | flagU), cpu.pc()}
+}
+
+func (cpu *CPU) emit(r regs) {
+	ops, fn := cpu.micro.ops, cpu.events
+	if len(ops) == 0 {
+		return
+	}
+
+	i := Instruction{}
+	switch ops[0].Kind {
+	case MicroOpcode:
+		code := []byte{}
+		for _, m := range ops {
+			if m.Kind == MicroOpcode || m.Kind == MicroOperand {
+				code = append(code, m.Data)
+			}
+		}
+		i = DecodeVariant(cpu.variant, code[0])
+		fn(Event{Kind: EventFetch, PC: r.pc, Instruction: i, Data: code[0]})
+
+		if i.Size() > 1 {
+			asm, _ := Disassembler{Variant: cpu.variant, Illegal: true}.Decode(r.pc, code)
+			e := Event{Kind: EventOperand, PC: r.pc, Instruction: i}
+			if _, e.Text, _ = strings.Cut(asm, " "); i.Mode != Immediate && i.Mode != Relative {
+				e.Addr = dataAddr(ops)
+			}
+			fn(e)
+		}
+	default:
+		e := Event{Kind: EventInterrupt, PC: r.pc, Text: "IRQ"}
+		if cpu.micro.nmi {
+			e.Text = "NMI"
+		}
+		fn(e)
+	}
+
+	in, out := byte(0), byte(0)
+	for _, m := range ops {
+		switch m.Kind {
+		case MicroRead, MicroPull, MicroVector:
+			in = m.Data
+			fn(Event{Kind: EventRead, PC: r.pc, Addr: m.Addr, Data: m.Data})
+		case MicroWrite, MicroPush:
+			out = m.Data
+			fn(Event{Kind: EventWrite, PC: r.pc, Addr: m.Addr, Data: m.Data})
+		}
+	}
+
+	if e, ok := cpu.alu(r, i, in, out); ok {
+		fn(e)
+	}
+	for _, c := range []struct {
+		name          string
+		before, after byte
+	}{{"A", r.a, cpu.a}, {"X", r.x, cpu.x}, {"Y", r.y, cpu.y}, {"S", r.s, cpu.s}} {
+		if c.before != c.after {
+			fn(Event{Kind: EventRegister, PC: r.pc, Text: c.name, Before: c.before, After: c.after})
+		}
+	}
+	if p := byte(cpu.p | flagU); p != r.p {
+		fn(Event{Kind: EventFlags, PC: r.pc, Before: r.p, After: p})
+	}
+}
+
+// alu describes the ALU operation of the instruction, given the registers
+// before, the last value read from and the last value written to memory.
+func (cpu *CPU) alu(r regs, i Instruction, in, out byte) (Event, bool) {
+	e := Event{Kind: EventALU, PC: r.pc, Instruction: i}
+
+	switch i.Op {
+	case OpADC, OpSBC, OpAND, OpORA, OpEOR:
+		e.Before, e.Data, e.After = r.a, in, cpu.a
+	case OpCMP:
+		e.Before, e.Data, e.After = r.a, in, r.a-in
+	case OpCPX:
+		e.Before, e.Data, e.After = r.x, in, r.x-in
+	case OpCPY:
+		e.Before, e.Data, e.After = r.y, in, r.y-in
+	case OpBIT:
+		e.Before, e.Data, e.After = r.a, in, r.a&in
+	case OpASL, OpLSR, OpROL, OpROR, OpINC, OpDEC:
+		e.Before, e.After = in, out
+		if i.Mode == Accumulator {
+			e.Before, e.After = r.a, cpu.a
+		}
+	case OpINX, OpDEX:
+		e.Before, e.After = r.x, cpu.x
+	case OpINY, OpDEY:
+		e.Before, e.After = r.y, cpu.y
+	default:
+		return e, false
+	}
+	return e, true
+}
+
+// dataAddr returns the address of the last data access, the effective
+// address, which follows the pointer reads of the indirect modes.
+func dataAddr(ops []MicroOp) uint16 {
+	for n := len(ops) - 1; n >= 0; n-- {
+		if k := ops[n].Kind; k == MicroRead || k == MicroWrite {
+			return ops[n].Addr
+		}
+	}
+	return 0
+}
+
+var eventKinds = [...]string{
+	EventInterrupt: "interrupt",
+	EventFetch:     "fetch",
+	EventOperand:   "operand",
+	EventRead:      "read",
+	EventWrite:     "write",
+	EventALU:       "alu",
+	EventRegister:  "register",
+	EventFlags:     "flags",
+}
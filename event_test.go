@@ -0,0 +1,65 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestEvents(t *testing.T) {
+	// ADC ($80),Y, NMI
+	cpu, bus := newTestCPU(NMOS6502, 0x71, 0x80)
+	bus.mem[0x0080], bus.mem[0x0081] = 0x00, 0x12
+	bus.mem[0x1201] = 0x05
+	cpu.a, cpu.y = 0x10, 0x01
+
+	log := []string{}
+	cpu.SetEvents(func(e Event) {
+		log = append(log, fmt.Sprintf("%s:%s:%s:%04X:%02X:%02X:%02X",
+			e.Kind, e.Instruction.Mnemonic(), e.Text, e.Addr, e.Data, e.Before, e.After))
+	})
+	if _, err := cpu.Step(); err != nil {
+		t.Fatal(err)
+	}
+	want := "fetch:ADC::0000:71:00:00 operand:ADC:($80),Y:1201:00:00:00 " +
+		"read:???::0080:00:00:00 read:???::0081:12:00:00 read:???::1201:05:00:00 " +
+		"alu:ADC::0000:05:10:15 register:???:A:0000:00:10:15"
+	if got := strings.Join(log, " "); got != want {
+		t.Fatalf("unexpected, got %s", got)
+	}
+
+	log = log[:0]
+	cpu.AssertNMI()
+	if _, err := cpu.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if log[0] != "interrupt:???:NMI:0000:00:00:00" || len(log) != 8 {
+		t.Fatalf("unexpected, got %s", log)
+	}
+	if log[7] != "flags:???::0000:00:20:24" || log[6] != "register:???:S:0000:00:FF:FC" {
+		t.Fatalf("unexpected, got %s", log)
+	}
+
+	// Relocated vectors, where 0xFFFB is the IRQ vector.
+	log = log[:0]
+	cpu.SetVectors(Vectors{NMI: 0x03F0, Reset: 0x03F2, IRQ: 0xFFFA, BRK: 0xFFFA})
+	cpu.PC(0x00, 0x02)
+	cpu.p &^= flagI
+	cpu.AssertIRQ()
+	if _, err := cpu.Step(); err != nil || log[0] != "interrupt:???:IRQ:0000:00:00:00" {
+		t.Fatalf("unexpected, got %v %s", err, log)
+	}
+	log = log[:0]
+	cpu.ReleaseNMI()
+	cpu.AssertNMI()
+	if _, err := cpu.Step(); err != nil || log[0] != "interrupt:???:NMI:0000:00:00:00" {
+		t.Fatalf("unexpected, got %v %s", err, log)
+	}
+
+	cpu.SetEvents(nil)
+	if cpu.micro.on || EventALU.String() != "alu" || EventKind(0).String() != "unknown" {
+		t.Fatal("unexpected")
+	}
+}
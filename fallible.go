@@ -0,0 +1,81 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "fmt"
+
+type (
+	// FallibleBus is a Bus reporting access failures, e.g. reading from
+	// unmapped memory, as errors instead of panics. Use NewFallible() to
+	// connect it. A failure aborts the current instruction: the registers
+	// are restored, the program counter points to the instruction, the
+	// accesses following the failure are skipped and Step() returns a
+	// *BusError wrapping the error.
+	FallibleBus interface {
+
+		// Read reads a byte from address space.
+		Read(lo, hi byte) (byte, error)
+
+		// Write writes a byte to address space.
+		Write(lo, hi, db byte) error
+	}
+
+	// BusError is returned from Step() when an access to a FallibleBus failed.
+	BusError struct {
+		PC    uint16 // Address of the instruction performing the access
+		Addr  uint16 // Address of the failed access
+		Write bool   // Failed access was a write
+		Err   error  // Error returned by the FallibleBus
+	}
+
+	// fallible connects a FallibleBus, a failure ends the instruction
+	// by the fail field of the CPU, like an invalid op code does.
+	fallible struct {
+		FallibleBus
+		cpu *CPU
+	}
+)
+
+// NewFallible creates a new 6502 CPU connected to a FallibleBus. Unlike with
+// New(), a failure reading the Reset Vector is returned as error.
+func NewFallible(bus FallibleBus, opts ...Option) (*CPU, error) {
+	b := &fallible{FallibleBus: bus}
+	cpu := New(b, append([]Option{func(cpu *CPU) { b.cpu, cpu.fallible = cpu, true }}, opts...)...)
+	if cpu.fail != nil {
+		return nil, cpu.fail
+	}
+	return cpu, nil
+}
+
+func (e *BusError) Error() string {
+	access := "read"
+	if e.Write {
+		access = "write"
+	}
+	return fmt.Sprintf("m6502: %04X: bus %s %04X: %v", e.PC, access, e.Addr, e.Err)
+}
+
+// Unwrap returns the error of the FallibleBus.
+func (e *BusError) Unwrap() error {
+	return e.Err
+}
+
+func (b *fallible) Read(l, h byte) byte {
+	if b.cpu.fail != nil {
+		return 0x00
+	}
+	db, err := b.FallibleBus.Read(l, h)
+	if err != nil {
+		b.cpu.fail = &BusError{PC: b.cpu.at, Addr: uint16(h)<<8 | uint16(l), Err: err}
+	}
+	return db
+}
+
+func (b *fallible) Write(l, h, db byte) {
+	if b.cpu.fail != nil {
+		return
+	}
+	if err := b.FallibleBus.Write(l, h, db); err != nil {
+		b.cpu.fail = &BusError{PC: b.cpu.at, Addr: uint16(h)<<8 | uint16(l), Write: true, Err: err}
+	}
+}
@@ -0,0 +1,122 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"errors"
+	"testing"
+)
+
+var errUnmapped = errors.New("unmapped")
+
+// partialBus maps the lower 32K and the vectors only.
+type partialBus struct{ memoryBus }
+
+func (b *partialBus) Read(l, h byte) (byte, error) {
+	if h >= 0x80 && h != 0xFF {
+		return 0, errUnmapped
+	}
+	return b.memoryBus.Read(l, h), nil
+}
+
+func (b *partialBus) Write(l, h, db byte) error {
+	if h >= 0x80 {
+		return errUnmapped
+	}
+	b.memoryBus.Write(l, h, db)
+	return nil
+}
+
+func TestFallibleBus(t *testing.T) {
+	bus := &partialBus{}
+	bus.mem[0xFFFC], bus.mem[0xFFFD] = 0x00, 0x02
+	copy(bus.mem[0x0200:], []byte{0xAD, 0x34, 0x12, 0x8D, 0x00, 0x90}) // LDA $1234, STA $9000
+
+	cpu, err := NewFallible(bus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cpu.Step(); err != nil {
+		t.Fatal(err)
+	}
+	cycles, err := cpu.Step()
+
+	var e *BusError
+	if !errors.As(err, &e) || cycles != 0 || e.PC != 0x0203 || e.Addr != 0x9000 || !e.Write {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	if !errors.Is(err, errUnmapped) || err.Error() != "m6502: 0203: bus write 9000: unmapped" {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
+
+func TestNewFallible(t *testing.T) {
+	bus := &partialBus{}
+
+	if _, err := NewFallible(bus); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFallible(unmappedBus{}); !errors.Is(err, errUnmapped) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
+
+func TestFallibleAbort(t *testing.T) {
+	bus := &partialBus{}
+	bus.mem[0xFFFC], bus.mem[0xFFFD] = 0x00, 0x02
+	copy(bus.mem[0x0200:], []byte{0xA9, 0x42, 0xAD, 0x00, 0x90, 0x20, 0x00, 0x90}) // LDA #$42, LDA $9000, JSR $9000
+
+	cpu, err := NewFallible(bus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cpu.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cpu.Step(); !errors.Is(err, errUnmapped) || cpu.a != 0x42 || cpu.pc() != 0x0202 {
+		t.Fatalf("unexpected, got %v %s", err, cpu)
+	}
+
+	// Failed op code fetch after JSR.
+	cpu.PC(0x05, 0x02)
+	if _, err = cpu.Step(); err != nil {
+		t.Fatal(err)
+	}
+	s := cpu.s
+	if _, err = cpu.Step(); !errors.Is(err, errUnmapped) || cpu.s != s || cpu.pc() != 0x9000 {
+		t.Fatalf("unexpected, got %v %s", err, cpu)
+	}
+	var e *BusError
+	if !errors.As(err, &e) || e.PC != 0x9000 || e.Addr != 0x9000 || e.Write {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
+
+type unmappedBus struct{}
+
+func (unmappedBus) Read(_, _ byte) (byte, error) { return 0, errUnmapped }
+func (unmappedBus) Write(_, _, _ byte) error     { return errUnmapped }
+
+func TestInterruptFault(t *testing.T) {
+	bus := &partialBus{}
+	bus.mem[0xFFFC], bus.mem[0xFFFD] = 0x00, 0x90
+
+	cpu, err := NewFallible(bus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cpu.bus = &fallible{FallibleBus: unmappedBus{}, cpu: cpu}
+	cycles, err := cpu.NMI()
+
+	var e *BusError
+	if !errors.As(err, &e) || cycles != 0 || e.PC != 0x9000 || e.Addr != 0x01FF || !e.Write {
+		t.Fatalf("unexpected, got %v", err)
+	}
+
+	// Legacy Bus panicking on the stack page.
+	m := NewMapper().Map(0xFF00, 0xFFFF, NewRAM(0x100))
+	cpu = New(m)
+	if _, err = cpu.IRQ(); err == nil || err.Error() != "m6502: 0000: unmapped write 01FF" {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"fmt"
+	"io"
+)
+
+// Format implements fmt.Formatter: %v and %s print the one-liner of
+// String(), %+v a multi-line dump of the state including the interrupt
+// lines, the cycle counters and the used part of the stack page. The
+// stack is read from the bus, beware of devices mapped to page 0x01.
+func (cpu *CPU) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('+') {
+		cpu.dump(f)
+		return
+	}
+	fmt.Fprintf(f, fmt.FormatString(f, verb), cpu.String())
+}
+
+func (cpu *CPU) dump(w io.Writer) {
+	yes := func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return "no"
+	}
+	fmt.Fprintf(w, "%s\n", cpu)
+	fmt.Fprintf(w, "  variant: %s\n", cpu.variant)
+	fmt.Fprintf(w, "  cycles:  %d total, %d last\n", cpu.total, cpu.cycles)
+	fmt.Fprintf(w, "  irq:     %s\n", yes(cpu.irq))
+	fmt.Fprintf(w, "  nmi:     %s, edge latched: %s\n", yes(cpu.nmi), yes(cpu.nmiEdge))
+	fmt.Fprintf(w, "  waiting: %s\n", yes(cpu.waiting))
+	if cpu.error != nil {
+		fmt.Fprintf(w, "  halted:  %v\n", cpu.error)
+	} else {
+		fmt.Fprintf(w, "  halted:  no\n")
+	}
+	fmt.Fprintf(w, "  stack:")
+	for a := int(cpu.s) + 1; a <= 0xFF; a++ {
+		if a == int(cpu.s)+1 || a%0x10 == 0 {
+			fmt.Fprintf(w, "\n    %04X ", 0x0100|a)
+		}
+		fmt.Fprintf(w, " %02X", cpu.bus.Read(byte(a), 0x01))
+	}
+	fmt.Fprintf(w, "\n")
+}
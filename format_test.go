@@ -0,0 +1,48 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	bus := &memoryBus{}
+	copy(bus.mem[0x0200:], []byte{0x20, 0x00, 0x03}) // JSR $0300
+	bus.mem[0x0300] = 0x02                           // HLT
+	cpu := New(bus)
+	cpu.PC(0x00, 0x02)
+	if _, err := cpu.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cpu.Step(); !errors.Is(err, ErrHalted) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	cpu.AssertIRQ()
+
+	if s := fmt.Sprintf("%v|%s|%q", cpu, cpu, cpu); s != `m6502: PC=0301 A=00 X=00 Y=00 [------] S=FD|`+
+		`m6502: PC=0301 A=00 X=00 Y=00 [------] S=FD|"m6502: PC=0301 A=00 X=00 Y=00 [------] S=FD"` {
+		t.Fatalf("unexpected, got %s", s)
+	}
+	want := "m6502: PC=0301 A=00 X=00 Y=00 [------] S=FD\n" +
+		"  variant: 6502\n" +
+		"  cycles:  13 total, 1 last\n" +
+		"  irq:     yes\n" +
+		"  nmi:     no, edge latched: no\n" +
+		"  waiting: no\n" +
+		"  halted:  CPU halted\n" +
+		"  stack:\n" +
+		"    01FE  02 02\n"
+	if s := fmt.Sprintf("%+v", cpu); s != want {
+		t.Fatalf("unexpected, got\n%s", s)
+	}
+	cpu.s = 0xEE
+	if s := fmt.Sprintf("%+v", cpu); !strings.HasSuffix(s, "stack:\n"+
+		"    01EF  00\n"+
+		"    01F0  00 00 00 00 00 00 00 00 00 00 00 00 00 00 02 02\n") {
+		t.Fatalf("unexpected, got\n%s", s)
+	}
+}
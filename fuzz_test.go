@@ -0,0 +1,58 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"errors"
+	"testing"
+)
+
+// FuzzStep performs arbitrary op code streams on arbitrary memory and
+// checks the invariants of each Step(): 2 to 8 cycles per instruction,
+// 7 per interrupt, errors only for halting or invalid op codes, and the
+// B and U flags never held in the processor status, but pushed as 1.
+func FuzzStep(f *testing.F) {
+	f.Add(byte(0), []byte{0xA9, 0x01, 0x69, 0xFF, 0x08, 0x28}, []byte{0x00})
+	f.Add(byte(1), []byte{0x00, 0x00, 0x40}, []byte{0xFF, 0x02})
+	f.Add(byte(2), []byte{0xF8, 0x69, 0x99, 0xE9, 0x01, 0x6C, 0xFF, 0x02}, []byte{0x10})
+
+	f.Fuzz(func(t *testing.T, variant byte, code, data []byte) {
+		bus := &memoryBus{}
+		for i := range bus.mem {
+			if len(data) > 0 {
+				bus.mem[i] = data[i%len(data)]
+			}
+		}
+		copy(bus.mem[0x0200:], code)
+
+		cpu := New(bus)
+		cpu.SetVariant(Variant(variant % 3))
+		cpu.PC(0x00, 0x02)
+
+		for i := 0; i < 64; i++ {
+			pc, s := cpu.pc(), cpu.s
+			cycles, err := cpu.Step()
+			if err != nil {
+				if !errors.Is(err, ErrHalted) && !errors.Is(err, ErrStopped) &&
+					!errors.As(err, new(*OpcodeError)) {
+					t.Fatalf("unexpected error at %04X: %s", pc, err)
+				}
+				return
+			}
+			if cpu.Waiting() {
+				return
+			}
+			if cycles < 2 || cycles > 8 {
+				t.Fatalf("unexpected, got %d cycles at %04X", cycles, pc)
+			}
+			if cpu.p&(flagB|flagU) != 0 {
+				t.Fatalf("unexpected, got P=%02X at %04X", byte(cpu.p), pc)
+			}
+			if cpu.s == s-3 || cpu.s == s-1 && bus.mem[pc] == 0x08 {
+				if p := bus.mem[0x0100|uint16(cpu.s+1)]; p&byte(flagU) == 0 {
+					t.Fatalf("unexpected, pushed P=%02X at %04X", p, pc)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,123 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+type (
+	// Histogram counts the performed instructions per op code, e.g. to
+	// find the hot spots of emulated code or of the emulator itself.
+	Histogram struct {
+		counts  [0x100]uint64
+		variant Variant
+	}
+
+	// Stats is the evaluation of a Histogram, see Stats().
+	Stats struct {
+		Total     uint64  // Instructions counted
+		Opcodes   []Count // Per op code, e.g. "A9 LDA immediate"
+		Mnemonics []Count // Per mnemonic, e.g. "LDA"
+		Modes     []Count // Per addressing mode, e.g. "immediate"
+	}
+
+	// Count is the number of instructions of a category.
+	Count struct {
+		Name string
+		N    uint64
+	}
+)
+
+// NewHistogram creates an empty Histogram.
+func NewHistogram() *Histogram {
+	return &Histogram{}
+}
+
+// Attach counts the instructions of the CPU by a Hook with PriorityProfiler,
+// decoded for the Variant of the CPU. The returned function detaches the
+// Histogram.
+func (h *Histogram) Attach(cpu *CPU) (detach func()) {
+	h.variant = cpu.variant
+	return cpu.AddHook(PriorityProfiler, func(_ *CPU, t Trace) bool {
+		h.counts[t.Opcode]++
+		return false
+	})
+}
+
+// Count returns the number of performed instructions of the op code.
+func (h *Histogram) Count(op byte) uint64 {
+	return h.counts[op]
+}
+
+// Reset clears the counters.
+func (h *Histogram) Reset() {
+	h.counts = [0x100]uint64{}
+}
+
+// Stats evaluates the counters. The categories are sorted by descending
+// count, categories without instructions are omitted.
+func (h *Histogram) Stats() Stats {
+	s := Stats{}
+	mnemonics, modes := map[string]uint64{}, map[string]uint64{}
+
+	for op, n := range h.counts {
+		if n == 0 {
+			continue
+		}
+		i := DecodeVariant(h.variant, byte(op))
+		s.Total += n
+		s.Opcodes = append(s.Opcodes, Count{fmt.Sprintf("%02X %s %s", op, i.Mnemonic(), i.Mode), n})
+		mnemonics[i.Mnemonic()] += n
+		modes[i.Mode.String()] += n
+	}
+	s.Mnemonics, s.Modes = counts(mnemonics), counts(modes)
+	sortCounts(s.Opcodes)
+	return s
+}
+
+// Report writes the n most performed op codes, all when n <= 0, followed
+// by the distribution of the addressing modes, with their share in percent:
+//
+//	total 1000
+//	  500  50.0%  E8 INX implied
+//	  ...
+//	  700  70.0%  implied
+func (s Stats) Report(w io.Writer, n int) error {
+	if _, err := fmt.Fprintf(w, "total %d\n", s.Total); err != nil {
+		return err
+	}
+	top := s.Opcodes
+	if n > 0 && n < len(top) {
+		top = top[:n]
+	}
+	for _, list := range [][]Count{top, s.Modes} {
+		for _, c := range list {
+			share := 100 * float64(c.N) / float64(s.Total)
+			if _, err := fmt.Fprintf(w, "%10d %5.1f%%  %s\n", c.N, share, c.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func counts(m map[string]uint64) []Count {
+	list := make([]Count, 0, len(m))
+	for name, n := range m {
+		list = append(list, Count{name, n})
+	}
+	sortCounts(list)
+	return list
+}
+
+func sortCounts(list []Count) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].N != list[j].N {
+			return list[i].N > list[j].N
+		}
+		return list[i].Name < list[j].Name
+	})
+}
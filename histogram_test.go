@@ -0,0 +1,61 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHistogram(t *testing.T) {
+	bus := &memoryBus{}
+	// LDX #$03, DEX, BNE -3, LDA #$00
+	copy(bus.mem[0x0200:], []byte{0xA2, 0x03, 0xCA, 0xD0, 0xFD, 0xA9, 0x00})
+	cpu := New(bus)
+	cpu.PC(0x00, 0x02)
+
+	h := NewHistogram()
+	detach := h.Attach(cpu)
+	for i := 0; i < 8; i++ {
+		_, _ = cpu.Step()
+	}
+	detach()
+	_, _ = cpu.Step()
+
+	if h.Count(0xCA) != 3 || h.Count(0xD0) != 3 || h.Count(0x00) != 0 {
+		t.Fatalf("unexpected, got %d %d", h.Count(0xCA), h.Count(0xD0))
+	}
+
+	s := h.Stats()
+	want := Stats{
+		Total: 8,
+		Opcodes: []Count{
+			{"CA DEX implied", 3}, {"D0 BNE relative", 3},
+			{"A2 LDX immediate", 1}, {"A9 LDA immediate", 1},
+		},
+		Mnemonics: []Count{{"BNE", 3}, {"DEX", 3}, {"LDA", 1}, {"LDX", 1}},
+		Modes:     []Count{{"implied", 3}, {"relative", 3}, {"immediate", 2}},
+	}
+	if !reflect.DeepEqual(s, want) {
+		t.Fatalf("unexpected, got %+v", s)
+	}
+
+	sb := &strings.Builder{}
+	if err := s.Report(sb, 1); err != nil {
+		t.Fatal(err)
+	}
+	report := "total 8\n" +
+		"         3  37.5%  CA DEX implied\n" +
+		"         3  37.5%  implied\n" +
+		"         3  37.5%  relative\n" +
+		"         2  25.0%  immediate\n"
+	if sb.String() != report {
+		t.Fatalf("unexpected, got %q", sb)
+	}
+
+	h.Reset()
+	if s := h.Stats(); s.Total != 0 || len(s.Opcodes) != 0 {
+		t.Fatalf("unexpected, got %+v", s)
+	}
+}
@@ -0,0 +1,72 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "sort"
+
+type (
+	// Hook is invoked with a Trace before each instruction is performed.
+	// Returning true consumes the event: hooks of lower priority will
+	// not be invoked for the current instruction.
+	Hook func(cpu *CPU, t Trace) (consumed bool)
+
+	hook struct {
+		priority int
+		seq      uint64
+		fn       Hook
+	}
+
+	hooks struct {
+		seq  uint64
+		list []hook
+	}
+)
+
+// Priorities of the hooks registered by the subsystems of this package.
+// User hooks may pick any value in between, e.g. PriorityDefault.
+const (
+	PriorityDebugger = 300 // Breakpoints and the like, may stop propagation
+	PriorityProfiler = 200 // Statistics and coverage collectors
+	PriorityTracer   = 100 // Tracer registered by SetTracer()
+	PriorityDefault  = 0   // Suggested for user hooks
+)
+
+// AddHook registers a Hook with the given priority. Hooks with higher priority
+// are invoked first, hooks of equal priority in order of registration. The
+// returned function removes the hook; it is safe to call more than once.
+func (cpu *CPU) AddHook(priority int, h Hook) (remove func()) {
+	seq := cpu.hooks.add(priority, h)
+	return func() { cpu.hooks.remove(seq) }
+}
+
+// add and remove replace the list instead of modifying it: a hook changing
+// the hooks while running leaves the list of the current instruction intact.
+func (hs *hooks) add(priority int, fn Hook) uint64 {
+	hs.seq++
+	list := make([]hook, len(hs.list), len(hs.list)+1)
+	copy(list, hs.list)
+	list = append(list, hook{priority: priority, seq: hs.seq, fn: fn})
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].priority > list[j].priority
+	})
+	hs.list = list
+	return hs.seq
+}
+
+func (hs *hooks) remove(seq uint64) {
+	for i := range hs.list {
+		if hs.list[i].seq == seq {
+			hs.list = append(hs.list[:i:i], hs.list[i+1:]...)
+			return
+		}
+	}
+}
+
+func (hs *hooks) run(cpu *CPU, t Trace) {
+	for _, h := range hs.list {
+		if h.fn(cpu, t) {
+			return
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+func TestAddHook(t *testing.T) {
+	cpu := New(&memoryBus{}) // NOP (BRK) stream
+
+	calls := ""
+	add := func(prio int, name string, consume bool) func() {
+		return cpu.AddHook(prio, func(_ *CPU, _ Trace) bool {
+			calls += name
+			return consume
+		})
+	}
+	add(PriorityDefault, "a", false)
+	removeB := add(PriorityDebugger, "b", false)
+	add(PriorityDefault, "c", false)
+	removeD := add(PriorityProfiler, "d", true)
+
+	step := func() string {
+		calls = ""
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+		return calls
+	}
+
+	if s := step(); s != "bd" {
+		t.Fatalf("unexpected, got %s", s)
+	}
+	removeD()
+	removeD()
+	if s := step(); s != "bac" {
+		t.Fatalf("unexpected, got %s", s)
+	}
+	removeB()
+	if s := step(); s != "ac" {
+		t.Fatalf("unexpected, got %s", s)
+	}
+}
+
+func TestAddHookRemoveWhileRunning(t *testing.T) {
+	cpu := New(&memoryBus{})
+
+	n := 0
+	var remove func()
+	remove = cpu.AddHook(PriorityDefault, func(_ *CPU, _ Trace) bool {
+		remove()
+		return false
+	})
+	cpu.AddHook(PriorityDefault, func(_ *CPU, _ Trace) bool {
+		n++
+		return false
+	})
+
+	_, _ = cpu.Step()
+	_, _ = cpu.Step()
+
+	if n != 2 || len(cpu.hooks.list) != 1 {
+		t.Fatalf("unexpected, got %d/%d", n, len(cpu.hooks.list))
+	}
+}
+
+func TestAddHookAddWhileRunning(t *testing.T) {
+	cpu := New(&memoryBus{})
+
+	calls := ""
+	cpu.AddHook(PriorityDefault, func(cpu *CPU, _ Trace) bool {
+		calls += "a"
+		if len(cpu.hooks.list) == 3 {
+			cpu.AddHook(PriorityDebugger, func(_ *CPU, _ Trace) bool { calls += "d"; return false })
+		}
+		return false
+	})
+	cpu.AddHook(PriorityDefault, func(_ *CPU, _ Trace) bool { calls += "b"; return false })
+	cpu.AddHook(PriorityDefault, func(_ *CPU, _ Trace) bool { calls += "c"; return false })
+
+	_, _ = cpu.Step()
+	_, _ = cpu.Step()
+
+	if calls != "abcdabc" {
+		t.Fatalf("unexpected, got %s", calls)
+	}
+}
+
+func TestSetTracerReplaces(t *testing.T) {
+	cpu := New(&memoryBus{})
+
+	a, b := 0, 0
+	cpu.SetTracer(func(Trace) { a++ })
+	cpu.SetTracer(func(Trace) { b++ })
+	_, _ = cpu.Step()
+
+	if a != 0 || b != 1 || len(cpu.hooks.list) != 1 {
+		t.Fatalf("unexpected, got %d/%d/%d", a, b, len(cpu.hooks.list))
+	}
+}
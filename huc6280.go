@@ -0,0 +1,264 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+type (
+	// HuC holds the registers the Hudson HuC6280 adds to the 65C02.
+	HuC struct {
+		MPR  [8]byte // Mapping registers, physical bank of the logical 8K pages
+		T    bool    // T flag, set by SET for the next instruction
+		Fast bool    // High speed mode, set by CSH and cleared by CSL
+	}
+
+	huc struct {
+		mpr    [8]byte
+		t, now bool // T flag set, T flag applying to the instruction
+		fast   bool
+		port   bool // ST0, ST1 or ST2 in progress
+		extra  uint // Cycles beyond the op code table
+	}
+
+	// Physical is the 21 bit physical address space of the HuC6280,
+	// e.g. the ROM banks, the RAM and the hardware page 0xFF.
+	Physical interface {
+		Read(addr uint32) byte
+		Write(addr uint32, db byte)
+	}
+
+	// MMU is a Bus translating the logical addresses of a HuC6280 to the
+	// Physical address space by the mapping registers MPR0 to MPR7.
+	MMU struct {
+		phys Physical
+		cpu  *CPU // CPU created with the MMU, holding the registers
+	}
+)
+
+// NewMMU creates an MMU accessing the Physical address space p.
+// Pass it to New() to link it to the registers of the CPU.
+func NewMMU(p Physical) *MMU {
+	return &MMU{phys: p}
+}
+
+// Translate returns the physical address of a logical address.
+func (m *MMU) Translate(lo, hi byte) uint32 {
+	bank := byte(0x00)
+	if m.cpu != nil {
+		bank = m.cpu.hu.mpr[hi>>5]
+		if m.cpu.hu.port {
+			bank = 0xFF
+		}
+	}
+	return uint32(bank)<<13 | uint32(hi&0x1F)<<8 | uint32(lo)
+}
+
+// Read reads a byte from the physical address of a logical address.
+func (m *MMU) Read(lo, hi byte) byte {
+	return m.phys.Read(m.Translate(lo, hi))
+}
+
+// Write writes a byte to the physical address of a logical address.
+func (m *MMU) Write(lo, hi, db byte) {
+	m.phys.Write(m.Translate(lo, hi), db)
+}
+
+// HuC returns the registers of the HuC6280. Reset() clears MPR7, the T
+// flag and the high speed mode, the other mapping registers are retained.
+func (cpu *CPU) HuC() HuC {
+	return HuC{MPR: cpu.hu.mpr, T: cpu.hu.t, Fast: cpu.hu.fast}
+}
+
+// SetHuC sets the registers of the HuC6280, e.g. when loading a snapshot.
+func (cpu *CPU) SetHuC(r HuC) {
+	cpu.hu.mpr, cpu.hu.t, cpu.hu.fast = r.MPR, r.T, r.Fast
+}
+
+// taken costs the cycles of a taken branch.
+func (cpu *CPU) taken() {
+	if cpu.variant == HuC6280 {
+		cpu.hu.extra += 2
+		return
+	}
+	cpu.cost(1)
+}
+
+// st writes to the hardware page 0xFF, i.e. to the physical addresses
+// 0x1FE000 and up on an MMU, regardless of MPR0. Other buses see the
+// logical address 0x0000 and up, where the hardware page is usually mapped.
+func (cpu *CPU) st(l byte) {
+	b := cpu.fetch()
+	cpu.hu.port = true
+	defer func() { cpu.hu.port = false }()
+	cpu.write(l, 0x00, b)
+}
+
+func (cpu *CPU) hplp() {
+	p := cpu.pop()
+	cpu.p, cpu.hu.t = flag(p)&^(flagU|flagB), flag(p)&flagU != 0
+}
+
+func (cpu *CPU) tst(m, b byte) {
+	cpu.setN(b)
+	cpu.setF(b&0x40 != 0, flagV)
+	cpu.setF(m&b == 0, flagZ)
+}
+
+func (cpu *CPU) word() uint16 {
+	l := cpu.fetch()
+	return uint16(cpu.fetch())<<8 | uint16(l)
+}
+
+// transfer copies the block of TII, TDD, TIN, TIA and TAI, src and dst
+// return the offsets of the addresses for each byte. A length of zero
+// copies 64K, six cycles per byte. Interrupts are not serviced during
+// the transfer.
+func (cpu *CPU) transfer(src, dst func(i uint16) uint16) {
+	s, d, n := cpu.word(), cpu.word(), cpu.word()
+	for i := uint16(0); ; i++ {
+		a, b := s+src(i), d+dst(i)
+		cpu.write(byte(b), byte(b>>8), cpu.read(byte(a), byte(a>>8)))
+		cpu.hu.extra += 6
+		if n--; n == 0 {
+			break
+		}
+	}
+}
+
+func blockInc(i uint16) uint16 { return i }
+func blockDec(i uint16) uint16 { return -i }
+func blockFix(uint16) uint16   { return 0 }
+func blockAlt(i uint16) uint16 { return i & 1 }
+
+// tmode applies the T flag: ORA, AND, EOR and ADC operate on the zero page
+// address X instead of A, taking three more cycles.
+func tmode(f func(cpu *CPU)) func(cpu *CPU) {
+	return func(cpu *CPU) {
+		if !cpu.hu.now {
+			f(cpu)
+			return
+		}
+		a := cpu.a
+		cpu.a = cpu.read(cpu.x, cpu.ce.b)
+		f(cpu)
+		cpu.write(cpu.x, cpu.ce.b, cpu.a)
+		cpu.a = a
+		cpu.hu.extra += 3
+	}
+}
+
+// decimal costs the cycle of ADC and SBC in decimal mode.
+func decimal(m DecimalMode, f func(cpu *CPU)) func(cpu *CPU) {
+	return func(cpu *CPU) {
+		if cpu.bcd(m) {
+			cpu.hu.extra++
+		}
+		f(cpu)
+	}
+}
+
+// hucops performs the instructions of the HuC6280 by op code. The op codes
+// shared with the 65CE02 use its handlers, with the zero page at 0x2000, the
+// stack at 0x2100 and Z remaining zero, i.e. STZ stores zero and ($LL),Z
+// reads ($LL). The cycles are padded to the hudson op code table.
+var hucops = func() (t [0x100]func(cpu *CPU)) {
+	own := [0x100]func(cpu *CPU){
+		0x00: /* BRK */ func(cpu *CPU) {
+			cpu.fetch()
+			cpu.pushPC()
+			cpu.push(byte(cpu.p | flagB))
+			cpu.setPC(cpu.vread(cpu.vecs.BRK))
+			cpu.setI(true)
+			if cpu.quirks.ClearD {
+				cpu.setF(false, flagD)
+			}
+			if cpu.calls.on {
+				cpu.calls.call(Frame{Interrupt: true, From: cpu.at, To: cpu.pc(), S: cpu.s})
+			}
+		},
+		0x02:/* SXY */ func(cpu *CPU) { cpu.x, cpu.y = cpu.y, cpu.x },
+		0x03:/* ST0 #oper */ func(cpu *CPU) { cpu.st(0x00) },
+		0x08:/* PHP */ func(cpu *CPU) { cpu.push(byte(cpu.p | flagB)) },
+		0x13:/* ST1 #oper */ func(cpu *CPU) { cpu.st(0x02) },
+		0x22:/* SAX */ func(cpu *CPU) { cpu.a, cpu.x = cpu.x, cpu.a },
+		0x23:/* ST2 #oper */ func(cpu *CPU) { cpu.st(0x03) },
+		0x28:/* PLP */ func(cpu *CPU) { cpu.hplp() },
+		0x40: /* RTI */ func(cpu *CPU) {
+			if cpu.calls.on {
+				cpu.ret(true, cpu.at, cpu.s)
+			}
+			cpu.hplp()
+			cpu.setPC(cpu.popPC())
+		},
+		0x42:/* SAY */ func(cpu *CPU) { cpu.a, cpu.y = cpu.y, cpu.a },
+		0x43: /* TMA #oper */ func(cpu *CPU) {
+			m := cpu.fetch()
+			for i := 0; i < 8; i++ {
+				if m&(1<<i) != 0 {
+					cpu.a = cpu.hu.mpr[i]
+					break
+				}
+			}
+		},
+		0x44: /* BSR oper */ func(cpu *CPU) {
+			b := cpu.fetch()
+			cpu.pushPCW()
+			pc := cpu.pc() + uint16(int8(b))
+			cpu.jsr(byte(pc), byte(pc>>8))
+		},
+		0x53: /* TAM #oper */ func(cpu *CPU) {
+			m := cpu.fetch()
+			for i := 0; i < 8; i++ {
+				if m&(1<<i) != 0 {
+					cpu.hu.mpr[i] = cpu.a
+				}
+			}
+		},
+		0x54:/* CSL */ func(cpu *CPU) { cpu.hu.fast = false },
+		0x62:/* CLA */ func(cpu *CPU) { cpu.a = 0x00 },
+		0x73:/* TII */ func(cpu *CPU) { cpu.transfer(blockInc, blockInc) },
+		0x80:/* BRA oper */ func(cpu *CPU) { b := cpu.fetch(); cpu.jump(cpu.pc() + uint16(int8(b))) },
+		0x82:/* CLX */ func(cpu *CPU) { cpu.x = 0x00 },
+		0x83:/* TST #oper,oper */ func(cpu *CPU) { m := cpu.fetch(); cpu.tst(m, cpu.read(cpu.bp())) },
+		0x93:/* TST #oper,oper */ func(cpu *CPU) { m := cpu.fetch(); cpu.tst(m, cpu.read(cpu.abs())) },
+		0xA3:/* TST #oper,oper,X */ func(cpu *CPU) { m := cpu.fetch(); cpu.tst(m, cpu.read(cpu.bpN(cpu.x))) },
+		0xB3:/* TST #oper,oper,X */ func(cpu *CPU) { m := cpu.fetch(); cpu.tst(m, cpu.read(cpu.absI(cpu.x))) },
+		0xC2:/* CLY */ func(cpu *CPU) { cpu.y = 0x00 },
+		0xC3:/* TDD */ func(cpu *CPU) { cpu.transfer(blockDec, blockDec) },
+		0xD3:/* TIN */ func(cpu *CPU) { cpu.transfer(blockInc, blockFix) },
+		0xD4:/* CSH */ func(cpu *CPU) { cpu.hu.fast = true },
+		0xE3:/* TIA */ func(cpu *CPU) { cpu.transfer(blockInc, blockAlt) },
+		0xF3:/* TAI */ func(cpu *CPU) { cpu.transfer(blockAlt, blockInc) },
+		0xF4:/* SET */ func(cpu *CPU) { cpu.hu.t = true },
+	}
+	for op := range t {
+		i, c := hudson[op], csg[op]
+		f := own[op]
+		if f == nil && i.Op == c.Op && (i.Mode == c.Mode || i.Mode == ZeroPageIndirect && c.Mode == ZeroPageIndirectZ) {
+			f = ce02ops[op]
+		}
+		if f == nil {
+			f = func(*CPU) {} // NOP
+		}
+		switch i.Op {
+		case OpORA, OpAND, OpEOR:
+			f = tmode(f)
+		case OpADC:
+			f = tmode(decimal(DecimalADC, f))
+		case OpSBC:
+			f = decimal(DecimalSBC, f)
+		}
+		t[op] = pad(i.Cycles, f)
+	}
+	return t
+}()
+
+// pad runs an instruction of the HuC6280 and costs the cycles missing to the
+// op code table and the extra cycles. The T flag applies to one instruction.
+func pad(cycles byte, f func(cpu *CPU)) func(cpu *CPU) {
+	return func(cpu *CPU) {
+		cpu.hu.now, cpu.hu.t, cpu.hu.extra = cpu.hu.t, false, 0
+		f(cpu)
+		for n := uint(cycles) + cpu.hu.extra; cpu.cycles < n; {
+			cpu.cost(byte(min(n-cpu.cycles, 0xFF)))
+		}
+	}
+}
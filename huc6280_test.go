@@ -0,0 +1,243 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+type physicalMemory struct{ mem [0x200000]byte }
+
+func (p *physicalMemory) Read(addr uint32) byte      { return p.mem[addr] }
+func (p *physicalMemory) Write(addr uint32, db byte) { p.mem[addr] = db }
+
+func TestHuCCycles(t *testing.T) {
+	for op := 0; op < 0x100; op++ {
+		i := DecodeVariant(HuC6280, byte(op))
+		switch {
+		case i.Mode == Relative && i.Op != OpBRA && i.Op != OpBSR:
+			continue
+		case i.Mode == ZeroPageRelative || i.Mode == BlockTransfer:
+			continue
+		}
+		cpu, _ := newTestCPU(HuC6280, byte(op))
+		cycles, err := cpu.Step()
+		if err != nil || cycles != uint(i.Cycles) || (i.Op == OpInvalid) != i.Illegal {
+			t.Errorf("unexpected, got %d %v for %02X %s", cycles, err, op, i.Mnemonic())
+		}
+	}
+	if HuC6280.String() != "HuC6280" {
+		t.Error("unexpected")
+	}
+}
+
+func TestHuCRegisters(t *testing.T) {
+	// LDA #$F8, TAM #$02, LDX #$01, LDY #$02, SXY, SAX, SAY, TMA #$06, CLX, CSH
+	cpu, _ := newTestCPU(HuC6280, 0xA9, 0xF8, 0x53, 0x02, 0xA2, 0x01, 0xA0, 0x02, 0x02, 0x22, 0x42, 0x43, 0x06, 0x82, 0xD4)
+
+	for i := 0; i < 5; i++ {
+		_, _ = cpu.Step()
+	}
+	if cpu.x != 0x02 || cpu.y != 0x01 || cpu.HuC().MPR[1] != 0xF8 {
+		t.Fatalf("unexpected, got %+v %s", cpu.HuC(), cpu)
+	}
+	for i := 0; i < 2; i++ {
+		_, _ = cpu.Step()
+	}
+	if cpu.a != 0x01 || cpu.x != 0xF8 || cpu.y != 0x02 {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+	for i := 0; i < 3; i++ {
+		_, _ = cpu.Step()
+	}
+	if r := cpu.HuC(); cpu.a != 0xF8 || cpu.x != 0x00 || !r.Fast {
+		t.Fatalf("unexpected, got %+v %s", r, cpu)
+	}
+
+	cpu.SetHuC(HuC{MPR: [8]byte{0xFF, 0xF8, 7: 0x03}, T: true})
+	if cpu.Reset(); cpu.HuC() != (HuC{MPR: [8]byte{0xFF, 0xF8}}) {
+		t.Fatalf("unexpected, got %+v", cpu.HuC())
+	}
+	if r := cpu.CE02(); r.B != 0x20 || r.SPH != 0x21 {
+		t.Fatalf("unexpected, got %+v", r)
+	}
+}
+
+func TestHuCPages(t *testing.T) {
+	// LDA $10, PHA, STZ $11, LDA ($12)
+	cpu, bus := newTestCPU(HuC6280, 0xA5, 0x10, 0x48, 0x64, 0x11, 0xB2, 0x12)
+	bus.mem[0x2010], bus.mem[0x2011] = 0x55, 0x66
+	bus.mem[0x2012], bus.mem[0x2013], bus.mem[0x4000] = 0x00, 0x40, 0x77
+
+	for i := 0; i < 3; i++ {
+		_, _ = cpu.Step()
+	}
+	if bus.mem[0x21FF] != 0x55 || bus.mem[0x2011] != 0x00 {
+		t.Fatalf("unexpected, got %02X %02X", bus.mem[0x21FF], bus.mem[0x2011])
+	}
+	if cycles, _ := cpu.Step(); cpu.a != 0x77 || cycles != 7 {
+		t.Fatalf("unexpected, got %d %s", cycles, cpu)
+	}
+}
+
+func TestHuCMMU(t *testing.T) {
+	p := &physicalMemory{}
+	p.mem[0x1FFE], p.mem[0x1FFF] = 0x00, 0xE0 // Reset vector in bank 0x00
+
+	// LDA #$F8, TAM #$02, STA $2000, LDA #$01, TAM #$01, ST0 #$AA, ST1 #$BB, ST2 #$CC
+	copy(p.mem[0x0000:], []byte{
+		0xA9, 0xF8, 0x53, 0x02, 0x8D, 0x00, 0x20, 0xA9, 0x01,
+		0x53, 0x01, 0x03, 0xAA, 0x13, 0xBB, 0x23, 0xCC,
+	})
+
+	m := NewMMU(p)
+	cpu := New(m)
+	cpu.SetVariant(HuC6280)
+	if cpu.Reset(); cpu.pc() != 0xE000 {
+		t.Fatalf("unexpected, got %04X", cpu.pc())
+	}
+	for i := 0; i < 8; i++ {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if p.mem[0x1F0000] != 0xF8 || p.mem[0x2000] != 0x00 {
+		t.Fatalf("unexpected, got %02X", p.mem[0x1F0000])
+	}
+	if p.mem[0x1FE000] != 0xAA || p.mem[0x1FE002] != 0xBB || p.mem[0x1FE003] != 0xCC {
+		t.Fatalf("unexpected, got % X", p.mem[0x1FE000:0x1FE004])
+	}
+	if m.Translate(0x34, 0x32) != 0x1F1234 {
+		t.Fatalf("unexpected, got %06X", m.Translate(0x34, 0x32))
+	}
+}
+
+func TestHuCTransfer(t *testing.T) {
+	tests := []struct {
+		op   byte
+		want []byte // at 0x3000
+	}{
+		{0x73, []byte{1, 2, 3, 4}}, // TII
+		{0xC3, []byte{1, 2, 3, 4}}, // TDD, from 0x1003 to 0x3003 downwards
+		{0xD3, []byte{4, 0, 0, 0}}, // TIN
+		{0xE3, []byte{3, 4, 0, 0}}, // TIA
+		{0xF3, []byte{1, 2, 1, 2}}, // TAI
+	}
+	for _, tt := range tests {
+		src, dst := byte(0x00), byte(0x00)
+		if tt.op == 0xC3 {
+			src, dst = 0x03, 0x03
+		}
+		cpu, bus := newTestCPU(HuC6280, tt.op, src, 0x10, dst, 0x30, 0x04, 0x00)
+		copy(bus.mem[0x1000:], []byte{1, 2, 3, 4})
+		cycles, err := cpu.Step()
+		if err != nil || cycles != 17+6*4 || cpu.pc() != 0x0207 {
+			t.Errorf("unexpected, got %d %v for %02X", cycles, err, tt.op)
+		}
+		if got := bus.mem[0x3000:0x3004]; string(got) != string(tt.want) || bus.mem[0x2FFF]|bus.mem[0x3004] != 0 {
+			t.Errorf("unexpected, got % X for %02X", got, tt.op)
+		}
+	}
+}
+
+func TestHuCFlagT(t *testing.T) {
+	// LDX #$10, SET, ORA #$0F, ORA #$F0, SET, ADC #$01
+	cpu, bus := newTestCPU(HuC6280, 0xA2, 0x10, 0xF4, 0x09, 0x0F, 0x09, 0xF0, 0xF4, 0x69, 0x01)
+	bus.mem[0x2010], cpu.a = 0x80, 0x01
+
+	_, _ = cpu.Step()
+	if _, _ = cpu.Step(); !cpu.HuC().T {
+		t.Fatal("unexpected")
+	}
+	if cycles, _ := cpu.Step(); bus.mem[0x2010] != 0x8F || cpu.a != 0x01 || cycles != 5 || cpu.HuC().T {
+		t.Fatalf("unexpected, got %02X %d %s", bus.mem[0x2010], cycles, cpu)
+	}
+	if cycles, _ := cpu.Step(); cpu.a != 0xF1 || cycles != 2 {
+		t.Fatalf("unexpected, got %d %s", cycles, cpu)
+	}
+	_, _ = cpu.Step()
+	if _, _ = cpu.Step(); bus.mem[0x2010] != 0x90 || cpu.a != 0xF1 {
+		t.Fatalf("unexpected, got %02X %s", bus.mem[0x2010], cpu)
+	}
+}
+
+func TestHuCTST(t *testing.T) {
+	// TST #$01,$10, TST #$C0,$3000,X
+	cpu, bus := newTestCPU(HuC6280, 0x83, 0x01, 0x10, 0xB3, 0xC0, 0x00, 0x30)
+	bus.mem[0x2010], bus.mem[0x3001], cpu.x = 0xC2, 0x40, 0x01
+
+	if cycles, _ := cpu.Step(); cpu.p&(flagN|flagV|flagZ) != flagN|flagV|flagZ || cycles != 7 {
+		t.Fatalf("unexpected, got %d %s", cycles, cpu)
+	}
+	if cycles, _ := cpu.Step(); cpu.p&(flagN|flagV|flagZ) != flagV || cycles != 8 {
+		t.Fatalf("unexpected, got %d %s", cycles, cpu)
+	}
+}
+
+func TestHuCBranches(t *testing.T) {
+	cpu, bus := newTestCPU(HuC6280, 0x44, 0x7E, 0xD0, 0x10)     // BSR $0280, BNE $0214
+	copy(bus.mem[0x0280:], []byte{0xF0, 0x01, 0x00})            // BEQ $0283
+	copy(bus.mem[0x0283:], []byte{0x8F, 0x10, 0x0A})            // BBS0 $10,$0290
+	bus.mem[0x0290], bus.mem[0x2010], cpu.p = 0x60, 0x01, flagZ // RTS
+
+	steps := []struct {
+		pc     uint16
+		cycles uint
+	}{
+		{0x0280, 8},
+		{0x0283, 4},
+		{0x0290, 8},
+		{0x0202, 7},
+		{0x0204, 2},
+	}
+	for i, s := range steps {
+		if cycles, _ := cpu.Step(); cpu.pc() != s.pc || cycles != s.cycles {
+			t.Fatalf("unexpected, got %04X %d in step %d", cpu.pc(), cycles, i)
+		}
+		if i == 0 && (bus.mem[0x21FF] != 0x02 || bus.mem[0x21FE] != 0x01) {
+			t.Fatalf("unexpected, got %02X %02X", bus.mem[0x21FF], bus.mem[0x21FE])
+		}
+	}
+}
+
+func TestHuCInterrupts(t *testing.T) {
+	cpu, bus := newTestCPU(HuC6280, 0x00, 0x00) // BRK
+	copy(bus.mem[0xFFF6:], []byte{0x00, 0x30, 0x00, 0x40, 0x00, 0x00, 0x00, 0x50, 0x00, 0x60})
+	if cpu.Reset(); cpu.pc() != 0x6000 {
+		t.Fatalf("unexpected, got %04X", cpu.pc())
+	}
+	cpu.PC(0x00, 0x02)
+	if cycles, _ := cpu.Step(); cpu.pc() != 0x3000 || cycles != 8 || bus.mem[0x21FD]&byte(flagB|flagU) != byte(flagB) {
+		t.Fatalf("unexpected, got %04X %d %02X", cpu.pc(), cycles, bus.mem[0x21FD])
+	}
+	cpu.p &^= flagI
+	if _, _ = cpu.IRQ(); cpu.pc() != 0x4000 {
+		t.Fatalf("unexpected, got %04X", cpu.pc())
+	}
+	if _, _ = cpu.NMI(); cpu.pc() != 0x5000 {
+		t.Fatalf("unexpected, got %04X", cpu.pc())
+	}
+}
+
+func TestHuCDisassembler(t *testing.T) {
+	d := Disassembler{Variant: HuC6280}
+	tests := []struct {
+		code []byte
+		want string
+	}{
+		{[]byte{0x83, 0x01, 0x10}, "TST #$01,$10"},
+		{[]byte{0xA3, 0x01, 0x10}, "TST #$01,$10,X"},
+		{[]byte{0x93, 0x01, 0x00, 0x30}, "TST #$01,$3000"},
+		{[]byte{0xB3, 0x01, 0x00, 0x30}, "TST #$01,$3000,X"},
+		{[]byte{0x73, 0x00, 0x10, 0x00, 0x30, 0x04, 0x00}, "TII $1000,$3000,$0004"},
+		{[]byte{0x53, 0x02}, "TAM #$02"},
+		{[]byte{0x44, 0xFE}, "BSR $0200"},
+		{[]byte{0x12, 0x10}, "ORA ($10)"},
+		{[]byte{0xF4}, "SET"},
+		{[]byte{0x22}, "SAX"},
+		{[]byte{0x33}, ".byte $33"},
+	}
+	for _, tt := range tests {
+		if asm, n := d.Decode(0x0200, tt.code); asm != tt.want || n != len(tt.code) && n != 1 {
+			t.Errorf("unexpected, got %q %d", asm, n)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "fmt"
+
+// Addresses of the interrupt vectors.
+const (
+	VectorNMI   uint16 = 0xFFFA
+	VectorReset uint16 = 0xFFFC
+	VectorIRQ   uint16 = 0xFFFE
+)
+
+// SetResetVector patches the Reset Vector of the image loaded at origin to
+// target. It returns an error when the image does not cover the vector.
+func SetResetVector(img []byte, origin, target uint16) error {
+	return SetVector(img, origin, VectorReset, target)
+}
+
+// SetNMIVector patches the NMI vector, see SetResetVector().
+func SetNMIVector(img []byte, origin, target uint16) error {
+	return SetVector(img, origin, VectorNMI, target)
+}
+
+// SetIRQVector patches the IRQ/BRK vector, see SetResetVector().
+func SetIRQVector(img []byte, origin, target uint16) error {
+	return SetVector(img, origin, VectorIRQ, target)
+}
+
+// SetVector writes target in little-endian order to the address vector
+// of the image loaded at origin. It returns an error when the image does
+// not cover both bytes.
+func SetVector(img []byte, origin, vector, target uint16) error {
+	at := int(vector) - int(origin)
+	if at < 0 || at+1 >= len(img) {
+		return fmt.Errorf("m6502: vector %04X outside image %04X-%04X", vector, origin, int(origin)+len(img)-1)
+	}
+	img[at], img[at+1] = byte(target), byte(target>>8)
+	return nil
+}
@@ -0,0 +1,37 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+func TestSetVector(t *testing.T) {
+	img := make([]byte, 0x2000) // 0xE000-0xFFFF
+
+	if SetResetVector(img, 0xE000, 0xE123) != nil ||
+		SetNMIVector(img, 0xE000, 0xE456) != nil ||
+		SetIRQVector(img, 0xE000, 0xE789) != nil {
+		t.Fatal("unexpected")
+	}
+	if img[0x1FFA] != 0x56 || img[0x1FFB] != 0xE4 || img[0x1FFC] != 0x23 || img[0x1FFD] != 0xE1 ||
+		img[0x1FFE] != 0x89 || img[0x1FFF] != 0xE7 {
+		t.Fatalf("unexpected, got % X", img[0x1FFA:])
+	}
+
+	cpu := New(Adapt16(NewROM(img)))
+	if cpu.pc() != 0xE123 {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+}
+
+func TestSetVectorOutside(t *testing.T) {
+	err := SetResetVector(make([]byte, 0x1000), 0xE000, 0xE000)
+	if err == nil || err.Error() != "m6502: vector FFFC outside image E000-EFFF" {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	if SetIRQVector(make([]byte, 0x0FFF), 0xF000, 0xF000) == nil {
+		t.Fatal("unexpected")
+	}
+	if SetIRQVector(make([]byte, 0x1000), 0xF000, 0xF000) != nil {
+		t.Fatal("unexpected")
+	}
+}
@@ -0,0 +1,155 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+// Package ines reads NES cartridge images in the iNES and NES 2.0 formats
+// and maps the PRG-ROM of mapper 0 (NROM) into the address space, so that
+// CPU test ROMs like nestest run on a Ricoh2A03 CPU:
+//
+//	rom, err := ines.Read(r)
+//	bus, err := ines.NewBus(rom)
+//	cpu := m6502.New(bus, m6502.WithVariant(m6502.Ricoh2A03))
+package ines
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dtgorski/m6502"
+)
+
+type (
+	// ROM is a parsed cartridge image.
+	ROM struct {
+		NES2      bool      // Header in NES 2.0 format
+		Mapper    uint16    // Mapper number
+		Submapper byte      // Submapper number, NES 2.0 only
+		Mirroring Mirroring // Nametable mirroring
+		Battery   bool      // Battery-backed PRG-RAM present
+		Trainer   []byte    // 512 bytes loaded to 0x7000, or nil
+		PRG       []byte    // PRG-ROM
+		CHR       []byte    // CHR-ROM, empty when the board has CHR-RAM
+	}
+
+	// Mirroring is the nametable arrangement of the cartridge.
+	Mirroring byte
+)
+
+// Nametable arrangements.
+const (
+	Horizontal Mirroring = iota // Vertical arrangement, horizontal mirroring
+	Vertical                    // Horizontal arrangement, vertical mirroring
+	FourScreen                  // Four-screen VRAM on the cartridge
+)
+
+// Sizes of the header, the trainer and the units of the ROM sizes.
+const (
+	HeaderSize  = 16
+	TrainerSize = 512
+	PRGUnit     = 0x4000
+	CHRUnit     = 0x2000
+)
+
+var magic = []byte{'N', 'E', 'S', 0x1A}
+
+var names = [...]string{"horizontal", "vertical", "four-screen"}
+
+// String returns the name of the Mirroring.
+func (m Mirroring) String() string {
+	if int(m) < len(names) {
+		return names[m]
+	}
+	return "unknown"
+}
+
+// Parse parses a cartridge image.
+func Parse(b []byte) (*ROM, error) {
+	if len(b) < HeaderSize || !bytes.Equal(b[:4], magic) {
+		return nil, errors.New("ines: missing header")
+	}
+	h := b[:HeaderSize]
+	r := &ROM{
+		NES2:    h[7]&0x0C == 0x08,
+		Mapper:  uint16(h[6]>>4 | h[7]&0xF0),
+		Battery: h[6]&0x02 != 0,
+	}
+	switch {
+	case h[6]&0x08 != 0:
+		r.Mirroring = FourScreen
+	case h[6]&0x01 != 0:
+		r.Mirroring = Vertical
+	}
+	prg, chr := int(h[4])*PRGUnit, int(h[5])*CHRUnit
+	if r.NES2 {
+		r.Mapper |= uint16(h[8]&0x0F) << 8
+		r.Submapper = h[8] >> 4
+		prg = size(h[9]&0x0F, h[4], PRGUnit)
+		chr = size(h[9]>>4, h[5], CHRUnit)
+	}
+	b = b[HeaderSize:]
+	if h[6]&0x04 != 0 {
+		if len(b) < TrainerSize {
+			return nil, errors.New("ines: truncated trainer")
+		}
+		r.Trainer, b = b[:TrainerSize], b[TrainerSize:]
+	}
+	if prg < 0 || chr < 0 || len(b) < prg+chr {
+		return nil, fmt.Errorf("ines: truncated image, want %d bytes of PRG and CHR, got %d", prg+chr, len(b))
+	}
+	r.PRG, r.CHR = b[:prg], b[prg:prg+chr]
+	return r, nil
+}
+
+// Read reads and parses a cartridge image.
+func Read(r io.Reader) (*ROM, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("ines: %w", err)
+	}
+	return Parse(b)
+}
+
+// size decodes a NES 2.0 ROM size from the MSB nibble and the LSB byte,
+// either in units or in exponent-multiplier notation. It returns -1 when
+// the size exceeds an int.
+func size(msb, lsb byte, unit int) int {
+	if msb != 0x0F {
+		return (int(msb)<<8 | int(lsb)) * unit
+	}
+	if e := lsb >> 2; e < 31 {
+		return 1 << e * (int(lsb&0x03)*2 + 1)
+	}
+	return -1
+}
+
+// MapNROM maps the PRG-ROM of mapper 0 to 0x8000-0xFFFF, a 16K PRG-ROM is
+// mirrored, and 8K PRG-RAM to 0x6000-0x7FFF, holding the trainer if any.
+func (r *ROM) MapNROM(m *m6502.Mapper) error {
+	if r.Mapper != 0 {
+		return fmt.Errorf("ines: unsupported mapper %d", r.Mapper)
+	}
+	if len(r.PRG) != PRGUnit && len(r.PRG) != 2*PRGUnit {
+		return fmt.Errorf("ines: invalid NROM PRG-ROM size %d", len(r.PRG))
+	}
+	ram := m6502.NewRAM(0x2000)
+	ram.Load(0x1000, r.Trainer)
+	m.Map(0x6000, 0x7FFF, ram)
+	m.Map(0x8000, 0xFFFF, m6502.NewROM(r.PRG))
+	return nil
+}
+
+// NewBus creates the CPU address space of a NES with the cartridge: 2K
+// RAM mirrored up to 0x1FFF, the PPU registers mirrored up to 0x3FFF,
+// the APU and I/O registers and the expansion area up to 0x5FFF, see
+// MapNROM() for the rest. The registers are plain memory without function,
+// sufficient for CPU tests not depending on the PPU and APU.
+func NewBus(r *ROM) (*m6502.Mapper, error) {
+	m := m6502.NewMapper().
+		Map(0x0000, 0x1FFF, m6502.NewRAM(0x0800)).
+		Map(0x2000, 0x3FFF, m6502.NewRAM(0x0008)).
+		Map(0x4000, 0x5FFF, m6502.NewRAM(0x2000))
+	if err := r.MapNROM(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
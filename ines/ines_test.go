@@ -0,0 +1,115 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package ines
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/dtgorski/m6502"
+)
+
+// image creates a cartridge image with the header and the sizes of PRG and
+// CHR, PRG filled with its offset / 0x100 and CHR with 0xCC.
+func image(h []byte, prg, chr int) []byte {
+	b := append(append([]byte("NES\x1A"), h...), make([]byte, HeaderSize-4-len(h))...)
+	for i := 0; i < prg; i++ {
+		b = append(b, byte(i>>8))
+	}
+	return append(b, bytes.Repeat([]byte{0xCC}, chr)...)
+}
+
+func TestParse(t *testing.T) {
+	r, err := Parse(image([]byte{0x01, 0x01, 0x13, 0x40}, PRGUnit, CHRUnit))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.NES2 || r.Mapper != 0x41 || r.Mirroring != Vertical || !r.Battery || r.Trainer != nil ||
+		len(r.PRG) != PRGUnit || len(r.CHR) != CHRUnit || r.CHR[0] != 0xCC {
+		t.Fatalf("unexpected, got %+v", r)
+	}
+
+	// NES 2.0 with trainer, 4M PRG via MSB nibble, 24K CHR in exponent-multiplier notation.
+	h := []byte{0x00, 0x35, 0x0C, 0x08, 0x52, 0xF1}
+	b := image(h, 0, 0)
+	b = append(append(b, bytes.Repeat([]byte{0x77}, TrainerSize)...), image(nil, 0x100*PRGUnit, 0x6000)[HeaderSize:]...)
+	if r, err = Parse(b); err != nil {
+		t.Fatal(err)
+	}
+	if !r.NES2 || r.Mapper != 0x0200 || r.Submapper != 5 || r.Mirroring != FourScreen ||
+		len(r.Trainer) != TrainerSize || len(r.PRG) != 0x100*PRGUnit || len(r.CHR) != 0x6000 {
+		t.Fatalf("unexpected, got %v %v %d %d", r.NES2, r.Mapper, len(r.PRG), len(r.CHR))
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for _, tt := range []struct {
+		b   []byte
+		err string
+	}{
+		{[]byte("NES"), "ines: missing header"},
+		{image([]byte{0x01, 0x00, 0x04}, 0, 0), "ines: truncated trainer"},
+		{
+			image([]byte{0x02, 0x01}, PRGUnit, 0),
+			"ines: truncated image, want 40960 bytes of PRG and CHR, got 16384",
+		},
+		{
+			image([]byte{0x00, 0x00, 0x00, 0x08, 0x00, 0x0F}, 0, 0),
+			"ines: truncated image, want 1 bytes of PRG and CHR, got 0",
+		},
+	} {
+		if _, err := Parse(tt.b); err == nil || err.Error() != tt.err {
+			t.Fatalf("unexpected, got %v", err)
+		}
+	}
+	errFoo := errors.New("foo")
+	if _, err := Read(failingReader{errFoo}); !errors.Is(err, errFoo) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
+
+func TestNewBus(t *testing.T) {
+	for _, prg := range []int{PRGUnit, 2 * PRGUnit} {
+		b := image([]byte{byte(prg / PRGUnit), 0x01, 0x04}, prg, CHRUnit)
+		b = append(b[:HeaderSize:HeaderSize], append(bytes.Repeat([]byte{0x77}, TrainerSize), b[HeaderSize:]...)...)
+		r, err := Read(bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		bus, err := NewBus(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bus.Write(0x12, 0x00, 0x42)
+		if bus.Read(0x12, 0x18) != 0x42 || bus.Read(0x00, 0x70) != 0x77 || bus.Read(0x00, 0x6F) != 0x00 {
+			t.Fatal("unexpected RAM")
+		}
+		bus.Write(0x01, 0x20, 0x43)
+		if bus.Read(0x09, 0x3F) != 0x43 {
+			t.Fatal("unexpected PPU registers")
+		}
+		if bus.Read(0x00, 0x80) != 0x00 || bus.Read(0xFF, 0xBF) != 0x3F || bus.Read(0xFF, 0xFF) != byte((prg-1)>>8) {
+			t.Fatalf("unexpected PRG, got %02X", bus.Read(0xFF, 0xFF))
+		}
+	}
+
+	r := &ROM{Mapper: 1, PRG: make([]byte, PRGUnit)}
+	if _, err := NewBus(r); err == nil || err.Error() != "ines: unsupported mapper 1" {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	r = &ROM{PRG: make([]byte, 3*PRGUnit)}
+	if err := r.MapNROM(m6502.NewMapper()); err == nil || err.Error() != "ines: invalid NROM PRG-ROM size 49152" {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
+
+func TestMirroring(t *testing.T) {
+	if Horizontal.String() != "horizontal" || FourScreen.String() != "four-screen" || Mirroring(3).String() != "unknown" {
+		t.Fatal("unexpected")
+	}
+}
+
+type failingReader struct{ err error }
+
+func (r failingReader) Read([]byte) (int, error) { return 0, r.err }
@@ -0,0 +1,173 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+type (
+	// Accuracy selects the trade-off between speed and hardware accuracy.
+	Accuracy byte
+
+	// lines keeps the timing of interrupt line changes within the
+	// current instruction and the results of the interrupt polling.
+	lines struct {
+		irqAt   uint // Cycle of IRQ assertion within instruction
+		irqRel  uint // Cycle of IRQ release within instruction
+		nmiAt   uint // Cycle of NMI edge within instruction
+		irqPoll bool // IRQ recognized by last polling
+		nmiPoll bool // NMI recognized by last polling
+	}
+
+	// drive keeps the levels the sources drive the interrupt lines to. A
+	// line is asserted as long as any source asserts it, i.e. wired-OR.
+	drive struct {
+		irq, nmi           bool // Driven by the methods of the CPU
+		irqAsync, nmiAsync bool // Driven by Lines, as sampled
+	}
+)
+
+const (
+	// AccuracyMinimal samples the interrupt lines at the instruction
+	// boundary: a pending interrupt is serviced by the next Step().
+	AccuracyMinimal Accuracy = iota
+
+	// AccuracyCycle polls the interrupt lines like the original processor,
+	// during the penultimate cycle of an instruction. An interrupt asserted
+	// later, e.g. by a device during the last cycle, or between two calls
+	// to Step(), is serviced after the following instruction. CLI, SEI and
+	// PLP change the I flag after polling, a taken branch without page cross
+	// polls before its last two cycles.
+	AccuracyCycle
+)
+
+// SetAccuracy selects the Accuracy of the emulation. Defaults to AccuracyMinimal.
+func (cpu *CPU) SetAccuracy(a Accuracy) {
+	cpu.acc = a
+}
+
+// SetNMIHijack enables the NMOS BRK/NMI hijack quirk: when an NMI edge occurs
+// before the BRK sequence fetches the vector, the CPU continues with the NMI
+// vector instead, while the pushed status still has the B flag set. The NMI is
+// consumed. With AccuracyCycle, an NMI asserted right before a BRK hijacks it.
+// Defaults to false.
+func (cpu *CPU) SetNMIHijack(on bool) {
+	cpu.hijack = on
+}
+
+// AssertIRQ pulls the level-sensitive IRQ line low. The line is sampled
+// by Step() at instruction boundaries: as long as it stays asserted and
+// the I flag is clear, the interrupt will be serviced instead of the
+// next instruction. The line remains asserted until ReleaseIRQ(). Like
+// all methods of the CPU, not safe for concurrent use, see Lines.
+func (cpu *CPU) AssertIRQ() {
+	cpu.drive.irq = true
+	cpu.driveIRQ()
+}
+
+// ReleaseIRQ releases the IRQ line, see AssertIRQ(). The line remains
+// asserted while Lines asserts it.
+func (cpu *CPU) ReleaseIRQ() {
+	cpu.drive.irq = false
+	cpu.driveIRQ()
+}
+
+// driveIRQ sets the IRQ line to the level driven by the sources.
+func (cpu *CPU) driveIRQ() {
+	on := cpu.drive.irq || cpu.drive.irqAsync
+	switch {
+	case on && !cpu.irq:
+		cpu.lines.irqAt = cpu.cycle()
+	case !on && cpu.irq:
+		cpu.lines.irqRel = cpu.cycle()
+	}
+	cpu.irq = on
+}
+
+// AssertNMI pulls the edge-sensitive NMI line low. The falling edge is
+// latched and serviced by Step() at the next instruction boundary. As long
+// as the line stays asserted, no further NMI will be latched: the line has
+// to be released with ReleaseNMI() before another edge can occur.
+func (cpu *CPU) AssertNMI() {
+	cpu.drive.nmi = true
+	cpu.driveNMI()
+}
+
+// ReleaseNMI releases the NMI line, see AssertNMI(). The line remains
+// asserted while Lines asserts it.
+func (cpu *CPU) ReleaseNMI() {
+	cpu.drive.nmi = false
+	cpu.driveNMI()
+}
+
+// driveNMI sets the NMI line to the level driven by the sources.
+func (cpu *CPU) driveNMI() {
+	on := cpu.drive.nmi || cpu.drive.nmiAsync
+	if on && !cpu.nmi {
+		cpu.nmiEdge = true
+		cpu.lines.nmiAt = cpu.cycle()
+	}
+	cpu.nmi = on
+}
+
+// SetOverflowPin drives the SO (Set Overflow) pin, true pulls it low. Like
+// on the original processor, the falling edge sets the V flag. Disk drive
+// controllers, e.g. of the 1541, signal byte-ready this way, polled by a
+// tight BVC loop. The pin can be driven from within bus accesses.
+func (cpu *CPU) SetOverflowPin(asserted bool) {
+	if asserted && !cpu.so {
+		cpu.p.set(true, flagV)
+	}
+	cpu.so = asserted
+}
+
+// SO pulses the SO pin, i.e. sets the V flag, see SetOverflowPin().
+func (cpu *CPU) SO() {
+	cpu.SetOverflowPin(true)
+	cpu.SetOverflowPin(false)
+}
+
+// cycle returns the current cycle within the instruction in progress,
+// or 0 when called between instructions.
+func (cpu *CPU) cycle() uint {
+	if cpu.busy {
+		return cpu.cycles
+	}
+	return 0
+}
+
+// poll samples the interrupt lines at the end of an instruction as if
+// sampled during the penultimate cycle, or during the given cycle if > 0.
+// The I flag has to be provided as seen by the polling.
+func (cpu *CPU) poll(at uint, flgI bool) {
+	if at == 0 {
+		at = cpu.cycles - 1
+	}
+	l := &cpu.lines
+	irq := cpu.irq && l.irqAt <= at || !cpu.irq && l.irqRel > at
+
+	l.nmiPoll = cpu.nmiEdge && l.nmiAt <= at
+	l.irqPoll = irq && !flgI
+	l.irqAt, l.irqRel, l.nmiAt = 0, 0, 0
+}
+
+// Interrupts returns the number of IRQ and NMI sequences performed since
+// Reset(), by Step() as well as by IRQ() and NMI(). BRK is not counted.
+func (cpu *CPU) Interrupts() (irq, nmi uint64) {
+	return cpu.irqs, cpu.nmis
+}
+
+// Waiting reports whether the CPU waits for an interrupt after WAI (65C02).
+// An asserted IRQ or NMI line resumes the execution: the interrupt will be
+// serviced, or with IRQ and the I flag set, the next instruction performed.
+func (cpu *CPU) Waiting() bool {
+	return cpu.waiting
+}
+
+// wake ends the waiting state when an interrupt line is asserted.
+func (cpu *CPU) wake() bool {
+	if !cpu.irq && !cpu.nmiEdge {
+		return false
+	}
+	cpu.waiting = false
+	cpu.lines.nmiPoll = cpu.nmiEdge
+	cpu.lines.irqPoll = cpu.irq && !cpu.p.has(flagI)
+	return true
+}
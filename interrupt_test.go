@@ -0,0 +1,294 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"errors"
+	"testing"
+)
+
+func stepPC(t *testing.T, cpu *CPU) uint16 {
+	t.Helper()
+	if _, err := cpu.Step(); err != nil {
+		t.Fatal(err)
+	}
+	return cpu.pc()
+}
+
+func TestAccuracyCycleBetweenSteps(t *testing.T) {
+	cpu, _ := newTestCPU(NMOS6502, 0xEA, 0xEA, 0xEA)
+	cpu.SetAccuracy(AccuracyCycle)
+
+	cpu.AssertIRQ()
+	if pc := stepPC(t, cpu); pc != 0x0201 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	if pc := stepPC(t, cpu); pc != 0x9000 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+
+	cpu, _ = newTestCPU(NMOS6502, 0xEA, 0xEA, 0xEA)
+	cpu.SetAccuracy(AccuracyCycle)
+	cpu.AssertNMI()
+	if pc := stepPC(t, cpu); pc != 0x0201 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	if pc := stepPC(t, cpu); pc != 0x8000 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+}
+
+func TestAccuracyCycleWithinInstruction(t *testing.T) {
+	// LDA $10 (3 cycles), IRQ asserted during operand fetch (cycle 2).
+	cpu, bus := newTestCPU(NMOS6502, 0xA5, 0x10, 0xEA)
+	cpu.SetAccuracy(AccuracyCycle)
+	bus.addr, bus.fn = 0x0201, cpu.AssertIRQ
+
+	if pc := stepPC(t, cpu); pc != 0x0202 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	if pc := stepPC(t, cpu); pc != 0x9000 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+
+	// LDA $10 (3 cycles), IRQ asserted during the last cycle is too late.
+	cpu, bus = newTestCPU(NMOS6502, 0xA5, 0x10, 0xEA)
+	cpu.SetAccuracy(AccuracyCycle)
+	bus.addr, bus.fn = 0x0010, cpu.AssertIRQ
+
+	if pc := stepPC(t, cpu); pc != 0x0202 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	if pc := stepPC(t, cpu); pc != 0x0203 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	if pc := stepPC(t, cpu); pc != 0x9000 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+
+	// Released after polling, still recognized.
+	cpu, bus = newTestCPU(NMOS6502, 0xA5, 0x10, 0xEA)
+	cpu.SetAccuracy(AccuracyCycle)
+	cpu.AssertIRQ()
+	bus.addr, bus.fn = 0x0010, cpu.ReleaseIRQ
+
+	if pc := stepPC(t, cpu); pc != 0x0202 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	if pc := stepPC(t, cpu); pc != 0x9000 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+}
+
+func TestAccuracyCycleBranch(t *testing.T) {
+	// BNE +0 taken without page cross, IRQ asserted during operand fetch.
+	cpu, bus := newTestCPU(NMOS6502, 0xD0, 0x00, 0xEA, 0xEA)
+	cpu.SetAccuracy(AccuracyCycle)
+	bus.addr, bus.fn = 0x0201, cpu.AssertIRQ
+
+	if pc := stepPC(t, cpu); pc != 0x0202 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	if pc := stepPC(t, cpu); pc != 0x0203 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	if pc := stepPC(t, cpu); pc != 0x9000 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+}
+
+func TestAccuracyCycleFlagI(t *testing.T) {
+	// IRQ pending while SEI executes, serviced nonetheless.
+	cpu, _ := newTestCPU(NMOS6502, 0xEA, 0x78, 0xEA)
+	cpu.SetAccuracy(AccuracyCycle)
+	_ = stepPC(t, cpu)
+	cpu.AssertIRQ()
+
+	if pc := stepPC(t, cpu); pc != 0x0202 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	if pc := stepPC(t, cpu); pc != 0x9000 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+
+	// IRQ pending while CLI executes, one more instruction follows.
+	cpu, _ = newTestCPU(NMOS6502, 0x58, 0xEA, 0xEA)
+	cpu.SetAccuracy(AccuracyCycle)
+	cpu.p.set(true, flagI)
+	cpu.AssertIRQ()
+
+	if pc := stepPC(t, cpu); pc != 0x0201 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	if pc := stepPC(t, cpu); pc != 0x0202 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	if pc := stepPC(t, cpu); pc != 0x9000 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+}
+
+func TestAccuracyMinimal(t *testing.T) {
+	cpu, bus := newTestCPU(NMOS6502, 0xA5, 0x10, 0xEA)
+	cpu.SetAccuracy(AccuracyMinimal)
+	bus.addr, bus.fn = 0x0010, cpu.AssertIRQ
+
+	if pc := stepPC(t, cpu); pc != 0x0202 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	if pc := stepPC(t, cpu); pc != 0x9000 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+}
+
+func TestNMIHijack(t *testing.T) {
+	// NMI latched right before BRK, recognized by the BRK polling.
+	cpu, bus := newTestCPU(NMOS6502, 0x00, 0x00, 0xEA)
+	cpu.SetAccuracy(AccuracyCycle)
+	cpu.SetNMIHijack(true)
+	cpu.AssertNMI()
+
+	if pc := stepPC(t, cpu); pc != 0x8000 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	if bus.mem[0x01FD]&byte(flagB) == 0 || !cpu.p.has(flagI) || cpu.nmiEdge {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+	bus.mem[0x8000] = 0xEA
+	if pc := stepPC(t, cpu); pc != 0x8001 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+
+	// Disabled, BRK handler is entered, NMI serviced afterwards.
+	cpu, _ = newTestCPU(NMOS6502, 0x00, 0x00, 0xEA)
+	cpu.SetAccuracy(AccuracyCycle)
+	cpu.AssertNMI()
+
+	if pc := stepPC(t, cpu); pc != 0x9000 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	if pc := stepPC(t, cpu); pc != 0x8000 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+
+	// NMI edge during the BRK sequence with AccuracyMinimal.
+	cpu, bus = newTestCPU(NMOS6502, 0x00, 0x00, 0xEA)
+	cpu.SetAccuracy(AccuracyMinimal)
+	cpu.SetNMIHijack(true)
+	bus.addr, bus.fn = 0x0201, cpu.AssertNMI
+
+	if pc := stepPC(t, cpu); pc != 0x8000 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+}
+
+func TestWAI(t *testing.T) {
+	cpu, _ := newTestCPU(NMOS6502, 0xCB, 0xEA, 0xEA)
+	cpu.SetVariant(CMOS65C02)
+
+	if pc := stepPC(t, cpu); pc != 0x0201 || !cpu.Waiting() {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	for i := 0; i < 3; i++ {
+		if cycles, err := cpu.Step(); err != nil || cycles != 1 || cpu.pc() != 0x0201 {
+			t.Fatalf("unexpected, got %d %v", cycles, err)
+		}
+	}
+	cpu.AssertIRQ()
+	if pc := stepPC(t, cpu); pc != 0x9000 || cpu.Waiting() {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+
+	// I flag set: IRQ resumes with the next instruction.
+	cpu, _ = newTestCPU(NMOS6502, 0x78, 0xCB, 0xEA, 0xEA)
+	cpu.SetVariant(CMOS65C02)
+	_, _ = stepPC(t, cpu), stepPC(t, cpu)
+	cpu.AssertIRQ()
+	if pc := stepPC(t, cpu); pc != 0x0203 || cpu.Waiting() {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+
+	// NMOS does not know WAI.
+	cpu, _ = newTestCPU(NMOS6502, 0xCB)
+	if _, err := cpu.Step(); err == nil || cpu.Waiting() {
+		t.Fatal("unexpected")
+	}
+}
+
+func TestSTP(t *testing.T) {
+	cpu, _ := newTestCPU(NMOS6502, 0xDB, 0xEA)
+	cpu.SetVariant(CMOS65C02)
+
+	for i := 0; i < 2; i++ {
+		if _, err := cpu.Step(); !errors.Is(err, ErrStopped) {
+			t.Fatalf("unexpected, got %v", err)
+		}
+	}
+	cpu.AssertNMI()
+	if _, err := cpu.Step(); !errors.Is(err, ErrStopped) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	cpu.Reset()
+	cpu.PC(0x01, 0x02)
+	if pc := stepPC(t, cpu); pc != 0x0202 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+}
+
+func TestOverflowPin(t *testing.T) {
+	// BVC -2 polls until SO sets V.
+	cpu, _ := newTestCPU(NMOS6502, 0x50, 0xFE, 0xEA)
+
+	for i := 0; i < 3; i++ {
+		if pc := stepPC(t, cpu); pc != 0x0200 {
+			t.Fatalf("unexpected, got %04X", pc)
+		}
+	}
+	cpu.SO()
+	if pc := stepPC(t, cpu); pc != 0x0202 || !cpu.p.has(flagV) {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+
+	// Only the falling edge sets V.
+	cpu.p.set(false, flagV)
+	cpu.SetOverflowPin(true)
+	cpu.p.set(false, flagV)
+	cpu.SetOverflowPin(true)
+	if cpu.p.has(flagV) {
+		t.Fatal("unexpected")
+	}
+	cpu.SetOverflowPin(false)
+	cpu.SetOverflowPin(true)
+	if !cpu.p.has(flagV) {
+		t.Fatal("unexpected")
+	}
+}
+
+func TestInterrupts(t *testing.T) {
+	// CLI, BRK: BRK is not counted.
+	cpu, _ := newTestCPU(NMOS6502, 0x58, 0x00)
+	cpu.SetAccuracy(AccuracyMinimal)
+	_ = stepPC(t, cpu)
+	_ = stepPC(t, cpu)
+
+	cpu.PC(0x00, 0x02)
+	cpu.p.set(false, flagI)
+	cpu.AssertIRQ()
+	if pc := stepPC(t, cpu); pc != 0x9000 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	cpu.ReleaseIRQ()
+	cpu.AssertNMI()
+	if pc := stepPC(t, cpu); pc != 0x8000 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	_, _ = cpu.NMI()
+
+	if irq, nmi := cpu.Interrupts(); irq != 1 || nmi != 2 {
+		t.Fatalf("unexpected, got %d %d", irq, nmi)
+	}
+	cpu.Reset()
+	if irq, nmi := cpu.Interrupts(); irq != 0 || nmi != 0 {
+		t.Fatalf("unexpected, got %d %d", irq, nmi)
+	}
+}
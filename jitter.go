@@ -0,0 +1,100 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "math/rand"
+
+type (
+	// Jitter is a test utility delaying interrupt assertions by a bounded,
+	// random number of cycles. Devices assert the interrupt lines through the
+	// Jitter instead of the CPU, and the host runs the CPU by Jitter.Step().
+	// This stresses the timing assumptions of the emulated software, while
+	// the seed keeps failing runs reproducible.
+	Jitter struct {
+		cpu *CPU
+		max uint
+		rnd *rand.Rand
+		irq delay
+		nmi delay
+	}
+
+	delay struct {
+		pending  bool // Assertion scheduled
+		released bool // Released before delivery (NMI only)
+		cycles   uint // Remaining cycles until delivery
+	}
+)
+
+// NewJitter creates a Jitter for the CPU delaying interrupt assertions
+// by 0 to maxDelay cycles, chosen by a pseudo-random generator.
+func NewJitter(cpu *CPU, maxDelay uint, seed int64) *Jitter {
+	return &Jitter{cpu: cpu, max: maxDelay, rnd: rand.New(rand.NewSource(seed))}
+}
+
+// AssertIRQ schedules the IRQ line assertion after a random delay.
+func (j *Jitter) AssertIRQ() {
+	if !j.irq.pending && !j.cpu.irq {
+		j.irq = delay{pending: true, cycles: j.next()}
+	}
+}
+
+// ReleaseIRQ releases the IRQ line immediately, a pending
+// delayed assertion is canceled.
+func (j *Jitter) ReleaseIRQ() {
+	j.irq = delay{}
+	j.cpu.ReleaseIRQ()
+}
+
+// AssertNMI schedules the NMI line assertion after a random delay.
+func (j *Jitter) AssertNMI() {
+	if !j.nmi.pending && !j.cpu.nmi {
+		j.nmi = delay{pending: true, cycles: j.next()}
+	}
+}
+
+// ReleaseNMI releases the NMI line. When the assertion is still pending,
+// the edge will be delivered nonetheless, as a delayed pulse.
+func (j *Jitter) ReleaseNMI() {
+	if j.nmi.pending {
+		j.nmi.released = true
+		return
+	}
+	j.cpu.ReleaseNMI()
+}
+
+// Step performs CPU.Step() and delivers the interrupt assertions,
+// whose delay has elapsed.
+func (j *Jitter) Step() (uint, error) {
+	j.deliver(0)
+	cycles, err := j.cpu.Step()
+	j.deliver(cycles)
+	return cycles, err
+}
+
+func (j *Jitter) deliver(cycles uint) {
+	if j.irq.pending && j.irq.elapse(cycles) {
+		j.irq = delay{}
+		j.cpu.AssertIRQ()
+	}
+	if j.nmi.pending && j.nmi.elapse(cycles) {
+		released := j.nmi.released
+		j.nmi = delay{}
+		j.cpu.AssertNMI()
+		if released {
+			j.cpu.ReleaseNMI()
+		}
+	}
+}
+
+func (j *Jitter) next() uint {
+	return uint(j.rnd.Int63n(int64(j.max) + 1))
+}
+
+func (d *delay) elapse(cycles uint) bool {
+	if cycles >= d.cycles {
+		d.cycles = 0
+		return true
+	}
+	d.cycles -= cycles
+	return false
+}
@@ -0,0 +1,98 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"bytes"
+	"testing"
+)
+
+var nops = bytes.Repeat([]byte{0xEA}, 0x100) // NOP
+
+// latency returns the cycles from assertion until the handler is entered.
+func latency(t *testing.T, j *Jitter, cpu *CPU, assert func()) uint {
+	t.Helper()
+	total := uint(0)
+	assert()
+	for i := 0; i < 100; i++ {
+		cycles, err := j.Step()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cpu.pch == 0x80 || cpu.pch == 0x90 {
+			return total
+		}
+		total += cycles
+	}
+	t.Fatal("unexpected, interrupt not serviced")
+	return 0
+}
+
+func TestJitterBounds(t *testing.T) {
+	seen := map[uint]bool{}
+	for seed := int64(0); seed < 50; seed++ {
+		cpu, _ := newTestCPU(NMOS6502, nops...)
+		j := NewJitter(cpu, 10, seed)
+
+		l := latency(t, j, cpu, j.AssertIRQ)
+		if l > 10+2 { // delay plus one NOP overshoot
+			t.Fatalf("unexpected, got %d", l)
+		}
+		seen[l] = true
+	}
+	if len(seen) < 3 {
+		t.Fatalf("unexpected, got %v", seen)
+	}
+}
+
+func TestJitterReproducible(t *testing.T) {
+	run := func(seed int64) (ls []uint) {
+		cpu, _ := newTestCPU(NMOS6502, nops...)
+		j := NewJitter(cpu, 20, seed)
+		for i := 0; i < 5; i++ {
+			ls = append(ls, latency(t, j, cpu, j.AssertNMI))
+			j.ReleaseNMI()
+			cpu.PC(0x00, 0x02)
+		}
+		return ls
+	}
+	a, b := run(42), run(42)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("unexpected, got %v and %v", a, b)
+		}
+	}
+}
+
+func TestJitterRelease(t *testing.T) {
+	cpu, _ := newTestCPU(NMOS6502, nops...)
+	j := NewJitter(cpu, 100, 1)
+
+	j.AssertIRQ()
+	j.ReleaseIRQ()
+	for i := 0; i < 50; i++ {
+		_, _ = j.Step()
+	}
+	if cpu.irq || cpu.pch != 0x02 {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+
+	// Pulse shorter than the delay still delivers the NMI edge.
+	j.AssertNMI()
+	j.ReleaseNMI()
+	for i := 0; i < 60 && cpu.pch != 0x80; i++ {
+		_, _ = j.Step()
+	}
+	if cpu.pch != 0x80 || cpu.nmi {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+}
+
+func TestJitterZero(t *testing.T) {
+	cpu, _ := newTestCPU(NMOS6502, nops...)
+	j := NewJitter(cpu, 0, 1)
+
+	if l := latency(t, j, cpu, j.AssertIRQ); l != 0 {
+		t.Fatalf("unexpected, got %d", l)
+	}
+}
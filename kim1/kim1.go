@@ -0,0 +1,169 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+// Package kim1 is an example machine composed of the CPU and the devices of
+// this module, the MOS KIM-1 single board computer, usable as a template for
+// other machines. It has 1K RAM, two 6530 RRIOT chips with 1K ROM, 64 bytes
+// RAM, I/O ports and an interval timer each, a six digit seven-segment
+// display and a keypad. Address lines A13-A15 are not decoded, the 8K
+// address space is mirrored, which places the vectors of the monitor ROM
+// at 0x1FFA-0x1FFF. The ROM images are not part of this module.
+//
+//	k := kim1.New(rom002, rom003)
+//	k.Reset()
+//	for {
+//	    k.Step()
+//	    fmt.Printf("\r%s", k.Digits())
+//	}
+//
+// The serial TTY interface and the cassette interface are not emulated.
+package kim1
+
+import "github.com/dtgorski/m6502"
+
+type (
+	// Machine is a KIM-1.
+	Machine struct {
+		CPU     *m6502.CPU
+		Bus     *m6502.DeviceBus // Undecoded 8K address space
+		r002    *m6502.RIOT      // 6530-002: monitor, display and keypad
+		r003    *m6502.RIOT      // 6530-003: cassette, expansion ports
+		display [6]byte
+		key     Key
+	}
+
+	// Key is a key of the keypad.
+	Key byte
+
+	// rriot adapts the registers of a 6532 RIOT to the 6530 layout,
+	// which selects the timer by A2 alone, not by A2 and A4.
+	rriot struct{ m6502.Device }
+
+	// mirror ignores the address lines A13-A15.
+	mirror struct{ *m6502.DeviceBus }
+)
+
+// Keys of the keypad: the hex digits 0x00-0x0F, then the command keys. ST
+// triggers an NMI, RS resets the CPU.
+const (
+	KeyAD   Key = 0x10 + iota // Address mode
+	KeyDA                     // Data mode
+	KeyPlus                   // Increment address
+	KeyGO                     // Run at address
+	KeyPC                     // Recall program counter
+	KeyST                     // Stop, NMI
+	KeyRS                     // Reset
+	KeyNone Key = 0xFF
+)
+
+// segments holds the seven-segment patterns of the hex digits, a = bit 0.
+var segments = [0x10]byte{
+	0x3F, 0x06, 0x5B, 0x4F, 0x66, 0x6D, 0x7D, 0x07,
+	0x7F, 0x6F, 0x77, 0x7C, 0x39, 0x5E, 0x79, 0x71,
+}
+
+// New creates a KIM-1 with the 1K images of the 6530-002 ROM at 0x1C00
+// and the 6530-003 ROM at 0x1800. Call Reset() to start the monitor. New
+// panics on empty images, see m6502.NewROM().
+func New(rom002, rom003 []byte) *Machine {
+	m := &Machine{
+		Bus:  m6502.NewDeviceBus(),
+		r002: m6502.NewRIOT(),
+		r003: m6502.NewRIOT(),
+		key:  KeyNone,
+	}
+	m.Bus.Attach(0x0000, 0x03FF, 0, m6502.NewRAM(0x0400))
+	m.Bus.Attach(0x1700, 0x173F, 0, rriot{m.r003.IO()})
+	m.Bus.Attach(0x1740, 0x177F, 0, rriot{m.r002.IO()})
+	m.Bus.Attach(0x1780, 0x17BF, 0, m.r003.RAM())
+	m.Bus.Attach(0x17C0, 0x17FF, 0, m.r002.RAM())
+	m.Bus.Attach(0x1800, 0x1BFF, 0, m6502.NewROM(rom003))
+	m.Bus.Attach(0x1C00, 0x1FFF, 0, m6502.NewROM(rom002))
+	m.CPU = m6502.New(mirror{m.Bus})
+	return m
+}
+
+// Reset resets the CPU, like the RS key.
+func (m *Machine) Reset() {
+	m.CPU.Reset()
+}
+
+// Step performs an instruction, advances the timers and updates the display
+// and the keypad, see m6502.CPU.Step().
+func (m *Machine) Step() (uint, error) {
+	n, err := m.CPU.Step()
+	m.r002.Tick(n)
+	m.r003.Tick(n)
+	m.scan()
+	return n, err
+}
+
+// Press presses the key, replacing a key pressed before. KeyST asserts the
+// NMI line until Release(), KeyRS resets the CPU.
+func (m *Machine) Press(k Key) {
+	switch m.Release(); k {
+	case KeyST:
+		m.CPU.Lines().AssertNMI()
+	case KeyRS:
+		m.Reset()
+	default:
+		m.key = k
+	}
+	m.scan()
+}
+
+// Release releases the key pressed.
+func (m *Machine) Release() {
+	m.CPU.Lines().ReleaseNMI()
+	m.key = KeyNone
+	m.scan()
+}
+
+// Display returns the segment patterns of the digits shown, left to right,
+// segment a in bit 0 to segment g in bit 6.
+func (m *Machine) Display() [6]byte {
+	return m.display
+}
+
+// Digits returns the hex digits shown, "?" for other patterns.
+func (m *Machine) Digits() string {
+	s := []byte("??????")
+	for i, p := range m.display {
+		for d, q := range segments {
+			if p == q {
+				s[i] = "0123456789ABCDEF"[d]
+			}
+		}
+	}
+	return string(s)
+}
+
+// scan follows the multiplexing of the display and the keypad. PB1-PB4
+// select via a decoder a row of the keypad (0-2) or a digit (4-9). The
+// segments are driven by PA0-PA6, the keys of the selected row pull the
+// inputs PA6 (first key) to PA0 (seventh key) low.
+func (m *Machine) scan() {
+	sel := int(m.r002.PortB()>>1) & 0x0F
+	if sel >= 4 && sel <= 9 && m.r002.IO().Read(0x01)&0x7F != 0 {
+		m.display[sel-4] = m.r002.PortA() & 0x7F
+	}
+	in := byte(0xFF)
+	if m.key < KeyST && int(m.key)/7 == sel {
+		in &^= 0x40 >> (m.key % 7)
+	}
+	m.r002.SetPortA(in)
+}
+
+func (r rriot) Write(addr uint16, db byte) {
+	if addr&0x04 != 0 {
+		addr |= 0x10 // Timer
+	}
+	r.Device.Write(addr, db)
+}
+
+func (b mirror) Read(lo, hi byte) byte {
+	return b.DeviceBus.Read(lo, hi&0x1F)
+}
+
+func (b mirror) Write(lo, hi, db byte) {
+	b.DeviceBus.Write(lo, hi&0x1F, db)
+}
@@ -0,0 +1,83 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package kim1
+
+import (
+	"testing"
+
+	"github.com/dtgorski/m6502/prog"
+)
+
+// program shows "1A2B3C" and then stores the keys of row 1 to 0x0000.
+var program = prog.New(0x1C00).
+	LDAImm(0x7F).STAAbs(0x1741). // PADD: segments output
+	LDAImm(0x1E).STAAbs(0x1743). // PBDD: decoder output
+	LDXImm(0x08).Label("digit"). // Select digit 0
+	STXAbs(0x1742).
+	LDAAbsX(0x1E00).
+	STAAbs(0x1740).
+	INX().INX().CPXImm(0x14).BNE("digit").
+	LDAImm(0x00).STAAbs(0x1741). // PADD: keys input
+	LDXImm(0x02).STXAbs(0x1742). // Select row 1
+	LDAAbs(0x1740).STAZp(0x00).
+	Label("done").JMP("done")
+
+func rom(t *testing.T) []byte {
+	code, err := program.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := make([]byte, 0x0400)
+	copy(img, code)
+	for i, d := range []byte{0x1, 0xA, 0x2, 0xB, 0x3, 0xC} {
+		img[0x0208+2*i] = segments[d]
+	}
+	img[0x0100] = 0x02                    // HLT at 0x1D00
+	img[0x03FA], img[0x03FB] = 0x00, 0x1D // NMI
+	img[0x03FC], img[0x03FD] = 0x00, 0x1C // Reset
+	return img
+}
+
+func TestMachine(t *testing.T) {
+	k := New(rom(t), make([]byte, 0x0400))
+	k.Reset()
+	k.Press(0x08)
+
+	for i := 0; i < 100; i++ {
+		if _, err := k.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if k.Digits() != "1A2B3C" || k.Display()[0] != 0x06 {
+		t.Fatalf("unexpected, got %s", k.Digits())
+	}
+	if b := k.Bus.Read(0x00, 0x00); b != 0xDF {
+		t.Fatalf("unexpected, got %02X", b)
+	}
+
+	k.Press(KeyST)
+	_, _ = k.Step()
+	if _, err := k.Step(); err == nil || k.CPU.PCH() != 0x1D {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	k.Press(KeyRS)
+	if k.CPU.PCH() != 0x1C || k.CPU.PCL() != 0x00 {
+		t.Fatalf("unexpected, got %s", k.CPU)
+	}
+}
+
+func TestTimer(t *testing.T) {
+	k := New(rom(t), make([]byte, 0x0400))
+	mirror{k.Bus}.Write(0x04, 0xF7, 0x10) // 6530-003 timer /1, mirrored
+	k.r003.Tick(4)
+	if b := k.Bus.Read(0x06, 0x17); b != 0x0C {
+		t.Fatalf("unexpected, got %02X", b)
+	}
+}
+
+func TestDigits(t *testing.T) {
+	k := New(rom(t), rom(t))
+	if k.Digits() != "??????" {
+		t.Fatalf("unexpected, got %s", k.Digits())
+	}
+}
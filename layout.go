@@ -0,0 +1,83 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Layout is a template of the state string returned by String(), also
+// applicable to a Trace, to match the logs of other emulators for diffing.
+// Placeholders are replaced by the state, other text is copied verbatim:
+//
+//	{CPU}    "m6502", or "m6502[name]" of a named CPU
+//	{PC}     Program counter, 4 hex digits
+//	{A}      Accumulator, 2 hex digits, likewise {X}, {Y}, {S} and {P}
+//	{FLAGS}  Flags NVDIZC, "-" when clear
+//	{flags}  Flags NVDIZC, lowercase when clear
+//	{CYC}    Cycles since Reset(), decimal
+type Layout string
+
+// DefaultLayout is the Layout of String() unless set otherwise.
+const DefaultLayout Layout = "{CPU}: PC={PC} A={A} X={X} Y={Y} [{FLAGS}] S={S}"
+
+// SetLayout sets the Layout of String(). Passing "" restores DefaultLayout.
+func (cpu *CPU) SetLayout(l Layout) {
+	cpu.layout = l
+}
+
+// Render returns the state string of the Trace.
+func (l Layout) Render(t Trace) string {
+	if l == "" {
+		l = DefaultLayout
+	}
+	b := strings.Builder{}
+	for s := string(l); s != ""; {
+		i := strings.IndexByte(s, '{')
+		j := strings.IndexByte(s[i+1:], '}') + i + 1
+		if i < 0 || j <= i {
+			b.WriteString(s)
+			break
+		}
+		b.WriteString(s[:i])
+		b.WriteString(placeholder(s[i:j+1], t))
+		s = s[j+1:]
+	}
+	return b.String()
+}
+
+func placeholder(p string, t Trace) string {
+	switch p {
+	case "{CPU}":
+		if t.CPU == "" {
+			return "m6502"
+		}
+		return "m6502[" + t.CPU + "]"
+	case "{PC}":
+		return fmt.Sprintf("%04X", t.PC)
+	case "{A}":
+		return fmt.Sprintf("%02X", t.A)
+	case "{X}":
+		return fmt.Sprintf("%02X", t.X)
+	case "{Y}":
+		return fmt.Sprintf("%02X", t.Y)
+	case "{S}":
+		return fmt.Sprintf("%02X", t.S)
+	case "{P}":
+		return fmt.Sprintf("%02X", t.P)
+	case "{FLAGS}":
+		return flag(t.P).String()
+	case "{flags}":
+		f := []byte(flag(t.P).String())
+		for i, c := range f {
+			if c == '-' {
+				f[i] = "nvdizc"[i]
+			}
+		}
+		return string(f)
+	case "{CYC}":
+		return fmt.Sprint(t.Cycles)
+	}
+	return p
+}
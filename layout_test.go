@@ -0,0 +1,29 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+func TestLayout(t *testing.T) {
+	cpu := New(&memoryBus{})
+	cpu.PC(0x34, 0x12)
+	cpu.a, cpu.x, cpu.y, cpu.s = 0x01, 0x02, 0x03, 0xFD
+	cpu.p.set(true, flagN|flagC)
+
+	if s := cpu.String(); s != "m6502: PC=1234 A=01 X=02 Y=03 [N----C] S=FD" {
+		t.Fatalf("unexpected, got %s", s)
+	}
+	cpu.SetName("main")
+	cpu.SetLayout("{PC} A:{A} X:{X} Y:{Y} P:{P} {flags} CYC:{CYC} {CPU} {FOO} {")
+	if s := cpu.String(); s != "1234 A:01 X:02 Y:03 P:A1 NvdizC CYC:7 m6502[main] {FOO} {" {
+		t.Fatalf("unexpected, got %s", s)
+	}
+	cpu.SetLayout("")
+	if s := cpu.String(); s != "m6502[main]: PC=1234 A=01 X=02 Y=03 [N----C] S=FD" {
+		t.Fatalf("unexpected, got %s", s)
+	}
+	l := Layout("{PC} {S}} }{")
+	if s := l.Render(Trace{PC: 0xC000, S: 0xFF}); s != "C000 FF} }{" {
+		t.Fatalf("unexpected, got %s", s)
+	}
+}
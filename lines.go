@@ -0,0 +1,74 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "sync/atomic"
+
+// Lines drives the interrupt lines of a CPU from other goroutines, e.g. UI
+// or timer goroutines of peripherals. Its methods are safe for concurrent
+// use, also concurrently to Step(). Changes are sampled by Step() at the
+// next instruction boundary, thus without cycle accuracy. A line is
+// asserted as long as either the Lines or the CPU methods assert it.
+//
+// The methods of the CPU driving the lines, e.g. CPU.AssertIRQ(), are not
+// safe for concurrent use: call them from the goroutine running Step(),
+// e.g. from within Bus callbacks, where they are accurate to the cycle.
+type Lines struct {
+	irq   atomic.Bool // IRQ line level requested
+	nmi   atomic.Bool // NMI line level requested
+	edge  atomic.Bool // NMI falling edge occurred
+	dirty atomic.Bool // Changes not yet sampled
+}
+
+// Lines returns the concurrency-safe interrupt lines of the CPU.
+func (cpu *CPU) Lines() *Lines {
+	return &cpu.async
+}
+
+// AssertIRQ pulls the IRQ line low, see CPU.AssertIRQ().
+func (l *Lines) AssertIRQ() {
+	l.irq.Store(true)
+	l.dirty.Store(true)
+}
+
+// ReleaseIRQ releases the IRQ line, see CPU.ReleaseIRQ().
+func (l *Lines) ReleaseIRQ() {
+	l.irq.Store(false)
+	l.dirty.Store(true)
+}
+
+// AssertNMI pulls the NMI line low, see CPU.AssertNMI(). The falling
+// edge is delivered, even when the line is released before sampling.
+func (l *Lines) AssertNMI() {
+	if l.nmi.CompareAndSwap(false, true) {
+		l.edge.Store(true)
+	}
+	l.dirty.Store(true)
+}
+
+// ReleaseNMI releases the NMI line, see CPU.ReleaseNMI().
+func (l *Lines) ReleaseNMI() {
+	l.nmi.Store(false)
+	l.dirty.Store(true)
+}
+
+// sample applies the changes of the Lines at an instruction boundary. The
+// Lines are a source of their own, combined with the lines driven by the
+// methods of the CPU. An NMI edge is delivered as a pulse of the Lines.
+func (cpu *CPU) sample() {
+	l := &cpu.async
+	if !l.dirty.Swap(false) {
+		return
+	}
+	cpu.drive.irqAsync = l.irq.Load()
+	cpu.driveIRQ()
+
+	if l.edge.Swap(false) {
+		cpu.drive.nmiAsync = false
+		cpu.driveNMI()
+		cpu.drive.nmiAsync = true
+		cpu.driveNMI()
+	}
+	cpu.drive.nmiAsync = l.nmi.Load()
+	cpu.driveNMI()
+}
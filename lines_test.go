@@ -0,0 +1,102 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestLinesIRQ(t *testing.T) {
+	cpu, _ := newTestCPU(NMOS6502, 0x58, 0xEA, 0xEA) // CLI, NOP, NOP
+	stepPC(t, cpu)
+
+	cpu.Lines().AssertIRQ()
+	if cpu.irq {
+		t.Fatal("unexpected, sampled before boundary")
+	}
+	if pc := stepPC(t, cpu); pc != 0x9000 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	cpu.Lines().ReleaseIRQ()
+	cpu.sample()
+	if cpu.irq {
+		t.Fatal("unexpected, still asserted")
+	}
+}
+
+func TestLinesNMI(t *testing.T) {
+	cpu, _ := newTestCPU(NMOS6502, 0xEA, 0xEA)
+
+	// Edge is delivered, although released before sampling.
+	cpu.Lines().AssertNMI()
+	cpu.Lines().ReleaseNMI()
+
+	if pc := stepPC(t, cpu); pc != 0x8000 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	if cpu.nmi || cpu.nmiEdge {
+		t.Fatal("unexpected, line not released")
+	}
+}
+
+func TestLinesWiredOR(t *testing.T) {
+	cpu, bus := newTestCPU(NMOS6502, 0x58, 0xEA, 0xEA) // CLI, NOP, NOP
+	bus.mem[0x8000] = 0x40                             // RTI
+	stepPC(t, cpu)
+
+	// The device keeps IRQ asserted, released by the timer only.
+	cpu.p |= flagI
+	cpu.AssertIRQ()
+	cpu.Lines().AssertIRQ()
+	cpu.Lines().ReleaseIRQ()
+	stepPC(t, cpu)
+	if !cpu.irq {
+		t.Fatal("unexpected, device IRQ lost")
+	}
+	cpu.p &^= flagI
+	if pc := stepPC(t, cpu); pc != 0x9000 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	if irq, _ := cpu.Interrupts(); irq != 1 {
+		t.Fatalf("unexpected, got %d", irq)
+	}
+
+	// The timer keeps NMI asserted, the device releases its own.
+	cpu.Lines().AssertNMI()
+	stepPC(t, cpu)
+	cpu.AssertNMI()
+	cpu.ReleaseNMI()
+	if !cpu.nmi || cpu.nmiEdge {
+		t.Fatal("unexpected, no wired-OR")
+	}
+	cpu.ReleaseIRQ()
+	cpu.Lines().ReleaseNMI()
+	if stepPC(t, cpu); cpu.irq || cpu.nmi {
+		t.Fatal("unexpected, still asserted")
+	}
+}
+
+func TestLinesConcurrent(t *testing.T) {
+	cpu, bus := newTestCPU(NMOS6502, 0x58, 0x4C, 0x01, 0x02) // CLI; JMP $0201
+	bus.mem[0x8000] = 0x40                                   // RTI
+	bus.mem[0x9000] = 0x40                                   // RTI
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			cpu.Lines().AssertIRQ()
+			cpu.Lines().AssertNMI()
+			cpu.Lines().ReleaseNMI()
+			cpu.Lines().ReleaseIRQ()
+		}
+	}()
+	for i := 0; i < 10000; i++ {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+}
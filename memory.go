@@ -0,0 +1,114 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "fmt"
+
+type (
+	// RAM is a readable and writable memory region. Addresses beyond the
+	// size are mirrored. RAM implements Bus16, use it with Adapt16() or as
+	// region of a Mapper.
+	RAM struct{ data []byte }
+
+	// ROM is a read-only memory region, writes are ignored. Addresses beyond
+	// the size are mirrored. ROM implements Bus16, see RAM.
+	ROM struct{ data []byte }
+
+	// Mapper is a Bus routing address ranges to regions. A region sees the
+	// addresses relative to the start of its range. Accessing an address
+	// not mapped panics, Step() returns the error.
+	Mapper struct {
+		ranges []mapping
+		cpu    *CPU // CPU created with the Mapper, receiving diagnostics
+	}
+
+	mapping struct {
+		start, end uint16
+		region     Bus16
+		protect    WriteProtect
+	}
+)
+
+// NewRAM creates a zeroed RAM region of size bytes, 1 to 0x10000.
+func NewRAM(size int) *RAM {
+	if size < 1 || size > 0x10000 {
+		panic(fmt.Sprintf("m6502: invalid RAM size %d", size))
+	}
+	return &RAM{data: make([]byte, size)}
+}
+
+// NewROM creates a ROM region holding a copy of data, 1 to 0x10000 bytes.
+func NewROM(data []byte) *ROM {
+	if len(data) < 1 || len(data) > 0x10000 {
+		panic(fmt.Sprintf("m6502: invalid ROM size %d", len(data)))
+	}
+	return &ROM{data: append([]byte(nil), data...)}
+}
+
+// Read reads a byte from the RAM.
+func (r *RAM) Read(addr uint16) byte {
+	return r.data[int(addr)%len(r.data)]
+}
+
+// Write writes a byte to the RAM.
+func (r *RAM) Write(addr uint16, db byte) {
+	r.data[int(addr)%len(r.data)] = db
+}
+
+// Load copies data into the RAM at addr.
+func (r *RAM) Load(addr uint16, data []byte) {
+	for i, b := range data {
+		r.Write(addr+uint16(i), b)
+	}
+}
+
+// Read reads a byte from the ROM.
+func (r *ROM) Read(addr uint16) byte {
+	return r.data[int(addr)%len(r.data)]
+}
+
+// Write is ignored.
+func (r *ROM) Write(uint16, byte) {}
+
+// NewMapper creates a Mapper without mapped ranges.
+func NewMapper() *Mapper {
+	return &Mapper{}
+}
+
+// Map routes the addresses start to end (inclusive) to the region. A range
+// mapped later takes precedence over overlapping earlier ranges, e.g. to
+// place I/O registers within RAM. Map panics when end is less than start.
+func (m *Mapper) Map(start, end uint16, region Bus16) *Mapper {
+	if end < start {
+		panic(fmt.Sprintf("m6502: invalid range %04X-%04X", start, end))
+	}
+	m.ranges = append([]mapping{{start, end, region, ProtectNone}}, m.ranges...)
+	return m
+}
+
+// Read reads a byte from the region mapped at the address.
+func (m *Mapper) Read(lo, hi byte) byte {
+	addr := uint16(hi)<<8 | uint16(lo)
+	r := m.lookup(addr, "read")
+	return r.region.Read(addr - r.start)
+}
+
+// Write writes a byte to the region mapped at the address.
+func (m *Mapper) Write(lo, hi, db byte) {
+	addr := uint16(hi)<<8 | uint16(lo)
+	r := m.lookup(addr, "write")
+	if r.protect != ProtectNone {
+		m.protected(r, addr, db)
+		return
+	}
+	r.region.Write(addr-r.start, db)
+}
+
+func (m *Mapper) lookup(addr uint16, access string) *mapping {
+	for i := range m.ranges {
+		if r := &m.ranges[i]; addr >= r.start && addr <= r.end {
+			return r
+		}
+	}
+	panic(fmt.Sprintf("m6502: unmapped %s %04X", access, addr))
+}
@@ -0,0 +1,55 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+func TestRAM(t *testing.T) {
+	ram := NewRAM(0x0800)
+	ram.Write(0x0001, 0x42)
+
+	if ram.Read(0x0001) != 0x42 || ram.Read(0x0801) != 0x42 {
+		t.Fatal("unexpected")
+	}
+	ram.Load(0x07FF, []byte{0x01, 0x02})
+	if ram.Read(0x07FF) != 0x01 || ram.Read(0x0000) != 0x02 {
+		t.Fatal("unexpected")
+	}
+}
+
+func TestROM(t *testing.T) {
+	data := []byte{0x11, 0x22}
+	rom := NewROM(data)
+	data[0] = 0x00
+	rom.Write(0x0000, 0x33)
+
+	if rom.Read(0x0000) != 0x11 || rom.Read(0x0003) != 0x22 {
+		t.Fatal("unexpected")
+	}
+}
+
+func TestMapper(t *testing.T) {
+	rom := make([]byte, 0x1000)
+	rom[0x0FFC], rom[0x0FFD] = 0x00, 0x02 // Reset vector
+	io := NewRAM(0x10)
+
+	m := NewMapper().
+		Map(0x0000, 0xEFFF, NewRAM(0x10000)).
+		Map(0xF000, 0xFFFF, NewROM(rom)).
+		Map(0xD000, 0xD0FF, io)
+
+	cpu := New(m)
+	if cpu.pc() != 0x0200 {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+	m.Write(0x12, 0xD0, 0x42)
+	if io.Read(0x0002) != 0x42 || m.Read(0x02, 0xD0) != 0x42 {
+		t.Fatal("unexpected")
+	}
+
+	m = NewMapper().Map(0x0000, 0x00FF, NewRAM(0x100)).Map(0xFF00, 0xFFFF, NewROM(rom[0x0F00:]))
+	cpu = New(m)
+	if _, err := cpu.Step(); err == nil || err.Error() != "m6502: 0200: unmapped read 0200" {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
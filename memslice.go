@@ -0,0 +1,36 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+// MemSlicer is an optional interface of a Bus or Bus16 backed by a plain
+// slice of 64K bytes without side effects. New() detects it and lets the
+// CPU access the slice directly, bypassing the Read() and Write() calls.
+// Buses decorating another one must not implement it.
+type MemSlicer interface {
+
+	// MemSlice returns the backing slice. It is called once by New(),
+	// a length other than 0x10000 disables the fast path.
+	MemSlice() []byte
+}
+
+// MemSlice returns the backing slice of the RAM, see MemSlicer.
+func (r *RAM) MemSlice() []byte {
+	return r.data
+}
+
+func (b bus16) MemSlice() []byte {
+	if m, ok := b.Bus16.(MemSlicer); ok {
+		return m.MemSlice()
+	}
+	return nil
+}
+
+// flat returns the backing memory of the bus, nil when not a MemSlicer.
+func flat(bus Bus) *[0x10000]byte {
+	if m, ok := bus.(MemSlicer); ok {
+		if s := m.MemSlice(); len(s) == 0x10000 {
+			return (*[0x10000]byte)(s)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+type sliceBus struct{ memoryBus }
+
+func (m *sliceBus) MemSlice() []byte { return m.mem[:] }
+
+var loop = []byte{
+	0xA2, 0x00, // LDX #$00
+	0xBD, 0x00, 0x30, // LDA $3000,X
+	0x69, 0x01, // ADC #$01
+	0x9D, 0x00, 0x30, // STA $3000,X
+	0xE8,       // INX
+	0xD0, 0xF5, // BNE $0202
+	0x4C, 0x00, 0x02, // JMP $0200
+}
+
+func TestMemSlice(t *testing.T) {
+	if cpu := New(Adapt16(NewRAM(0x8000))); cpu.mem != nil {
+		t.Fatal("unexpected, fast path for 32K")
+	}
+	if cpu := New(&memoryBus{}); cpu.mem != nil {
+		t.Fatal("unexpected, fast path without MemSlicer")
+	}
+	ram := NewRAM(0x10000)
+	ram.Load(0x0200, loop)
+	fast, slow := New(Adapt16(ram)), New(&memoryBus{})
+	copy(slow.bus.(*memoryBus).mem[0x0200:], loop)
+
+	if fast.mem == nil {
+		t.Fatal("unexpected, no fast path")
+	}
+	fast.PC(0x00, 0x02)
+	slow.PC(0x00, 0x02)
+
+	for i := 0; i < 10000; i++ {
+		c1, err1 := fast.Step()
+		c2, err2 := slow.Step()
+		if c1 != c2 || err1 != nil || err2 != nil || fast.String() != slow.String() {
+			t.Fatalf("unexpected, got %s vs %s", fast, slow)
+		}
+	}
+	for a := uint16(0x3000); a < 0x3100; a++ {
+		if ram.Read(a) != slow.bus.(*memoryBus).mem[a] {
+			t.Fatalf("unexpected, got %02X at %04X", ram.Read(a), a)
+		}
+	}
+}
+
+func BenchmarkMemSlice(b *testing.B) {
+	for name, bus := range map[string]Bus{"Bus": &memoryBus{}, "MemSlice": &sliceBus{}} {
+		b.Run(name, func(b *testing.B) {
+			cpu := New(bus)
+			for i, v := range loop {
+				bus.Write(byte(i), 0x02, v)
+			}
+			cpu.PC(0x00, 0x02)
+
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				if _, err := cpu.Step(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+// Package metrics exposes the activity of a CPU for long-running emulation
+// services, as expvar variable or in the Prometheus text format. The CPU is
+// stepped through the Collector, the metrics may be read concurrently:
+//
+//	c := metrics.New(cpu)
+//	c.Publish("m6502")                      // expvar, see /debug/vars
+//	http.Handle("/metrics", c.Handler())   // Prometheus
+//	for {
+//		if _, err := c.Step(); err != nil {
+//			...
+//		}
+//	}
+package metrics
+
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dtgorski/m6502"
+)
+
+type (
+	// Collector counts the activity of a CPU stepped by Step().
+	Collector struct {
+		cpu          *m6502.CPU
+		instructions atomic.Uint64
+		cycles       atomic.Uint64
+		irqs         atomic.Uint64
+		nmis         atomic.Uint64
+		halts        atomic.Uint64
+		remove       func()
+
+		mu    sync.Mutex // Guards the rate sampling
+		start time.Time
+		then  time.Time
+		count uint64 // Instructions at the previous sample
+	}
+
+	// Snapshot is the state of a Collector.
+	Snapshot struct {
+		Instructions uint64  `json:"instructions"` // Instructions retired
+		Cycles       uint64  `json:"cycles"`       // Cycles performed by Step()
+		IRQs         uint64  `json:"irqs"`         // IRQ sequences
+		NMIs         uint64  `json:"nmis"`         // NMI sequences
+		Halts        uint64  `json:"halts"`        // Transitions into the halted state
+		IPS          float64 `json:"ips"`          // Instructions per second, see Snapshot()
+	}
+)
+
+// New creates a Collector for the CPU. The instructions are counted by a
+// Hook with m6502.PriorityProfiler, see Close().
+func New(cpu *m6502.CPU) *Collector {
+	c := &Collector{cpu: cpu, start: time.Now()}
+	c.then = c.start
+	c.remove = cpu.AddHook(m6502.PriorityProfiler, func(*m6502.CPU, m6502.Trace) bool {
+		c.instructions.Add(1)
+		return false
+	})
+	return c
+}
+
+// Close removes the Hook of the Collector, the counters are kept.
+func (c *Collector) Close() {
+	c.remove()
+}
+
+// Step performs CPU.Step() and counts its outcome, interrupts serviced
+// by CPU.IRQ() and CPU.NMI() are not counted.
+func (c *Collector) Step() (uint, error) {
+	irq, nmi := c.cpu.Interrupts()
+	halted := c.cpu.Halted()
+	cycles, err := c.cpu.Step()
+
+	c.cycles.Add(uint64(cycles))
+	i, n := c.cpu.Interrupts()
+	c.irqs.Add(i - irq)
+	c.nmis.Add(n - nmi)
+
+	if !halted && c.cpu.Halted() {
+		c.halts.Add(1)
+	}
+	return cycles, err
+}
+
+// Snapshot returns the current counters. IPS is the rate of instructions
+// since the previous Snapshot(), since New() for the first one.
+func (c *Collector) Snapshot() Snapshot {
+	s := Snapshot{
+		Instructions: c.instructions.Load(),
+		Cycles:       c.cycles.Load(),
+		IRQs:         c.irqs.Load(),
+		NMIs:         c.nmis.Load(),
+		Halts:        c.halts.Load(),
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if d := now.Sub(c.then).Seconds(); d > 0 {
+		s.IPS = float64(s.Instructions-c.count) / d
+	}
+	c.then, c.count = now, s.Instructions
+	return s
+}
+
+// Publish exports the Snapshot as expvar variable. Like expvar.Publish(),
+// it panics when the name is already in use.
+func (c *Collector) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any { return c.Snapshot() }))
+}
+
+// WritePrometheus writes the Snapshot in the Prometheus text format, the
+// metrics are prefixed by "m6502_".
+func (c *Collector) WritePrometheus(w io.Writer) error {
+	s := c.Snapshot()
+	for _, m := range []struct {
+		name, kind, help string
+		value            any
+	}{
+		{"instructions_total", "counter", "Instructions retired.", s.Instructions},
+		{"cycles_total", "counter", "Cycles performed.", s.Cycles},
+		{"irqs_total", "counter", "IRQ sequences performed.", s.IRQs},
+		{"nmis_total", "counter", "NMI sequences performed.", s.NMIs},
+		{"halts_total", "counter", "Transitions into the halted state.", s.Halts},
+		{"instructions_per_second", "gauge", "Instructions per second since the previous scrape.", s.IPS},
+	} {
+		_, err := fmt.Fprintf(w, "# HELP m6502_%s %s\n# TYPE m6502_%s %s\nm6502_%s %v\n",
+			m.name, m.help, m.name, m.kind, m.name, m.value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler returns a http.Handler serving WritePrometheus().
+func (c *Collector) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		_ = c.WritePrometheus(w)
+	})
+}
@@ -0,0 +1,101 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dtgorski/m6502"
+)
+
+type memory [0x10000]byte
+
+func (m *memory) Read(l, h byte) byte { return m[uint16(h)<<8|uint16(l)] }
+func (m *memory) Write(l, h, db byte) { m[uint16(h)<<8|uint16(l)] = db }
+
+func newCollector() (*Collector, *m6502.CPU) {
+	mem := &memory{}
+	// CLI, NOP, HLT, HLT
+	copy(mem[0x0200:], []byte{0x58, 0xEA, 0x02, 0x02})
+	mem[0x9000] = 0x40 // RTI
+	mem[0xFFFA], mem[0xFFFB] = 0x00, 0x90
+	mem[0xFFFE], mem[0xFFFF] = 0x00, 0x90
+
+	cpu := m6502.New(mem)
+	cpu.PC(0x00, 0x02)
+	return New(cpu), cpu
+}
+
+func TestCollector(t *testing.T) {
+	c, cpu := newCollector()
+
+	_, _ = c.Step() // CLI
+	cpu.AssertIRQ()
+	_, _ = c.Step() // IRQ
+	cpu.ReleaseIRQ()
+	_, _ = c.Step() // RTI
+	cpu.AssertNMI()
+	_, _ = c.Step() // NMI
+	_, _ = c.Step() // RTI
+	_, _ = c.Step() // NOP
+	for i := 0; i < 3; i++ {
+		if _, err := c.Step(); err == nil {
+			t.Fatal("unexpected, got nil")
+		}
+	}
+	cpu.Resume()
+	_, _ = c.Step()
+
+	s := c.Snapshot()
+	want := Snapshot{Instructions: 6, Cycles: 2 + 7 + 6 + 7 + 6 + 2 + 1 + 1, IRQs: 1, NMIs: 1, Halts: 2}
+	if s.IPS <= 0 {
+		t.Fatalf("unexpected, got %f", s.IPS)
+	}
+	if s.IPS = 0; s != want {
+		t.Fatalf("unexpected, got %+v", s)
+	}
+
+	c.Close()
+	_, _ = cpu.Step()
+	if s := c.Snapshot(); s.Instructions != 6 {
+		t.Fatalf("unexpected, got %d", s.Instructions)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	c, _ := newCollector()
+	_, _ = c.Step()
+
+	c.Publish("m6502_test")
+	s := Snapshot{}
+	if err := json.Unmarshal([]byte(expvar.Get("m6502_test").String()), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Instructions != 1 || s.Cycles != 2 {
+		t.Fatalf("unexpected, got %+v", s)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	c, _ := newCollector()
+	_, _ = c.Step()
+
+	rec := httptest.NewRecorder()
+	c.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		"# TYPE m6502_instructions_total counter\nm6502_instructions_total 1\n",
+		"m6502_cycles_total 2\n",
+		"m6502_halts_total 0\n",
+		"# TYPE m6502_instructions_per_second gauge\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("unexpected, missing %q in\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+type (
+	// MicroOp describes what the CPU does during one clock cycle of
+	// an instruction or of an interrupt sequence.
+	MicroOp struct {
+		Cycle uint      // Cycle within the Step(), starting at 1
+		Kind  MicroKind // Kind of the operation
+		Addr  uint16    // Address of the bus access, 0 for MicroInternal
+		Data  byte      // Data transferred, 0 for MicroInternal
+	}
+
+	// MicroKind enumerates the kinds of MicroOp.
+	MicroKind byte
+
+	micro struct {
+		on   bool // Recording, by SetMicroOps() or SetEvents()
+		user bool // Enabled by SetMicroOps()
+		nmi  bool // Recorded interrupt sequence is an NMI
+		ops  []MicroOp
+	}
+)
+
+// Kinds of MicroOp.
+const (
+	MicroInternal   MicroKind = iota + 1 // Cycle without emulated bus access
+	MicroOpcode                          // Op code fetch
+	MicroOperand                         // Operand fetch
+	MicroRead                            // Data read
+	MicroWrite                           // Data write
+	MicroDummyRead                       // Read discarded, e.g. indexed page cross
+	MicroDummyWrite                      // Write of the unmodified value (NMOS RMW)
+	MicroPush                            // Stack push
+	MicroPull                            // Stack pull
+	MicroVector                          // Interrupt vector fetch
+)
+
+// SetMicroOps enables the recording of the micro-operations of each Step().
+// Cycles of the original processor without an emulated bus access, e.g.
+// internal operations, are recorded as MicroInternal. Defaults to false.
+func (cpu *CPU) SetMicroOps(on bool) {
+	cpu.micro = micro{on: on || cpu.events != nil || cpu.capture != nil, user: on}
+	cpu.track()
+}
+
+// MicroOps returns the micro-operations of the last Step(), one per cycle.
+// The micro-operations are only recorded with SetMicroOps() enabled.
+func (cpu *CPU) MicroOps() []MicroOp {
+	return append([]MicroOp(nil), cpu.micro.ops...)
+}
+
+// String returns the name of the micro-operation kind.
+func (k MicroKind) String() string {
+	if int(k) < len(microKinds) && microKinds[k] != "" {
+		return microKinds[k]
+	}
+	return "unknown"
+}
+
+func (cpu *CPU) record(k MicroKind, addr uint16, data byte) {
+	if cpu.micro.on {
+		m := &cpu.micro
+		m.ops = append(m.ops, MicroOp{Cycle: uint(len(m.ops) + 1), Kind: k, Addr: addr, Data: data})
+		if cpu.capture != nil {
+			cpu.captured(m.ops[len(m.ops)-1])
+		}
+	}
+}
+
+var microKinds = [...]string{
+	MicroInternal:   "internal",
+	MicroOpcode:     "opcode fetch",
+	MicroOperand:    "operand fetch",
+	MicroRead:       "read",
+	MicroWrite:      "write",
+	MicroDummyRead:  "dummy read",
+	MicroDummyWrite: "dummy write",
+	MicroPush:       "push",
+	MicroPull:       "pull",
+	MicroVector:     "vector fetch",
+}
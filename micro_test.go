@@ -0,0 +1,54 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func microOps(cpu *CPU) string {
+	s := []string{}
+	for _, m := range cpu.MicroOps() {
+		s = append(s, fmt.Sprintf("%d:%s:%04X:%02X", m.Cycle, m.Kind, m.Addr, m.Data))
+	}
+	return strings.Join(s, " ")
+}
+
+func TestMicroOps(t *testing.T) {
+	// INC $1234,X (NMOS), JSR $0300, IRQ
+	cpu, bus := newTestCPU(NMOS6502, 0xFE, 0x34, 0x12, 0x20, 0x00, 0x03)
+	bus.mem[0x1235] = 0x41
+	cpu.x = 0x01
+	cpu.SetMicroOps(true)
+
+	tests := []string{
+		"1:opcode fetch:0200:FE 2:operand fetch:0201:34 3:operand fetch:0202:12 " +
+			"4:dummy read:1235:41 5:read:1235:41 6:dummy write:1235:41 7:write:1235:42",
+		"1:opcode fetch:0203:20 2:operand fetch:0204:00 3:push:01FF:02 " +
+			"4:push:01FE:05 5:operand fetch:0205:03 6:internal:0000:00",
+		"1:internal:0000:00 2:internal:0000:00 3:push:01FD:03 4:push:01FC:00 " +
+			"5:push:01FB:20 6:vector fetch:FFFE:00 7:vector fetch:FFFF:90",
+	}
+	for i, want := range tests {
+		if i == 2 {
+			cpu.AssertIRQ()
+		}
+		cycles, err := cpu.Step()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := microOps(cpu); got != want || len(cpu.MicroOps()) != int(cycles) {
+			t.Errorf("unexpected, got %s", got)
+		}
+	}
+
+	cpu.SetMicroOps(false)
+	if _, err := cpu.Step(); err != nil || len(cpu.MicroOps()) != 0 {
+		t.Fatal("unexpected")
+	}
+	if MicroVector.String() != "vector fetch" || MicroKind(0).String() != "unknown" {
+		t.Fatal("unexpected")
+	}
+}
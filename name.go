@@ -0,0 +1,49 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "strings"
+
+// NamedError wraps the errors of a named CPU, see SetName().
+type NamedError struct {
+	Name string // Name of the CPU
+	Err  error
+}
+
+// SetName names the CPU, e.g. "drive" in a machine with several CPUs. The
+// name appears in String(), in Trace.CPU and wraps the errors returned by
+// the CPU as *NamedError. Defaults to "", no name.
+func (cpu *CPU) SetName(name string) {
+	cpu.name = name
+}
+
+// Name returns the name of the CPU, see SetName().
+func (cpu *CPU) Name() string {
+	return cpu.name
+}
+
+// Error replaces the "m6502" prefix of the wrapped error by "m6502[name]".
+func (e *NamedError) Error() string {
+	msg := strings.TrimPrefix(e.Err.Error(), "m6502: ")
+	return "m6502[" + e.Name + "]: " + msg
+}
+
+func (e *NamedError) Unwrap() error {
+	return e.Err
+}
+
+// named wraps the error when the CPU has a name.
+func (cpu *CPU) named(err error) error {
+	if err == nil || cpu.name == "" {
+		return err
+	}
+	return &NamedError{Name: cpu.name, Err: err}
+}
+
+// prefix returns the prefix of texts describing the CPU.
+func (cpu *CPU) prefix() string {
+	if cpu.name == "" {
+		return "m6502"
+	}
+	return "m6502[" + cpu.name + "]"
+}
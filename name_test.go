@@ -0,0 +1,45 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestName(t *testing.T) {
+	bus := &memoryBus{}
+	bus.mem[0x0200] = 0xEA // NOP
+	bus.mem[0x0201] = 0x02 // JAM
+
+	cpu := New(bus)
+	cpu.PC(0x00, 0x02)
+	cpu.SetName("drive")
+
+	if cpu.Name() != "drive" || !strings.HasPrefix(cpu.String(), "m6502[drive]: PC=0200") {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+	trace := Trace{}
+	cpu.SetTracer(func(tr Trace) { trace = tr })
+	if _, err := cpu.Step(); err != nil || trace.CPU != "drive" {
+		t.Fatalf("unexpected, got %v %+v", err, trace)
+	}
+
+	_, err := cpu.Step()
+	named := &NamedError{}
+	if !errors.As(err, &named) || named.Name != "drive" {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "m6502[drive]: ") || strings.Contains(err.Error(), "m6502: ") {
+		t.Fatalf("unexpected, got %s", err)
+	}
+	if _, err = cpu.Step(); !errors.Is(err, ErrHalted) || err.Error() != "m6502[drive]: CPU halted" {
+		t.Fatalf("unexpected, got %v", err)
+	}
+
+	cpu.SetName("")
+	if _, err = cpu.Step(); !errors.Is(err, ErrHalted) || !strings.HasPrefix(cpu.String(), "m6502: ") {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
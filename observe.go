@@ -0,0 +1,68 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+type (
+	// Observer is invoked on a bus access of the CPU to an observed
+	// address range, see Observe(). It must not access the bus itself.
+	Observer func(a Access)
+
+	observer struct {
+		start, end uint16
+		seq        uint64
+		fn         Observer
+	}
+
+	observers struct {
+		seq   uint64
+		list  []observer
+		pages [0x100]uint16 // Number of observers per page
+	}
+)
+
+// Observe registers an Observer for the bus accesses of the CPU to the
+// addresses start to end (inclusive): op code and operand fetches, data,
+// stack and vector accesses, e.g. to log the traffic of an I/O device
+// without a bus decorator. Observers do not affect the execution and are
+// invoked in order of registration. The returned function removes the
+// Observer; it is safe to call more than once.
+func (cpu *CPU) Observe(start, end uint16, fn Observer) (remove func()) {
+	obs := &cpu.observers
+	obs.seq++
+	obs.list = append(obs.list, observer{start, end, obs.seq, fn})
+	obs.count(start, end, 1)
+	cpu.track()
+
+	seq := obs.seq
+	return func() {
+		for i, o := range obs.list {
+			if o.seq == seq {
+				obs.list = append(obs.list[:i:i], obs.list[i+1:]...)
+				obs.count(o.start, o.end, -1)
+				cpu.track()
+				return
+			}
+		}
+	}
+}
+
+// observe invokes the observers of the address, when the page is observed.
+func (cpu *CPU) observe(addr uint16, db byte, write bool) {
+	if cpu.observers.pages[addr>>8] == 0 {
+		return
+	}
+	for _, o := range cpu.observers.list {
+		if addr >= o.start && addr <= o.end {
+			o.fn(Access{Addr: addr, Data: db, Write: write})
+		}
+	}
+}
+
+func (obs *observers) count(start, end uint16, n int) {
+	for p := start >> 8; p <= end>>8; p++ {
+		obs.pages[p] = uint16(int(obs.pages[p]) + n)
+		if p == 0xFF {
+			break
+		}
+	}
+}
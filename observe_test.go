@@ -0,0 +1,53 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestObserve(t *testing.T) {
+	bus := &memoryBus{}
+	// LDA $D012, STA $D020, INC $D020
+	copy(bus.mem[0x0200:], []byte{0xAD, 0x12, 0xD0, 0x8D, 0x20, 0xD0, 0xEE, 0x20, 0xD0})
+	bus.mem[0xD012] = 0x33
+	bus.mem[0xFFFE], bus.mem[0xFFFF] = 0x00, 0x03
+
+	cpu := New(bus)
+	cpu.PC(0x00, 0x02)
+
+	var io, all, vec []Access
+	removeIO := cpu.Observe(0xD000, 0xD0FF, func(a Access) { io = append(io, a) })
+	cpu.Observe(0xD020, 0xD020, func(a Access) { all = append(all, a) })
+	cpu.Observe(0xFFFE, 0xFFFF, func(a Access) { vec = append(vec, a) })
+
+	for i := 0; i < 3; i++ {
+		_, _ = cpu.Step()
+	}
+	want := []Access{
+		{0xD012, 0x33, false},
+		{0xD020, 0x33, true},
+		{0xD020, 0x33, false},
+		{0xD020, 0x33, true}, // Dummy write
+		{0xD020, 0x34, true},
+	}
+	if !reflect.DeepEqual(io, want) || !reflect.DeepEqual(all, want[1:]) {
+		t.Fatalf("unexpected, got %v %v", io, all)
+	}
+
+	removeIO()
+	removeIO()
+	cpu.PC(0x03, 0x02)
+	_, _ = cpu.Step()
+	if len(io) != 5 || len(all) != 5 || cpu.observers.pages[0xD0] != 1 {
+		t.Fatalf("unexpected, got %d %d", len(io), len(all))
+	}
+
+	cpu.p.set(false, flagI)
+	cpu.AssertIRQ()
+	_, _ = cpu.Step()
+	if !reflect.DeepEqual(vec, []Access{{0xFFFE, 0x00, false}, {0xFFFF, 0x03, false}}) {
+		t.Fatalf("unexpected, got %v", vec)
+	}
+}
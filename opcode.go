@@ -0,0 +1,1295 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+type (
+	// Op identifies an instruction independent of its op code and addressing
+	// mode, e.g. OpLDA for all LDA op codes. The values are stable across
+	// versions: new instructions will be appended, existing ones never renumbered.
+	Op byte
+
+	// Mode identifies the addressing mode of an instruction.
+	Mode byte
+
+	// Instruction describes an op code.
+	Instruction struct {
+		Op      Op   // Instruction identifier
+		Mode    Mode // Addressing mode
+		Cycles  byte // Base cycle count, without page cross or branch penalties
+		Illegal bool // Undocumented op code
+	}
+)
+
+// Instruction identifiers. OpInvalid denotes an op code without instruction.
+const (
+	OpInvalid Op = iota
+	OpADC
+	OpAND
+	OpASL
+	OpBCC
+	OpBCS
+	OpBEQ
+	OpBIT
+	OpBMI
+	OpBNE
+	OpBPL
+	OpBRK
+	OpBVC
+	OpBVS
+	OpCLC
+	OpCLD
+	OpCLI
+	OpCLV
+	OpCMP
+	OpCPX
+	OpCPY
+	OpDEC
+	OpDEX
+	OpDEY
+	OpEOR
+	OpINC
+	OpINX
+	OpINY
+	OpJMP
+	OpJSR
+	OpLDA
+	OpLDX
+	OpLDY
+	OpLSR
+	OpNOP
+	OpORA
+	OpPHA
+	OpPHP
+	OpPLA
+	OpPLP
+	OpROL
+	OpROR
+	OpRTI
+	OpRTS
+	OpSBC
+	OpSEC
+	OpSED
+	OpSEI
+	OpSTA
+	OpSTX
+	OpSTY
+	OpTAX
+	OpTAY
+	OpTSX
+	OpTXA
+	OpTXS
+	OpTYA
+
+	// Undocumented NMOS instructions
+	OpALR
+	OpANC
+	OpANE
+	OpARR
+	OpDCP
+	OpHLT
+	OpISC
+	OpLAS
+	OpLAX
+	OpLXA
+	OpRLA
+	OpRRA
+	OpSAX
+	OpSBX
+	OpSHA
+	OpSHX
+	OpSHY
+	OpSLO
+	OpSRE
+	OpTAS
+
+	// 65C02 instructions
+	OpBRA
+	OpPHX
+	OpPHY
+	OpPLX
+	OpPLY
+	OpSTZ
+	OpTRB
+	OpTSB
+	OpSTP
+	OpWAI
+
+	// 65CE02 instructions
+	OpASR
+	OpASW
+	OpAUG
+	OpBBR
+	OpBBS
+	OpBSR
+	OpCLE
+	OpCPZ
+	OpDEW
+	OpDEZ
+	OpINW
+	OpINZ
+	OpLDZ
+	OpNEG
+	OpPHW
+	OpPHZ
+	OpPLZ
+	OpRMB
+	OpROW
+	OpSEE
+	OpSMB
+	OpTAB
+	OpTAZ
+	OpTBA
+	OpTSY
+	OpTYS
+	OpTZA
+
+	// HuC6280 instructions, SAX swaps A and X on the HuC6280
+	OpCLA
+	OpCLX
+	OpCLY
+	OpCSH
+	OpCSL
+	OpSAY
+	OpSET
+	OpST0
+	OpST1
+	OpST2
+	OpSXY
+	OpTAI
+	OpTAM
+	OpTDD
+	OpTIA
+	OpTII
+	OpTIN
+	OpTMA
+	OpTST
+)
+
+// Addressing modes.
+const (
+	Implied     Mode = iota // OPC
+	Accumulator             // OPC A
+	Immediate               // OPC #$BB
+	ZeroPage                // OPC $LL
+	ZeroPageX               // OPC $LL,X
+	ZeroPageY               // OPC $LL,Y
+	Relative                // OPC $BB
+	Absolute                // OPC $LLHH
+	AbsoluteX               // OPC $LLHH,X
+	AbsoluteY               // OPC $LLHH,Y
+	Indirect                // OPC ($LLHH)
+	IndirectX               // OPC ($LL,X)
+	IndirectY               // OPC ($LL),Y
+
+	// 65C02 addressing modes
+	ZeroPageIndirect  // OPC ($LL)
+	AbsoluteIndirectX // OPC ($LLHH,X)
+
+	// 65CE02 addressing modes
+	ZeroPageIndirectZ // OPC ($LL),Z
+	StackIndirectY    // OPC ($LL,SP),Y
+	RelativeWord      // OPC $BBBB
+	ImmediateWord     // OPC #$HHLL
+	ZeroPageRelative  // OPC $LL,$BB
+	Augment           // OPC, followed by 3 reserved bytes
+
+	// HuC6280 addressing modes
+	ImmediateZeroPage  // OPC #$BB,$LL
+	ImmediateZeroPageX // OPC #$BB,$LL,X
+	ImmediateAbsolute  // OPC #$BB,$LLHH
+	ImmediateAbsoluteX // OPC #$BB,$LLHH,X
+	BlockTransfer      // OPC $LLHH,$LLHH,$LLHH
+)
+
+var mnemonics = [...]string{
+	OpInvalid: "???",
+	OpADC:     "ADC",
+	OpAND:     "AND",
+	OpASL:     "ASL",
+	OpBCC:     "BCC",
+	OpBCS:     "BCS",
+	OpBEQ:     "BEQ",
+	OpBIT:     "BIT",
+	OpBMI:     "BMI",
+	OpBNE:     "BNE",
+	OpBPL:     "BPL",
+	OpBRK:     "BRK",
+	OpBVC:     "BVC",
+	OpBVS:     "BVS",
+	OpCLC:     "CLC",
+	OpCLD:     "CLD",
+	OpCLI:     "CLI",
+	OpCLV:     "CLV",
+	OpCMP:     "CMP",
+	OpCPX:     "CPX",
+	OpCPY:     "CPY",
+	OpDEC:     "DEC",
+	OpDEX:     "DEX",
+	OpDEY:     "DEY",
+	OpEOR:     "EOR",
+	OpINC:     "INC",
+	OpINX:     "INX",
+	OpINY:     "INY",
+	OpJMP:     "JMP",
+	OpJSR:     "JSR",
+	OpLDA:     "LDA",
+	OpLDX:     "LDX",
+	OpLDY:     "LDY",
+	OpLSR:     "LSR",
+	OpNOP:     "NOP",
+	OpORA:     "ORA",
+	OpPHA:     "PHA",
+	OpPHP:     "PHP",
+	OpPLA:     "PLA",
+	OpPLP:     "PLP",
+	OpROL:     "ROL",
+	OpROR:     "ROR",
+	OpRTI:     "RTI",
+	OpRTS:     "RTS",
+	OpSBC:     "SBC",
+	OpSEC:     "SEC",
+	OpSED:     "SED",
+	OpSEI:     "SEI",
+	OpSTA:     "STA",
+	OpSTX:     "STX",
+	OpSTY:     "STY",
+	OpTAX:     "TAX",
+	OpTAY:     "TAY",
+	OpTSX:     "TSX",
+	OpTXA:     "TXA",
+	OpTXS:     "TXS",
+	OpTYA:     "TYA",
+	OpALR:     "ALR",
+	OpANC:     "ANC",
+	OpANE:     "ANE",
+	OpARR:     "ARR",
+	OpDCP:     "DCP",
+	OpHLT:     "HLT",
+	OpISC:     "ISC",
+	OpLAS:     "LAS",
+	OpLAX:     "LAX",
+	OpLXA:     "LXA",
+	OpRLA:     "RLA",
+	OpRRA:     "RRA",
+	OpSAX:     "SAX",
+	OpSBX:     "SBX",
+	OpSHA:     "SHA",
+	OpSHX:     "SHX",
+	OpSHY:     "SHY",
+	OpSLO:     "SLO",
+	OpSRE:     "SRE",
+	OpTAS:     "TAS",
+	OpBRA:     "BRA",
+	OpPHX:     "PHX",
+	OpPHY:     "PHY",
+	OpPLX:     "PLX",
+	OpPLY:     "PLY",
+	OpSTZ:     "STZ",
+	OpTRB:     "TRB",
+	OpTSB:     "TSB",
+	OpSTP:     "STP",
+	OpWAI:     "WAI",
+	OpASR:     "ASR",
+	OpASW:     "ASW",
+	OpAUG:     "AUG",
+	OpBBR:     "BBR",
+	OpBBS:     "BBS",
+	OpBSR:     "BSR",
+	OpCLE:     "CLE",
+	OpCPZ:     "CPZ",
+	OpDEW:     "DEW",
+	OpDEZ:     "DEZ",
+	OpINW:     "INW",
+	OpINZ:     "INZ",
+	OpLDZ:     "LDZ",
+	OpNEG:     "NEG",
+	OpPHW:     "PHW",
+	OpPHZ:     "PHZ",
+	OpPLZ:     "PLZ",
+	OpRMB:     "RMB",
+	OpROW:     "ROW",
+	OpSEE:     "SEE",
+	OpSMB:     "SMB",
+	OpTAB:     "TAB",
+	OpTAZ:     "TAZ",
+	OpTBA:     "TBA",
+	OpTSY:     "TSY",
+	OpTYS:     "TYS",
+	OpTZA:     "TZA",
+	OpCLA:     "CLA",
+	OpCLX:     "CLX",
+	OpCLY:     "CLY",
+	OpCSH:     "CSH",
+	OpCSL:     "CSL",
+	OpSAY:     "SAY",
+	OpSET:     "SET",
+	OpST0:     "ST0",
+	OpST1:     "ST1",
+	OpST2:     "ST2",
+	OpSXY:     "SXY",
+	OpTAI:     "TAI",
+	OpTAM:     "TAM",
+	OpTDD:     "TDD",
+	OpTIA:     "TIA",
+	OpTII:     "TII",
+	OpTIN:     "TIN",
+	OpTMA:     "TMA",
+	OpTST:     "TST",
+}
+
+var modes = [...]string{
+	Implied:     "implied",
+	Accumulator: "accumulator",
+	Immediate:   "immediate",
+	ZeroPage:    "zeropage",
+	ZeroPageX:   "zeropage,X",
+	ZeroPageY:   "zeropage,Y",
+	Relative:    "relative",
+	Absolute:    "absolute",
+	AbsoluteX:   "absolute,X",
+	AbsoluteY:   "absolute,Y",
+	Indirect:    "indirect",
+	IndirectX:   "(indirect,X)",
+	IndirectY:   "(indirect),Y",
+
+	ZeroPageIndirect:  "(zeropage)",
+	AbsoluteIndirectX: "(absolute,X)",
+
+	ZeroPageIndirectZ: "(zeropage),Z",
+	StackIndirectY:    "(stack,SP),Y",
+	RelativeWord:      "relative word",
+	ImmediateWord:     "immediate word",
+	ZeroPageRelative:  "zeropage,relative",
+	Augment:           "augment",
+
+	ImmediateZeroPage:  "immediate,zeropage",
+	ImmediateZeroPageX: "immediate,zeropage,X",
+	ImmediateAbsolute:  "immediate,absolute",
+	ImmediateAbsoluteX: "immediate,absolute,X",
+	BlockTransfer:      "block transfer",
+}
+
+var sizes = [...]byte{
+	Implied:     1,
+	Accumulator: 1,
+	Immediate:   2,
+	ZeroPage:    2,
+	ZeroPageX:   2,
+	ZeroPageY:   2,
+	Relative:    2,
+	Absolute:    3,
+	AbsoluteX:   3,
+	AbsoluteY:   3,
+	Indirect:    3,
+	IndirectX:   2,
+	IndirectY:   2,
+
+	ZeroPageIndirect:  2,
+	AbsoluteIndirectX: 3,
+
+	ZeroPageIndirectZ: 2,
+	StackIndirectY:    2,
+	RelativeWord:      3,
+	ImmediateWord:     3,
+	ZeroPageRelative:  3,
+	Augment:           4,
+
+	ImmediateZeroPage:  3,
+	ImmediateZeroPageX: 3,
+	ImmediateAbsolute:  4,
+	ImmediateAbsoluteX: 4,
+	BlockTransfer:      7,
+}
+
+// Decode returns the description of an NMOS 6502 op code.
+func Decode(opcode byte) Instruction {
+	return nmos[opcode]
+}
+
+// DecodeVariant returns the description of an op code of the processor model.
+// Op codes reserved on the 65C02 are decoded as OpInvalid, flagged Illegal,
+// with the size and cycles of the NOP they perform.
+func DecodeVariant(v Variant, opcode byte) Instruction {
+	switch v {
+	case CMOS65C02:
+		return cmos[opcode]
+	case CSG65CE02:
+		return csg[opcode]
+	case HuC6280:
+		return hudson[opcode]
+	}
+	return nmos[opcode]
+}
+
+// Mnemonic returns the mnemonic of the instruction, e.g. "LDA".
+func (i Instruction) Mnemonic() string {
+	return i.Op.String()
+}
+
+// Size returns the number of bytes of the instruction including operands.
+func (i Instruction) Size() byte {
+	return sizes[i.Mode]
+}
+
+// String returns the mnemonic of the instruction identifier.
+func (o Op) String() string {
+	if int(o) < len(mnemonics) && mnemonics[o] != "" {
+		return mnemonics[o]
+	}
+	return mnemonics[OpInvalid]
+}
+
+// String returns the name of the addressing mode.
+func (m Mode) String() string {
+	if int(m) < len(modes) {
+		return modes[m]
+	}
+	return "unknown"
+}
+
+var nmos = [0x100]Instruction{
+	0x00: {OpBRK, Implied, 7, false},
+	0x01: {OpORA, IndirectX, 6, false},
+	0x02: {OpHLT, Implied, 1, true},
+	0x03: {OpSLO, IndirectX, 8, true},
+	0x04: {OpNOP, ZeroPage, 3, true},
+	0x05: {OpORA, ZeroPage, 3, false},
+	0x06: {OpASL, ZeroPage, 5, false},
+	0x07: {OpSLO, ZeroPage, 5, true},
+	0x08: {OpPHP, Implied, 3, false},
+	0x09: {OpORA, Immediate, 2, false},
+	0x0A: {OpASL, Accumulator, 2, false},
+	0x0B: {OpANC, Immediate, 2, true},
+	0x0C: {OpNOP, Absolute, 4, true},
+	0x0D: {OpORA, Absolute, 4, false},
+	0x0E: {OpASL, Absolute, 6, false},
+	0x0F: {OpSLO, Absolute, 6, true},
+	0x10: {OpBPL, Relative, 2, false},
+	0x11: {OpORA, IndirectY, 5, false},
+	0x12: {OpHLT, Implied, 1, true},
+	0x13: {OpSLO, IndirectY, 8, true},
+	0x14: {OpNOP, ZeroPageX, 4, true},
+	0x15: {OpORA, ZeroPageX, 4, false},
+	0x16: {OpASL, ZeroPageX, 6, false},
+	0x17: {OpSLO, ZeroPageX, 6, true},
+	0x18: {OpCLC, Implied, 2, false},
+	0x19: {OpORA, AbsoluteY, 4, false},
+	0x1A: {OpNOP, Implied, 2, true},
+	0x1B: {OpSLO, AbsoluteY, 7, true},
+	0x1C: {OpNOP, AbsoluteX, 4, true},
+	0x1D: {OpORA, AbsoluteX, 4, false},
+	0x1E: {OpASL, AbsoluteX, 7, false},
+	0x1F: {OpSLO, AbsoluteX, 7, true},
+	0x20: {OpJSR, Absolute, 6, false},
+	0x21: {OpAND, IndirectX, 6, false},
+	0x22: {OpHLT, Implied, 1, true},
+	0x23: {OpRLA, IndirectX, 8, true},
+	0x24: {OpBIT, ZeroPage, 3, false},
+	0x25: {OpAND, ZeroPage, 3, false},
+	0x26: {OpROL, ZeroPage, 5, false},
+	0x27: {OpRLA, ZeroPage, 5, true},
+	0x28: {OpPLP, Implied, 4, false},
+	0x29: {OpAND, Immediate, 2, false},
+	0x2A: {OpROL, Accumulator, 2, false},
+	0x2B: {OpANC, Immediate, 2, true},
+	0x2C: {OpBIT, Absolute, 4, false},
+	0x2D: {OpAND, Absolute, 4, false},
+	0x2E: {OpROL, Absolute, 6, false},
+	0x2F: {OpRLA, Absolute, 6, true},
+	0x30: {OpBMI, Relative, 2, false},
+	0x31: {OpAND, IndirectY, 5, false},
+	0x32: {OpHLT, Implied, 1, true},
+	0x33: {OpRLA, IndirectY, 8, true},
+	0x34: {OpNOP, ZeroPageX, 4, true},
+	0x35: {OpAND, ZeroPageX, 4, false},
+	0x36: {OpROL, ZeroPageX, 6, false},
+	0x37: {OpRLA, ZeroPageX, 6, true},
+	0x38: {OpSEC, Implied, 2, false},
+	0x39: {OpAND, AbsoluteY, 4, false},
+	0x3A: {OpNOP, Implied, 2, true},
+	0x3B: {OpRLA, AbsoluteY, 7, true},
+	0x3C: {OpNOP, AbsoluteX, 4, true},
+	0x3D: {OpAND, AbsoluteX, 4, false},
+	0x3E: {OpROL, AbsoluteX, 7, false},
+	0x3F: {OpRLA, AbsoluteX, 7, true},
+	0x40: {OpRTI, Implied, 7, false},
+	0x41: {OpEOR, IndirectX, 6, false},
+	0x42: {OpHLT, Implied, 1, true},
+	0x43: {OpSRE, IndirectX, 8, true},
+	0x44: {OpNOP, ZeroPage, 3, true},
+	0x45: {OpEOR, ZeroPage, 3, false},
+	0x46: {OpLSR, ZeroPage, 5, false},
+	0x47: {OpSRE, ZeroPage, 5, true},
+	0x48: {OpPHA, Implied, 3, false},
+	0x49: {OpEOR, Immediate, 2, false},
+	0x4A: {OpLSR, Accumulator, 2, false},
+	0x4B: {OpALR, Immediate, 2, true},
+	0x4C: {OpJMP, Absolute, 3, false},
+	0x4D: {OpEOR, Absolute, 4, false},
+	0x4E: {OpLSR, Absolute, 6, false},
+	0x4F: {OpSRE, Absolute, 6, true},
+	0x50: {OpBVC, Relative, 2, false},
+	0x51: {OpEOR, IndirectY, 5, false},
+	0x52: {OpHLT, Implied, 1, true},
+	0x53: {OpSRE, IndirectY, 8, true},
+	0x54: {OpNOP, ZeroPageX, 4, true},
+	0x55: {OpEOR, ZeroPageX, 4, false},
+	0x56: {OpLSR, ZeroPageX, 6, false},
+	0x57: {OpSRE, ZeroPageX, 6, true},
+	0x58: {OpCLI, Implied, 2, false},
+	0x59: {OpEOR, AbsoluteY, 4, false},
+	0x5A: {OpNOP, Implied, 2, true},
+	0x5B: {OpSRE, AbsoluteY, 7, true},
+	0x5C: {OpNOP, AbsoluteX, 4, true},
+	0x5D: {OpEOR, AbsoluteX, 4, false},
+	0x5E: {OpLSR, AbsoluteX, 7, false},
+	0x5F: {OpSRE, AbsoluteX, 7, true},
+	0x60: {OpRTS, Implied, 6, false},
+	0x61: {OpADC, IndirectX, 6, false},
+	0x62: {OpHLT, Implied, 1, true},
+	0x63: {OpRRA, IndirectX, 8, true},
+	0x64: {OpNOP, ZeroPage, 3, true},
+	0x65: {OpADC, ZeroPage, 3, false},
+	0x66: {OpROR, ZeroPage, 5, false},
+	0x67: {OpRRA, ZeroPage, 5, true},
+	0x68: {OpPLA, Implied, 4, false},
+	0x69: {OpADC, Immediate, 2, false},
+	0x6A: {OpROR, Accumulator, 2, false},
+	0x6B: {OpARR, Immediate, 2, true},
+	0x6C: {OpJMP, Indirect, 5, false},
+	0x6D: {OpADC, Absolute, 4, false},
+	0x6E: {OpROR, Absolute, 6, false},
+	0x6F: {OpRRA, Absolute, 6, true},
+	0x70: {OpBVS, Relative, 2, false},
+	0x71: {OpADC, IndirectY, 5, false},
+	0x72: {OpHLT, Implied, 1, true},
+	0x73: {OpRRA, IndirectY, 8, true},
+	0x74: {OpNOP, ZeroPageX, 4, true},
+	0x75: {OpADC, ZeroPageX, 4, false},
+	0x76: {OpROR, ZeroPageX, 6, false},
+	0x77: {OpRRA, ZeroPageX, 6, true},
+	0x78: {OpSEI, Implied, 2, false},
+	0x79: {OpADC, AbsoluteY, 4, false},
+	0x7A: {OpNOP, Implied, 2, true},
+	0x7B: {OpRRA, AbsoluteY, 7, true},
+	0x7C: {OpNOP, AbsoluteX, 4, true},
+	0x7D: {OpADC, AbsoluteX, 4, false},
+	0x7E: {OpROR, AbsoluteX, 7, false},
+	0x7F: {OpRRA, AbsoluteX, 7, true},
+	0x80: {OpNOP, Immediate, 2, true},
+	0x81: {OpSTA, IndirectX, 6, false},
+	0x82: {OpNOP, Immediate, 2, true},
+	0x83: {OpSAX, IndirectX, 6, true},
+	0x84: {OpSTY, ZeroPage, 3, false},
+	0x85: {OpSTA, ZeroPage, 3, false},
+	0x86: {OpSTX, ZeroPage, 3, false},
+	0x87: {OpSAX, ZeroPage, 3, true},
+	0x88: {OpDEY, Implied, 2, false},
+	0x89: {OpNOP, Immediate, 2, true},
+	0x8A: {OpTXA, Implied, 2, false},
+	0x8B: {OpANE, Immediate, 2, true},
+	0x8C: {OpSTY, Absolute, 4, false},
+	0x8D: {OpSTA, Absolute, 4, false},
+	0x8E: {OpSTX, Absolute, 4, false},
+	0x8F: {OpSAX, Absolute, 4, true},
+	0x90: {OpBCC, Relative, 2, false},
+	0x91: {OpSTA, IndirectY, 6, false},
+	0x92: {OpHLT, Implied, 1, true},
+	0x93: {OpSHA, IndirectY, 6, true},
+	0x94: {OpSTY, ZeroPageX, 4, false},
+	0x95: {OpSTA, ZeroPageX, 4, false},
+	0x96: {OpSTX, ZeroPageY, 4, false},
+	0x97: {OpSAX, ZeroPageY, 4, true},
+	0x98: {OpTYA, Implied, 2, false},
+	0x99: {OpSTA, AbsoluteY, 5, false},
+	0x9A: {OpTXS, Implied, 2, false},
+	0x9B: {OpTAS, AbsoluteY, 5, true},
+	0x9C: {OpSHY, AbsoluteX, 5, true},
+	0x9D: {OpSTA, AbsoluteX, 5, false},
+	0x9E: {OpSHX, AbsoluteY, 5, true},
+	0x9F: {OpSHA, AbsoluteY, 5, true},
+	0xA0: {OpLDY, Immediate, 2, false},
+	0xA1: {OpLDA, IndirectX, 6, false},
+	0xA2: {OpLDX, Immediate, 2, false},
+	0xA3: {OpLAX, IndirectX, 6, true},
+	0xA4: {OpLDY, ZeroPage, 3, false},
+	0xA5: {OpLDA, ZeroPage, 3, false},
+	0xA6: {OpLDX, ZeroPage, 3, false},
+	0xA7: {OpLAX, ZeroPage, 3, true},
+	0xA8: {OpTAY, Implied, 2, false},
+	0xA9: {OpLDA, Immediate, 2, false},
+	0xAA: {OpTAX, Implied, 2, false},
+	0xAB: {OpLXA, Immediate, 2, true},
+	0xAC: {OpLDY, Absolute, 4, false},
+	0xAD: {OpLDA, Absolute, 4, false},
+	0xAE: {OpLDX, Absolute, 4, false},
+	0xAF: {OpLAX, Absolute, 4, true},
+	0xB0: {OpBCS, Relative, 2, false},
+	0xB1: {OpLDA, IndirectY, 5, false},
+	0xB2: {OpHLT, Implied, 1, true},
+	0xB3: {OpLAX, IndirectY, 5, true},
+	0xB4: {OpLDY, ZeroPageX, 4, false},
+	0xB5: {OpLDA, ZeroPageX, 4, false},
+	0xB6: {OpLDX, ZeroPageY, 4, false},
+	0xB7: {OpLAX, ZeroPageY, 4, true},
+	0xB8: {OpCLV, Implied, 2, false},
+	0xB9: {OpLDA, AbsoluteY, 4, false},
+	0xBA: {OpTSX, Implied, 2, false},
+	0xBB: {OpLAS, AbsoluteY, 4, true},
+	0xBC: {OpLDY, AbsoluteX, 4, false},
+	0xBD: {OpLDA, AbsoluteX, 4, false},
+	0xBE: {OpLDX, AbsoluteY, 4, false},
+	0xBF: {OpLAX, AbsoluteY, 4, true},
+	0xC0: {OpCPY, Immediate, 2, false},
+	0xC1: {OpCMP, IndirectX, 6, false},
+	0xC2: {OpNOP, Immediate, 2, true},
+	0xC3: {OpDCP, IndirectX, 8, true},
+	0xC4: {OpCPY, ZeroPage, 3, false},
+	0xC5: {OpCMP, ZeroPage, 3, false},
+	0xC6: {OpDEC, ZeroPage, 5, false},
+	0xC7: {OpDCP, ZeroPage, 5, true},
+	0xC8: {OpINY, Implied, 2, false},
+	0xC9: {OpCMP, Immediate, 2, false},
+	0xCA: {OpDEX, Implied, 2, false},
+	0xCB: {OpSBX, Immediate, 2, true},
+	0xCC: {OpCPY, Absolute, 4, false},
+	0xCD: {OpCMP, Absolute, 4, false},
+	0xCE: {OpDEC, Absolute, 6, false},
+	0xCF: {OpDCP, Absolute, 6, true},
+	0xD0: {OpBNE, Relative, 2, false},
+	0xD1: {OpCMP, IndirectY, 5, false},
+	0xD2: {OpHLT, Implied, 1, true},
+	0xD3: {OpDCP, IndirectY, 8, true},
+	0xD4: {OpNOP, ZeroPageX, 4, true},
+	0xD5: {OpCMP, ZeroPageX, 4, false},
+	0xD6: {OpDEC, ZeroPageX, 6, false},
+	0xD7: {OpDCP, ZeroPageX, 6, true},
+	0xD8: {OpCLD, Implied, 2, false},
+	0xD9: {OpCMP, AbsoluteY, 4, false},
+	0xDA: {OpNOP, Implied, 2, true},
+	0xDB: {OpDCP, AbsoluteY, 7, true},
+	0xDC: {OpNOP, AbsoluteX, 4, true},
+	0xDD: {OpCMP, AbsoluteX, 4, false},
+	0xDE: {OpDEC, AbsoluteX, 7, false},
+	0xDF: {OpDCP, AbsoluteX, 7, true},
+	0xE0: {OpCPX, Immediate, 2, false},
+	0xE1: {OpSBC, IndirectX, 6, false},
+	0xE2: {OpNOP, Immediate, 2, true},
+	0xE3: {OpISC, IndirectX, 8, true},
+	0xE4: {OpCPX, ZeroPage, 3, false},
+	0xE5: {OpSBC, ZeroPage, 3, false},
+	0xE6: {OpINC, ZeroPage, 5, false},
+	0xE7: {OpISC, ZeroPage, 5, true},
+	0xE8: {OpINX, Implied, 2, false},
+	0xE9: {OpSBC, Immediate, 2, false},
+	0xEA: {OpNOP, Implied, 2, false},
+	0xEB: {OpSBC, Immediate, 2, true},
+	0xEC: {OpCPX, Absolute, 4, false},
+	0xED: {OpSBC, Absolute, 4, false},
+	0xEE: {OpINC, Absolute, 6, false},
+	0xEF: {OpISC, Absolute, 6, true},
+	0xF0: {OpBEQ, Relative, 2, false},
+	0xF1: {OpSBC, IndirectY, 5, false},
+	0xF2: {OpHLT, Implied, 1, true},
+	0xF3: {OpISC, IndirectY, 8, true},
+	0xF4: {OpNOP, ZeroPageX, 4, true},
+	0xF5: {OpSBC, ZeroPageX, 4, false},
+	0xF6: {OpINC, ZeroPageX, 6, false},
+	0xF7: {OpISC, ZeroPageX, 6, true},
+	0xF8: {OpSED, Implied, 2, false},
+	0xF9: {OpSBC, AbsoluteY, 4, false},
+	0xFA: {OpNOP, Implied, 2, true},
+	0xFB: {OpISC, AbsoluteY, 7, true},
+	0xFC: {OpNOP, AbsoluteX, 4, true},
+	0xFD: {OpSBC, AbsoluteX, 4, false},
+	0xFE: {OpINC, AbsoluteX, 7, false},
+	0xFF: {OpISC, AbsoluteX, 7, true},
+}
+
+// cmos derives the 65C02 op codes from the NMOS table: new instructions
+// replace the undocumented ones, the remaining are reserved NOPs. The
+// Rockwell bit instructions RMB, SMB, BBR and BBS are included.
+var cmos = func() (t [0x100]Instruction) {
+	for op, i := range nmos {
+		if t[op] = i; !i.Illegal {
+			continue
+		}
+		switch op & 0x0F {
+		case 0x02:
+			t[op] = Instruction{OpInvalid, Immediate, 2, true}
+		case 0x03, 0x07, 0x0B, 0x0F:
+			t[op] = Instruction{OpInvalid, Implied, 1, true}
+		default:
+			t[op] = Instruction{OpInvalid, i.Mode, i.Cycles, true}
+		}
+	}
+	for op, i := range map[byte]Instruction{
+		0x04: {OpTSB, ZeroPage, 5, false},
+		0x0C: {OpTSB, Absolute, 6, false},
+		0x12: {OpORA, ZeroPageIndirect, 5, false},
+		0x14: {OpTRB, ZeroPage, 5, false},
+		0x1A: {OpINC, Accumulator, 2, false},
+		0x1C: {OpTRB, Absolute, 6, false},
+		0x1E: {OpASL, AbsoluteX, 6, false},
+		0x32: {OpAND, ZeroPageIndirect, 5, false},
+		0x34: {OpBIT, ZeroPageX, 4, false},
+		0x3A: {OpDEC, Accumulator, 2, false},
+		0x3C: {OpBIT, AbsoluteX, 4, false},
+		0x3E: {OpROL, AbsoluteX, 6, false},
+		0x44: {OpInvalid, ZeroPage, 3, true},
+		0x52: {OpEOR, ZeroPageIndirect, 5, false},
+		0x5A: {OpPHY, Implied, 3, false},
+		0x5C: {OpInvalid, Absolute, 8, true},
+		0x5E: {OpLSR, AbsoluteX, 6, false},
+		0x64: {OpSTZ, ZeroPage, 3, false},
+		0x6C: {OpJMP, Indirect, 6, false},
+		0x72: {OpADC, ZeroPageIndirect, 5, false},
+		0x74: {OpSTZ, ZeroPageX, 4, false},
+		0x7A: {OpPLY, Implied, 4, false},
+		0x7C: {OpJMP, AbsoluteIndirectX, 6, false},
+		0x7E: {OpROR, AbsoluteX, 6, false},
+		0x80: {OpBRA, Relative, 3, false},
+		0x89: {OpBIT, Immediate, 2, false},
+		0x92: {OpSTA, ZeroPageIndirect, 5, false},
+		0x9C: {OpSTZ, Absolute, 4, false},
+		0x9E: {OpSTZ, AbsoluteX, 5, false},
+		0xB2: {OpLDA, ZeroPageIndirect, 5, false},
+		0xCB: {OpWAI, Implied, 3, false},
+		0xD2: {OpCMP, ZeroPageIndirect, 5, false},
+		0xDA: {OpPHX, Implied, 3, false},
+		0xDB: {OpSTP, Implied, 3, false},
+		0xF2: {OpSBC, ZeroPageIndirect, 5, false},
+		0xFA: {OpPLX, Implied, 4, false},
+	} {
+		t[op] = i
+	}
+	for i := 0; i < 8; i++ {
+		t[i<<4|0x07] = Instruction{OpRMB, ZeroPage, 5, false}
+		t[i<<4|0x87] = Instruction{OpSMB, ZeroPage, 5, false}
+		t[i<<4|0x0F] = Instruction{OpBBR, ZeroPageRelative, 5, false}
+		t[i<<4|0x8F] = Instruction{OpBBS, ZeroPageRelative, 5, false}
+	}
+	return t
+}()
+
+// csg describes the op codes of the 65CE02. All op codes are defined, the
+// cycles follow the bus accesses, as the 65CE02 drops the dead cycles.
+var csg = [0x100]Instruction{
+	0x00: {OpBRK, Implied, 7, false},
+	0x01: {OpORA, IndirectX, 5, false},
+	0x02: {OpCLE, Implied, 1, false},
+	0x03: {OpSEE, Implied, 1, false},
+	0x04: {OpTSB, ZeroPage, 4, false},
+	0x05: {OpORA, ZeroPage, 3, false},
+	0x06: {OpASL, ZeroPage, 4, false},
+	0x07: {OpRMB, ZeroPage, 4, false},
+	0x08: {OpPHP, Implied, 2, false},
+	0x09: {OpORA, Immediate, 2, false},
+	0x0A: {OpASL, Accumulator, 1, false},
+	0x0B: {OpTSY, Implied, 1, false},
+	0x0C: {OpTSB, Absolute, 5, false},
+	0x0D: {OpORA, Absolute, 4, false},
+	0x0E: {OpASL, Absolute, 5, false},
+	0x0F: {OpBBR, ZeroPageRelative, 4, false},
+	0x10: {OpBPL, Relative, 2, false},
+	0x11: {OpORA, IndirectY, 5, false},
+	0x12: {OpORA, ZeroPageIndirectZ, 5, false},
+	0x13: {OpBPL, RelativeWord, 3, false},
+	0x14: {OpTRB, ZeroPage, 4, false},
+	0x15: {OpORA, ZeroPageX, 3, false},
+	0x16: {OpASL, ZeroPageX, 4, false},
+	0x17: {OpRMB, ZeroPage, 4, false},
+	0x18: {OpCLC, Implied, 1, false},
+	0x19: {OpORA, AbsoluteY, 4, false},
+	0x1A: {OpINC, Accumulator, 1, false},
+	0x1B: {OpINZ, Implied, 1, false},
+	0x1C: {OpTRB, Absolute, 5, false},
+	0x1D: {OpORA, AbsoluteX, 4, false},
+	0x1E: {OpASL, AbsoluteX, 5, false},
+	0x1F: {OpBBR, ZeroPageRelative, 4, false},
+	0x20: {OpJSR, Absolute, 5, false},
+	0x21: {OpAND, IndirectX, 5, false},
+	0x22: {OpJSR, Indirect, 7, false},
+	0x23: {OpJSR, AbsoluteIndirectX, 7, false},
+	0x24: {OpBIT, ZeroPage, 3, false},
+	0x25: {OpAND, ZeroPage, 3, false},
+	0x26: {OpROL, ZeroPage, 4, false},
+	0x27: {OpRMB, ZeroPage, 4, false},
+	0x28: {OpPLP, Implied, 2, false},
+	0x29: {OpAND, Immediate, 2, false},
+	0x2A: {OpROL, Accumulator, 1, false},
+	0x2B: {OpTYS, Implied, 1, false},
+	0x2C: {OpBIT, Absolute, 4, false},
+	0x2D: {OpAND, Absolute, 4, false},
+	0x2E: {OpROL, Absolute, 5, false},
+	0x2F: {OpBBR, ZeroPageRelative, 4, false},
+	0x30: {OpBMI, Relative, 2, false},
+	0x31: {OpAND, IndirectY, 5, false},
+	0x32: {OpAND, ZeroPageIndirectZ, 5, false},
+	0x33: {OpBMI, RelativeWord, 3, false},
+	0x34: {OpBIT, ZeroPageX, 3, false},
+	0x35: {OpAND, ZeroPageX, 3, false},
+	0x36: {OpROL, ZeroPageX, 4, false},
+	0x37: {OpRMB, ZeroPage, 4, false},
+	0x38: {OpSEC, Implied, 1, false},
+	0x39: {OpAND, AbsoluteY, 4, false},
+	0x3A: {OpDEC, Accumulator, 1, false},
+	0x3B: {OpDEZ, Implied, 1, false},
+	0x3C: {OpBIT, AbsoluteX, 4, false},
+	0x3D: {OpAND, AbsoluteX, 4, false},
+	0x3E: {OpROL, AbsoluteX, 5, false},
+	0x3F: {OpBBR, ZeroPageRelative, 4, false},
+	0x40: {OpRTI, Implied, 4, false},
+	0x41: {OpEOR, IndirectX, 5, false},
+	0x42: {OpNEG, Implied, 1, false},
+	0x43: {OpASR, Accumulator, 1, false},
+	0x44: {OpASR, ZeroPage, 4, false},
+	0x45: {OpEOR, ZeroPage, 3, false},
+	0x46: {OpLSR, ZeroPage, 4, false},
+	0x47: {OpRMB, ZeroPage, 4, false},
+	0x48: {OpPHA, Implied, 2, false},
+	0x49: {OpEOR, Immediate, 2, false},
+	0x4A: {OpLSR, Accumulator, 1, false},
+	0x4B: {OpTAZ, Implied, 1, false},
+	0x4C: {OpJMP, Absolute, 3, false},
+	0x4D: {OpEOR, Absolute, 4, false},
+	0x4E: {OpLSR, Absolute, 5, false},
+	0x4F: {OpBBR, ZeroPageRelative, 4, false},
+	0x50: {OpBVC, Relative, 2, false},
+	0x51: {OpEOR, IndirectY, 5, false},
+	0x52: {OpEOR, ZeroPageIndirectZ, 5, false},
+	0x53: {OpBVC, RelativeWord, 3, false},
+	0x54: {OpASR, ZeroPageX, 4, false},
+	0x55: {OpEOR, ZeroPageX, 3, false},
+	0x56: {OpLSR, ZeroPageX, 4, false},
+	0x57: {OpRMB, ZeroPage, 4, false},
+	0x58: {OpCLI, Implied, 1, false},
+	0x59: {OpEOR, AbsoluteY, 4, false},
+	0x5A: {OpPHY, Implied, 2, false},
+	0x5B: {OpTAB, Implied, 1, false},
+	0x5C: {OpAUG, Augment, 4, false},
+	0x5D: {OpEOR, AbsoluteX, 4, false},
+	0x5E: {OpLSR, AbsoluteX, 5, false},
+	0x5F: {OpBBR, ZeroPageRelative, 4, false},
+	0x60: {OpRTS, Implied, 3, false},
+	0x61: {OpADC, IndirectX, 5, false},
+	0x62: {OpRTS, Immediate, 4, false},
+	0x63: {OpBSR, RelativeWord, 5, false},
+	0x64: {OpSTZ, ZeroPage, 3, false},
+	0x65: {OpADC, ZeroPage, 3, false},
+	0x66: {OpROR, ZeroPage, 4, false},
+	0x67: {OpRMB, ZeroPage, 4, false},
+	0x68: {OpPLA, Implied, 2, false},
+	0x69: {OpADC, Immediate, 2, false},
+	0x6A: {OpROR, Accumulator, 1, false},
+	0x6B: {OpTZA, Implied, 1, false},
+	0x6C: {OpJMP, Indirect, 5, false},
+	0x6D: {OpADC, Absolute, 4, false},
+	0x6E: {OpROR, Absolute, 5, false},
+	0x6F: {OpBBR, ZeroPageRelative, 4, false},
+	0x70: {OpBVS, Relative, 2, false},
+	0x71: {OpADC, IndirectY, 5, false},
+	0x72: {OpADC, ZeroPageIndirectZ, 5, false},
+	0x73: {OpBVS, RelativeWord, 3, false},
+	0x74: {OpSTZ, ZeroPageX, 3, false},
+	0x75: {OpADC, ZeroPageX, 3, false},
+	0x76: {OpROR, ZeroPageX, 4, false},
+	0x77: {OpRMB, ZeroPage, 4, false},
+	0x78: {OpSEI, Implied, 1, false},
+	0x79: {OpADC, AbsoluteY, 4, false},
+	0x7A: {OpPLY, Implied, 2, false},
+	0x7B: {OpTBA, Implied, 1, false},
+	0x7C: {OpJMP, AbsoluteIndirectX, 5, false},
+	0x7D: {OpADC, AbsoluteX, 4, false},
+	0x7E: {OpROR, AbsoluteX, 5, false},
+	0x7F: {OpBBR, ZeroPageRelative, 4, false},
+	0x80: {OpBRA, Relative, 3, false},
+	0x81: {OpSTA, IndirectX, 5, false},
+	0x82: {OpSTA, StackIndirectY, 5, false},
+	0x83: {OpBRA, RelativeWord, 4, false},
+	0x84: {OpSTY, ZeroPage, 3, false},
+	0x85: {OpSTA, ZeroPage, 3, false},
+	0x86: {OpSTX, ZeroPage, 3, false},
+	0x87: {OpSMB, ZeroPage, 4, false},
+	0x88: {OpDEY, Implied, 1, false},
+	0x89: {OpBIT, Immediate, 2, false},
+	0x8A: {OpTXA, Implied, 1, false},
+	0x8B: {OpSTY, AbsoluteX, 4, false},
+	0x8C: {OpSTY, Absolute, 4, false},
+	0x8D: {OpSTA, Absolute, 4, false},
+	0x8E: {OpSTX, Absolute, 4, false},
+	0x8F: {OpBBS, ZeroPageRelative, 4, false},
+	0x90: {OpBCC, Relative, 2, false},
+	0x91: {OpSTA, IndirectY, 5, false},
+	0x92: {OpSTA, ZeroPageIndirectZ, 5, false},
+	0x93: {OpBCC, RelativeWord, 3, false},
+	0x94: {OpSTY, ZeroPageX, 3, false},
+	0x95: {OpSTA, ZeroPageX, 3, false},
+	0x96: {OpSTX, ZeroPageY, 3, false},
+	0x97: {OpSMB, ZeroPage, 4, false},
+	0x98: {OpTYA, Implied, 1, false},
+	0x99: {OpSTA, AbsoluteY, 4, false},
+	0x9A: {OpTXS, Implied, 1, false},
+	0x9B: {OpSTX, AbsoluteY, 4, false},
+	0x9C: {OpSTZ, Absolute, 4, false},
+	0x9D: {OpSTA, AbsoluteX, 4, false},
+	0x9E: {OpSTZ, AbsoluteX, 4, false},
+	0x9F: {OpBBS, ZeroPageRelative, 4, false},
+	0xA0: {OpLDY, Immediate, 2, false},
+	0xA1: {OpLDA, IndirectX, 5, false},
+	0xA2: {OpLDX, Immediate, 2, false},
+	0xA3: {OpLDZ, Immediate, 2, false},
+	0xA4: {OpLDY, ZeroPage, 3, false},
+	0xA5: {OpLDA, ZeroPage, 3, false},
+	0xA6: {OpLDX, ZeroPage, 3, false},
+	0xA7: {OpSMB, ZeroPage, 4, false},
+	0xA8: {OpTAY, Implied, 1, false},
+	0xA9: {OpLDA, Immediate, 2, false},
+	0xAA: {OpTAX, Implied, 1, false},
+	0xAB: {OpLDZ, Absolute, 4, false},
+	0xAC: {OpLDY, Absolute, 4, false},
+	0xAD: {OpLDA, Absolute, 4, false},
+	0xAE: {OpLDX, Absolute, 4, false},
+	0xAF: {OpBBS, ZeroPageRelative, 4, false},
+	0xB0: {OpBCS, Relative, 2, false},
+	0xB1: {OpLDA, IndirectY, 5, false},
+	0xB2: {OpLDA, ZeroPageIndirectZ, 5, false},
+	0xB3: {OpBCS, RelativeWord, 3, false},
+	0xB4: {OpLDY, ZeroPageX, 3, false},
+	0xB5: {OpLDA, ZeroPageX, 3, false},
+	0xB6: {OpLDX, ZeroPageY, 3, false},
+	0xB7: {OpSMB, ZeroPage, 4, false},
+	0xB8: {OpCLV, Implied, 1, false},
+	0xB9: {OpLDA, AbsoluteY, 4, false},
+	0xBA: {OpTSX, Implied, 1, false},
+	0xBB: {OpLDZ, AbsoluteX, 4, false},
+	0xBC: {OpLDY, AbsoluteX, 4, false},
+	0xBD: {OpLDA, AbsoluteX, 4, false},
+	0xBE: {OpLDX, AbsoluteY, 4, false},
+	0xBF: {OpBBS, ZeroPageRelative, 4, false},
+	0xC0: {OpCPY, Immediate, 2, false},
+	0xC1: {OpCMP, IndirectX, 5, false},
+	0xC2: {OpCPZ, Immediate, 2, false},
+	0xC3: {OpDEW, ZeroPage, 6, false},
+	0xC4: {OpCPY, ZeroPage, 3, false},
+	0xC5: {OpCMP, ZeroPage, 3, false},
+	0xC6: {OpDEC, ZeroPage, 4, false},
+	0xC7: {OpSMB, ZeroPage, 4, false},
+	0xC8: {OpINY, Implied, 1, false},
+	0xC9: {OpCMP, Immediate, 2, false},
+	0xCA: {OpDEX, Implied, 1, false},
+	0xCB: {OpASW, Absolute, 7, false},
+	0xCC: {OpCPY, Absolute, 4, false},
+	0xCD: {OpCMP, Absolute, 4, false},
+	0xCE: {OpDEC, Absolute, 5, false},
+	0xCF: {OpBBS, ZeroPageRelative, 4, false},
+	0xD0: {OpBNE, Relative, 2, false},
+	0xD1: {OpCMP, IndirectY, 5, false},
+	0xD2: {OpCMP, ZeroPageIndirectZ, 5, false},
+	0xD3: {OpBNE, RelativeWord, 3, false},
+	0xD4: {OpCPZ, ZeroPage, 3, false},
+	0xD5: {OpCMP, ZeroPageX, 3, false},
+	0xD6: {OpDEC, ZeroPageX, 4, false},
+	0xD7: {OpSMB, ZeroPage, 4, false},
+	0xD8: {OpCLD, Implied, 1, false},
+	0xD9: {OpCMP, AbsoluteY, 4, false},
+	0xDA: {OpPHX, Implied, 2, false},
+	0xDB: {OpPHZ, Implied, 2, false},
+	0xDC: {OpCPZ, Absolute, 4, false},
+	0xDD: {OpCMP, AbsoluteX, 4, false},
+	0xDE: {OpDEC, AbsoluteX, 5, false},
+	0xDF: {OpBBS, ZeroPageRelative, 4, false},
+	0xE0: {OpCPX, Immediate, 2, false},
+	0xE1: {OpSBC, IndirectX, 5, false},
+	0xE2: {OpLDA, StackIndirectY, 5, false},
+	0xE3: {OpINW, ZeroPage, 6, false},
+	0xE4: {OpCPX, ZeroPage, 3, false},
+	0xE5: {OpSBC, ZeroPage, 3, false},
+	0xE6: {OpINC, ZeroPage, 4, false},
+	0xE7: {OpSMB, ZeroPage, 4, false},
+	0xE8: {OpINX, Implied, 1, false},
+	0xE9: {OpSBC, Immediate, 2, false},
+	0xEA: {OpNOP, Implied, 1, false},
+	0xEB: {OpROW, Absolute, 7, false},
+	0xEC: {OpCPX, Absolute, 4, false},
+	0xED: {OpSBC, Absolute, 4, false},
+	0xEE: {OpINC, Absolute, 5, false},
+	0xEF: {OpBBS, ZeroPageRelative, 4, false},
+	0xF0: {OpBEQ, Relative, 2, false},
+	0xF1: {OpSBC, IndirectY, 5, false},
+	0xF2: {OpSBC, ZeroPageIndirectZ, 5, false},
+	0xF3: {OpBEQ, RelativeWord, 3, false},
+	0xF4: {OpPHW, ImmediateWord, 5, false},
+	0xF5: {OpSBC, ZeroPageX, 3, false},
+	0xF6: {OpINC, ZeroPageX, 4, false},
+	0xF7: {OpSMB, ZeroPage, 4, false},
+	0xF8: {OpSED, Implied, 1, false},
+	0xF9: {OpSBC, AbsoluteY, 4, false},
+	0xFA: {OpPLX, Implied, 2, false},
+	0xFB: {OpPLZ, Implied, 2, false},
+	0xFC: {OpPHW, Absolute, 7, false},
+	0xFD: {OpSBC, AbsoluteX, 4, false},
+	0xFE: {OpINC, AbsoluteX, 5, false},
+	0xFF: {OpBBS, ZeroPageRelative, 4, false},
+}
+
+// hudson describes the op codes of the HuC6280. Op codes without instruction
+// are decoded as OpInvalid, flagged Illegal, with the NOP they perform.
+var hudson = [0x100]Instruction{
+	0x00: {OpBRK, Implied, 8, false},
+	0x01: {OpORA, IndirectX, 7, false},
+	0x02: {OpSXY, Implied, 3, false},
+	0x03: {OpST0, Immediate, 4, false},
+	0x04: {OpTSB, ZeroPage, 6, false},
+	0x05: {OpORA, ZeroPage, 4, false},
+	0x06: {OpASL, ZeroPage, 6, false},
+	0x07: {OpRMB, ZeroPage, 7, false},
+	0x08: {OpPHP, Implied, 3, false},
+	0x09: {OpORA, Immediate, 2, false},
+	0x0A: {OpASL, Accumulator, 2, false},
+	0x0B: {OpInvalid, Implied, 2, true},
+	0x0C: {OpTSB, Absolute, 7, false},
+	0x0D: {OpORA, Absolute, 5, false},
+	0x0E: {OpASL, Absolute, 7, false},
+	0x0F: {OpBBR, ZeroPageRelative, 6, false},
+	0x10: {OpBPL, Relative, 2, false},
+	0x11: {OpORA, IndirectY, 7, false},
+	0x12: {OpORA, ZeroPageIndirect, 7, false},
+	0x13: {OpST1, Immediate, 4, false},
+	0x14: {OpTRB, ZeroPage, 6, false},
+	0x15: {OpORA, ZeroPageX, 4, false},
+	0x16: {OpASL, ZeroPageX, 6, false},
+	0x17: {OpRMB, ZeroPage, 7, false},
+	0x18: {OpCLC, Implied, 2, false},
+	0x19: {OpORA, AbsoluteY, 5, false},
+	0x1A: {OpINC, Accumulator, 2, false},
+	0x1B: {OpInvalid, Implied, 2, true},
+	0x1C: {OpTRB, Absolute, 7, false},
+	0x1D: {OpORA, AbsoluteX, 5, false},
+	0x1E: {OpASL, AbsoluteX, 7, false},
+	0x1F: {OpBBR, ZeroPageRelative, 6, false},
+	0x20: {OpJSR, Absolute, 7, false},
+	0x21: {OpAND, IndirectX, 7, false},
+	0x22: {OpSAX, Implied, 3, false},
+	0x23: {OpST2, Immediate, 4, false},
+	0x24: {OpBIT, ZeroPage, 4, false},
+	0x25: {OpAND, ZeroPage, 4, false},
+	0x26: {OpROL, ZeroPage, 6, false},
+	0x27: {OpRMB, ZeroPage, 7, false},
+	0x28: {OpPLP, Implied, 4, false},
+	0x29: {OpAND, Immediate, 2, false},
+	0x2A: {OpROL, Accumulator, 2, false},
+	0x2B: {OpInvalid, Implied, 2, true},
+	0x2C: {OpBIT, Absolute, 5, false},
+	0x2D: {OpAND, Absolute, 5, false},
+	0x2E: {OpROL, Absolute, 7, false},
+	0x2F: {OpBBR, ZeroPageRelative, 6, false},
+	0x30: {OpBMI, Relative, 2, false},
+	0x31: {OpAND, IndirectY, 7, false},
+	0x32: {OpAND, ZeroPageIndirect, 7, false},
+	0x33: {OpInvalid, Implied, 2, true},
+	0x34: {OpBIT, ZeroPageX, 4, false},
+	0x35: {OpAND, ZeroPageX, 4, false},
+	0x36: {OpROL, ZeroPageX, 6, false},
+	0x37: {OpRMB, ZeroPage, 7, false},
+	0x38: {OpSEC, Implied, 2, false},
+	0x39: {OpAND, AbsoluteY, 5, false},
+	0x3A: {OpDEC, Accumulator, 2, false},
+	0x3B: {OpInvalid, Implied, 2, true},
+	0x3C: {OpBIT, AbsoluteX, 5, false},
+	0x3D: {OpAND, AbsoluteX, 5, false},
+	0x3E: {OpROL, AbsoluteX, 7, false},
+	0x3F: {OpBBR, ZeroPageRelative, 6, false},
+	0x40: {OpRTI, Implied, 7, false},
+	0x41: {OpEOR, IndirectX, 7, false},
+	0x42: {OpSAY, Implied, 3, false},
+	0x43: {OpTMA, Immediate, 4, false},
+	0x44: {OpBSR, Relative, 8, false},
+	0x45: {OpEOR, ZeroPage, 4, false},
+	0x46: {OpLSR, ZeroPage, 6, false},
+	0x47: {OpRMB, ZeroPage, 7, false},
+	0x48: {OpPHA, Implied, 3, false},
+	0x49: {OpEOR, Immediate, 2, false},
+	0x4A: {OpLSR, Accumulator, 2, false},
+	0x4B: {OpInvalid, Implied, 2, true},
+	0x4C: {OpJMP, Absolute, 4, false},
+	0x4D: {OpEOR, Absolute, 5, false},
+	0x4E: {OpLSR, Absolute, 7, false},
+	0x4F: {OpBBR, ZeroPageRelative, 6, false},
+	0x50: {OpBVC, Relative, 2, false},
+	0x51: {OpEOR, IndirectY, 7, false},
+	0x52: {OpEOR, ZeroPageIndirect, 7, false},
+	0x53: {OpTAM, Immediate, 5, false},
+	0x54: {OpCSL, Implied, 3, false},
+	0x55: {OpEOR, ZeroPageX, 4, false},
+	0x56: {OpLSR, ZeroPageX, 6, false},
+	0x57: {OpRMB, ZeroPage, 7, false},
+	0x58: {OpCLI, Implied, 2, false},
+	0x59: {OpEOR, AbsoluteY, 5, false},
+	0x5A: {OpPHY, Implied, 3, false},
+	0x5B: {OpInvalid, Implied, 2, true},
+	0x5C: {OpInvalid, Implied, 2, true},
+	0x5D: {OpEOR, AbsoluteX, 5, false},
+	0x5E: {OpLSR, AbsoluteX, 7, false},
+	0x5F: {OpBBR, ZeroPageRelative, 6, false},
+	0x60: {OpRTS, Implied, 7, false},
+	0x61: {OpADC, IndirectX, 7, false},
+	0x62: {OpCLA, Implied, 2, false},
+	0x63: {OpInvalid, Implied, 2, true},
+	0x64: {OpSTZ, ZeroPage, 4, false},
+	0x65: {OpADC, ZeroPage, 4, false},
+	0x66: {OpROR, ZeroPage, 6, false},
+	0x67: {OpRMB, ZeroPage, 7, false},
+	0x68: {OpPLA, Implied, 4, false},
+	0x69: {OpADC, Immediate, 2, false},
+	0x6A: {OpROR, Accumulator, 2, false},
+	0x6B: {OpInvalid, Implied, 2, true},
+	0x6C: {OpJMP, Indirect, 7, false},
+	0x6D: {OpADC, Absolute, 5, false},
+	0x6E: {OpROR, Absolute, 7, false},
+	0x6F: {OpBBR, ZeroPageRelative, 6, false},
+	0x70: {OpBVS, Relative, 2, false},
+	0x71: {OpADC, IndirectY, 7, false},
+	0x72: {OpADC, ZeroPageIndirect, 7, false},
+	0x73: {OpTII, BlockTransfer, 17, false},
+	0x74: {OpSTZ, ZeroPageX, 4, false},
+	0x75: {OpADC, ZeroPageX, 4, false},
+	0x76: {OpROR, ZeroPageX, 6, false},
+	0x77: {OpRMB, ZeroPage, 7, false},
+	0x78: {OpSEI, Implied, 2, false},
+	0x79: {OpADC, AbsoluteY, 5, false},
+	0x7A: {OpPLY, Implied, 4, false},
+	0x7B: {OpInvalid, Implied, 2, true},
+	0x7C: {OpJMP, AbsoluteIndirectX, 7, false},
+	0x7D: {OpADC, AbsoluteX, 5, false},
+	0x7E: {OpROR, AbsoluteX, 7, false},
+	0x7F: {OpBBR, ZeroPageRelative, 6, false},
+	0x80: {OpBRA, Relative, 4, false},
+	0x81: {OpSTA, IndirectX, 7, false},
+	0x82: {OpCLX, Implied, 2, false},
+	0x83: {OpTST, ImmediateZeroPage, 7, false},
+	0x84: {OpSTY, ZeroPage, 4, false},
+	0x85: {OpSTA, ZeroPage, 4, false},
+	0x86: {OpSTX, ZeroPage, 4, false},
+	0x87: {OpSMB, ZeroPage, 7, false},
+	0x88: {OpDEY, Implied, 2, false},
+	0x89: {OpBIT, Immediate, 2, false},
+	0x8A: {OpTXA, Implied, 2, false},
+	0x8B: {OpInvalid, Implied, 2, true},
+	0x8C: {OpSTY, Absolute, 5, false},
+	0x8D: {OpSTA, Absolute, 5, false},
+	0x8E: {OpSTX, Absolute, 5, false},
+	0x8F: {OpBBS, ZeroPageRelative, 6, false},
+	0x90: {OpBCC, Relative, 2, false},
+	0x91: {OpSTA, IndirectY, 7, false},
+	0x92: {OpSTA, ZeroPageIndirect, 7, false},
+	0x93: {OpTST, ImmediateAbsolute, 8, false},
+	0x94: {OpSTY, ZeroPageX, 4, false},
+	0x95: {OpSTA, ZeroPageX, 4, false},
+	0x96: {OpSTX, ZeroPageY, 4, false},
+	0x97: {OpSMB, ZeroPage, 7, false},
+	0x98: {OpTYA, Implied, 2, false},
+	0x99: {OpSTA, AbsoluteY, 5, false},
+	0x9A: {OpTXS, Implied, 2, false},
+	0x9B: {OpInvalid, Implied, 2, true},
+	0x9C: {OpSTZ, Absolute, 5, false},
+	0x9D: {OpSTA, AbsoluteX, 5, false},
+	0x9E: {OpSTZ, AbsoluteX, 5, false},
+	0x9F: {OpBBS, ZeroPageRelative, 6, false},
+	0xA0: {OpLDY, Immediate, 2, false},
+	0xA1: {OpLDA, IndirectX, 7, false},
+	0xA2: {OpLDX, Immediate, 2, false},
+	0xA3: {OpTST, ImmediateZeroPageX, 7, false},
+	0xA4: {OpLDY, ZeroPage, 4, false},
+	0xA5: {OpLDA, ZeroPage, 4, false},
+	0xA6: {OpLDX, ZeroPage, 4, false},
+	0xA7: {OpSMB, ZeroPage, 7, false},
+	0xA8: {OpTAY, Implied, 2, false},
+	0xA9: {OpLDA, Immediate, 2, false},
+	0xAA: {OpTAX, Implied, 2, false},
+	0xAB: {OpInvalid, Implied, 2, true},
+	0xAC: {OpLDY, Absolute, 5, false},
+	0xAD: {OpLDA, Absolute, 5, false},
+	0xAE: {OpLDX, Absolute, 5, false},
+	0xAF: {OpBBS, ZeroPageRelative, 6, false},
+	0xB0: {OpBCS, Relative, 2, false},
+	0xB1: {OpLDA, IndirectY, 7, false},
+	0xB2: {OpLDA, ZeroPageIndirect, 7, false},
+	0xB3: {OpTST, ImmediateAbsoluteX, 8, false},
+	0xB4: {OpLDY, ZeroPageX, 4, false},
+	0xB5: {OpLDA, ZeroPageX, 4, false},
+	0xB6: {OpLDX, ZeroPageY, 4, false},
+	0xB7: {OpSMB, ZeroPage, 7, false},
+	0xB8: {OpCLV, Implied, 2, false},
+	0xB9: {OpLDA, AbsoluteY, 5, false},
+	0xBA: {OpTSX, Implied, 2, false},
+	0xBB: {OpInvalid, Implied, 2, true},
+	0xBC: {OpLDY, AbsoluteX, 5, false},
+	0xBD: {OpLDA, AbsoluteX, 5, false},
+	0xBE: {OpLDX, AbsoluteY, 5, false},
+	0xBF: {OpBBS, ZeroPageRelative, 6, false},
+	0xC0: {OpCPY, Immediate, 2, false},
+	0xC1: {OpCMP, IndirectX, 7, false},
+	0xC2: {OpCLY, Implied, 2, false},
+	0xC3: {OpTDD, BlockTransfer, 17, false},
+	0xC4: {OpCPY, ZeroPage, 4, false},
+	0xC5: {OpCMP, ZeroPage, 4, false},
+	0xC6: {OpDEC, ZeroPage, 6, false},
+	0xC7: {OpSMB, ZeroPage, 7, false},
+	0xC8: {OpINY, Implied, 2, false},
+	0xC9: {OpCMP, Immediate, 2, false},
+	0xCA: {OpDEX, Implied, 2, false},
+	0xCB: {OpInvalid, Implied, 2, true},
+	0xCC: {OpCPY, Absolute, 5, false},
+	0xCD: {OpCMP, Absolute, 5, false},
+	0xCE: {OpDEC, Absolute, 7, false},
+	0xCF: {OpBBS, ZeroPageRelative, 6, false},
+	0xD0: {OpBNE, Relative, 2, false},
+	0xD1: {OpCMP, IndirectY, 7, false},
+	0xD2: {OpCMP, ZeroPageIndirect, 7, false},
+	0xD3: {OpTIN, BlockTransfer, 17, false},
+	0xD4: {OpCSH, Implied, 3, false},
+	0xD5: {OpCMP, ZeroPageX, 4, false},
+	0xD6: {OpDEC, ZeroPageX, 6, false},
+	0xD7: {OpSMB, ZeroPage, 7, false},
+	0xD8: {OpCLD, Implied, 2, false},
+	0xD9: {OpCMP, AbsoluteY, 5, false},
+	0xDA: {OpPHX, Implied, 3, false},
+	0xDB: {OpInvalid, Implied, 2, true},
+	0xDC: {OpInvalid, Implied, 2, true},
+	0xDD: {OpCMP, AbsoluteX, 5, false},
+	0xDE: {OpDEC, AbsoluteX, 7, false},
+	0xDF: {OpBBS, ZeroPageRelative, 6, false},
+	0xE0: {OpCPX, Immediate, 2, false},
+	0xE1: {OpSBC, IndirectX, 7, false},
+	0xE2: {OpInvalid, Implied, 2, true},
+	0xE3: {OpTIA, BlockTransfer, 17, false},
+	0xE4: {OpCPX, ZeroPage, 4, false},
+	0xE5: {OpSBC, ZeroPage, 4, false},
+	0xE6: {OpINC, ZeroPage, 6, false},
+	0xE7: {OpSMB, ZeroPage, 7, false},
+	0xE8: {OpINX, Implied, 2, false},
+	0xE9: {OpSBC, Immediate, 2, false},
+	0xEA: {OpNOP, Implied, 2, false},
+	0xEB: {OpInvalid, Implied, 2, true},
+	0xEC: {OpCPX, Absolute, 5, false},
+	0xED: {OpSBC, Absolute, 5, false},
+	0xEE: {OpINC, Absolute, 7, false},
+	0xEF: {OpBBS, ZeroPageRelative, 6, false},
+	0xF0: {OpBEQ, Relative, 2, false},
+	0xF1: {OpSBC, IndirectY, 7, false},
+	0xF2: {OpSBC, ZeroPageIndirect, 7, false},
+	0xF3: {OpTAI, BlockTransfer, 17, false},
+	0xF4: {OpSET, Implied, 2, false},
+	0xF5: {OpSBC, ZeroPageX, 4, false},
+	0xF6: {OpINC, ZeroPageX, 6, false},
+	0xF7: {OpSMB, ZeroPage, 7, false},
+	0xF8: {OpSED, Implied, 2, false},
+	0xF9: {OpSBC, AbsoluteY, 5, false},
+	0xFA: {OpPLX, Implied, 4, false},
+	0xFB: {OpInvalid, Implied, 2, true},
+	0xFC: {OpInvalid, Implied, 2, true},
+	0xFD: {OpSBC, AbsoluteX, 5, false},
+	0xFE: {OpINC, AbsoluteX, 7, false},
+	0xFF: {OpBBS, ZeroPageRelative, 6, false},
+}
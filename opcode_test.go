@@ -0,0 +1,122 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		opcode   byte
+		mnemonic string
+		mode     Mode
+		size     byte
+		illegal  bool
+	}{
+		{0x00, "BRK", Implied, 1, false},
+		{0x0A, "ASL", Accumulator, 1, false},
+		{0x6C, "JMP", Indirect, 3, false},
+		{0xA9, "LDA", Immediate, 2, false},
+		{0xB6, "LDX", ZeroPageY, 2, false},
+		{0xB1, "LDA", IndirectY, 2, false},
+		{0x9D, "STA", AbsoluteX, 3, false},
+		{0xA7, "LAX", ZeroPage, 2, true},
+		{0xAB, "LXA", Immediate, 2, true},
+		{0x1C, "NOP", AbsoluteX, 3, true},
+		{0x02, "HLT", Implied, 1, true},
+	}
+	for _, tt := range tests {
+		i := Decode(tt.opcode)
+		if i.Mnemonic() != tt.mnemonic || i.Mode != tt.mode || i.Size() != tt.size || i.Illegal != tt.illegal {
+			t.Errorf("unexpected, got %s %s %d %t for %02X", i.Mnemonic(), i.Mode, i.Size(), i.Illegal, tt.opcode)
+		}
+	}
+	if Decode(0xA3).Op != OpLAX || Decode(0xAB).Op == OpLAX {
+		t.Error("unexpected")
+	}
+}
+
+func TestOpString(t *testing.T) {
+	if OpLDA.String() != "LDA" || OpTAS.String() != "TAS" || OpInvalid.String() != "???" || Op(0xFF).String() != "???" {
+		t.Error("unexpected")
+	}
+	if Mode(0xFF).String() != "unknown" || IndirectX.String() != "(indirect,X)" {
+		t.Error("unexpected")
+	}
+}
+
+func TestDecodeCycles(t *testing.T) {
+	bus := &memoryBus{}
+	cpu := New(bus)
+
+	for op := 0; op < 0x100; op++ {
+		i := Decode(byte(op))
+		if i.Mode == Relative {
+			continue
+		}
+		bus.Reset()
+		bus.mem[0x0400] = byte(op)
+		cpu.Reset()
+		cpu.PC(0x00, 0x04)
+
+		cycles, err := cpu.Step()
+		if err != nil {
+			continue
+		}
+		if cycles != uint(i.Cycles) {
+			t.Errorf("unexpected, want %d, got %d cycles for %02X %s", i.Cycles, cycles, op, i.Mnemonic())
+		}
+	}
+}
+
+func TestDecodeSizes(t *testing.T) {
+	for _, v := range []Variant{NMOS6502, Ricoh2A03, CMOS65C02} {
+		bus := &memoryBus{}
+		cpu := New(bus, WithVariant(v))
+
+		for op := 0; op < 0x100; op++ {
+			i := DecodeVariant(v, byte(op))
+			switch i.Op {
+			case OpBRK, OpJMP, OpJSR, OpRTS, OpRTI:
+				continue
+			}
+			bus.Reset()
+			bus.mem[0x0400] = byte(op) // Operands of zero, branches continue at the next op code
+			cpu.Reset()
+			cpu.PC(0x00, 0x04)
+
+			if _, err := cpu.Step(); err != nil {
+				continue
+			}
+			if pc := cpu.pc(); pc != 0x0400+uint16(i.Size()) {
+				t.Errorf("unexpected, got %04X for %02X %s on %s", pc, op, i.Mnemonic(), v)
+			}
+		}
+	}
+}
+
+func TestTraceOp(t *testing.T) {
+	bus := &memoryBus{}
+	bus.mem[0x0000] = 0xA9
+
+	cpu := New(bus)
+
+	op := OpInvalid
+	cpu.SetTracer(func(t Trace) { op = t.Op })
+	_, _ = cpu.Step()
+
+	if op != OpLDA {
+		t.Fatalf("unexpected, got %s", op)
+	}
+
+	// PHX, BRA on the 65C02.
+	ops := []Op{}
+	copy(bus.mem[0x0000:], []byte{0xDA, 0x80, 0x00})
+	cpu = New(bus, WithVariant(CMOS65C02))
+	cpu.SetTracer(func(t Trace) { ops = append(ops, t.Op) })
+	_, _ = cpu.Step()
+	_, _ = cpu.Step()
+
+	if len(ops) != 2 || ops[0] != OpPHX || ops[1] != OpBRA {
+		t.Fatalf("unexpected, got %v", ops)
+	}
+}
@@ -0,0 +1,1329 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+func when(d bool, t, g byte) byte {
+	if d {
+		return t
+	}
+	return g
+}
+
+func uadd(a, b byte) (byte, byte) { s := a + b; return s, when(s < b, 0x01, 0x00) }
+func ovfl(s int16) byte           { return when(s>>8 > 0x00, 0x01, when(s < 0, 0xFF, 0x00)) }
+func sadd(a byte, b int8) (byte, byte) {
+	s := int16(a) + int16(b)
+	return byte(s), ovfl(s)
+}
+func inc(l, h byte) (byte, byte) { l, c := uadd(l, 0x01); return l, h + c }
+
+func (cpu *CPU) cost(n byte) {
+	cpu.cycles += uint(n)
+	for ; (cpu.micro.on || cpu.phased != nil) && n > 0; n-- {
+		cpu.idle()
+	}
+}
+
+// access records a bus access, of the kind set for it, when not plain.
+func (cpu *CPU) access(k MicroKind, l, h, b byte) {
+	if cpu.observers.pages[h] != 0 {
+		cpu.observe(uint16(h)<<8|uint16(l), b, k == MicroWrite)
+	}
+	if cpu.kind == 0 && cpu.resolve != nil {
+		cpu.eff, cpu.effOK = Access{uint16(h)<<8 | uint16(l), b, k == MicroWrite}, true
+	}
+	if cpu.micro.on {
+		if cpu.kind != 0 {
+			k = cpu.kind
+		}
+		cpu.record(k, uint16(h)<<8|uint16(l), b)
+	}
+}
+
+// track updates the watch field of the CPU, whether access() has work to do.
+func (cpu *CPU) track() {
+	cpu.watch = len(cpu.observers.list) > 0 || cpu.resolve != nil || cpu.micro.on
+	if !cpu.micro.on {
+		cpu.micro.ops = cpu.micro.ops[:0]
+	}
+}
+
+func (cpu *CPU) setPC(l, h byte) { cpu.pcl, cpu.pch = l, h }
+func (cpu *CPU) incPC()          { cpu.setPC(inc(cpu.pcl, cpu.pch)) }
+
+func (cpu *CPU) read(l, h byte) byte {
+	cpu.cycles++
+	b := byte(0)
+	if cpu.mem != nil {
+		b = cpu.mem[uint16(h)<<8|uint16(l)]
+	} else {
+		cpu.addr, cpu.wr = uint16(h)<<8|uint16(l), false
+		b = cpu.bus.Read(l, h)
+	}
+	if cpu.watch {
+		cpu.access(MicroRead, l, h, b)
+	}
+	cpu.kind = 0
+	return b
+}
+func (cpu *CPU) zread(l byte) byte           { return cpu.read(l, 0x00) }
+func (cpu *CPU) vec(a uint16) byte           { cpu.kind = MicroVector; return cpu.read(byte(a), byte(a>>8)) }
+func (cpu *CPU) vread(a uint16) (byte, byte) { return cpu.vec(a), cpu.vec(a + 1) }
+
+func (cpu *CPU) write(l, h, b byte) {
+	if cpu.vectors != VectorIgnore && cpu.vecs.has(uint16(h)<<8|uint16(l)) {
+		cpu.stop = cpu.vectorWrite(cpu.at, uint16(h)<<8|uint16(l), b)
+	}
+	if cpu.requests.portOn && uint16(h)<<8|uint16(l) == cpu.requests.port {
+		cpu.stop = &RequestError{Request: Request(b), PC: cpu.at}
+	}
+	cpu.cycles++
+	if cpu.mem != nil {
+		cpu.mem[uint16(h)<<8|uint16(l)] = b
+	} else {
+		cpu.addr, cpu.wr = uint16(h)<<8|uint16(l), true
+		cpu.bus.Write(l, h, b)
+	}
+	if cpu.watch {
+		cpu.access(MicroWrite, l, h, b)
+	}
+	cpu.kind = 0
+}
+func (cpu *CPU) zwrite(l, b byte) { cpu.write(l, 0x00, b) }
+
+func (cpu *CPU) fetch() byte {
+	if cpu.kind == 0 {
+		cpu.kind = MicroOperand
+	}
+	cpu.cycles++
+	b := byte(0)
+	if cpu.mem != nil {
+		b = cpu.mem[uint16(cpu.pch)<<8|uint16(cpu.pcl)]
+	} else {
+		cpu.addr, cpu.wr = uint16(cpu.pch)<<8|uint16(cpu.pcl), false
+		b = cpu.bus.Read(cpu.pcl, cpu.pch)
+	}
+	if cpu.watch {
+		cpu.access(MicroRead, cpu.pcl, cpu.pch, b)
+	}
+	cpu.kind = 0
+	cpu.incPC()
+	return b
+}
+
+func (cpu *CPU) setF(c bool, f flag) { cpu.p.set(c, f) }
+func (cpu *CPU) hasF(f flag) bool    { return cpu.p.has(f) }
+
+func (cpu *CPU) setC(c bool)       { cpu.setF(c, flagC) }
+func (cpu *CPU) setI(c bool)       { cpu.setF(c, flagI) }
+func (cpu *CPU) setN(b byte)       { cpu.setF(b&0x80 != 0x00, flagN) }
+func (cpu *CPU) setNZ(b byte) byte { cpu.setN(b); cpu.setF(b == 0x00, flagZ); return b }
+
+func (cpu *CPU) setA(b byte) { cpu.a = cpu.setNZ(b) }
+func (cpu *CPU) setX(b byte) { cpu.x = cpu.setNZ(b) }
+func (cpu *CPU) setY(b byte) { cpu.y = cpu.setNZ(b) }
+
+func (cpu *CPU) push(b byte) {
+	if cpu.s == 0x00 && !cpu.ce.wide && cpu.stackWrap != StackWrapIgnore {
+		cpu.wrapped(cpu.at, true)
+	}
+	cpu.kind = MicroPush
+	cpu.write(cpu.s, cpu.sph, b)
+	cpu.down()
+}
+func (cpu *CPU) pop() byte {
+	if cpu.s == 0xFF && !cpu.ce.wide && cpu.stackWrap != StackWrapIgnore {
+		cpu.wrapped(cpu.at, false)
+	}
+	cpu.up()
+	cpu.kind = MicroPull
+	return cpu.read(cpu.s, cpu.sph)
+}
+
+// down and up move the stack pointer, by 16 bit on the 65CE02 with E clear.
+func (cpu *CPU) down() {
+	if cpu.s--; cpu.s == 0xFF && cpu.ce.wide {
+		cpu.sph--
+	}
+}
+func (cpu *CPU) up() {
+	if cpu.s++; cpu.s == 0x00 && cpu.ce.wide {
+		cpu.sph++
+	}
+}
+
+func (cpu *CPU) pushPC()             { cpu.push(cpu.pch); cpu.push(cpu.pcl) }
+func (cpu *CPU) popPC() (byte, byte) { return cpu.pop(), cpu.pop() }
+
+func (cpu *CPU) php() { cpu.push(byte(cpu.p | flagU | flagB)) }
+func (cpu *CPU) plp() { cpu.p = flag(cpu.pop()) & ^(flagU | flagB) }
+
+func (cpu *CPU) cmp(a, b byte) { cpu.setNZ(b - a); cpu.setC(b >= a) }
+func (cpu *CPU) bit(b byte) {
+	cpu.setN(b)
+	cpu.setF(b&cpu.a == 0, flagZ)
+	cpu.setF(b&0x40 != 0, flagV)
+}
+
+func (cpu *CPU) asl(b byte) byte { cpu.setC(b&0x80 != 0); return cpu.setNZ(b << 1) }
+func (cpu *CPU) lsr(b byte) byte { cpu.setC(b&0x01 != 0); return cpu.setNZ(b >> 1) }
+func (cpu *CPU) rol(b byte) byte {
+	c := byte(cpu.p & flagC)
+	cpu.setC(b&0x80 != 0)
+	return cpu.setNZ(b<<1 | c)
+}
+func (cpu *CPU) ror(b byte) byte {
+	c := byte(cpu.p & flagC)
+	cpu.setC(b&0x01 != 0)
+	return cpu.setNZ(b>>1 | c<<7)
+}
+
+func (cpu *CPU) dec(b byte) byte  { return cpu.setNZ(b - 1) }
+func (cpu *CPU) incr(b byte) byte { return cpu.setNZ(b + 1) }
+
+// Read-modify-write: NMOS writes the unmodified value back before the
+// modified one, CMOS reads the value twice instead.
+func (cpu *CPU) rmw(l, h byte, f func(*CPU, byte) byte) {
+	b := cpu.read(l, h)
+	if cpu.quirks.DummyWrite {
+		cpu.kind = MicroDummyWrite
+		cpu.write(l, h, b)
+	} else {
+		cpu.kind = MicroDummyRead
+		cpu.read(l, h)
+	}
+	cpu.write(l, h, f(cpu, b))
+}
+
+// Indexed addressing: NMOS reads from the address not yet corrected by
+// the carry into the high byte, CMOS rereads the last instruction byte.
+func (cpu *CPU) dummy(l, h byte) {
+	if cpu.kind = MicroDummyRead; cpu.quirks.DummyIndex {
+		cpu.read(l, h)
+	} else {
+		cpu.read(cpu.pcl-1, cpu.pch-when(cpu.pcl == 0x00, 1, 0))
+	}
+}
+func (cpu *CPU) cross(l, h, c byte) {
+	if c != 0 {
+		cpu.dummy(l, h-c)
+	}
+}
+
+// 65C02 shift/rotate absolute,X saves a cycle without page cross.
+func (cpu *CPU) shiftX(l, h, c byte) {
+	if cpu.quirks.ShiftX {
+		cpu.cross(l, h, c)
+	} else {
+		cpu.dummy(l, h-c)
+	}
+}
+
+func (cpu *CPU) abs() (byte, byte) { return cpu.fetch(), cpu.fetch() }
+func (cpu *CPU) absN(n byte) (byte, byte, byte) {
+	l, c := uadd(cpu.fetch(), n)
+	return l, cpu.fetch() + c, c
+}
+func (cpu *CPU) relN(n byte) (byte, byte, byte) {
+	l, o := sadd(cpu.pcl, int8(n))
+	return l, cpu.pch + o, o
+}
+
+func (cpu *CPU) indY() (byte, byte, byte) {
+	b := cpu.fetch()
+	l, c := uadd(cpu.zread(b), cpu.y)
+	return l, cpu.zread(b+1) + c, c
+}
+func (cpu *CPU) indX() (byte, byte) {
+	b := cpu.fetch() + cpu.x
+	return cpu.zread(b), cpu.zread(b + 1)
+}
+
+// sh stores b ANDed with the high byte of the base address plus one, the
+// unstable stores SHA, SHX, SHY and TAS. On a page cross, the high byte
+// of the target address is replaced by the value stored, unless the
+// stores have been set stable.
+func (cpu *CPU) sh(l, h, c, b byte) {
+	cpu.dummy(l, h-c)
+	b &= h - c + 1
+	if c != 0 && !cpu.stable {
+		h = b
+	}
+	cpu.write(l, h, b)
+}
+
+func (cpu *CPU) bcd(m DecimalMode) bool {
+	return cpu.hasF(flagD) && cpu.decimal&m != 0 && !cpu.quirks.NoDecimal
+}
+
+func (cpu *CPU) add(b byte) byte {
+	w := uint16(cpu.a) + uint16(b) + uint16(when(cpu.hasF(flagC), 0x01, 0x00))
+	r := byte(w)
+	cpu.setC(w > 0xFF)
+	cpu.setF((cpu.a^r)&(b^r)&0x80 != 0x00, flagV)
+	return r
+}
+
+// adc adds with carry to A. In decimal mode, NMOS sets N and V from the
+// sum of the high digits before their adjustment and Z from the binary sum,
+// CMOS sets N and Z from the result and takes an extra cycle.
+func (cpu *CPU) adc(b byte) {
+	if !cpu.bcd(DecimalADC) {
+		cpu.setA(cpu.add(b))
+		return
+	}
+	c := int(when(cpu.hasF(flagC), 0x01, 0x00))
+	l := int(cpu.a&0x0F) + int(b&0x0F) + c
+	if l >= 0x0A {
+		l = (l+0x06)&0x0F + 0x10
+	}
+	r := int(cpu.a&0xF0) + int(b&0xF0) + l
+	s := int(int8(cpu.a&0xF0)) + int(int8(b&0xF0)) + l
+	cpu.setF(s < -128 || s > 127, flagV)
+	n := byte(r)
+	if r >= 0xA0 {
+		r += 0x60
+	}
+	cpu.setC(r > 0xFF)
+	if cpu.quirks.BinaryFlags {
+		cpu.setNZ(cpu.a + b + byte(c))
+		cpu.setN(n)
+		cpu.a = byte(r)
+		return
+	}
+	cpu.setA(byte(r))
+	cpu.cost(1)
+}
+
+// sbc subtracts with borrow from A. In decimal mode, NMOS sets the flags
+// like in binary mode. CMOS adjusts the binary difference instead of the
+// digits, which differs for invalid BCD, sets N and Z from the result and
+// takes an extra cycle.
+func (cpu *CPU) sbc(b byte) {
+	if !cpu.bcd(DecimalSBC) {
+		cpu.setA(cpu.add(^b))
+		return
+	}
+	c := int(when(cpu.hasF(flagC), 0x01, 0x00))
+	l := int(cpu.a&0x0F) - int(b&0x0F) + c - 1
+	if cpu.quirks.BinaryFlags {
+		if l < 0 {
+			l = (l-0x06)&0x0F - 0x10
+		}
+		r := int(cpu.a&0xF0) - int(b&0xF0) + l
+		if r < 0 {
+			r -= 0x60
+		}
+		cpu.setNZ(cpu.add(^b)) // Flags like in binary mode
+		cpu.a = byte(r)
+		return
+	}
+	r := int(cpu.a) - int(b) + c - 1
+	if r < 0 {
+		r -= 0x60
+	}
+	if l < 0 {
+		r -= 0x06
+	}
+	cpu.add(^b) // C and V like in binary mode
+	cpu.setA(byte(r))
+	cpu.cost(1)
+}
+
+func (cpu *CPU) branch(c bool) {
+	if b := cpu.fetch(); c {
+		l, h, o := cpu.relN(b)
+		cpu.cost(1 + when(o == 0, 0, 1))
+		cpu.setPC(l, h)
+		cpu.pollAt = uint(when(o == 0, 1, 0)) // Taken branch without page cross polls early
+	}
+}
+
+// Bit manipulation of the 65C02: mbit clears or sets a bit of a zero page
+// address, RMB and SMB. bbit branches when the bit is clear or set, BBR and
+// BBS, relative to the next instruction like the branches.
+func (cpu *CPU) mbit(bit byte, set bool) {
+	if !cpu.quirks.Rockwell {
+		cpu.invalid()
+		return
+	}
+	l := cpu.fetch()
+	b := cpu.zread(l)
+	cpu.kind = MicroDummyRead
+	cpu.zread(l)
+	cpu.zwrite(l, when(set, b|1<<bit, b&^(1<<bit)))
+}
+func (cpu *CPU) bbit(bit byte, set bool) {
+	if !cpu.quirks.Rockwell {
+		cpu.invalid()
+		return
+	}
+	l := cpu.fetch()
+	b := cpu.zread(l)
+	cpu.kind = MicroDummyRead
+	cpu.zread(l)
+	cpu.branch((b&(1<<bit) != 0) == set)
+}
+
+// invalid ends the instruction with an *OpcodeError.
+func (cpu *CPU) invalid() {
+	cpu.fail = &OpcodeError{PC: cpu.at, Opcode: cpu.read(byte(cpu.at), byte(cpu.at>>8))}
+}
+
+// dispatch performs the instructions by op code, nil for invalid op codes.
+var dispatch = [0x100]func(cpu *CPU){
+	//  * add 1 to cycles if page boundary is crossed
+	// ** add 1 to cycles if branch occurs on same page
+	// ** add 2 to cycles if branch occurs to different page
+	// ^  65C02: 6 cycles, add 1 if page boundary is crossed
+	// °  65C02 only
+	// †  NMOS only, unstable, see SetMagic()
+	//
+	// ADC and SBC add 1 to cycles in decimal mode on the 65C02.
+	//
+	//   Op     | Mnemonic     |  Addressing  |  Processor Flags  | Cycles
+	//
+	0x00: /* BRK          |   implied    | N- Z- C- I+ D- V- | 7 */ func(cpu *CPU) {
+		cpu.fetch()
+		cpu.pushPC()
+		cpu.php()
+		if cpu.hijack && cpu.nmiEdge && cpu.lines.nmiAt <= 4 {
+			cpu.nmiEdge = false
+			cpu.setPC(cpu.vread(cpu.vecs.NMI))
+		} else {
+			cpu.setPC(cpu.vread(cpu.vecs.IRQ))
+		}
+		cpu.setI(true)
+		if cpu.quirks.ClearD {
+			cpu.setF(false, flagD)
+		}
+		if cpu.calls.on {
+			cpu.calls.call(Frame{Interrupt: true, From: cpu.at, To: cpu.pc(), S: cpu.s})
+		}
+	},
+	0x20: /* JSR oper     |   absolute   | N- Z- C- I- D- V- | 6  */ func(cpu *CPU) {
+		l := cpu.fetch()
+		cpu.pushPC()
+		cpu.setPC(l, cpu.fetch())
+		cpu.cost(1)
+		if cpu.calls.on {
+			cpu.calls.call(Frame{From: cpu.at, To: cpu.pc(), S: cpu.s})
+		}
+	},
+	0x40: /* RTI          |   implied    |    from stack     | 7 */ func(cpu *CPU) {
+		if cpu.calls.on {
+			cpu.ret(true, cpu.at, cpu.s)
+		}
+		cpu.plp()
+		cpu.setPC(cpu.popPC())
+		cpu.cost(3)
+	},
+	0x60: /* RTS          |   implied    | N- Z- C- I- D- V- | 6 */ func(cpu *CPU) {
+		if cpu.calls.on {
+			cpu.ret(false, cpu.at, cpu.s)
+		}
+		cpu.setPC(inc(cpu.popPC()))
+		cpu.cost(3)
+	},
+	0x80: /* NOP          |  immediate   | N- Z- C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.fetch()
+	},
+	0xA0: /* LDY #oper    |  immediate   | N+ Z+ C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setY(cpu.fetch())
+	},
+	0xC0: /* CPY #oper    |  immediate   | N+ Z+ C+ I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.cmp(cpu.fetch(), cpu.y)
+	},
+	0xE0: /* CPX #oper    |  immediate   | N+ Z+ C+ I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.cmp(cpu.fetch(), cpu.x)
+	},
+
+	0x01: /* ORA (oper,X) | (indirect,X) | N+ Z+ C- I- D- V- | 6 */ func(cpu *CPU) {
+		cpu.setA(cpu.a | cpu.read(cpu.indX()))
+		cpu.cost(1)
+	},
+	0x21: /* AND (oper,X) | (indirect,X) | N+ Z+ C- I- D- V- | 6 */ func(cpu *CPU) {
+		cpu.setA(cpu.a & cpu.read(cpu.indX()))
+		cpu.cost(1)
+	},
+	0x41: /* EOR (oper,X) | (indirect,X) | N+ Z+ C- I- D- V- | 6 */ func(cpu *CPU) {
+		cpu.setA(cpu.a ^ cpu.read(cpu.indX()))
+		cpu.cost(1)
+	},
+	0x61: /* ADC (oper,X) | (indirect,X) | N+ Z+ C+ I- D- V+ | 6 */ func(cpu *CPU) {
+		cpu.adc(cpu.read(cpu.indX()))
+		cpu.cost(1)
+	},
+	0x81: /* STA (oper,X) | (indirect,X) | N- Z- C- I- D- V- | 6 */ func(cpu *CPU) {
+		l, h := cpu.indX()
+		cpu.write(l, h, cpu.a)
+		cpu.cost(1)
+	},
+	0xA1: /* LDA (oper,X) | (indirect,X) | N+ Z+ C- I- D- V- | 6 */ func(cpu *CPU) {
+		cpu.setA(cpu.read(cpu.indX()))
+		cpu.cost(1)
+	},
+	0xC1: /* CMP (oper,X) | (indirect,X) | N+ Z+ C+ I- D- V- | 6 */ func(cpu *CPU) {
+		cpu.cmp(cpu.read(cpu.indX()), cpu.a)
+		cpu.cost(1)
+	},
+	0xE1: /* SBC (oper,X) | (indirect,X) | N+ Z+ C+ I- D- V+ | 6 */ func(cpu *CPU) {
+		cpu.sbc(cpu.read(cpu.indX()))
+		cpu.cost(1)
+	},
+
+	0x02: /* HLT          |              |                   | 1 */ func(cpu *CPU) {
+		cpu.halt(ErrHalted)
+	},
+	0x22: /* HLT          |              |                   | 1 */ func(cpu *CPU) {
+		cpu.halt(ErrHalted)
+	},
+	0x42: /* HLT          |              |                   | 1 */ func(cpu *CPU) {
+		cpu.halt(ErrHalted)
+	},
+	0x62: /* HLT          |              |                   | 1 */ func(cpu *CPU) {
+		cpu.halt(ErrHalted)
+	},
+	0x82: /* NOP          |  immediate   | N- Z- C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.fetch()
+	},
+	0xA2: /* LDX #oper    |  immediate   | N+ Z+ C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setX(cpu.fetch())
+	},
+	0xC2: /* NOP          |  immediate   | N- Z- C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.fetch()
+	},
+	0xE2: /* NOP          |  immediate   | N- Z- C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.fetch()
+	},
+
+	0x04: /* NOP          |   zeropage   | N- Z- C- I- D- V- | 3 */ func(cpu *CPU) {
+		cpu.zread(cpu.fetch())
+	},
+	0x24: /* BIT oper     |   zeropage   | N+ Z+ C- I- D- V+ | 3 */ func(cpu *CPU) {
+		cpu.bit(cpu.zread(cpu.fetch()))
+	},
+	0x44: /* NOP          |   zeropage   | N- Z- C- I- D- V- | 3 */ func(cpu *CPU) {
+		cpu.zread(cpu.fetch())
+	},
+	0x64: /* NOP          |   zeropage   | N- Z- C- I- D- V- | 3 */ func(cpu *CPU) {
+		cpu.zread(cpu.fetch())
+	},
+	0x84: /* STY oper     |   zeropage   | N- Z- C- I- D- V- | 3 */ func(cpu *CPU) {
+		cpu.zwrite(cpu.fetch(), cpu.y)
+	},
+	0xA4: /* LDY oper     |   zeropage   | N+ Z+ C- I- D- V- | 3 */ func(cpu *CPU) {
+		cpu.setY(cpu.zread(cpu.fetch()))
+	},
+	0xC4: /* CPY oper     |   zeropage   | N+ Z+ C+ I- D- V- | 3 */ func(cpu *CPU) {
+		cpu.cmp(cpu.zread(cpu.fetch()), cpu.y)
+	},
+	0xE4: /* CPX oper     |   zeropage   | N+ Z+ C+ I- D- V- | 3 */ func(cpu *CPU) {
+		cpu.cmp(cpu.zread(cpu.fetch()), cpu.x)
+	},
+
+	0x05: /* ORA oper     |   zeropage   | N+ Z+ C- I- D- V- | 3 */ func(cpu *CPU) {
+		cpu.setA(cpu.a | cpu.zread(cpu.fetch()))
+	},
+	0x25: /* AND oper     |   zeropage   | N+ Z+ C- I- D- V- | 3 */ func(cpu *CPU) {
+		cpu.setA(cpu.a & cpu.zread(cpu.fetch()))
+	},
+	0x45: /* EOR oper     |   zeropage   | N+ Z+ C- I- D- V- | 3 */ func(cpu *CPU) {
+		cpu.setA(cpu.a ^ cpu.zread(cpu.fetch()))
+	},
+	0x65: /* ADC oper     |   zeropage   | N+ Z+ C+ I- D- V+ | 3 */ func(cpu *CPU) {
+		cpu.adc(cpu.zread(cpu.fetch()))
+	},
+	0x85: /* STA oper     |   zeropage   | N- Z- C- I- D- V- | 3 */ func(cpu *CPU) {
+		cpu.zwrite(cpu.fetch(), cpu.a)
+	},
+	0xA5: /* LDA oper     |   zeropage   | N+ Z+ C- I- D- V- | 3 */ func(cpu *CPU) {
+		cpu.setA(cpu.zread(cpu.fetch()))
+	},
+	0xC5: /* CMP oper     |   zeropage   | N+ Z+ C+ I- D- V- | 3 */ func(cpu *CPU) {
+		cpu.cmp(cpu.zread(cpu.fetch()), cpu.a)
+	},
+	0xE5: /* SBC oper     |   zeropage   | N+ Z+ C+ I- D- V+ | 3 */ func(cpu *CPU) {
+		cpu.sbc(cpu.zread(cpu.fetch()))
+	},
+
+	0x06: /* ASL oper     |   zeropage   | N+ Z+ C+ I- D- V- | 5 */ func(cpu *CPU) {
+		cpu.rmw(cpu.fetch(), 0x00, (*CPU).asl)
+	},
+	0x26: /* ROL oper     |   zeropage   | N+ Z+ C+ I- D- V- | 5 */ func(cpu *CPU) {
+		cpu.rmw(cpu.fetch(), 0x00, (*CPU).rol)
+	},
+	0x46: /* LSR oper     |   zeropage   | N0 Z+ C+ I- D- V- | 5 */ func(cpu *CPU) {
+		cpu.rmw(cpu.fetch(), 0x00, (*CPU).lsr)
+	},
+	0x66: /* ROR oper     |   zeropage   | N+ Z+ C+ I- D- V- | 5 */ func(cpu *CPU) {
+		cpu.rmw(cpu.fetch(), 0x00, (*CPU).ror)
+	},
+	0x86: /* STX oper     |   zeropage   | N- Z- C- I- D- V- | 3 */ func(cpu *CPU) {
+		cpu.zwrite(cpu.fetch(), cpu.x)
+	},
+	0xA6: /* LDX oper     |   zeropage   | N+ Z+ C- I- D- V- | 3 */ func(cpu *CPU) {
+		cpu.setX(cpu.zread(cpu.fetch()))
+	},
+	0xC6: /* DEC oper     |   zeropage   | N+ Z+ C- I- D- V- | 5 */ func(cpu *CPU) {
+		cpu.rmw(cpu.fetch(), 0x00, (*CPU).dec)
+	},
+	0xE6: /* INC oper     |   zeropage   | N+ Z+ C- I- D- V- | 5 */ func(cpu *CPU) {
+		cpu.rmw(cpu.fetch(), 0x00, (*CPU).incr)
+	},
+
+	0x07: /* RMB0 oper    |   zeropage   | N- Z- C- I- D- V- | 5 ° */ func(cpu *CPU) {
+		cpu.mbit(0, false)
+	},
+	0x17: /* RMB1 oper    |   zeropage   | N- Z- C- I- D- V- | 5 ° */ func(cpu *CPU) {
+		cpu.mbit(1, false)
+	},
+	0x27: /* RMB2 oper    |   zeropage   | N- Z- C- I- D- V- | 5 ° */ func(cpu *CPU) {
+		cpu.mbit(2, false)
+	},
+	0x37: /* RMB3 oper    |   zeropage   | N- Z- C- I- D- V- | 5 ° */ func(cpu *CPU) {
+		cpu.mbit(3, false)
+	},
+	0x47: /* RMB4 oper    |   zeropage   | N- Z- C- I- D- V- | 5 ° */ func(cpu *CPU) {
+		cpu.mbit(4, false)
+	},
+	0x57: /* RMB5 oper    |   zeropage   | N- Z- C- I- D- V- | 5 ° */ func(cpu *CPU) {
+		cpu.mbit(5, false)
+	},
+	0x67: /* RMB6 oper    |   zeropage   | N- Z- C- I- D- V- | 5 ° */ func(cpu *CPU) {
+		cpu.mbit(6, false)
+	},
+	0x77: /* RMB7 oper    |   zeropage   | N- Z- C- I- D- V- | 5 ° */ func(cpu *CPU) {
+		cpu.mbit(7, false)
+	},
+	0x87: /* SMB0 oper    |   zeropage   | N- Z- C- I- D- V- | 5 ° */ func(cpu *CPU) {
+		cpu.mbit(0, true)
+	},
+	0x97: /* SMB1 oper    |   zeropage   | N- Z- C- I- D- V- | 5 ° */ func(cpu *CPU) {
+		cpu.mbit(1, true)
+	},
+	0xA7: /* SMB2 oper    |   zeropage   | N- Z- C- I- D- V- | 5 ° */ func(cpu *CPU) {
+		cpu.mbit(2, true)
+	},
+	0xB7: /* SMB3 oper    |   zeropage   | N- Z- C- I- D- V- | 5 ° */ func(cpu *CPU) {
+		cpu.mbit(3, true)
+	},
+	0xC7: /* SMB4 oper    |   zeropage   | N- Z- C- I- D- V- | 5 ° */ func(cpu *CPU) {
+		cpu.mbit(4, true)
+	},
+	0xD7: /* SMB5 oper    |   zeropage   | N- Z- C- I- D- V- | 5 ° */ func(cpu *CPU) {
+		cpu.mbit(5, true)
+	},
+	0xE7: /* SMB6 oper    |   zeropage   | N- Z- C- I- D- V- | 5 ° */ func(cpu *CPU) {
+		cpu.mbit(6, true)
+	},
+	0xF7: /* SMB7 oper    |   zeropage   | N- Z- C- I- D- V- | 5 ° */ func(cpu *CPU) {
+		cpu.mbit(7, true)
+	},
+
+	0x08: /* PHP          |   implied    | N- Z- C- I- D- V- | 3 */ func(cpu *CPU) {
+		cpu.php()
+		cpu.cost(1)
+	},
+	0x28: /* PLP          |   implied    |    from stack     | 4 */ func(cpu *CPU) {
+		cpu.plp()
+		cpu.cost(2)
+	},
+	0x48: /* PHA          |   implied    | N- Z- C- I- D- V- | 3 */ func(cpu *CPU) {
+		cpu.push(cpu.a)
+		cpu.cost(1)
+	},
+	0x68: /* PLA          |   implied    | N+ Z+ C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.setA(cpu.pop())
+		cpu.cost(2)
+	},
+	0x88: /* DEY          |   implied    | N+ Z+ C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setY(cpu.y - 1)
+		cpu.cost(1)
+	},
+	0xA8: /* TAY          |   implied    | N+ Z+ C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setY(cpu.a)
+		cpu.cost(1)
+	},
+	0xC8: /* INY          |   implied    | N+ Z+ C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setY(cpu.y + 1)
+		cpu.cost(1)
+	},
+	0xE8: /* INX          |   implied    | N+ Z+ C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setX(cpu.x + 1)
+		cpu.cost(1)
+	},
+
+	0x09: /* ORA #oper    |  immediate   | N+ Z+ C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setA(cpu.a | cpu.fetch())
+	},
+	0x29: /* AND #oper    |  immediate   | N+ Z+ C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setA(cpu.a & cpu.fetch())
+	},
+	0x49: /* EOR #oper    |  immediate   | N+ Z+ C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setA(cpu.a ^ cpu.fetch())
+	},
+	0x69: /* ADC #oper    |  immediate   | N+ Z+ C+ I- D- V+ | 2 */ func(cpu *CPU) {
+		cpu.adc(cpu.fetch())
+	},
+	0x89: /* NOP          |  immediate   | N- Z- C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.fetch()
+	},
+	0xA9: /* LDA #oper    |  immediate   | N+ Z+ C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setA(cpu.fetch())
+	},
+	0xC9: /* CMP #oper    |  immediate   | N+ Z+ C+ I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.cmp(cpu.fetch(), cpu.a)
+	},
+	0xE9: /* SBC #oper    |  immediate   | N+ Z+ C+ I- D- V+ | 2 */ func(cpu *CPU) {
+		cpu.sbc(cpu.fetch())
+	},
+
+	0x0A: /* ASL A        | accumulator  | N+ Z+ C+ I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setA(cpu.asl(cpu.a))
+		cpu.cost(1)
+	},
+	0x2A: /* ROL A        | accumulator  | N+ Z+ C+ I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setA(cpu.rol(cpu.a))
+		cpu.cost(1)
+	},
+	0x4A: /* LSR A        | accumulator  | N0 Z+ C+ I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setA(cpu.lsr(cpu.a))
+		cpu.cost(1)
+	},
+	0x6A: /* ROR A        | accumulator  | N+ Z+ C+ I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setA(cpu.ror(cpu.a))
+		cpu.cost(1)
+	},
+	0x8A: /* TXA          |   implied    | N+ Z+ C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setA(cpu.x)
+		cpu.cost(1)
+	},
+	0xAA: /* TAX          |   implied    | N+ Z+ C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setX(cpu.a)
+		cpu.cost(1)
+	},
+	0xCA: /* DEX          |   implied    | N+ Z+ C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setX(cpu.x - 1)
+		cpu.cost(1)
+	},
+	0xEA: /* NOP          |   implied    | N- Z- C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.cost(1)
+	},
+
+	0x8B: /* ANE #oper    |  immediate   | N+ Z+ C- I- D- V- | 2 † */ func(cpu *CPU) {
+		if !cpu.quirks.Unstable {
+			cpu.invalid()
+			return
+		}
+		cpu.setA((cpu.a | cpu.magic) & cpu.x & cpu.fetch())
+	},
+	0x9B: /* TAS oper,Y   |  absolute,Y  | N- Z- C- I- D- V- | 5 † */ func(cpu *CPU) {
+		if !cpu.quirks.Unstable {
+			cpu.invalid()
+			return
+		}
+		cpu.s = cpu.a & cpu.x
+		l, h, c := cpu.absN(cpu.y)
+		cpu.sh(l, h, c, cpu.s)
+	},
+	0xAB: /* LXA #oper    |  immediate   | N+ Z+ C- I- D- V- | 2 † */ func(cpu *CPU) {
+		if !cpu.quirks.Unstable {
+			cpu.invalid()
+			return
+		}
+		cpu.setA((cpu.a | cpu.magic) & cpu.fetch())
+		cpu.x = cpu.a
+	},
+	0xCB: /* WAI          |   implied    | N- Z- C- I- D- V- | 3 ° */ func(cpu *CPU) {
+		if !cpu.quirks.WaitStop {
+			cpu.invalid()
+			return
+		}
+		cpu.cost(2)
+		cpu.waiting = true
+	},
+	0xDB: /* STP          |   implied    | N- Z- C- I- D- V- | 3 ° */ func(cpu *CPU) {
+		if !cpu.quirks.WaitStop {
+			cpu.invalid()
+			return
+		}
+		cpu.cost(2)
+		cpu.halt(ErrStopped)
+	},
+
+	0x0C: /* NOP          |   absolute   | N- Z- C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.read(cpu.abs())
+	},
+	0x2C: /* BIT oper     |   absolute   | N+ Z+ C- I- D- V+ | 4 */ func(cpu *CPU) {
+		cpu.bit(cpu.read(cpu.abs()))
+	},
+	0x4C: /* JMP oper     |   absolute   | N- Z- C- I- D- V- | 3 */ func(cpu *CPU) {
+		cpu.setPC(cpu.abs())
+	},
+	0x6C: /* JMP (oper)   |   indirect   | N- Z- C- I- D- V- | 5 */ func(cpu *CPU) {
+		l, h := cpu.abs()
+		lo := cpu.read(l, h)
+		if cpu.quirks.JMPWrap {
+			cpu.setPC(lo, cpu.read(l+1, h)) // NMOS bug: vector wraps within page
+			return
+		}
+		cpu.setPC(lo, cpu.read(inc(l, h)))
+		cpu.cost(1)
+	},
+	0x8C: /* STY oper     |   absolute   | N- Z- C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.write(cpu.fetch(), cpu.fetch(), cpu.y)
+	},
+	0xAC: /* LDY oper     |   absolute   | N+ Z+ C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.setY(cpu.read(cpu.abs()))
+	},
+	0xCC: /* CPY oper     |   absolute   | N+ Z+ C+ I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.cmp(cpu.read(cpu.abs()), cpu.y)
+	},
+	0xEC: /* CPX oper     |   absolute   | N+ Z+ C+ I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.cmp(cpu.read(cpu.abs()), cpu.x)
+	},
+
+	0x0D: /* ORA oper     |   absolute   | N+ Z+ C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.setA(cpu.a | cpu.read(cpu.abs()))
+	},
+	0x2D: /* AND oper     |   absolute   | N+ Z+ C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.setA(cpu.a & cpu.read(cpu.abs()))
+	},
+	0x4D: /* EOR oper     |   absolute   | N+ Z+ C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.setA(cpu.a ^ cpu.read(cpu.abs()))
+	},
+	0x6D: /* ADC oper     |   absolute   | N+ Z+ C+ I- D- V+ | 4 */ func(cpu *CPU) {
+		cpu.adc(cpu.read(cpu.abs()))
+	},
+	0x8D: /* STA oper     |   absolute   | N- Z- C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.write(cpu.fetch(), cpu.fetch(), cpu.a)
+	},
+	0xAD: /* LDA oper     |   absolute   | N+ Z+ C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.setA(cpu.read(cpu.abs()))
+	},
+	0xCD: /* CMP oper     |   absolute   | N+ Z+ C+ I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.cmp(cpu.read(cpu.abs()), cpu.a)
+	},
+	0xED: /* SBC oper     |   absolute   | N+ Z+ C+ I- D- V+ | 4 */ func(cpu *CPU) {
+		cpu.sbc(cpu.read(cpu.abs()))
+	},
+
+	0x0E: /* ASL oper     |   absolute   | N+ Z+ C+ I- D- V- | 6 */ func(cpu *CPU) {
+		l, h := cpu.abs()
+		cpu.rmw(l, h, (*CPU).asl)
+	},
+	0x2E: /* ROL oper     |   absolute   | N+ Z+ C+ I- D- V- | 6 */ func(cpu *CPU) {
+		l, h := cpu.abs()
+		cpu.rmw(l, h, (*CPU).rol)
+	},
+	0x4E: /* LSR oper     |   absolute   | N0 Z+ C+ I- D- V- | 6 */ func(cpu *CPU) {
+		l, h := cpu.abs()
+		cpu.rmw(l, h, (*CPU).lsr)
+	},
+	0x6E: /* ROR oper     |   absolute   | N+ Z+ C+ I- D- V- | 6 */ func(cpu *CPU) {
+		l, h := cpu.abs()
+		cpu.rmw(l, h, (*CPU).ror)
+	},
+	0x8E: /* STX oper     |   absolute   | N- Z- C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.write(cpu.fetch(), cpu.fetch(), cpu.x)
+	},
+	0xAE: /* LDX oper     |   absolute   | N+ Z+ C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.setX(cpu.read(cpu.abs()))
+	},
+	0xCE: /* DEC oper     |   absolute   | N+ Z+ C- I- D- V- | 6 */ func(cpu *CPU) {
+		l, h := cpu.abs()
+		cpu.rmw(l, h, (*CPU).dec)
+	},
+	0xEE: /* INC oper     |   absolute   | N+ Z+ C- I- D- V- | 6 */ func(cpu *CPU) {
+		l, h := cpu.abs()
+		cpu.rmw(l, h, (*CPU).incr)
+	},
+
+	0x10: /* BPL oper     |   relative   | N- Z- C- I- D- V- | 2** */ func(cpu *CPU) {
+		cpu.branch(!cpu.hasF(flagN))
+	},
+	0x30: /* BMI oper     |   relative   | N- Z- C- I- D- V- | 2** */ func(cpu *CPU) {
+		cpu.branch(cpu.hasF(flagN))
+	},
+	0x50: /* BVC oper     |   relative   | N- Z- C- I- D- V- | 2** */ func(cpu *CPU) {
+		cpu.branch(!cpu.hasF(flagV))
+	},
+	0x70: /* BVS oper     |   relative   | N- Z- C- I- D- V- | 2** */ func(cpu *CPU) {
+		cpu.branch(cpu.hasF(flagV))
+	},
+	0x90: /* BCC oper     |   relative   | N- Z- C- I- D- V- | 2** */ func(cpu *CPU) {
+		cpu.branch(!cpu.hasF(flagC))
+	},
+	0xB0: /* BCS oper     |   relative   | N- Z- C- I- D- V- | 2** */ func(cpu *CPU) {
+		cpu.branch(cpu.hasF(flagC))
+	},
+	0xD0: /* BNE oper     |   relative   | N- Z- C- I- D- V- | 2** */ func(cpu *CPU) {
+		cpu.branch(!cpu.hasF(flagZ))
+	},
+	0xF0: /* BEQ oper     |   relative   | N- Z- C- I- D- V- | 2** */ func(cpu *CPU) {
+		cpu.branch(cpu.hasF(flagZ))
+	},
+
+	0x11: /* ORA (oper),Y | (indirect),Y | N+ Z+ C- I- D- V- | 5* */ func(cpu *CPU) {
+		l, h, c := cpu.indY()
+		cpu.cross(l, h, c)
+		cpu.setA(cpu.a | cpu.read(l, h))
+	},
+	0x31: /* AND (oper),Y | (indirect),Y | N+ Z+ C- I- D- V- | 5* */ func(cpu *CPU) {
+		l, h, c := cpu.indY()
+		cpu.cross(l, h, c)
+		cpu.setA(cpu.a & cpu.read(l, h))
+	},
+	0x51: /* EOR (oper),Y | (indirect),Y | N+ Z+ C- I- D- V- | 5* */ func(cpu *CPU) {
+		l, h, c := cpu.indY()
+		cpu.cross(l, h, c)
+		cpu.setA(cpu.a ^ cpu.read(l, h))
+	},
+	0x71: /* ADC (oper),Y | (indirect),Y | N+ Z+ C+ I- D- V+ | 5* */ func(cpu *CPU) {
+		l, h, c := cpu.indY()
+		cpu.cross(l, h, c)
+		cpu.adc(cpu.read(l, h))
+	},
+	0x91: /* STA (oper),Y | (indirect),Y | N- Z- C- I- D- V- | 6 */ func(cpu *CPU) {
+		l, h, c := cpu.indY()
+		cpu.dummy(l, h-c)
+		cpu.write(l, h, cpu.a)
+	},
+	0x93: /* SHA (oper),Y | (indirect),Y | N- Z- C- I- D- V- | 6 † */ func(cpu *CPU) {
+		if !cpu.quirks.Unstable {
+			cpu.invalid()
+			return
+		}
+		l, h, c := cpu.indY()
+		cpu.sh(l, h, c, cpu.a&cpu.x)
+	},
+	0xB1: /* LDA (oper),Y | (indirect),Y | N+ Z+ C- I- D- V- | 5* */ func(cpu *CPU) {
+		l, h, c := cpu.indY()
+		cpu.cross(l, h, c)
+		cpu.setA(cpu.read(l, h))
+	},
+	0xD1: /* CMP (oper),Y | (indirect),Y | N+ Z+ C+ I- D- V- | 5* */ func(cpu *CPU) {
+		l, h, c := cpu.indY()
+		cpu.cross(l, h, c)
+		cpu.cmp(cpu.read(l, h), cpu.a)
+	},
+	0xF1: /* SBC (oper),Y | (indirect),Y | N+ Z+ C+ I- D- V+ | 5* */ func(cpu *CPU) {
+		l, h, c := cpu.indY()
+		cpu.cross(l, h, c)
+		cpu.sbc(cpu.read(l, h))
+	},
+
+	0x12: /* HLT          |              |                   | 1 */ func(cpu *CPU) {
+		cpu.halt(ErrHalted)
+	},
+	0x32: /* HLT          |              |                   | 1 */ func(cpu *CPU) {
+		cpu.halt(ErrHalted)
+	},
+	0x52: /* HLT          |              |                   | 1 */ func(cpu *CPU) {
+		cpu.halt(ErrHalted)
+	},
+	0x72: /* HLT          |              |                   | 1 */ func(cpu *CPU) {
+		cpu.halt(ErrHalted)
+	},
+	0x92: /* HLT          |              |                   | 1 */ func(cpu *CPU) {
+		cpu.halt(ErrHalted)
+	},
+	0xB2: /* HLT          |              |                   | 1 */ func(cpu *CPU) {
+		cpu.halt(ErrHalted)
+	},
+	0xD2: /* HLT          |              |                   | 1 */ func(cpu *CPU) {
+		cpu.halt(ErrHalted)
+	},
+	0xF2: /* HLT          |              |                   | 1 */ func(cpu *CPU) {
+		cpu.halt(ErrHalted)
+	},
+
+	0x14: /* NOP          |  zeropage,X  | N- Z- C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.zread(cpu.fetch() + cpu.x)
+		cpu.cost(1)
+	},
+	0x34: /* NOP          |  zeropage,X  | N- Z- C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.zread(cpu.fetch() + cpu.x)
+		cpu.cost(1)
+	},
+	0x54: /* NOP          |  zeropage,X  | N- Z- C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.zread(cpu.fetch() + cpu.x)
+		cpu.cost(1)
+	},
+	0x74: /* NOP          |  zeropage,X  | N- Z- C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.zread(cpu.fetch() + cpu.x)
+		cpu.cost(1)
+	},
+	0x94: /* STY oper,X   |  zeropage,X  | N- Z- C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.zwrite(cpu.fetch()+cpu.x, cpu.y)
+		cpu.cost(1)
+	},
+	0xB4: /* LDY oper,X   |  zeropage,X  | N+ Z+ C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.setY(cpu.zread(cpu.fetch() + cpu.x))
+		cpu.cost(1)
+	},
+	0xD4: /* NOP          |  zeropage,X  | N- Z- C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.zread(cpu.fetch() + cpu.x)
+		cpu.cost(1)
+	},
+	0xF4: /* NOP          |  zeropage,X  | N- Z- C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.zread(cpu.fetch() + cpu.x)
+		cpu.cost(1)
+	},
+
+	0x15: /* ORA oper,X   |  zeropage,X  | N+ Z+ C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.setA(cpu.a | cpu.zread(cpu.fetch()+cpu.x))
+		cpu.cost(1)
+	},
+	0x35: /* AND oper,X   |  zeropage,X  | N+ Z+ C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.setA(cpu.a & cpu.zread(cpu.fetch()+cpu.x))
+		cpu.cost(1)
+	},
+	0x55: /* EOR oper,X   |  zeropage,X  | N+ Z+ C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.setA(cpu.a ^ cpu.zread(cpu.fetch()+cpu.x))
+		cpu.cost(1)
+	},
+	0x75: /* ADC oper,X   |  zeropage,X  | N+ Z+ C+ I- D- V+ | 4 */ func(cpu *CPU) {
+		cpu.adc(cpu.zread(cpu.fetch() + cpu.x))
+		cpu.cost(1)
+	},
+	0x95: /* STA oper,X   |  zeropage,X  | N- Z- C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.zwrite(cpu.fetch()+cpu.x, cpu.a)
+		cpu.cost(1)
+	},
+	0xB5: /* LDA oper,X   |  zeropage,X  | N+ Z+ C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.setA(cpu.zread(cpu.fetch() + cpu.x))
+		cpu.cost(1)
+	},
+	0xD5: /* CMP oper,X   |  zeropage,X  | N+ Z+ C+ I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.cmp(cpu.zread(cpu.fetch()+cpu.x), cpu.a)
+		cpu.cost(1)
+	},
+	0xF5: /* SBC oper,X   |  zeropage,X  | N+ Z+ C+ I- D- V+ | 4 */ func(cpu *CPU) {
+		cpu.sbc(cpu.zread(cpu.fetch() + cpu.x))
+		cpu.cost(1)
+	},
+
+	0x16: /* ASL oper,X   |  zeropage,X  | N+ Z+ C+ I- D- V- | 6 */ func(cpu *CPU) {
+		cpu.rmw(cpu.fetch()+cpu.x, 0x00, (*CPU).asl)
+		cpu.cost(1)
+	},
+	0x36: /* ROL oper,X   |  zeropage,X  | N+ Z+ C+ I- D- V- | 6 */ func(cpu *CPU) {
+		cpu.rmw(cpu.fetch()+cpu.x, 0x00, (*CPU).rol)
+		cpu.cost(1)
+	},
+	0x56: /* LSR oper,X   |  zeropage,X  | N0 Z+ C+ I- D- V- | 6 */ func(cpu *CPU) {
+		cpu.rmw(cpu.fetch()+cpu.x, 0x00, (*CPU).lsr)
+		cpu.cost(1)
+	},
+	0x76: /* ROR oper,X   |  zeropage,X  | N+ Z+ C+ I- D- V- | 6 */ func(cpu *CPU) {
+		cpu.rmw(cpu.fetch()+cpu.x, 0x00, (*CPU).ror)
+		cpu.cost(1)
+	},
+	0x96: /* STX oper,Y   |  zeropage,Y  | N- Z- C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.zwrite(cpu.fetch()+cpu.y, cpu.x)
+		cpu.cost(1)
+	},
+	0xB6: /* LDX oper,Y   |  zeropage,Y  | N+ Z+ C- I- D- V- | 4 */ func(cpu *CPU) {
+		cpu.setX(cpu.zread(cpu.fetch() + cpu.y))
+		cpu.cost(1)
+	},
+	0xD6: /* DEC oper,X   |  zeropage,X  | N+ Z+ C- I- D- V- | 6 */ func(cpu *CPU) {
+		cpu.rmw(cpu.fetch()+cpu.x, 0x00, (*CPU).dec)
+		cpu.cost(1)
+	},
+	0xF6: /* INC oper,X   |  zeropage,X  | N+ Z+ C- I- D- V- | 6 */ func(cpu *CPU) {
+		cpu.rmw(cpu.fetch()+cpu.x, 0x00, (*CPU).incr)
+		cpu.cost(1)
+	},
+
+	0x18: /* CLC          |   implied    | N- Z- C0 I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setC(false)
+		cpu.cost(1)
+	},
+	0x38: /* SEC          |   implied    | N- Z- C1 I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setC(true)
+		cpu.cost(1)
+	},
+	0x58: /* CLI          |   implied    | N- Z- C- I0 D- V- | 2 */ func(cpu *CPU) {
+		cpu.setI(false)
+		cpu.cost(1)
+	},
+	0x78: /* SEI          |   implied    | N- Z- C- I1 D- V- | 2 */ func(cpu *CPU) {
+		cpu.setI(true)
+		cpu.cost(1)
+	},
+	0x98: /* TYA          |   implied    | N+ Z+ C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setA(cpu.y)
+		cpu.cost(1)
+	},
+	0xB8: /* CLV          |   implied    | N- Z- C- I- D- V0 | 2 */ func(cpu *CPU) {
+		cpu.setF(false, flagV)
+		cpu.cost(1)
+	},
+	0xD8: /* CLD          |   implied    | N- Z- C- I- D0 V- | 2 */ func(cpu *CPU) {
+		cpu.setF(false, flagD)
+		cpu.cost(1)
+	},
+	0xF8: /* SED          |   implied    | N- Z- C- I- D1 V- | 2 */ func(cpu *CPU) {
+		cpu.setF(true, flagD)
+		cpu.cost(1)
+	},
+
+	0x19: /* ORA oper,Y   |  absolute,Y  | N+ Z+ C- I- D- V- | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.y)
+		cpu.cross(l, h, c)
+		cpu.setA(cpu.a | cpu.read(l, h))
+	},
+	0x39: /* AND oper,Y   |  absolute,Y  | N+ Z+ C- I- D- V- | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.y)
+		cpu.cross(l, h, c)
+		cpu.setA(cpu.a & cpu.read(l, h))
+	},
+	0x59: /* EOR oper,Y   |  absolute,Y  | N+ Z+ C- I- D- V- | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.y)
+		cpu.cross(l, h, c)
+		cpu.setA(cpu.a ^ cpu.read(l, h))
+	},
+	0x79: /* ADC oper,Y   |  absolute,Y  | N+ Z+ C+ I- D- V+ | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.y)
+		cpu.cross(l, h, c)
+		cpu.adc(cpu.read(l, h))
+	},
+	0x99: /* STA oper,Y   |  absolute,Y  | N- Z- C- I- D- V- | 5 */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.y)
+		cpu.dummy(l, h-c)
+		cpu.write(l, h, cpu.a)
+	},
+	0xB9: /* LDA oper,Y   |  absolute,Y  | N+ Z+ C- I- D- V- | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.y)
+		cpu.cross(l, h, c)
+		cpu.setA(cpu.read(l, h))
+	},
+	0xD9: /* CMP oper,Y   |  absolute,Y  | N+ Z+ C+ I- D- V- | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.y)
+		cpu.cross(l, h, c)
+		cpu.cmp(cpu.read(l, h), cpu.a)
+	},
+	0xF9: /* SBC oper,Y   |  absolute,Y  | N+ Z+ C+ I- D- V+ | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.y)
+		cpu.cross(l, h, c)
+		cpu.sbc(cpu.read(l, h))
+	},
+
+	0x1A: /* NOP          |   implied    | N- Z- C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.cost(1)
+	},
+	0x3A: /* NOP          |   implied    | N- Z- C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.cost(1)
+	},
+	0x5A: /* NOP          |   implied    | N- Z- C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.cost(1)
+	},
+	0x7A: /* NOP          |   implied    | N- Z- C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.cost(1)
+	},
+	0x9A: /* TXS          |   implied    | N- Z- C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.s = cpu.x
+		cpu.cost(1)
+	},
+	0xBA: /* TSX          |   implied    | N+ Z+ C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.setX(cpu.s)
+		cpu.cost(1)
+	},
+	0xDA: /* NOP          |   implied    | N- Z- C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.cost(1)
+	},
+	0xFA: /* NOP          |   implied    | N- Z- C- I- D- V- | 2 */ func(cpu *CPU) {
+		cpu.cost(1)
+	},
+
+	0x1C: /* NOP          |  absolute,X  | N- Z- C- I- D- V- | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.cross(l, h, c)
+		cpu.read(l, h)
+	},
+	0x3C: /* NOP          |  absolute,X  | N- Z- C- I- D- V- | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.cross(l, h, c)
+		cpu.read(l, h)
+	},
+	0x5C: /* NOP          |  absolute,X  | N- Z- C- I- D- V- | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.cross(l, h, c)
+		cpu.read(l, h)
+	},
+	0x7C: /* NOP          |  absolute,X  | N- Z- C- I- D- V- | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.cross(l, h, c)
+		cpu.read(l, h)
+	},
+	0x9C: /* SHY oper,X   |  absolute,X  | N- Z- C- I- D- V- | 5 † */ func(cpu *CPU) {
+		if !cpu.quirks.Unstable {
+			cpu.invalid()
+			return
+		}
+		l, h, c := cpu.absN(cpu.x)
+		cpu.sh(l, h, c, cpu.y)
+	},
+	0xBC: /* LDY oper,X   |  absolute,X  | N+ Z+ C- I- D- V- | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.cross(l, h, c)
+		cpu.setY(cpu.read(l, h))
+	},
+	0xDC: /* NOP          |  absolute,X  | N- Z- C- I- D- V- | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.cross(l, h, c)
+		cpu.read(l, h)
+	},
+	0xFC: /* NOP          |  absolute,X  | N- Z- C- I- D- V- | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.cross(l, h, c)
+		cpu.read(l, h)
+	},
+
+	0x1D: /* ORA oper,X   |  absolute,X  | N+ Z+ C- I- D- V- | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.cross(l, h, c)
+		cpu.setA(cpu.a | cpu.read(l, h))
+	},
+	0x3D: /* AND oper,X   |  absolute,X  | N+ Z+ C- I- D- V- | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.cross(l, h, c)
+		cpu.setA(cpu.a & cpu.read(l, h))
+	},
+	0x5D: /* EOR oper,X   |  absolute,X  | N+ Z+ C- I- D- V- | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.cross(l, h, c)
+		cpu.setA(cpu.a ^ cpu.read(l, h))
+	},
+	0x7D: /* ADC oper,X   |  absolute,X  | N+ Z+ C+ I- D- V+ | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.cross(l, h, c)
+		cpu.adc(cpu.read(l, h))
+	},
+	0x9D: /* STA oper,X   |  absolute,X  | N- Z- C- I- D- V- | 5 */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.dummy(l, h-c)
+		cpu.write(l, h, cpu.a)
+	},
+	0xBD: /* LDA oper,X   |  absolute,X  | N+ Z+ C- I- D- V- | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.cross(l, h, c)
+		cpu.setA(cpu.read(l, h))
+	},
+	0xDD: /* CMP oper,X   |  absolute,X  | N+ Z+ C+ I- D- V- | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.cross(l, h, c)
+		cpu.cmp(cpu.read(l, h), cpu.a)
+	},
+	0xFD: /* SBC oper,X   |  absolute,X  | N+ Z+ C+ I- D- V+ | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.cross(l, h, c)
+		cpu.sbc(cpu.read(l, h))
+	},
+
+	0x1E: /* ASL oper,X   |  absolute,X  | N+ Z+ C+ I- D- V- | 7^ */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.shiftX(l, h, c)
+		cpu.rmw(l, h, (*CPU).asl)
+	},
+	0x3E: /* ROL oper,X   |  absolute,X  | N+ Z+ C+ I- D- V- | 7^ */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.shiftX(l, h, c)
+		cpu.rmw(l, h, (*CPU).rol)
+	},
+	0x5E: /* LSR oper,X   |  absolute,X  | N0 Z+ C+ I- D- V- | 7^ */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.shiftX(l, h, c)
+		cpu.rmw(l, h, (*CPU).lsr)
+	},
+	0x7E: /* ROR oper,X   |  absolute,X  | N+ Z+ C+ I- D- V- | 7^ */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.shiftX(l, h, c)
+		cpu.rmw(l, h, (*CPU).ror)
+	},
+	0x9E: /* SHX oper,Y   |  absolute,Y  | N- Z- C- I- D- V- | 5 † */ func(cpu *CPU) {
+		if !cpu.quirks.Unstable {
+			cpu.invalid()
+			return
+		}
+		l, h, c := cpu.absN(cpu.y)
+		cpu.sh(l, h, c, cpu.x)
+	},
+	0xBE: /* LDX oper,Y   |  absolute,Y  | N+ Z+ C- I- D- V- | 4* */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.y)
+		cpu.cross(l, h, c)
+		cpu.setX(cpu.read(l, h))
+	},
+	0xDE: /* DEC oper,X   |  absolute,X  | N+ Z+ C- I- D- V- | 7 */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.dummy(l, h-c)
+		cpu.rmw(l, h, (*CPU).dec)
+	},
+	0xFE: /* INC oper,X   |  absolute,X  | N+ Z+ C- I- D- V- | 7 */ func(cpu *CPU) {
+		l, h, c := cpu.absN(cpu.x)
+		cpu.dummy(l, h-c)
+		cpu.rmw(l, h, (*CPU).incr)
+	},
+
+	0x9F: /* SHA oper,Y   |  absolute,Y  | N- Z- C- I- D- V- | 5 † */ func(cpu *CPU) {
+		if cpu.quirks.Rockwell {
+			cpu.bbit(1, true) // BBS1 oper
+			return
+		}
+		if !cpu.quirks.Unstable {
+			cpu.invalid()
+			return
+		}
+		l, h, c := cpu.absN(cpu.y)
+		cpu.sh(l, h, c, cpu.a&cpu.x)
+	},
+
+	0x0F: /* BBR0 oper    | zeropage,rel | N- Z- C- I- D- V- | 5** ° */ func(cpu *CPU) {
+		cpu.bbit(0, false)
+	},
+	0x1F: /* BBR1 oper    | zeropage,rel | N- Z- C- I- D- V- | 5** ° */ func(cpu *CPU) {
+		cpu.bbit(1, false)
+	},
+	0x2F: /* BBR2 oper    | zeropage,rel | N- Z- C- I- D- V- | 5** ° */ func(cpu *CPU) {
+		cpu.bbit(2, false)
+	},
+	0x3F: /* BBR3 oper    | zeropage,rel | N- Z- C- I- D- V- | 5** ° */ func(cpu *CPU) {
+		cpu.bbit(3, false)
+	},
+	0x4F: /* BBR4 oper    | zeropage,rel | N- Z- C- I- D- V- | 5** ° */ func(cpu *CPU) {
+		cpu.bbit(4, false)
+	},
+	0x5F: /* BBR5 oper    | zeropage,rel | N- Z- C- I- D- V- | 5** ° */ func(cpu *CPU) {
+		cpu.bbit(5, false)
+	},
+	0x6F: /* BBR6 oper    | zeropage,rel | N- Z- C- I- D- V- | 5** ° */ func(cpu *CPU) {
+		cpu.bbit(6, false)
+	},
+	0x7F: /* BBR7 oper    | zeropage,rel | N- Z- C- I- D- V- | 5** ° */ func(cpu *CPU) {
+		cpu.bbit(7, false)
+	},
+	0x8F: /* BBS0 oper    | zeropage,rel | N- Z- C- I- D- V- | 5** ° */ func(cpu *CPU) {
+		cpu.bbit(0, true)
+	},
+	0xAF: /* BBS2 oper    | zeropage,rel | N- Z- C- I- D- V- | 5** ° */ func(cpu *CPU) {
+		cpu.bbit(2, true)
+	},
+	0xBF: /* BBS3 oper    | zeropage,rel | N- Z- C- I- D- V- | 5** ° */ func(cpu *CPU) {
+		cpu.bbit(3, true)
+	},
+	0xCF: /* BBS4 oper    | zeropage,rel | N- Z- C- I- D- V- | 5** ° */ func(cpu *CPU) {
+		cpu.bbit(4, true)
+	},
+	0xDF: /* BBS5 oper    | zeropage,rel | N- Z- C- I- D- V- | 5** ° */ func(cpu *CPU) {
+		cpu.bbit(5, true)
+	},
+	0xEF: /* BBS6 oper    | zeropage,rel | N- Z- C- I- D- V- | 5** ° */ func(cpu *CPU) {
+		cpu.bbit(6, true)
+	},
+	0xFF: /* BBS7 oper    | zeropage,rel | N- Z- C- I- D- V- | 5** ° */ func(cpu *CPU) {
+		cpu.bbit(7, true)
+	},
+}
@@ -0,0 +1,72 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+// Option configures a CPU created by New(). The options are applied in
+// order before the reset sequence, e.g. WithQuirks() after WithVariant().
+type Option func(cpu *CPU)
+
+// WithVariant selects the processor model, see SetVariant().
+func WithVariant(v Variant) Option {
+	return func(cpu *CPU) { cpu.SetVariant(v) }
+}
+
+// WithQuirks replaces the quirks of the processor model, see SetQuirks().
+func WithQuirks(q Quirks) Option {
+	return func(cpu *CPU) { cpu.SetQuirks(q) }
+}
+
+// WithVectors replaces the vector addresses of the processor model, see SetVectors().
+func WithVectors(v Vectors) Option {
+	return func(cpu *CPU) { cpu.SetVectors(v) }
+}
+
+// WithDecimalMode selects the instructions honoring the D flag, see SetDecimalMode().
+func WithDecimalMode(m DecimalMode) Option {
+	return func(cpu *CPU) { cpu.SetDecimalMode(m) }
+}
+
+// WithAccuracy selects the Accuracy of the emulation, see SetAccuracy().
+func WithAccuracy(a Accuracy) Option {
+	return func(cpu *CPU) { cpu.SetAccuracy(a) }
+}
+
+// WithResetMode selects the behavior of Reset(), see SetResetMode().
+func WithResetMode(m ResetMode) Option {
+	return func(cpu *CPU) { cpu.SetResetMode(m) }
+}
+
+// WithStackReset selects how Reset() initializes S, see SetStackReset().
+func WithStackReset(r StackReset, init byte) Option {
+	return func(cpu *CPU) { cpu.SetStackReset(r, init) }
+}
+
+// WithStackWrap selects the handling of the stack pointer wrapping, see SetStackWrap().
+func WithStackWrap(w StackWrap) Option {
+	return func(cpu *CPU) { cpu.SetStackWrap(w) }
+}
+
+// WithStrictReturns checks the returns against the calls, see SetStrictReturns().
+func WithStrictReturns() Option {
+	return func(cpu *CPU) { cpu.SetStrictReturns(true) }
+}
+
+// WithName names the CPU, see SetName().
+func WithName(name string) Option {
+	return func(cpu *CPU) { cpu.SetName(name) }
+}
+
+// WithHook adds a Hook, see AddHook().
+func WithHook(priority int, h Hook) Option {
+	return func(cpu *CPU) { cpu.AddHook(priority, h) }
+}
+
+// WithTracer registers a Tracer, see SetTracer().
+func WithTracer(t Tracer) Option {
+	return func(cpu *CPU) { cpu.SetTracer(t) }
+}
+
+// WithDiagnostics registers the function receiving Diagnostic events, see SetDiagnostics().
+func WithDiagnostics(fn func(Diagnostic)) Option {
+	return func(cpu *CPU) { cpu.SetDiagnostics(fn) }
+}
@@ -0,0 +1,40 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+func TestOptions(t *testing.T) {
+	bus := &memoryBus{}
+	bus.mem[0xFFFE], bus.mem[0xFFFF] = 0x00, 0x02 // HuC6280 reset vector
+	bus.mem[0x0200] = 0xEA                        // NOP
+
+	traces := 0
+	q := HuC6280.Quirks()
+	q.ClearD = false
+
+	cpu := New(bus,
+		WithVariant(HuC6280),
+		WithQuirks(q),
+		WithName("pce"),
+		WithStackReset(StackLoad, 0x80),
+		WithDecimalMode(DecimalADC),
+		WithTracer(func(Trace) { traces++ }),
+	)
+	if cpu.Variant() != HuC6280 || cpu.Quirks() != q || cpu.Name() != "pce" {
+		t.Fatalf("unexpected, got %s %+v %q", cpu.Variant(), cpu.Quirks(), cpu.Name())
+	}
+	if cpu.pc() != 0x0200 || cpu.s != 0x80 || cpu.decimal != DecimalADC {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+	if _, err := cpu.Step(); err != nil || traces != 1 {
+		t.Fatalf("unexpected, got %v %d", err, traces)
+	}
+
+	if cpu := New(Adapt16(NewRAM(0x10000)), WithVariant(CMOS65C02)); cpu.Variant() != CMOS65C02 {
+		t.Fatalf("unexpected, got %s", cpu.Variant())
+	}
+	if cpu := New(&memoryBus{}); cpu.Variant() != NMOS6502 || cpu.Quirks() != NMOS6502.Quirks() {
+		t.Fatalf("unexpected, got %s", cpu.Variant())
+	}
+}
@@ -0,0 +1,52 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+type (
+	// PhaseBus models the two-phase clock of the original processor for
+	// co-simulation with peripheral chips sensitive to the clock phase.
+	// Each cycle performing a bus access is split into φ1, where the address
+	// and the R/W line become valid, and φ2, where the data is transferred.
+	// Use NewPhased() to connect it.
+	PhaseBus interface {
+
+		// Phi1 signals the address and the direction of the access.
+		Phi1(addr uint16, write bool)
+
+		// Phi2 transfers the data: on write, db holds the data written,
+		// on read, the data read has to be returned.
+		Phi2(addr uint16, write bool, db byte) byte
+
+		// Internal signals a cycle without an emulated bus access, so that
+		// peripherals can be clocked on each cycle nonetheless.
+		Internal()
+	}
+
+	phased struct{ PhaseBus }
+)
+
+// NewPhased creates a new 6502 CPU connected to a PhaseBus. See New().
+// The reset sequence performed by New() is phased already.
+func NewPhased(bus PhaseBus, opts ...Option) *CPU {
+	return New(phased{bus}, append([]Option{func(cpu *CPU) { cpu.phased = bus }}, opts...)...)
+}
+
+func (b phased) Read(l, h byte) byte {
+	addr := uint16(h)<<8 | uint16(l)
+	b.Phi1(addr, false)
+	return b.Phi2(addr, false, 0)
+}
+
+func (b phased) Write(l, h, db byte) {
+	addr := uint16(h)<<8 | uint16(l)
+	b.Phi1(addr, true)
+	b.Phi2(addr, true, db)
+}
+
+// idle accounts for a cycle without emulated bus access.
+func (cpu *CPU) idle() {
+	cpu.record(MicroInternal, 0, 0)
+	if cpu.phased != nil {
+		cpu.phased.Internal()
+	}
+}
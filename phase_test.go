@@ -0,0 +1,73 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// phaseLog is a PhaseBus logging the phases.
+type phaseLog struct {
+	memoryBus
+	log []string
+}
+
+func (b *phaseLog) Phi1(addr uint16, write bool) {
+	b.log = append(b.log, fmt.Sprintf("1:%04X:%t", addr, write))
+}
+
+func (b *phaseLog) Phi2(addr uint16, write bool, db byte) byte {
+	if write {
+		b.mem[addr] = db
+	} else {
+		db = b.mem[addr]
+	}
+	b.log = append(b.log, fmt.Sprintf("2:%02X", db))
+	return db
+}
+
+func (b *phaseLog) Internal() {
+	b.log = append(b.log, "-")
+}
+
+func TestPhaseBus(t *testing.T) {
+	bus := &phaseLog{}
+	bus.mem[0xFFFC], bus.mem[0xFFFD] = 0x00, 0x02
+	copy(bus.mem[0x0200:], []byte{0x85, 0x10, 0xEA}) // STA $10, NOP
+
+	cpu := NewPhased(bus)
+	want := "- - - - - 1:FFFC:false 2:00 1:FFFD:false 2:02"
+	if got := strings.Join(bus.log, " "); got != want {
+		t.Fatalf("unexpected, got %s", got)
+	}
+	bus.log = nil
+	if cycles := cpu.Reset(); len(bus.log) != 9 || cycles != 7 {
+		t.Fatalf("unexpected, got %d %s", cycles, bus.log)
+	}
+	cpu.a = 0x42
+	bus.log = nil
+
+	total := uint(0)
+	for i := 0; i < 2; i++ {
+		cycles, err := cpu.Step()
+		if err != nil {
+			t.Fatal(err)
+		}
+		total += cycles
+	}
+	want = "1:0200:false 2:85 1:0201:false 2:10 1:0010:true 2:42 1:0202:false 2:EA -"
+	if got := strings.Join(bus.log, " "); got != want {
+		t.Fatalf("unexpected, got %s", got)
+	}
+	n := uint(0)
+	for _, s := range bus.log {
+		if s[0] != '1' {
+			n++
+		}
+	}
+	if n != total {
+		t.Fatalf("unexpected, got %d cycles logged, %d stepped", n, total)
+	}
+}
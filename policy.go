@@ -0,0 +1,66 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "fmt"
+
+type (
+	// OpcodeSet is a set of op codes, the zero value is empty.
+	OpcodeSet [4]uint64
+
+	// PolicyError is returned from Step() when the op code at PC is rejected
+	// by the policy of the CPU, see SetBlacklist() and SetWhitelist(). The
+	// instruction is not performed, PC remains at its address.
+	PolicyError struct {
+		PC     uint16 // Address of the rejected instruction
+		Opcode byte   // Rejected op code
+	}
+)
+
+// NewOpcodeSet creates an OpcodeSet of the op codes.
+func NewOpcodeSet(opcodes ...byte) OpcodeSet {
+	s := OpcodeSet{}
+	s.Add(opcodes...)
+	return s
+}
+
+// IllegalOpcodes returns the set of the undocumented NMOS op codes.
+func IllegalOpcodes() OpcodeSet {
+	s := OpcodeSet{}
+	for op, i := range nmos {
+		if i.Illegal {
+			s.Add(byte(op))
+		}
+	}
+	return s
+}
+
+// Add adds the op codes to the set.
+func (s *OpcodeSet) Add(opcodes ...byte) {
+	for _, op := range opcodes {
+		s[op>>6] |= 1 << (op & 0x3F)
+	}
+}
+
+// Has reports whether the op code is in the set.
+func (s *OpcodeSet) Has(op byte) bool {
+	return s[op>>6]&(1<<(op&0x3F)) != 0
+}
+
+// SetBlacklist rejects the op codes of the set with a PolicyError.
+// An empty set allows all op codes, the default. Replaces a whitelist.
+func (cpu *CPU) SetBlacklist(s OpcodeSet) {
+	cpu.forbidden = s
+}
+
+// SetWhitelist rejects the op codes not in the set with a PolicyError.
+// Replaces a blacklist. Use SetBlacklist(OpcodeSet{}) to allow all op codes.
+func (cpu *CPU) SetWhitelist(s OpcodeSet) {
+	for i := range s {
+		cpu.forbidden[i] = ^s[i]
+	}
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("m6502: %04X: op code %02X rejected by policy", e.PC, e.Opcode)
+}
@@ -0,0 +1,56 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOpcodeSet(t *testing.T) {
+	s := NewOpcodeSet(0x00, 0x3F, 0x40, 0xFF)
+	for op := 0; op < 0x100; op++ {
+		want := op == 0x00 || op == 0x3F || op == 0x40 || op == 0xFF
+		if s.Has(byte(op)) != want {
+			t.Fatalf("unexpected, got %02X", op)
+		}
+	}
+	ill := IllegalOpcodes()
+	if !ill.Has(0xA7) || !ill.Has(0x02) || ill.Has(0xA9) || ill.Has(0xEA) {
+		t.Fatal("unexpected")
+	}
+}
+
+func TestBlacklist(t *testing.T) {
+	cpu, _ := newTestCPU(NMOS6502, 0xEA, 0x00, 0xEA)
+	cpu.SetBlacklist(NewOpcodeSet(0x00))
+
+	if pc := stepPC(t, cpu); pc != 0x0201 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	for i := 0; i < 2; i++ {
+		cycles, err := cpu.Step()
+
+		var e *PolicyError
+		if !errors.As(err, &e) || e.PC != 0x0201 || e.Opcode != 0x00 || cycles != 0 || cpu.pc() != 0x0201 {
+			t.Fatalf("unexpected, got %v", err)
+		}
+	}
+	cpu.SetBlacklist(OpcodeSet{})
+	if pc := stepPC(t, cpu); pc != 0x9000 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+}
+
+func TestWhitelist(t *testing.T) {
+	cpu, _ := newTestCPU(NMOS6502, 0xEA, 0xE8, 0xEA)
+	cpu.SetWhitelist(NewOpcodeSet(0xEA))
+
+	if pc := stepPC(t, cpu); pc != 0x0201 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	_, err := cpu.Step()
+	if err == nil || err.Error() != "m6502: 0201: op code E8 rejected by policy" {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "math/rand"
+
+// PowerOn selects the register state of the first Reset() after New()
+// or PowerCycle(), i.e. the state the CPU powers on with.
+type PowerOn byte
+
+const (
+	// PowerOnZero follows the ResetMode like any Reset(), ResetLegacy
+	// as well as ResetAccurate clear the registers and set S to 0xFF.
+	PowerOnZero PowerOn = iota
+
+	// PowerOnAccurate sets the I flag and S to 0xFD, as the reset sequence
+	// decrements S from 0x00. A, X, Y and the other flags are cleared.
+	PowerOnAccurate
+
+	// PowerOnRandom is PowerOnAccurate with A, X, Y and the flags other
+	// than I taken from a seeded source, as they are undefined on silicon.
+	PowerOnRandom
+)
+
+// SetPowerOn selects the power-on state, the seed makes PowerOnRandom
+// reproducible. It applies to PowerCycle(), or to New() when passed by
+// WithPowerOn(). Defaults to PowerOnZero. The D flag is cleared with the
+// ClearD quirk.
+func (cpu *CPU) SetPowerOn(p PowerOn, seed int64) {
+	cpu.power, cpu.rnd = p, rand.New(rand.NewSource(seed))
+}
+
+// WithPowerOn selects the power-on state, see SetPowerOn().
+func WithPowerOn(p PowerOn, seed int64) Option {
+	return func(cpu *CPU) { cpu.SetPowerOn(p, seed) }
+}
+
+// PowerCycle turns the CPU off and on, i.e. performs the reset sequence
+// with the power-on state. It returns the cycles like Reset().
+func (cpu *CPU) PowerCycle() (cycles uint) {
+	cpu.powered, cpu.s = false, 0x00
+	return cpu.Reset()
+}
+
+// powerOn sets the power-on state and returns S.
+func (cpu *CPU) powerOn() byte {
+	cpu.a, cpu.x, cpu.y, cpu.p = 0x00, 0x00, 0x00, flagI
+	if cpu.power == PowerOnRandom {
+		cpu.a, cpu.x, cpu.y = byte(cpu.rnd.Intn(0x100)), byte(cpu.rnd.Intn(0x100)), byte(cpu.rnd.Intn(0x100))
+		cpu.p |= flag(cpu.rnd.Intn(0x100)) & (flagN | flagV | flagD | flagZ | flagC)
+	}
+	if cpu.quirks.ClearD {
+		cpu.p &^= flagD
+	}
+	return 0xFD
+}
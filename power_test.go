@@ -0,0 +1,49 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+func TestPowerOnZero(t *testing.T) {
+	cpu := New(&memoryBus{}, WithResetMode(ResetAccurate))
+	if cpu.a != 0x00 || cpu.p != 0 || cpu.s != 0xFF {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+}
+
+func TestPowerOnAccurate(t *testing.T) {
+	cpu := New(&memoryBus{}, WithPowerOn(PowerOnAccurate, 0))
+	if cpu.a|cpu.x|cpu.y != 0x00 || cpu.p != flagI || cpu.s != 0xFD {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+	cpu.a, cpu.s, cpu.p = 0x12, 0x40, flagC
+	if cpu.Reset(); cpu.a != 0x00 || cpu.s != 0xFF {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+	if cycles := cpu.PowerCycle(); cycles != 7 || cpu.p != flagI || cpu.s != 0xFD {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+}
+
+func TestPowerOnRandom(t *testing.T) {
+	state := func(cpu *CPU) [4]byte { return [4]byte{cpu.a, cpu.x, cpu.y, byte(cpu.p)} }
+
+	a := New(&memoryBus{}, WithPowerOn(PowerOnRandom, 1))
+	b := New(&memoryBus{}, WithPowerOn(PowerOnRandom, 1))
+	c := New(&memoryBus{}, WithPowerOn(PowerOnRandom, 2))
+
+	if state(a) != state(b) || state(a) == state(c) || !a.p.has(flagI) || a.s != 0xFD {
+		t.Fatalf("unexpected, got %v %v %v", state(a), state(b), state(c))
+	}
+	first := state(a)
+	if a.PowerCycle(); state(a) == first {
+		t.Fatalf("unexpected, got %v", state(a))
+	}
+
+	for seed := int64(0); seed < 32; seed++ {
+		cpu := New(&memoryBus{}, WithVariant(CMOS65C02), WithPowerOn(PowerOnRandom, seed))
+		if cpu.p.has(flagD) || cpu.p&(flagB|flagU) != 0 {
+			t.Fatalf("unexpected, got %s", cpu)
+		}
+	}
+}
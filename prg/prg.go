@@ -0,0 +1,104 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+// Package prg reads Commodore program files (.prg): a little-endian load
+// address followed by the payload. Machine code programs for the C64 often
+// start with a BASIC stub like "10 SYS 2061", see SYS().
+//
+//	f, err := prg.Read(r)
+//	load, end := f.Write(bus)
+//	if addr, ok := f.SYS(); ok {
+//	    cpu.PC(byte(addr), byte(addr>>8))
+//	}
+package prg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dtgorski/m6502"
+)
+
+// File is a parsed program file.
+type File struct {
+	Load uint16 // Load address
+	Data []byte // Payload
+}
+
+// tokenSYS is the BASIC V2 token of the SYS keyword.
+const tokenSYS = 0x9E
+
+// Parse parses the content of a program file.
+func Parse(b []byte) (*File, error) {
+	if len(b) < 2 {
+		return nil, errors.New("prg: missing load address")
+	}
+	f := &File{Load: uint16(b[0]) | uint16(b[1])<<8, Data: b[2:]}
+	if int(f.Load)+len(f.Data) > 0x10000 {
+		return nil, fmt.Errorf("prg: payload of %d bytes exceeds memory at %04X", len(f.Data), f.Load)
+	}
+	return f, nil
+}
+
+// Read reads and parses a program file.
+func Read(r io.Reader) (*File, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("prg: %w", err)
+	}
+	return Parse(b)
+}
+
+// Load reads a program file and writes its payload to the bus.
+// It returns the load and end address, see File.End().
+func Load(bus m6502.Bus, r io.Reader) (load, end uint16, err error) {
+	f, err := Read(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	load, end = f.Write(bus)
+	return load, end, nil
+}
+
+// End returns the address following the payload, like the end pointer
+// set by the KERNAL LOAD routine. It is 0x0000 when the payload reaches
+// the end of memory.
+func (f *File) End() uint16 {
+	return f.Load + uint16(len(f.Data))
+}
+
+// Write writes the payload to the bus and returns the load and end address.
+func (f *File) Write(bus m6502.Bus) (load, end uint16) {
+	for i, b := range f.Data {
+		addr := f.Load + uint16(i)
+		bus.Write(byte(addr), byte(addr>>8), b)
+	}
+	return f.Load, f.End()
+}
+
+// SYS returns the address of the SYS statement in the first line of a
+// BASIC stub, e.g. 2061 for "10 SYS 2061". It returns false when the
+// payload does not start with such a line.
+func (f *File) SYS() (uint16, bool) {
+	if len(f.Data) < 5 {
+		return 0, false
+	}
+	line := f.Data[4:] // Skip link and line number
+	i := 0
+	for i < len(line) && line[i] == ' ' {
+		i++
+	}
+	if i == len(line) || line[i] != tokenSYS {
+		return 0, false
+	}
+	for i++; i < len(line) && (line[i] == ' ' || line[i] == '('); i++ {
+	}
+	addr, digits := 0, 0
+	for ; i < len(line) && line[i] >= '0' && line[i] <= '9'; i++ {
+		if addr = addr*10 + int(line[i]-'0'); addr > 0xFFFF {
+			return 0, false
+		}
+		digits++
+	}
+	return uint16(addr), digits > 0
+}
@@ -0,0 +1,81 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package prg
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type memoryBus struct{ mem [0x10000]byte }
+
+func (m *memoryBus) Read(l, h byte) byte     { return m.mem[uint16(h)<<8|uint16(l)] }
+func (m *memoryBus) Write(l, h byte, b byte) { m.mem[uint16(h)<<8|uint16(l)] = b }
+
+// stub is "10 SYS2061" followed by INC $D020, JMP $080D.
+var stub = []byte{
+	0x01, 0x08, // Load address $0801
+	0x0B, 0x08, 0x0A, 0x00, 0x9E, '2', '0', '6', '1', 0x00, 0x00, 0x00,
+	0xEE, 0x20, 0xD0, 0x4C, 0x0D, 0x08,
+}
+
+func TestLoad(t *testing.T) {
+	bus := &memoryBus{}
+	load, end, err := Load(bus, bytes.NewReader(stub))
+	if err != nil || load != 0x0801 || end != 0x0813 {
+		t.Fatalf("unexpected, got %04X %04X %v", load, end, err)
+	}
+	if !bytes.Equal(bus.mem[0x0801:0x0813], stub[2:]) || bus.mem[0x0813] != 0x00 {
+		t.Fatalf("unexpected, got % X", bus.mem[0x0800:0x0814])
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, tt := range []struct {
+		data []byte
+		err  string
+	}{
+		{[]byte{0x01}, "prg: missing load address"},
+		{[]byte{0xFF, 0xFF, 0x01, 0x02}, "prg: payload of 2 bytes exceeds memory at FFFF"},
+	} {
+		if _, err := Parse(tt.data); err == nil || err.Error() != tt.err {
+			t.Fatalf("unexpected, got %v", err)
+		}
+	}
+	f, err := Parse([]byte{0xFE, 0xFF, 0x01, 0x02})
+	if err != nil || f.End() != 0x0000 {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
+
+func TestRead(t *testing.T) {
+	errFoo := errors.New("foo")
+	if _, err := Read(failingReader{errFoo}); !errors.Is(err, errFoo) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
+
+func TestSYS(t *testing.T) {
+	for _, tt := range []struct {
+		line []byte
+		addr uint16
+		ok   bool
+	}{
+		{[]byte{0x9E, '2', '0', '6', '1', 0x00}, 2061, true},
+		{[]byte{' ', 0x9E, ' ', '(', '4', '9', '1', '5', '2', ')', 0x00}, 49152, true},
+		{[]byte{0x9E, '9', '9', '9', '9', '9', 0x00}, 0, false},
+		{[]byte{0x9E, 0x00}, 0, false},
+		{[]byte{0x99, '2', '0', '6', '1', 0x00}, 0, false}, // PRINT
+		{[]byte{}, 0, false},
+	} {
+		f := &File{Load: 0x0801, Data: append([]byte{0x0B, 0x08, 0x0A, 0x00}, tt.line...)}
+		if addr, ok := f.SYS(); addr != tt.addr || ok != tt.ok {
+			t.Fatalf("unexpected, got %d %t for % X", addr, ok, tt.line)
+		}
+	}
+}
+
+type failingReader struct{ err error }
+
+func (r failingReader) Read([]byte) (int, error) { return 0, r.err }
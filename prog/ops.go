@@ -0,0 +1,179 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package prog
+
+// Load and store.
+
+// LDAImm emits LDA #b.
+func (p *Program) LDAImm(b byte) *Program { return p.Op(0xA9, b) }
+
+// LDXImm emits LDX #b.
+func (p *Program) LDXImm(b byte) *Program { return p.Op(0xA2, b) }
+
+// LDYImm emits LDY #b.
+func (p *Program) LDYImm(b byte) *Program { return p.Op(0xA0, b) }
+
+// LDAZp emits LDA zp.
+func (p *Program) LDAZp(zp byte) *Program { return p.Op(0xA5, zp) }
+
+// LDAAbs emits LDA addr.
+func (p *Program) LDAAbs(addr uint16) *Program { return p.abs(0xAD, addr) }
+
+// LDAAbsX emits LDA addr,X.
+func (p *Program) LDAAbsX(addr uint16) *Program { return p.abs(0xBD, addr) }
+
+// STAZp emits STA zp.
+func (p *Program) STAZp(zp byte) *Program { return p.Op(0x85, zp) }
+
+// STAAbs emits STA addr.
+func (p *Program) STAAbs(addr uint16) *Program { return p.abs(0x8D, addr) }
+
+// STAAbsX emits STA addr,X.
+func (p *Program) STAAbsX(addr uint16) *Program { return p.abs(0x9D, addr) }
+
+// STXAbs emits STX addr.
+func (p *Program) STXAbs(addr uint16) *Program { return p.abs(0x8E, addr) }
+
+// STYAbs emits STY addr.
+func (p *Program) STYAbs(addr uint16) *Program { return p.abs(0x8C, addr) }
+
+// Arithmetic and logic.
+
+// ADCImm emits ADC #b.
+func (p *Program) ADCImm(b byte) *Program { return p.Op(0x69, b) }
+
+// SBCImm emits SBC #b.
+func (p *Program) SBCImm(b byte) *Program { return p.Op(0xE9, b) }
+
+// ANDImm emits AND #b.
+func (p *Program) ANDImm(b byte) *Program { return p.Op(0x29, b) }
+
+// ORAImm emits ORA #b.
+func (p *Program) ORAImm(b byte) *Program { return p.Op(0x09, b) }
+
+// EORImm emits EOR #b.
+func (p *Program) EORImm(b byte) *Program { return p.Op(0x49, b) }
+
+// CMPImm emits CMP #b.
+func (p *Program) CMPImm(b byte) *Program { return p.Op(0xC9, b) }
+
+// CPXImm emits CPX #b.
+func (p *Program) CPXImm(b byte) *Program { return p.Op(0xE0, b) }
+
+// CPYImm emits CPY #b.
+func (p *Program) CPYImm(b byte) *Program { return p.Op(0xC0, b) }
+
+// INCAbs emits INC addr.
+func (p *Program) INCAbs(addr uint16) *Program { return p.abs(0xEE, addr) }
+
+// DECAbs emits DEC addr.
+func (p *Program) DECAbs(addr uint16) *Program { return p.abs(0xCE, addr) }
+
+// Implied.
+
+// INX emits INX.
+func (p *Program) INX() *Program { return p.Op(0xE8) }
+
+// INY emits INY.
+func (p *Program) INY() *Program { return p.Op(0xC8) }
+
+// DEX emits DEX.
+func (p *Program) DEX() *Program { return p.Op(0xCA) }
+
+// DEY emits DEY.
+func (p *Program) DEY() *Program { return p.Op(0x88) }
+
+// TAX emits TAX.
+func (p *Program) TAX() *Program { return p.Op(0xAA) }
+
+// TXA emits TXA.
+func (p *Program) TXA() *Program { return p.Op(0x8A) }
+
+// TAY emits TAY.
+func (p *Program) TAY() *Program { return p.Op(0xA8) }
+
+// TYA emits TYA.
+func (p *Program) TYA() *Program { return p.Op(0x98) }
+
+// TXS emits TXS.
+func (p *Program) TXS() *Program { return p.Op(0x9A) }
+
+// PHA emits PHA.
+func (p *Program) PHA() *Program { return p.Op(0x48) }
+
+// PLA emits PLA.
+func (p *Program) PLA() *Program { return p.Op(0x68) }
+
+// CLC emits CLC.
+func (p *Program) CLC() *Program { return p.Op(0x18) }
+
+// SEC emits SEC.
+func (p *Program) SEC() *Program { return p.Op(0x38) }
+
+// CLI emits CLI.
+func (p *Program) CLI() *Program { return p.Op(0x58) }
+
+// SEI emits SEI.
+func (p *Program) SEI() *Program { return p.Op(0x78) }
+
+// CLD emits CLD.
+func (p *Program) CLD() *Program { return p.Op(0xD8) }
+
+// SED emits SED.
+func (p *Program) SED() *Program { return p.Op(0xF8) }
+
+// NOP emits NOP.
+func (p *Program) NOP() *Program { return p.Op(0xEA) }
+
+// BRK emits BRK followed by the padding byte.
+func (p *Program) BRK() *Program { return p.Op(0x00, 0x00) }
+
+// RTS emits RTS.
+func (p *Program) RTS() *Program { return p.Op(0x60) }
+
+// RTI emits RTI.
+func (p *Program) RTI() *Program { return p.Op(0x40) }
+
+// HLT emits the (undocumented) HLT op code $02.
+func (p *Program) HLT() *Program { return p.Op(0x02) }
+
+// Control flow.
+
+// JMP emits JMP label.
+func (p *Program) JMP(label string) *Program { return p.ref(0x4C, label) }
+
+// JMPAbs emits JMP addr.
+func (p *Program) JMPAbs(addr uint16) *Program { return p.abs(0x4C, addr) }
+
+// JMPInd emits JMP (addr).
+func (p *Program) JMPInd(addr uint16) *Program { return p.abs(0x6C, addr) }
+
+// JSR emits JSR label.
+func (p *Program) JSR(label string) *Program { return p.ref(0x20, label) }
+
+// JSRAbs emits JSR addr.
+func (p *Program) JSRAbs(addr uint16) *Program { return p.abs(0x20, addr) }
+
+// BPL emits BPL label.
+func (p *Program) BPL(label string) *Program { return p.rel(0x10, label) }
+
+// BMI emits BMI label.
+func (p *Program) BMI(label string) *Program { return p.rel(0x30, label) }
+
+// BVC emits BVC label.
+func (p *Program) BVC(label string) *Program { return p.rel(0x50, label) }
+
+// BVS emits BVS label.
+func (p *Program) BVS(label string) *Program { return p.rel(0x70, label) }
+
+// BCC emits BCC label.
+func (p *Program) BCC(label string) *Program { return p.rel(0x90, label) }
+
+// BCS emits BCS label.
+func (p *Program) BCS(label string) *Program { return p.rel(0xB0, label) }
+
+// BNE emits BNE label.
+func (p *Program) BNE(label string) *Program { return p.rel(0xD0, label) }
+
+// BEQ emits BEQ label.
+func (p *Program) BEQ(label string) *Program { return p.rel(0xF0, label) }
@@ -0,0 +1,134 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+// Package prog is a tiny fluent builder emitting 6502 machine code with
+// label resolution. It is meant for readable example programs and tests:
+//
+//	p := prog.New(0x0200)
+//	p.Label("loop").LDAImm(0x10).STAAbs(0xD020).DEX().BNE("loop").JMP("loop")
+//	image, err := p.Bytes()
+package prog
+
+import (
+	"fmt"
+
+	"github.com/dtgorski/m6502"
+)
+
+type (
+	// Program collects machine code starting at an origin address.
+	Program struct {
+		origin uint16
+		code   []byte
+		labels map[string]uint16
+		fixups []fixup
+		err    error
+	}
+
+	fixup struct {
+		at       int    // Offset of the operand in code
+		label    string // Referenced label
+		relative bool   // Branch offset instead of absolute address
+	}
+)
+
+// New creates an empty Program located at origin.
+func New(origin uint16) *Program {
+	return &Program{origin: origin, labels: map[string]uint16{}}
+}
+
+// Origin returns the load address of the Program.
+func (p *Program) Origin() uint16 {
+	return p.origin
+}
+
+// PC returns the address of the next emitted byte.
+func (p *Program) PC() uint16 {
+	return p.origin + uint16(len(p.code))
+}
+
+// Label defines a label at the current address.
+func (p *Program) Label(name string) *Program {
+	if _, ok := p.labels[name]; ok {
+		return p.fail("duplicate label %q", name)
+	}
+	p.labels[name] = p.PC()
+	return p
+}
+
+// Byte emits raw bytes.
+func (p *Program) Byte(b ...byte) *Program {
+	p.code = append(p.code, b...)
+	return p
+}
+
+// Word emits 16-bit little-endian words.
+func (p *Program) Word(w ...uint16) *Program {
+	for _, v := range w {
+		p.code = append(p.code, byte(v), byte(v>>8))
+	}
+	return p
+}
+
+// Op emits an op code followed by its operand bytes.
+func (p *Program) Op(opcode byte, operand ...byte) *Program {
+	return p.Byte(opcode).Byte(operand...)
+}
+
+// Bytes resolves the labels and returns the machine code.
+func (p *Program) Bytes() ([]byte, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	code := append([]byte(nil), p.code...)
+
+	for _, f := range p.fixups {
+		addr, ok := p.labels[f.label]
+		if !ok {
+			return nil, fmt.Errorf("prog: undefined label %q", f.label)
+		}
+		if !f.relative {
+			code[f.at], code[f.at+1] = byte(addr), byte(addr>>8)
+			continue
+		}
+		d := int(addr) - (int(p.origin) + f.at + 1)
+		if d < -128 || d > 127 {
+			return nil, fmt.Errorf("prog: branch to %q out of range (%d)", f.label, d)
+		}
+		code[f.at] = byte(int8(d))
+	}
+	return code, nil
+}
+
+// Load resolves the labels and writes the machine code to the bus.
+func (p *Program) Load(bus m6502.Bus) error {
+	code, err := p.Bytes()
+	if err != nil {
+		return err
+	}
+	for i, b := range code {
+		addr := p.origin + uint16(i)
+		bus.Write(byte(addr), byte(addr>>8), b)
+	}
+	return nil
+}
+
+func (p *Program) fail(format string, a ...any) *Program {
+	if p.err == nil {
+		p.err = fmt.Errorf("prog: "+format, a...)
+	}
+	return p
+}
+
+func (p *Program) abs(opcode byte, addr uint16) *Program {
+	return p.Op(opcode, byte(addr), byte(addr>>8))
+}
+
+func (p *Program) ref(opcode byte, label string) *Program {
+	p.fixups = append(p.fixups, fixup{at: len(p.code) + 1, label: label})
+	return p.Op(opcode, 0x00, 0x00)
+}
+
+func (p *Program) rel(opcode byte, label string) *Program {
+	p.fixups = append(p.fixups, fixup{at: len(p.code) + 1, label: label, relative: true})
+	return p.Op(opcode, 0x00)
+}
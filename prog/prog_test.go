@@ -0,0 +1,95 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package prog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dtgorski/m6502"
+)
+
+type memoryBus struct{ mem [0x10000]byte }
+
+func (m *memoryBus) Read(l, h byte) byte   { return m.mem[uint16(h)<<8|uint16(l)] }
+func (m *memoryBus) Write(l, h, data byte) { m.mem[uint16(h)<<8|uint16(l)] = data }
+
+func TestBytes(t *testing.T) {
+	p := New(0x0200)
+	p.LDXImm(0x03).Label("loop").DEX().BNE("loop").JSR("sub").JMP("loop").Label("sub").RTS()
+
+	code, err := p.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{
+		0xA2, 0x03, // 0200 LDX #$03
+		0xCA,       // 0202 DEX
+		0xD0, 0xFD, // 0203 BNE $0202
+		0x20, 0x0B, 0x02, // 0205 JSR $020B
+		0x4C, 0x02, 0x02, // 0208 JMP $0202
+		0x60, // 020B RTS
+	}
+	if !bytes.Equal(code, want) {
+		t.Fatalf("unexpected, got % X", code)
+	}
+	if p.Origin() != 0x0200 || p.PC() != 0x020C {
+		t.Fatalf("unexpected, got %04X", p.PC())
+	}
+}
+
+func TestForwardBranch(t *testing.T) {
+	code, err := New(0x1000).BEQ("end").NOP().Label("end").Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(code, []byte{0xF0, 0x01, 0xEA}) {
+		t.Fatalf("unexpected, got % X", code)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		p   *Program
+		err string
+	}{
+		{New(0).JMP("nowhere"), "undefined label"},
+		{New(0).Label("a").Label("a"), "duplicate label"},
+		{New(0).Label("far").Byte(make([]byte, 200)...).BNE("far"), "out of range"},
+	}
+	for _, tt := range tests {
+		if _, err := tt.p.Bytes(); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("unexpected, got %v", err)
+		}
+		if err := tt.p.Load(&memoryBus{}); err == nil {
+			t.Error("unexpected")
+		}
+	}
+}
+
+func TestLoadAndRun(t *testing.T) {
+	bus := &memoryBus{}
+
+	p := New(0x0200)
+	p.LDAImm(0x00).LDXImm(0x05).
+		Label("loop").CLC().ADCImm(0x03).DEX().BNE("loop").
+		STAAbs(0x0300).
+		Label("end").JMP("end")
+
+	if err := p.Load(bus); err != nil {
+		t.Fatal(err)
+	}
+
+	cpu := m6502.New(bus)
+	cpu.PC(0x00, 0x02)
+
+	for i := 0; i < 100; i++ {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if bus.mem[0x0300] != 0x0F {
+		t.Fatalf("unexpected, got %02X", bus.mem[0x0300])
+	}
+}
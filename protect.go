@@ -0,0 +1,40 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "fmt"
+
+// WriteProtect selects the handling of writes to a range of a Mapper.
+type WriteProtect byte
+
+const (
+	// ProtectNone passes writes to the region, the default.
+	ProtectNone WriteProtect = iota
+
+	// ProtectIgnore drops writes silently, as ROM chips do.
+	ProtectIgnore
+
+	// ProtectReport drops writes and reports them as DiagROMWrite
+	// Diagnostic to the CPU created with the Mapper as its bus.
+	ProtectReport
+)
+
+// MapProtected maps the region like Map(), with the write protection p,
+// e.g. to catch accidental writes into ROM or into a RAM holding code.
+func (m *Mapper) MapProtected(start, end uint16, region Bus16, p WriteProtect) *Mapper {
+	m.Map(start, end, region)
+	m.ranges[0].protect = p
+	return m
+}
+
+// protected handles the write of db to the protected address.
+func (m *Mapper) protected(r *mapping, addr uint16, db byte) {
+	if r.protect != ProtectReport || m.cpu == nil || m.cpu.diagnostics == nil {
+		return
+	}
+	m.cpu.diagnostics(Diagnostic{
+		Kind: DiagROMWrite, PC: m.cpu.at,
+		Text: fmt.Sprintf("write to protected %04X: %02X", addr, db),
+		Addr: addr, New: db,
+	})
+}
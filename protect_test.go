@@ -0,0 +1,42 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+func TestMapProtected(t *testing.T) {
+	ram, code, rom := NewRAM(0x8000), NewRAM(0x1000), make([]byte, 0x1000)
+	rom[0x0FFC], rom[0x0FFD] = 0x00, 0x80 // Reset vector
+	// LDA #$42, STA $9000, STA $F000, STA $0010
+	code.Load(0x0000, []byte{0xA9, 0x42, 0x8D, 0x00, 0x90, 0x8D, 0x00, 0xF0, 0x85, 0x10})
+
+	m := NewMapper().
+		Map(0x0000, 0x7FFF, ram).
+		MapProtected(0x8000, 0x9FFF, code, ProtectReport).
+		MapProtected(0xF000, 0xFFFF, NewROM(rom), ProtectIgnore)
+
+	var diags []Diagnostic
+	cpu := New(m)
+	cpu.SetDiagnostics(func(d Diagnostic) { diags = append(diags, d) })
+
+	for i := 0; i < 4; i++ {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if code.Read(0x1000) != 0xA9 || ram.Read(0x0010) != 0x42 {
+		t.Fatalf("unexpected, got %02X %02X", code.Read(0x1000), ram.Read(0x0010))
+	}
+	want := Diagnostic{
+		Kind: DiagROMWrite, PC: 0x8002, Text: "write to protected 9000: 42",
+		Addr: 0x9000, New: 0x42,
+	}
+	if len(diags) != 1 || diags[0] != want {
+		t.Fatalf("unexpected, got %+v", diags)
+	}
+
+	regions := m.Regions()
+	if len(regions) != 4 || !regions[0].Writable || regions[1].Writable || regions[1].Kind != RegionRAM {
+		t.Fatalf("unexpected, got %+v", regions)
+	}
+}
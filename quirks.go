@@ -0,0 +1,65 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+// Quirks are the behavioral differences between the processor models.
+// SetVariant() selects the quirks of the model, SetQuirks() mixes them
+// per target machine, e.g. an NMOS 6502 clearing D on interrupts.
+type Quirks struct {
+	JMPWrap     bool // JMP ($xxFF) reads the high byte from $xx00
+	BinaryFlags bool // Decimal ADC and SBC set the flags like in binary mode, without extra cycle
+	NoDecimal   bool // ADC and SBC ignore the D flag
+	DummyWrite  bool // Read-modify-write writes the unmodified value back, instead of reading it twice
+	DummyIndex  bool // Indexing reads from the uncorrected address, instead of the last instruction byte
+	ShiftX      bool // ASL, LSR, ROL and ROR absolute,X save a cycle without page cross
+	ClearD      bool // Interrupts and BRK clear the D flag
+	Illegal     bool // Undocumented op codes are performed, by the NMOS models
+	Unstable    bool // ANE, LXA, SHA, SHX, SHY and TAS are performed, with Illegal
+	Rockwell    bool // RMB, SMB, BBR and BBS are performed
+	WaitStop    bool // WAI and STP are performed
+}
+
+// Quirks returns the quirks of the processor model.
+func (v Variant) Quirks() Quirks {
+	switch v {
+	case NMOS6502:
+		return Quirks{
+			JMPWrap: true, BinaryFlags: true, DummyWrite: true, DummyIndex: true,
+			Illegal: true, Unstable: true,
+		}
+	case Ricoh2A03:
+		return Quirks{
+			JMPWrap: true, BinaryFlags: true, NoDecimal: true, DummyWrite: true, DummyIndex: true,
+			Illegal: true, Unstable: true,
+		}
+	case CMOS65C02:
+		return Quirks{ShiftX: true, ClearD: true, Rockwell: true, WaitStop: true}
+	case CSG65CE02, HuC6280:
+		return Quirks{ClearD: true}
+	}
+	return Quirks{}
+}
+
+// SetQuirks replaces the quirks selected by SetVariant(). The op code
+// tables of the Disassembler and the traces still follow the variant,
+// as does the instruction set, e.g. the 65C02 performs BRA and STZ.
+func (cpu *CPU) SetQuirks(q Quirks) {
+	cpu.quirks = q
+	cpu.table()
+}
+
+// Quirks returns the quirks in effect.
+func (cpu *CPU) Quirks() Quirks {
+	return cpu.quirks
+}
+
+// documented performs the documented NMOS instructions, the undocumented
+// op codes are invalid. It replaces dispatch without the Illegal quirk.
+var documented = func() (t [0x100]func(cpu *CPU)) {
+	for op := range t {
+		if !nmos[op].Illegal {
+			t[op] = dispatch[op]
+		}
+	}
+	return t
+}()
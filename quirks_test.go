@@ -0,0 +1,94 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQuirksVariant(t *testing.T) {
+	cpu := New(&memoryBus{})
+	if cpu.Quirks() != NMOS6502.Quirks() || !cpu.Quirks().JMPWrap {
+		t.Fatalf("unexpected, got %+v", cpu.Quirks())
+	}
+	if cpu.SetVariant(Ricoh2A03); !cpu.Quirks().NoDecimal {
+		t.Fatalf("unexpected, got %+v", cpu.Quirks())
+	}
+	if cpu.SetVariant(CMOS65C02); cpu.Quirks() != CMOS65C02.Quirks() || cpu.Quirks().DummyWrite {
+		t.Fatalf("unexpected, got %+v", cpu.Quirks())
+	}
+	if Variant(0xFF).Quirks() != (Quirks{}) {
+		t.Fatal("unexpected")
+	}
+}
+
+func TestQuirksMixed(t *testing.T) {
+	bus := &memoryBus{}
+	// JMP ($03FF), at 0x1234: SED, LDA #$09, ADC #$01, ANE #$FF
+	copy(bus.mem[0x0200:], []byte{0x6C, 0xFF, 0x03})
+	bus.mem[0x03FF], bus.mem[0x0400], bus.mem[0x0300] = 0x34, 0x12, 0x56
+	copy(bus.mem[0x1234:], []byte{0xF8, 0xA9, 0x09, 0x69, 0x01, 0x8B, 0xFF})
+
+	cpu := New(bus)
+	q := NMOS6502.Quirks()
+	q.JMPWrap, q.NoDecimal = false, true
+	cpu.SetQuirks(q)
+	cpu.PC(0x00, 0x02)
+
+	if cycles, _ := cpu.Step(); cpu.pc() != 0x1234 || cycles != 6 {
+		t.Fatalf("unexpected, got %04X %d", cpu.pc(), cycles)
+	}
+	for i := 0; i < 3; i++ {
+		_, _ = cpu.Step()
+	}
+	if cpu.a != 0x0A {
+		t.Fatalf("unexpected, got %s", cpu)
+	}
+	q.Unstable = false
+	cpu.SetQuirks(q)
+	if _, err := cpu.Step(); !errors.As(err, new(*OpcodeError)) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
+
+func TestQuirksIllegal(t *testing.T) {
+	for _, illegal := range []bool{true, false} {
+		// LDA #$01, NOP $10 (undocumented)
+		cpu, _ := newTestCPU(NMOS6502, 0xA9, 0x01, 0x04, 0x10)
+		q := NMOS6502.Quirks()
+		q.Illegal = illegal
+		cpu.SetQuirks(q)
+
+		if _, err := cpu.Step(); err != nil || cpu.a != 0x01 {
+			t.Fatalf("unexpected, got %v %s", err, cpu)
+		}
+		_, err := cpu.Step()
+		if illegal && (err != nil || cpu.pc() != 0x0204) {
+			t.Fatalf("unexpected, got %v %s", err, cpu)
+		}
+		if e := (*OpcodeError)(nil); !illegal && (!errors.As(err, &e) || e.Opcode != 0x04 || e.PC != 0x0202) {
+			t.Fatalf("unexpected, got %v %s", err, cpu)
+		}
+	}
+}
+
+func TestQuirksClearD(t *testing.T) {
+	for _, v := range []Variant{NMOS6502, CMOS65C02} {
+		bus := &memoryBus{}
+		bus.mem[0x0200] = 0x00 // BRK
+		cpu := New(bus)
+		cpu.SetVariant(v)
+		cpu.PC(0x00, 0x02)
+		cpu.p |= flagD
+
+		_, _ = cpu.Step()
+		if cpu.p.has(flagD) != !v.Quirks().ClearD || bus.mem[0x01FD]&byte(flagD) == 0 {
+			t.Errorf("unexpected, got %s for BRK on %s", cpu, v)
+		}
+		cpu.p |= flagD
+		if _, _ = cpu.NMI(); cpu.p.has(flagD) != !v.Quirks().ClearD {
+			t.Errorf("unexpected, got %s for NMI on %s", cpu, v)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+type (
+	// RandomBus is a Bus decorator returning seeded pseudo-random data when
+	// reading from selected ranges, simulating floating inputs or hardware
+	// not yet initialized. Writes and reads outside the ranges are passed
+	// to the decorated Bus. Use it to fuzz the robustness of emulated
+	// programs against arbitrary input values.
+	RandomBus struct {
+		bus    Bus
+		rnd    *rand.Rand
+		ranges []span
+	}
+
+	span struct{ start, end uint16 }
+)
+
+// NewRandomBus creates a RandomBus decorating the bus. The seed makes
+// the sequence of random values, and thus failing runs, reproducible.
+func NewRandomBus(bus Bus, seed int64) *RandomBus {
+	return &RandomBus{bus: bus, rnd: rand.New(rand.NewSource(seed))}
+}
+
+// Randomize selects the addresses start to end (inclusive) to return random
+// data. It panics when end is less than start.
+func (b *RandomBus) Randomize(start, end uint16) *RandomBus {
+	if end < start {
+		panic(fmt.Sprintf("m6502: invalid range %04X-%04X", start, end))
+	}
+	b.ranges = append(b.ranges, span{start, end})
+	return b
+}
+
+// Read returns a random byte within the selected ranges, and reads
+// from the decorated Bus otherwise.
+func (b *RandomBus) Read(lo, hi byte) byte {
+	addr := uint16(hi)<<8 | uint16(lo)
+	for _, r := range b.ranges {
+		if addr >= r.start && addr <= r.end {
+			return byte(b.rnd.Intn(0x100))
+		}
+	}
+	return b.bus.Read(lo, hi)
+}
+
+// Write writes to the decorated Bus.
+func (b *RandomBus) Write(lo, hi, db byte) {
+	b.bus.Write(lo, hi, db)
+}
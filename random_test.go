@@ -0,0 +1,44 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+func TestRandomBus(t *testing.T) {
+	mem := &memoryBus{}
+	mem.mem[0x0010] = 0x42
+
+	sample := func(seed int64) []byte {
+		b := NewRandomBus(mem, seed).Randomize(0xD000, 0xD0FF)
+		out := []byte{}
+		for i := 0; i < 16; i++ {
+			out = append(out, b.Read(byte(i), 0xD0))
+		}
+		return out
+	}
+	a, b := sample(1), sample(1)
+	if string(a) != string(b) || string(a) == string(sample(2)) {
+		t.Fatalf("unexpected, got % X", a)
+	}
+
+	rb := NewRandomBus(mem, 1).Randomize(0xD000, 0xD0FF)
+	rb.Write(0x00, 0xD0, 0x11)
+	if rb.Read(0x10, 0x00) != 0x42 || mem.mem[0xD000] != 0x11 {
+		t.Fatal("unexpected")
+	}
+}
+
+// TestRandomBusCore runs random data as code: the CPU core must either
+// perform an instruction or return an error, never panic or hang.
+func TestRandomBusCore(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		bus := NewRandomBus(&memoryBus{}, seed).Randomize(0x0000, 0xFFFF)
+		cpu := New(bus)
+
+		for i := 0; i < 1000; i++ {
+			if _, err := cpu.Step(); err != nil {
+				cpu.Reset()
+			}
+		}
+	}
+}
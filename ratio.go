@@ -0,0 +1,69 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+type (
+	// Ratio converts CPU cycles to ticks of a derived clock running at
+	// num/den of the CPU clock, e.g. 1/8 for a shift register or 1e9/hz
+	// for nanoseconds. The fractional remainder is carried over, so the
+	// conversion does not drift, regardless of how the cycles are split
+	// into calls of Convert().
+	Ratio struct {
+		num, den uint64
+		acc      uint64 // Fractional remainder, in units of 1/den
+		phase    uint64 // Initial remainder, see Rounding
+	}
+
+	// Rounding selects when a derived tick occurs within its period.
+	Rounding byte
+)
+
+const (
+	// RoundDown yields a tick when a full period has passed.
+	RoundDown Rounding = iota
+
+	// RoundNearest yields a tick when half a period has passed.
+	RoundNearest
+
+	// RoundUp yields a tick as soon as a period has been entered.
+	RoundUp
+)
+
+// NewRatio creates a Ratio of num/den, with den > 0.
+func NewRatio(num, den uint64, r Rounding) *Ratio {
+	if den == 0 {
+		panic(fmt.Sprintf("m6502: invalid ratio %d/0", num))
+	}
+	phase := uint64(0)
+	switch r {
+	case RoundNearest:
+		phase = den / 2
+	case RoundUp:
+		phase = den - 1
+	}
+	return &Ratio{num: num, den: den, acc: phase, phase: phase}
+}
+
+// Convert returns the derived ticks that occurred during the cycles.
+func (r *Ratio) Convert(cycles uint64) uint64 {
+	hi, lo := bits.Mul64(cycles, r.num)
+	lo, carry := bits.Add64(lo, r.acc, 0)
+	hi += carry
+
+	if hi >= r.den {
+		panic("m6502: ratio overflow")
+	}
+	ticks, rem := bits.Div64(hi, lo, r.den)
+	r.acc = rem
+	return ticks
+}
+
+// Reset discards the fractional remainder.
+func (r *Ratio) Reset() {
+	r.acc = r.phase
+}
@@ -0,0 +1,45 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRatio(t *testing.T) {
+	for _, tt := range []struct {
+		rounding Rounding
+		want     []uint64 // Ticks after each of 8 single cycles
+	}{
+		{RoundDown, []uint64{0, 0, 1, 0, 0, 1, 0, 1}},
+		{RoundNearest, []uint64{0, 1, 0, 1, 0, 0, 1, 0}},
+		{RoundUp, []uint64{1, 0, 1, 0, 0, 1, 0, 0}},
+	} {
+		r := NewRatio(3, 8, tt.rounding)
+		for i, want := range tt.want {
+			if got := r.Convert(1); got != want {
+				t.Fatalf("unexpected, got %d at %d with %d", got, i, tt.rounding)
+			}
+		}
+		r.Reset()
+		if got := r.Convert(8); got != 3 {
+			t.Fatalf("unexpected, got %d", got)
+		}
+	}
+}
+
+func TestRatioDrift(t *testing.T) {
+	a, b := NewRatio(1_000_000_000, ClockC64PAL, RoundDown), NewRatio(1_000_000_000, ClockC64PAL, RoundDown)
+
+	sum := uint64(0)
+	for i := 0; i < ClockC64PAL; i += 7 {
+		sum += a.Convert(7)
+	}
+	if got := b.Convert(ClockC64PAL/7*7 + 7); got != sum {
+		t.Fatalf("unexpected, got %d and %d", got, sum)
+	}
+	if got := NewRatio(math.MaxUint64, math.MaxUint64, RoundDown).Convert(math.MaxUint64); got != math.MaxUint64 {
+		t.Fatalf("unexpected, got %d", got)
+	}
+}
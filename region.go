@@ -0,0 +1,100 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "fmt"
+
+type (
+	// RegionInfo describes an address range of a Mapper as perceived by
+	// the CPU, see Mapper.Regions().
+	RegionInfo struct {
+		Start, End uint16     // Addresses, inclusive
+		Kind       RegionKind // Kind of the region
+		Bank       int        // Bank shown by a RegionBanked window, -1 otherwise
+		Writable   bool       // Writes take effect, assumed for devices
+		Name       string     // Name() of the region if implemented, its type otherwise
+	}
+
+	// RegionKind enumerates the kinds of RegionInfo.
+	RegionKind byte
+)
+
+// Kinds of RegionInfo.
+const (
+	RegionUnmapped RegionKind = iota // Accessing the range panics
+	RegionRAM                        // A *RAM
+	RegionROM                        // A *ROM
+	RegionBanked                     // A window of a *Banked
+	RegionDevice                     // Any other Bus16
+)
+
+var regionKinds = [...]string{"unmapped", "RAM", "ROM", "banked", "device"}
+
+// String returns the name of the region kind.
+func (k RegionKind) String() string {
+	if int(k) < len(regionKinds) {
+		return regionKinds[k]
+	}
+	return "unknown"
+}
+
+// Regions returns the address space as currently perceived by the CPU, in
+// ascending order of addresses: overlapping ranges are resolved, windows
+// of a Banked region are listed separately with their selected banks,
+// and unmapped ranges are included as RegionUnmapped.
+func (m *Mapper) Regions() []RegionInfo {
+	type key struct {
+		m      *mapping
+		window int
+	}
+	var (
+		list []RegionInfo
+		last key
+	)
+	for a := 0; a <= 0xFFFF; a++ {
+		addr := uint16(a)
+		k := key{window: -1}
+		for i := range m.ranges {
+			if r := &m.ranges[i]; addr >= r.start && addr <= r.end {
+				k.m = r
+				break
+			}
+		}
+		if k.m != nil {
+			if b, ok := k.m.region.(*Banked); ok {
+				k.window = int(addr-k.m.start) % (b.size * len(b.selected)) / b.size
+			}
+		}
+		if a > 0 && k == last {
+			list[len(list)-1].End = addr
+			continue
+		}
+		list, last = append(list, info(k.m, k.window, addr)), k
+	}
+	return list
+}
+
+func info(m *mapping, window int, addr uint16) RegionInfo {
+	ri := RegionInfo{Start: addr, End: addr, Bank: -1}
+	if m == nil {
+		return ri
+	}
+	ri.Kind, ri.Writable = RegionDevice, true
+	switch r := m.region.(type) {
+	case *RAM:
+		ri.Kind = RegionRAM
+	case *ROM:
+		ri.Kind, ri.Writable = RegionROM, false
+	case *Banked:
+		ri.Kind, ri.Bank = RegionBanked, r.Bank(window)
+	}
+	if m.protect != ProtectNone {
+		ri.Writable = false
+	}
+	if n, ok := m.region.(interface{ Name() string }); ok {
+		ri.Name = n.Name()
+	} else {
+		ri.Name = fmt.Sprintf("%T", m.region)
+	}
+	return ri
+}
@@ -0,0 +1,48 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+type namedDevice struct{ *register }
+
+func (namedDevice) Name() string { return "VIA" }
+
+func TestRegions(t *testing.T) {
+	banked := NewBanked(make([]byte, 0x4000), 0x1000, 2)
+	banked.SelectBank(1, 3)
+
+	m := NewMapper().
+		Map(0x0000, 0x7FFF, NewRAM(0x8000)).
+		Map(0x4000, 0x4FFF, NewRAM(0x1000)).
+		Map(0x8000, 0x9FFF, banked).
+		Map(0xD000, 0xD00F, namedDevice{&register{}}).
+		Map(0xE000, 0xFFFF, NewROM([]byte{0xEA}))
+
+	want := []RegionInfo{
+		{0x0000, 0x3FFF, RegionRAM, -1, true, "*m6502.RAM"},
+		{0x4000, 0x4FFF, RegionRAM, -1, true, "*m6502.RAM"},
+		{0x5000, 0x7FFF, RegionRAM, -1, true, "*m6502.RAM"},
+		{0x8000, 0x8FFF, RegionBanked, 0, true, "*m6502.Banked"},
+		{0x9000, 0x9FFF, RegionBanked, 3, true, "*m6502.Banked"},
+		{0xA000, 0xCFFF, RegionUnmapped, -1, false, ""},
+		{0xD000, 0xD00F, RegionDevice, -1, true, "VIA"},
+		{0xD010, 0xDFFF, RegionUnmapped, -1, false, ""},
+		{0xE000, 0xFFFF, RegionROM, -1, false, "*m6502.ROM"},
+	}
+	got := m.Regions()
+	if len(got) != len(want) {
+		t.Fatalf("unexpected, got %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("unexpected, got %+v at %d", got[i], i)
+		}
+	}
+	if RegionBanked.String() != "banked" || RegionKind(9).String() != "unknown" {
+		t.Fatal("unexpected")
+	}
+	if r := NewMapper().Regions(); len(r) != 1 || r[0].End != 0xFFFF {
+		t.Fatalf("unexpected, got %+v", r)
+	}
+}
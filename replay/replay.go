@@ -0,0 +1,288 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+// Package replay records the events injected into a CPU from the outside,
+// i.e. interrupt line changes and bytes read from input devices, with their
+// cycle timestamps, and replays them into a fresh CPU to reproduce the run
+// bit-exactly:
+//
+//	rec := replay.NewRecorder(cpu)
+//	bus.Attach(0xD010, 0xD01F, 0, rec.Input(keyboard))
+//	rec.AssertIRQ()                       // instead of cpu.AssertIRQ()
+//	...
+//	rec.Log().WriteTo(file)
+//
+//	log, _ := replay.Read(file)
+//	rep := replay.NewReplayer(cpu, log)
+//	bus.Attach(0xD010, 0xD01F, 0, rep.Input(keyboard))
+//	for !rep.Done() {
+//		rep.Step()
+//	}
+//
+// Line changes are recorded at the instruction boundary, so the changes have
+// to be injected between two calls to Step(), not by a bus access within an
+// instruction. The input devices are identified by the order of Input() calls.
+package replay
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dtgorski/m6502"
+)
+
+type (
+	// Event is an element of the Log.
+	Event struct {
+		Cycle  uint64 // CPU.TotalCycles() before the instruction
+		Kind   Kind
+		Device int    // Index of the input device, see Input()
+		Addr   uint16 // Address read, relative to the device
+		Data   byte   // Value read
+	}
+
+	// Kind enumerates the kinds of Event.
+	Kind byte
+
+	// Log is a recorded sequence of events.
+	Log []Event
+
+	// Recorder forwards events to a CPU and logs them.
+	Recorder struct {
+		cpu     *m6502.CPU
+		log     Log
+		devices int
+	}
+
+	// Replayer injects the events of a Log into a CPU.
+	Replayer struct {
+		cpu     *m6502.CPU
+		lines   Log // Pending line changes
+		inputs  Log // Pending input bytes
+		devices int
+	}
+
+	// DivergenceError reports a replayed run deviating from the Log. It is
+	// raised by the bus access and returned wrapped by CPU.Step().
+	DivergenceError struct {
+		Cycle uint64
+		Want  Event
+		Got   Event
+	}
+
+	recording struct {
+		rec *Recorder
+		id  int
+		dev m6502.Device
+	}
+
+	replaying struct {
+		rep *Replayer
+		id  int
+		dev m6502.Device
+	}
+)
+
+// Kinds of Event.
+const (
+	AssertIRQ Kind = iota + 1
+	ReleaseIRQ
+	AssertNMI
+	ReleaseNMI
+	Input
+)
+
+var kinds = [...]string{
+	AssertIRQ: "AssertIRQ", ReleaseIRQ: "ReleaseIRQ",
+	AssertNMI: "AssertNMI", ReleaseNMI: "ReleaseNMI",
+	Input: "Input",
+}
+
+// NewRecorder creates a Recorder for the CPU.
+func NewRecorder(cpu *m6502.CPU) *Recorder {
+	return &Recorder{cpu: cpu}
+}
+
+// Log returns the events recorded so far.
+func (r *Recorder) Log() Log {
+	return r.log
+}
+
+// AssertIRQ records and performs CPU.AssertIRQ().
+func (r *Recorder) AssertIRQ() { r.line(AssertIRQ) }
+
+// ReleaseIRQ records and performs CPU.ReleaseIRQ().
+func (r *Recorder) ReleaseIRQ() { r.line(ReleaseIRQ) }
+
+// AssertNMI records and performs CPU.AssertNMI().
+func (r *Recorder) AssertNMI() { r.line(AssertNMI) }
+
+// ReleaseNMI records and performs CPU.ReleaseNMI().
+func (r *Recorder) ReleaseNMI() { r.line(ReleaseNMI) }
+
+// Input returns a Device recording the bytes read from dev.
+func (r *Recorder) Input(dev m6502.Device) m6502.Device {
+	r.devices++
+	return &recording{rec: r, id: r.devices - 1, dev: dev}
+}
+
+func (r *Recorder) line(k Kind) {
+	r.log = append(r.log, Event{Cycle: r.cpu.TotalCycles(), Kind: k})
+	apply(r.cpu, k)
+}
+
+func (d *recording) Read(addr uint16) byte {
+	db := d.dev.Read(addr)
+	d.rec.log = append(d.rec.log, Event{
+		Cycle: d.rec.cpu.TotalCycles(), Kind: Input, Device: d.id, Addr: addr, Data: db,
+	})
+	return db
+}
+
+func (d *recording) Write(addr uint16, db byte) {
+	d.dev.Write(addr, db)
+}
+
+// NewReplayer creates a Replayer injecting the events of log into the CPU.
+// The CPU has to be in the state of the recorded CPU at the start of the
+// recording.
+func NewReplayer(cpu *m6502.CPU, log Log) *Replayer {
+	r := &Replayer{cpu: cpu}
+	for _, e := range log {
+		if e.Kind == Input {
+			r.inputs = append(r.inputs, e)
+		} else {
+			r.lines = append(r.lines, e)
+		}
+	}
+	return r
+}
+
+// Input returns a Device serving the recorded bytes instead of reading dev.
+// Writes are passed through to dev, pass nil to discard them.
+func (r *Replayer) Input(dev m6502.Device) m6502.Device {
+	r.devices++
+	return &replaying{rep: r, id: r.devices - 1, dev: dev}
+}
+
+// Step injects the line changes due and performs CPU.Step().
+func (r *Replayer) Step() (uint, error) {
+	for len(r.lines) > 0 && r.lines[0].Cycle <= r.cpu.TotalCycles() {
+		apply(r.cpu, r.lines[0].Kind)
+		r.lines = r.lines[1:]
+	}
+	return r.cpu.Step()
+}
+
+// Done reports whether all events have been replayed.
+func (r *Replayer) Done() bool {
+	return len(r.lines) == 0 && len(r.inputs) == 0
+}
+
+func (d *replaying) Read(addr uint16) byte {
+	got := Event{Cycle: d.rep.cpu.TotalCycles(), Kind: Input, Device: d.id, Addr: addr}
+	if len(d.rep.inputs) == 0 {
+		panic(&DivergenceError{Cycle: got.Cycle, Got: got})
+	}
+	want := d.rep.inputs[0]
+	if want.Cycle != got.Cycle || want.Device != got.Device || want.Addr != got.Addr {
+		panic(&DivergenceError{Cycle: got.Cycle, Want: want, Got: got})
+	}
+	d.rep.inputs = d.rep.inputs[1:]
+	return want.Data
+}
+
+func (d *replaying) Write(addr uint16, db byte) {
+	if d.dev != nil {
+		d.dev.Write(addr, db)
+	}
+}
+
+func apply(cpu *m6502.CPU, k Kind) {
+	switch k {
+	case AssertIRQ:
+		cpu.AssertIRQ()
+	case ReleaseIRQ:
+		cpu.ReleaseIRQ()
+	case AssertNMI:
+		cpu.AssertNMI()
+	case ReleaseNMI:
+		cpu.ReleaseNMI()
+	}
+}
+
+// WriteTo writes the Log as text, one event per line, see Read().
+func (l Log) WriteTo(w io.Writer) (int64, error) {
+	n := int64(0)
+	for _, e := range l {
+		m, err := fmt.Fprintf(w, "%s\n", e)
+		if n += int64(m); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// Read reads a Log written by WriteTo().
+func Read(r io.Reader) (Log, error) {
+	log := Log{}
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		e, err := parse(sc.Text())
+		if err != nil {
+			return nil, fmt.Errorf("replay: line %d: %w", line, err)
+		}
+		log = append(log, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+	return log, nil
+}
+
+func parse(s string) (Event, error) {
+	e, name := Event{}, ""
+	if _, err := fmt.Sscanf(s, "%d %s", &e.Cycle, &name); err != nil {
+		return e, err
+	}
+	for k, n := range kinds {
+		if n != "" && n == name {
+			e.Kind = Kind(k)
+		}
+	}
+	switch e.Kind {
+	case 0:
+		return e, fmt.Errorf("unknown event %q", name)
+	case Input:
+		_, err := fmt.Sscanf(s, "%d Input %d %x %x", &e.Cycle, &e.Device, &e.Addr, &e.Data)
+		return e, err
+	}
+	return e, nil
+}
+
+// String returns the event as written by Log.WriteTo().
+func (e Event) String() string {
+	if e.Kind == Input {
+		return fmt.Sprintf("%d %s %d %04X %02X", e.Cycle, e.Kind, e.Device, e.Addr, e.Data)
+	}
+	return fmt.Sprintf("%d %s", e.Cycle, e.Kind)
+}
+
+// String returns the name of the event kind.
+func (k Kind) String() string {
+	if int(k) < len(kinds) && kinds[k] != "" {
+		return kinds[k]
+	}
+	return "unknown"
+}
+
+func (e *DivergenceError) Error() string {
+	if e.Want.Kind == 0 {
+		return fmt.Sprintf("replay: diverged at cycle %d: unexpected %s", e.Cycle, e.Got)
+	}
+	return fmt.Sprintf("replay: diverged at cycle %d: want %s, got %s", e.Cycle, e.Want, e.Got)
+}
@@ -0,0 +1,153 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package replay
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dtgorski/m6502"
+)
+
+type (
+	// random is a nondeterministic input device.
+	random struct{ rnd *rand.Rand }
+
+	// machine is a CPU with RAM and an input device at 0xF000.
+	machine struct {
+		cpu *m6502.CPU
+		ram *m6502.RAM
+		bus *m6502.DeviceBus
+	}
+)
+
+func (d random) Read(uint16) byte   { return byte(d.rnd.Intn(0x100)) }
+func (d random) Write(uint16, byte) {}
+
+func newMachine() *machine {
+	ram := m6502.NewRAM(0x10000)
+	prog := map[uint16][]byte{
+		// CLI, LDA $F000, CLC, ADC $20, STA $20, JMP $0201
+		0x0200: {0x58, 0xAD, 0x00, 0xF0, 0x18, 0x65, 0x20, 0x85, 0x20, 0x4C, 0x01, 0x02},
+		// INC $21, RTI
+		0x0300: {0xE6, 0x21, 0x40},
+		0xFFFA: {0x00, 0x03, 0x00, 0x02, 0x00, 0x03},
+	}
+	for addr, b := range prog {
+		for i := range b {
+			ram.Write(addr+uint16(i), b[i])
+		}
+	}
+	bus := m6502.NewDeviceBus()
+	bus.Attach(0x0000, 0xFFFF, 0, ram)
+	cpu := m6502.New(bus)
+	cpu.Reset()
+	return &machine{cpu: cpu, ram: ram, bus: bus}
+}
+
+func (m *machine) state() string {
+	return m.cpu.String() + m.ram.String()
+}
+
+func TestReplay(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+
+	m := newMachine()
+	rec := NewRecorder(m.cpu)
+	m.bus.Attach(0xF000, 0xF000, 1, rec.Input(random{rnd}))
+
+	for i := 0; i < 1000; i++ {
+		switch rnd.Intn(50) {
+		case 0:
+			rec.AssertIRQ()
+		case 1:
+			rec.ReleaseIRQ()
+		case 2:
+			rec.AssertNMI()
+		case 3:
+			rec.ReleaseNMI()
+		}
+		if _, err := m.cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if irq, nmi := m.cpu.Interrupts(); irq == 0 || nmi == 0 {
+		t.Fatalf("unexpected, got %d %d", irq, nmi)
+	}
+
+	buf := &bytes.Buffer{}
+	if _, err := rec.Log().WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	log, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := newMachine()
+	rep := NewReplayer(r.cpu, log)
+	r.bus.Attach(0xF000, 0xF000, 1, rep.Input(nil))
+
+	for i := 0; i < 1000; i++ {
+		if _, err := rep.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !rep.Done() || r.state() != m.state() {
+		t.Fatalf("unexpected, got\n%s\nwant\n%s", r.state(), m.state())
+	}
+}
+
+func TestReplayDivergence(t *testing.T) {
+	r := newMachine()
+	rep := NewReplayer(r.cpu, Log{{Cycle: 9, Kind: Input, Addr: 0x0001}})
+	r.bus.Attach(0xF000, 0xF000, 1, rep.Input(nil))
+
+	_, _ = rep.Step()
+	_, err := rep.Step()
+
+	e := &DivergenceError{}
+	if !errors.As(err, &e) || e.Cycle != 9 || e.Got.Addr != 0x0000 {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	want := "m6502: 0201: replay: diverged at cycle 9: want 9 Input 0 0001 00, got 9 Input 0 0000 00"
+	if err.Error() != want {
+		t.Fatalf("unexpected, got %q", err)
+	}
+
+	rep = NewReplayer(r.cpu, nil)
+	e = &DivergenceError{Got: Event{Kind: Input}}
+	if !strings.Contains(e.Error(), "unexpected 0 Input 0 0000 00") || !rep.Done() {
+		t.Fatalf("unexpected, got %q", e)
+	}
+}
+
+func TestRead(t *testing.T) {
+	log := Log{
+		{Cycle: 7, Kind: AssertIRQ},
+		{Cycle: 12, Kind: Input, Device: 1, Addr: 0x0010, Data: 0xAB},
+		{Cycle: 20, Kind: ReleaseNMI},
+	}
+	buf := &bytes.Buffer{}
+	_, _ = log.WriteTo(buf)
+	if buf.String() != "7 AssertIRQ\n12 Input 1 0010 AB\n20 ReleaseNMI\n" {
+		t.Fatalf("unexpected, got %q", buf)
+	}
+	got, err := Read(strings.NewReader(buf.String() + "\n"))
+	if err != nil || !reflect.DeepEqual(got, log) {
+		t.Fatalf("unexpected, got %v %v", got, err)
+	}
+
+	for _, s := range []string{"x", "7 Foo", "7 Input 1"} {
+		if _, err := Read(strings.NewReader(s)); err == nil || !strings.HasPrefix(err.Error(), "replay: line 1: ") {
+			t.Fatalf("unexpected, got %v for %q", err, s)
+		}
+	}
+	if Kind(0).String() != "unknown" || Input.String() != "Input" {
+		t.Fatal("unexpected")
+	}
+}
@@ -0,0 +1,62 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "fmt"
+
+type (
+	// Request is a machine request of emulated software, see SetRequestPort()
+	// and SetRequestOpcode(). Values other than the predefined ones are passed
+	// on as well, their meaning is up to the host.
+	Request byte
+
+	// RequestError is returned by Step() after the instruction issuing the
+	// Request completed. The CPU is not halted: the host decides, e.g. to
+	// Reset() the CPU or to shut the machine down.
+	RequestError struct {
+		Request Request
+		PC      uint16 // Address of the instruction
+	}
+
+	requests struct {
+		port   uint16
+		portOn bool
+		op     byte
+		opOn   bool
+	}
+)
+
+// Predefined requests.
+const (
+	RequestReset    Request = 0x01 // Reset the machine
+	RequestPowerOff Request = 0x02 // Power off the machine
+)
+
+// SetRequestPort reserves the address as request port: a value written
+// there issues a Request, e.g. STA port with A=0x02 to power off. The
+// write is passed to the bus as well. Passing false for on removes it.
+func (cpu *CPU) SetRequestPort(addr uint16, on bool) {
+	cpu.requests.port, cpu.requests.portOn = addr, on
+}
+
+// SetRequestOpcode makes the op code issue the Request given by its one
+// byte operand, e.g. 0x02 0x01 to reset, when 0x02 (JAM) is configured.
+// It replaces the instruction of the op code, the PC advances past the
+// operand. Passing false for on removes it.
+func (cpu *CPU) SetRequestOpcode(op byte, on bool) {
+	cpu.requests.op, cpu.requests.opOn = op, on
+}
+
+func (r Request) String() string {
+	switch r {
+	case RequestReset:
+		return "reset"
+	case RequestPowerOff:
+		return "power-off"
+	}
+	return "unknown"
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("m6502: %04X: %s requested (%02X)", e.PC, e.Request, byte(e.Request))
+}
@@ -0,0 +1,58 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRequestPort(t *testing.T) {
+	bus := &memoryBus{}
+	copy(bus.mem[0x0200:], []byte{
+		0xA9, 0x02, //       LDA #$02
+		0x8D, 0x00, 0xD0, // STA $D000
+		0xEA, //             NOP
+	})
+	cpu := New(bus)
+	cpu.PC(0x00, 0x02)
+	cpu.SetRequestPort(0xD000, true)
+
+	err := cpu.Run(Limits{Instructions: 10})
+	req := &RequestError{}
+	if !errors.As(err, &req) || req.Request != RequestPowerOff || req.PC != 0x0202 {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	if err.Error() != "m6502: 0202: power-off requested (02)" || bus.mem[0xD000] != 0x02 {
+		t.Fatalf("unexpected, got %s", err)
+	}
+	if pc := stepPC(t, cpu); pc != 0x0206 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+}
+
+func TestRequestOpcode(t *testing.T) {
+	bus := &memoryBus{}
+	copy(bus.mem[0x0200:], []byte{0x02, 0x01, 0x02, 0x7F})
+
+	cpu := New(bus)
+	cpu.PC(0x00, 0x02)
+	cpu.SetRequestOpcode(0x02, true)
+
+	for _, want := range []Request{RequestReset, 0x7F} {
+		_, err := cpu.Step()
+		req := &RequestError{}
+		if !errors.As(err, &req) || req.Request != want {
+			t.Fatalf("unexpected, got %v", err)
+		}
+	}
+	if cpu.pc() != 0x0204 || Request(0x7F).String() != "unknown" || RequestReset.String() != "reset" {
+		t.Fatalf("unexpected, got %04X", cpu.pc())
+	}
+
+	cpu.SetRequestOpcode(0x02, false)
+	cpu.PC(0x00, 0x02)
+	if _, err := cpu.Step(); err == nil || errors.As(err, new(*RequestError)) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"fmt"
+	"io"
+)
+
+type (
+	// Access is the effective memory access of an instruction, i.e. the
+	// last data access apart from op code, operands, stack and vectors.
+	// For read-modify-write instructions, it is the write of the result.
+	Access struct {
+		Addr  uint16 // Effective address
+		Data  byte   // Value read or written
+		Write bool   // Access is a write
+	}
+
+	// Resolved is the Trace of a performed instruction completed by its
+	// effective memory access, see SetResolvedTracer().
+	Resolved struct {
+		Trace
+		Access Access // Effective memory access, when Memory is true
+		Memory bool   // Instruction accessed data memory
+	}
+)
+
+// SetResolvedTracer registers a function receiving a Resolved trace after
+// each performed instruction, replacing a previously set function. Unlike
+// the Tracer, which sees the state before an instruction, it may show the
+// effective address and the value transferred. Interrupt sequences and
+// failed instructions are not reported. Passing nil removes the function.
+func (cpu *CPU) SetResolvedTracer(fn func(Resolved)) {
+	cpu.resolve = fn
+	cpu.track()
+}
+
+// String returns the access in the notation of ResolvedWriter(),
+// e.g. "[@$1236=7F]".
+func (a Access) String() string {
+	return fmt.Sprintf("[@$%04X=%02X]", a.Addr, a.Data)
+}
+
+// ResolvedWriter returns a function for SetResolvedTracer() writing a
+// line per instruction to w, the disassembly followed by the effective
+// access. The operand bytes are read from the bus of the CPU. Errors of
+// w are ignored, consider a buffered writer.
+//
+//	0200  BD 34 12  LDA $1234,X [@$1236=7F]
+func ResolvedWriter(cpu *CPU, w io.Writer) func(Resolved) {
+	d := cpu.Disassembler()
+	d.Illegal = true
+	return func(r Resolved) {
+		i := DecodeVariant(d.Variant, r.Opcode)
+		code := []byte{r.Opcode, 0, 0}[:max(i.Size(), 1)]
+		for n := range code[1:] {
+			addr := r.PC + uint16(n) + 1
+			code[n+1] = cpu.bus.Read(byte(addr), byte(addr>>8))
+		}
+		asm, n := d.Decode(r.PC, code)
+		if r.Memory {
+			asm += " " + r.Access.String()
+		}
+		fmt.Fprintf(w, "%04X  %-8s  %s\n", r.PC, fmt.Sprintf("% X", code[:n]), asm)
+	}
+}
@@ -0,0 +1,58 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolvedTracer(t *testing.T) {
+	bus := &memoryBus{}
+	copy(bus.mem[0x0200:], []byte{
+		0xA2, 0x02, // LDX #$02
+		0xBD, 0x34, 0x12, // LDA $1234,X
+		0x85, 0x10, // STA $10
+		0xE6, 0x10, // INC $10
+		0x48,       // PHA
+		0xB1, 0x20, // LDA ($20),Y
+	})
+	bus.mem[0x1236] = 0x7F
+	bus.mem[0x20], bus.mem[0x21] = 0x00, 0x30
+	bus.mem[0x3000] = 0x42
+
+	cpu := New(bus)
+	cpu.PC(0x00, 0x02)
+
+	sb := &strings.Builder{}
+	cpu.SetResolvedTracer(ResolvedWriter(cpu, sb))
+	for i := 0; i < 6; i++ {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := "0200  A2 02     LDX #$02\n" +
+		"0202  BD 34 12  LDA $1234,X [@$1236=7F]\n" +
+		"0205  85 10     STA $10 [@$0010=7F]\n" +
+		"0207  E6 10     INC $10 [@$0010=80]\n" +
+		"0209  48        PHA\n" +
+		"020A  B1 20     LDA ($20),Y [@$3000=42]\n"
+	if sb.String() != want {
+		t.Fatalf("unexpected, got\n%s", sb)
+	}
+
+	var r Resolved
+	cpu.SetResolvedTracer(func(res Resolved) { r = res })
+	cpu.PC(0x05, 0x02)
+	_, _ = cpu.Step()
+	if !r.Memory || r.Access != (Access{0x0010, 0x42, true}) || r.PC != 0x0205 || r.A != 0x42 {
+		t.Fatalf("unexpected, got %+v", r)
+	}
+
+	cpu.SetResolvedTracer(nil)
+	r = Resolved{}
+	_, _ = cpu.Step()
+	if r.PC != 0 {
+		t.Fatalf("unexpected, got %+v", r)
+	}
+}
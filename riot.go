@@ -0,0 +1,200 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+type (
+	// RIOT emulates a 6532 RAM-I/O-Timer, e.g. of the KIM-1 or the Atari
+	// 2600: 128 bytes RAM, two 8 bit ports and an interval timer. As a
+	// Device, the RAM occupies the offsets 0x00-0x7F and the registers
+	// 0x80-0x9F, mirrored every 0x100. Where the RAM select line is decoded
+	// differently, map RAM() and IO() separately. Registers, by A4-A0:
+	//
+	//	x-000  Port A data     x-001  Port A direction
+	//	x-010  Port B data     x-011  Port B direction
+	//	0x1ep  Write: PA7 edge detect, e: interrupt enabled, p: positive edge
+	//	1e1dd  Write: timer, dd: divider 1, 8, 64, 1024, e: interrupt enabled
+	//	xe1x0  Read: timer, e: interrupt enabled, clears the timer flag
+	//	xx1x1  Read: interrupt flags, bit 7 timer, bit 6 PA7, clears PA7
+	//
+	// The timer decrements once per divider cycles. When counting through
+	// zero, the timer flag is set and the timer continues to decrement
+	// once per cycle until written again. The RIOT does not know about the
+	// cycles performed by the CPU: pass them to Tick() after each Step().
+	RIOT struct {
+		ram       [0x80]byte
+		ora, ddra byte
+		orb, ddrb byte
+		ina, inb  byte // Inputs applied to the ports
+		timer     byte
+		divider   uint
+		prescale  uint
+		flagTimer bool
+		flagEdge  bool
+		irqTimer  bool
+		irqEdge   bool
+		edgePos   bool
+		pa7       bool // Last level of PA7
+		lines     *Lines
+	}
+
+	riotRAM struct{ *RIOT }
+	riotIO  struct{ *RIOT }
+)
+
+// NewRIOT creates a RIOT. The ports are inputs pulled high.
+func NewRIOT() *RIOT {
+	r := &RIOT{ina: 0xFF, inb: 0xFF, divider: 1024, prescale: 1024}
+	r.pa7 = r.PortA()&0x80 != 0
+	return r
+}
+
+// SetIRQ connects the RIOT to the interrupt lines of a CPU.
+func (r *RIOT) SetIRQ(l *Lines) {
+	r.lines = l
+	r.update()
+}
+
+// RAM returns the RAM of the RIOT as Device, 128 bytes mirrored.
+func (r *RIOT) RAM() Device {
+	return riotRAM{r}
+}
+
+// IO returns the registers of the RIOT as Device, 32 bytes mirrored.
+func (r *RIOT) IO() Device {
+	return riotIO{r}
+}
+
+// SetPortA applies the input levels to port A, effective for the bits
+// configured as inputs. A change of PA7 may set the PA7 flag.
+func (r *RIOT) SetPortA(in byte) {
+	r.ina = in
+	r.edge()
+}
+
+// SetPortB applies the input levels to port B, see SetPortA().
+func (r *RIOT) SetPortB(in byte) {
+	r.inb = in
+}
+
+// PortA returns the levels of port A: output bits as written, input bits
+// as applied.
+func (r *RIOT) PortA() byte {
+	return r.ora&r.ddra | r.ina&^r.ddra
+}
+
+// PortB returns the levels of port B, see PortA().
+func (r *RIOT) PortB() byte {
+	return r.orb&r.ddrb | r.inb&^r.ddrb
+}
+
+// Tick advances the timer by the cycles.
+func (r *RIOT) Tick(cycles uint) {
+	for ; cycles > 0; cycles-- {
+		if r.prescale--; r.prescale > 0 {
+			continue
+		}
+		r.prescale = r.divider
+		if r.timer--; r.timer == 0xFF {
+			r.flagTimer, r.divider, r.prescale = true, 1, 1
+			r.update()
+		}
+	}
+}
+
+// Read reads the RAM or a register.
+func (r *RIOT) Read(addr uint16) byte {
+	if addr&0x80 == 0 {
+		return r.ram[addr&0x7F]
+	}
+	return r.io(addr)
+}
+
+// Write writes the RAM or a register.
+func (r *RIOT) Write(addr uint16, db byte) {
+	if addr&0x80 == 0 {
+		r.ram[addr&0x7F] = db
+		return
+	}
+	r.setIO(addr, db)
+}
+
+func (r riotRAM) Read(addr uint16) byte      { return r.ram[addr&0x7F] }
+func (r riotRAM) Write(addr uint16, db byte) { r.ram[addr&0x7F] = db }
+func (r riotIO) Read(addr uint16) byte       { return r.io(addr) }
+func (r riotIO) Write(addr uint16, db byte)  { r.setIO(addr, db) }
+
+func (r *RIOT) io(addr uint16) byte {
+	if addr&0x04 == 0 {
+		switch addr & 0x03 {
+		case 0:
+			return r.PortA()
+		case 1:
+			return r.ddra
+		case 2:
+			return r.PortB()
+		}
+		return r.ddrb
+	}
+	if addr&0x01 != 0 {
+		flags := byte(0)
+		if r.flagTimer {
+			flags |= 0x80
+		}
+		if r.flagEdge {
+			flags |= 0x40
+		}
+		r.flagEdge = false
+		r.update()
+		return flags
+	}
+	r.irqTimer = addr&0x08 != 0
+	r.flagTimer = false
+	r.update()
+	return r.timer
+}
+
+func (r *RIOT) setIO(addr uint16, db byte) {
+	if addr&0x04 == 0 {
+		switch addr & 0x03 {
+		case 0:
+			r.ora = db
+		case 1:
+			r.ddra = db
+		case 2:
+			r.orb = db
+		case 3:
+			r.ddrb = db
+		}
+		r.edge()
+		return
+	}
+	if addr&0x10 == 0 {
+		r.edgePos, r.irqEdge = addr&0x01 != 0, addr&0x02 != 0
+		r.update()
+		return
+	}
+	r.timer, r.divider = db, [...]uint{1, 8, 64, 1024}[addr&0x03]
+	r.prescale, r.irqTimer, r.flagTimer = r.divider, addr&0x08 != 0, false
+	r.update()
+}
+
+// edge detects a transition of PA7 with the selected polarity.
+func (r *RIOT) edge() {
+	pa7 := r.PortA()&0x80 != 0
+	if pa7 != r.pa7 && pa7 == r.edgePos {
+		r.flagEdge = true
+		r.update()
+	}
+	r.pa7 = pa7
+}
+
+func (r *RIOT) update() {
+	if r.lines == nil {
+		return
+	}
+	if r.flagTimer && r.irqTimer || r.flagEdge && r.irqEdge {
+		r.lines.AssertIRQ()
+	} else {
+		r.lines.ReleaseIRQ()
+	}
+}
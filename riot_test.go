@@ -0,0 +1,73 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+func TestRIOTPorts(t *testing.T) {
+	r := NewRIOT()
+	r.Write(0x05, 0x42) // RAM
+	if r.Read(0x05) != 0x42 || r.RAM().Read(0x85) != 0x42 {
+		t.Fatal("unexpected, RAM lost")
+	}
+
+	r.Write(0x81, 0x0F) // Port A low nibble output
+	r.Write(0x80, 0x05)
+	r.SetPortA(0x30)
+	if a := r.Read(0x80); a != 0x35 || r.PortA() != 0x35 || r.Read(0x81) != 0x0F {
+		t.Fatalf("unexpected, got %02X", a)
+	}
+	r.IO().Write(0x03, 0xFF) // Port B output
+	r.IO().Write(0x02, 0xA5)
+	r.SetPortB(0x00)
+	if b := r.Read(0x82); b != 0xA5 || r.PortB() != 0xA5 || r.Read(0x83) != 0xFF {
+		t.Fatalf("unexpected, got %02X", b)
+	}
+}
+
+func TestRIOTTimer(t *testing.T) {
+	r, lines := NewRIOT(), &Lines{}
+	r.SetIRQ(lines)
+
+	r.Write(0x9D, 0x02) // 2 * 8 cycles, interrupt enabled
+	r.Tick(16)
+	if r.Read(0x84) != 0x00 {
+		t.Fatalf("unexpected, got %02X", r.Read(0x84))
+	}
+	r.Write(0x9D, 0x02)
+	r.Tick(24)
+	if r.Read(0x85)&0x80 == 0 || !lines.irq.Load() {
+		t.Fatal("unexpected, timer flag clear")
+	}
+	r.Tick(1) // One count per cycle after expiry
+	if v := r.Read(0x8C); v != 0xFE || lines.irq.Load() {
+		t.Fatalf("unexpected, got %02X", v)
+	}
+	if r.Read(0x85)&0x80 != 0 {
+		t.Fatal("unexpected, timer flag set")
+	}
+
+	r.Write(0x94, 0x01) // Divider 1, interrupt disabled
+	r.Tick(2)
+	if r.Read(0x85)&0x80 == 0 || lines.irq.Load() {
+		t.Fatal("unexpected, interrupt")
+	}
+}
+
+func TestRIOTEdge(t *testing.T) {
+	r, lines := NewRIOT(), &Lines{}
+	r.SetIRQ(lines)
+
+	r.Write(0x87, 0x00) // Positive edge, interrupt enabled
+	r.SetPortA(0x00)
+	if lines.irq.Load() {
+		t.Fatal("unexpected, negative edge detected")
+	}
+	r.SetPortA(0x80)
+	if !lines.irq.Load() {
+		t.Fatal("unexpected, positive edge missed")
+	}
+	if r.Read(0x85) != 0x40 || r.Read(0x85) != 0x00 || lines.irq.Load() {
+		t.Fatal("unexpected, flag not cleared")
+	}
+}
@@ -0,0 +1,171 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type (
+	// Limits bounds the resources of Run() and RunContext(). A zero
+	// value field leaves the resource unlimited.
+	Limits struct {
+		Cycles       uint64        // Maximum number of cycles
+		Instructions uint64        // Maximum number of steps, incl. interrupts
+		Time         time.Duration // Maximum wall clock time
+	}
+
+	// LimitError is returned by Run() and RunContext() when a limit
+	// has been exceeded.
+	LimitError struct {
+		Kind         LimitKind // Exceeded limit
+		PC           uint16    // Program counter at termination
+		Cycles       uint64    // Cycles consumed
+		Instructions uint64    // Steps performed
+	}
+
+	// LimitKind identifies the limit of a LimitError.
+	LimitKind byte
+
+	// StopReason tells why RunUntil() or StepN() returned.
+	StopReason byte
+)
+
+// Kinds of Limits.
+const (
+	LimitCycles LimitKind = iota + 1
+	LimitInstructions
+	LimitTime
+)
+
+// Reasons of RunUntil() and StepN() to return.
+const (
+	StopTarget StopReason = iota + 1 // PC reached the target address
+	StopCount                        // Number of instructions performed
+	StopLimit                        // Cycle limit reached
+	StopError                        // Step() returned an error
+)
+
+var stopReasons = [...]string{"", "target", "count", "limit", "error"}
+
+// Steps between checks of the wall clock and the context.
+const runCheckInterval = 1024
+
+// Run performs instructions until Step() returns an error or a limit of l
+// has been exceeded, see RunContext().
+func (cpu *CPU) Run(l Limits) error {
+	return cpu.RunContext(context.Background(), l)
+}
+
+// RunContext performs instructions until Step() returns an error, a limit
+// of l has been exceeded or the context is done. The error of Step() and of
+// the context are returned as is, an exceeded limit as a *LimitError. The
+// wall clock and the context are checked every 1024 instructions.
+func (cpu *CPU) RunContext(ctx context.Context, l Limits) error {
+	start := time.Now()
+	cycles, steps := uint64(0), uint64(0)
+
+	for {
+		n, err := cpu.Step()
+		if err != nil {
+			return err
+		}
+		cycles += uint64(n)
+		steps++
+
+		kind := LimitKind(0)
+		switch {
+		case l.Cycles > 0 && cycles >= l.Cycles:
+			kind = LimitCycles
+		case l.Instructions > 0 && steps >= l.Instructions:
+			kind = LimitInstructions
+		case steps%runCheckInterval != 0:
+			continue
+		case l.Time > 0 && time.Since(start) >= l.Time:
+			kind = LimitTime
+		case ctx.Err() != nil:
+			return ctx.Err()
+		default:
+			continue
+		}
+		return cpu.named(&LimitError{Kind: kind, PC: cpu.pc(), Cycles: cycles, Instructions: steps})
+	}
+}
+
+// RunCycles performs instructions until at least n cycles have been
+// consumed, e.g. to slice the execution per scanline or frame. The
+// instruction crossing the budget completes, its excess cycles are
+// returned as overshoot to be deducted from the next budget. An error
+// of Step() ends the run, consumed reports the cycles until then.
+func (cpu *CPU) RunCycles(n uint64) (consumed, overshoot uint64, err error) {
+	for consumed < n {
+		c, err := cpu.Step()
+		if err != nil {
+			return consumed, 0, err
+		}
+		consumed += uint64(c)
+	}
+	return consumed, consumed - n, nil
+}
+
+// RunUntil performs instructions until the PC equals pc after an instruction,
+// at least one instruction is performed. A maxCycles > 0 limits the run:
+// it stops at the instruction boundary reaching the limit. The error of
+// Step() is returned as is, together with StopError.
+//
+//	cycles, reason, err := cpu.RunUntil(0x3469, 100_000_000)
+func (cpu *CPU) RunUntil(pc uint16, maxCycles uint64) (cycles uint64, reason StopReason, err error) {
+	for {
+		n, err := cpu.Step()
+		if err != nil {
+			return cycles, StopError, err
+		}
+		cycles += uint64(n)
+		switch {
+		case cpu.pc() == pc:
+			return cycles, StopTarget, nil
+		case maxCycles > 0 && cycles >= maxCycles:
+			return cycles, StopLimit, nil
+		}
+	}
+}
+
+// StepN performs n instructions, less when Step() returns an error. The
+// error is returned as is, together with StopError.
+func (cpu *CPU) StepN(n int) (cycles uint64, reason StopReason, err error) {
+	for i := 0; i < n; i++ {
+		c, err := cpu.Step()
+		if err != nil {
+			return cycles, StopError, err
+		}
+		cycles += uint64(c)
+	}
+	return cycles, StopCount, nil
+}
+
+// String returns the name of the stop reason.
+func (r StopReason) String() string {
+	if int(r) < len(stopReasons) && stopReasons[r] != "" {
+		return stopReasons[r]
+	}
+	return "unknown"
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("m6502: %04X: %s limit exceeded", e.PC, e.Kind)
+}
+
+// String returns the name of the limit.
+func (k LimitKind) String() string {
+	switch k {
+	case LimitCycles:
+		return "cycle"
+	case LimitInstructions:
+		return "instruction"
+	case LimitTime:
+		return "time"
+	}
+	return "unknown"
+}
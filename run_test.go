@@ -0,0 +1,130 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var nopJmp = []byte{0xEA, 0x4C, 0x00, 0x02} // NOP, JMP $0200
+
+func TestRunLimits(t *testing.T) {
+	var e *LimitError
+
+	cpu, _ := newTestCPU(NMOS6502, nopJmp...)
+	err := cpu.Run(Limits{Cycles: 100})
+	if !errors.As(err, &e) || e.Kind != LimitCycles || e.Cycles != 100 || e.Instructions != 40 {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	cpu, _ = newTestCPU(NMOS6502, nopJmp...)
+	err = cpu.Run(Limits{Instructions: 7})
+	if !errors.As(err, &e) || e.Kind != LimitInstructions || e.PC != 0x0201 || e.Cycles != 17 {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	if err.Error() != "m6502: 0201: instruction limit exceeded" {
+		t.Fatalf("unexpected, got %s", err)
+	}
+	cpu, _ = newTestCPU(NMOS6502, nopJmp...)
+	err = cpu.Run(Limits{Time: time.Millisecond})
+	if !errors.As(err, &e) || e.Kind != LimitTime {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
+
+func TestRunContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cpu, _ := newTestCPU(NMOS6502, nopJmp...)
+	if err := cpu.RunContext(ctx, Limits{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
+
+func TestRunError(t *testing.T) {
+	cpu, bus := newTestCPU(NMOS6502, nopJmp...)
+	bus.mem[0x0200] = 0x02 // HLT
+
+	if err := cpu.Run(Limits{Cycles: 100}); err == nil {
+		t.Fatal("unexpected, got nil")
+	}
+}
+
+func TestRunCycles(t *testing.T) {
+	cpu, _ := newTestCPU(NMOS6502, nopJmp...)
+
+	// NOP 2, JMP 3: 2, 5, 7, 10, 12 ...
+	for _, tt := range []struct{ n, consumed, overshoot uint64 }{
+		{6, 7, 1},
+		{5, 5, 0},
+		{0, 0, 0},
+		{1, 3, 2},
+	} {
+		consumed, overshoot, err := cpu.RunCycles(tt.n)
+		if err != nil || consumed != tt.consumed || overshoot != tt.overshoot {
+			t.Fatalf("unexpected, got %d %d %v for %d", consumed, overshoot, err, tt.n)
+		}
+	}
+	if cpu.TotalCycles() != 7+15 {
+		t.Fatalf("unexpected, got %d", cpu.TotalCycles())
+	}
+
+	bus := &memoryBus{}
+	copy(bus.mem[0x0200:], []byte{0xEA, 0x02}) // NOP, HLT
+	cpu = New(bus)
+	cpu.PC(0x00, 0x02)
+	if consumed, _, err := cpu.RunCycles(100); consumed != 2 || !errors.Is(err, ErrHalted) {
+		t.Fatalf("unexpected, got %d %v", consumed, err)
+	}
+}
+
+func TestRunUntil(t *testing.T) {
+	cpu, _ := newTestCPU(NMOS6502, nopJmp...)
+
+	// NOP 2, JMP 3
+	for _, tt := range []struct {
+		pc     uint16
+		max    uint64
+		cycles uint64
+		reason StopReason
+	}{
+		{0x0201, 0, 2, StopTarget},
+		{0x0201, 0, 5, StopTarget}, // At least one instruction
+		{0x1234, 12, 13, StopLimit},
+		{0x0200, 100, 5, StopTarget},
+	} {
+		cycles, reason, err := cpu.RunUntil(tt.pc, tt.max)
+		if err != nil || cycles != tt.cycles || reason != tt.reason {
+			t.Fatalf("unexpected, got %d %s %v for %04X", cycles, reason, err, tt.pc)
+		}
+	}
+
+	bus := &memoryBus{}
+	copy(bus.mem[0x0200:], []byte{0xEA, 0x02}) // NOP, HLT
+	cpu = New(bus)
+	cpu.PC(0x00, 0x02)
+	if cycles, reason, err := cpu.RunUntil(0x1234, 0); cycles != 2 || reason != StopError || !errors.Is(err, ErrHalted) {
+		t.Fatalf("unexpected, got %d %s %v", cycles, reason, err)
+	}
+}
+
+func TestStepN(t *testing.T) {
+	cpu, _ := newTestCPU(NMOS6502, nopJmp...)
+	if cycles, reason, err := cpu.StepN(3); cycles != 7 || reason != StopCount || err != nil || cpu.pc() != 0x0201 {
+		t.Fatalf("unexpected, got %d %s %v", cycles, reason, err)
+	}
+
+	bus := &memoryBus{}
+	copy(bus.mem[0x0200:], []byte{0xEA, 0x02}) // NOP, HLT
+	cpu = New(bus)
+	cpu.PC(0x00, 0x02)
+	if cycles, reason, err := cpu.StepN(3); cycles != 2 || reason != StopError || !errors.Is(err, ErrHalted) {
+		t.Fatalf("unexpected, got %d %s %v", cycles, reason, err)
+	}
+	if StopLimit.String() != "limit" || StopReason(0).String() != "unknown" {
+		t.Fatal("unexpected")
+	}
+}
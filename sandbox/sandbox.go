@@ -0,0 +1,165 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+// Package sandbox is a deterministic 6502 machine profile for programming
+// challenges and education. A submission is loaded to 0x0200 and executed
+// with a fixed memory map, a console and a seeded random number generator,
+// bounded by a cycle budget:
+//
+//	res := sandbox.Run(image, sandbox.Config{Seed: 42, Input: []byte("abc")})
+//	fmt.Printf("%s exit=%d err=%v\n", res.Output, res.ExitCode, res.Err)
+//
+// Memory map:
+//
+//	0000-EFFF  RAM, the image is loaded at 0x0200
+//	F000       Console output (write)
+//	F001       Console input (read), 0x00 when exhausted
+//	F002       Random number (read)
+//	F003       Exit (write), stops the machine with the written exit code
+//	F004       Argument length (read), see below
+//	F005-F0FF  Reserved, reads 0x00, writes are ignored
+//	F100-FFFF  RAM, the vectors are preset to 0x0200
+//
+// Arguments are passed to the program by copying up to 255 bytes to 0xF100,
+// their length can be read from 0xF004. Thus 6502 code can serve as a
+// deterministic scripting target parametrized by the host:
+//
+//	res := sandbox.RunImageWithArgs(image, []byte{0x03, 0x04})
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/dtgorski/m6502"
+)
+
+type (
+	// Config configures a sandbox run.
+	Config struct {
+		Seed  int64  // Seed of the random number generator
+		Input []byte // Console input
+		Args  []byte // Arguments, up to MaxArgs bytes
+
+		// MaxCycles limits the run, DefaultMaxCycles when 0.
+		MaxCycles uint64
+	}
+
+	// Result reports the outcome of a sandbox run.
+	Result struct {
+		Output       []byte // Console output
+		ExitCode     byte   // Value written to the exit port
+		Exited       bool   // Program wrote to the exit port
+		Cycles       uint64 // Cycles consumed
+		Instructions uint64 // Steps performed, incl. interrupts
+
+		// Err is nil when the program exited, a *m6502.LimitError when the
+		// cycle budget has been exceeded, or the error of the CPU.
+		Err error
+	}
+
+	machine struct {
+		mem    [0x10000]byte
+		rnd    *rand.Rand
+		input  []byte
+		output []byte
+		argLen byte
+		exit   byte
+		exited bool
+	}
+)
+
+// Addresses of the sandbox devices.
+const (
+	Origin  = 0x0200 // Load address and entry point
+	ConOut  = 0xF000 // Console output port
+	ConIn   = 0xF001 // Console input port
+	Random  = 0xF002 // Random number port
+	Exit    = 0xF003 // Exit port
+	ArgLen  = 0xF004 // Argument length port
+	Args    = 0xF100 // Location of the arguments
+	MaxArgs = 0xFF   // Maximum length of the arguments
+	ioPage  = 0xF0
+	maxSize = 0xF000 - Origin
+)
+
+// DefaultMaxCycles is the cycle budget of a run, when not configured.
+const DefaultMaxCycles = 10_000_000
+
+// Run executes the image in a fresh sandbox machine. Runs with the same
+// image and Config yield the same Result.
+func Run(image []byte, cfg Config) Result {
+	if len(image) > maxSize {
+		return Result{Err: fmt.Errorf("sandbox: image exceeds %d bytes", maxSize)}
+	}
+	if len(cfg.Args) > MaxArgs {
+		return Result{Err: fmt.Errorf("sandbox: arguments exceed %d bytes", MaxArgs)}
+	}
+	if cfg.MaxCycles == 0 {
+		cfg.MaxCycles = DefaultMaxCycles
+	}
+	m := &machine{rnd: rand.New(rand.NewSource(cfg.Seed)), input: cfg.Input}
+	copy(m.mem[Origin:], image)
+	copy(m.mem[Args:], cfg.Args)
+	m.argLen = byte(len(cfg.Args))
+	for v := 0xFFFA; v < 0x10000; v += 2 {
+		m.mem[v], m.mem[v+1] = Origin&0xFF, Origin>>8
+	}
+
+	cpu := m6502.New(m)
+	cpu.SetRequestPort(Exit, true)
+	res := Result{}
+	cpu.AddHook(m6502.PriorityDefault, func(*m6502.CPU, m6502.Trace) bool {
+		res.Instructions++
+		return false
+	})
+	start := cpu.TotalCycles()
+	err := cpu.Run(m6502.Limits{Cycles: cfg.MaxCycles})
+
+	// The exit port is the request port of the CPU, ending the run.
+	if req := (*m6502.RequestError)(nil); !errors.As(err, &req) {
+		res.Err = err
+	}
+	res.Cycles = cpu.TotalCycles() - start
+	res.Output, res.ExitCode, res.Exited = m.output, m.exit, m.exited
+	return res
+}
+
+// RunImageWithArgs executes the image with arguments and the defaults of
+// Config otherwise, see Run().
+func RunImageWithArgs(image, args []byte) Result {
+	return Run(image, Config{Args: args})
+}
+
+func (m *machine) Read(l, h byte) byte {
+	if h != ioPage {
+		return m.mem[uint16(h)<<8|uint16(l)]
+	}
+	switch uint16(h)<<8 | uint16(l) {
+	case ConIn:
+		if len(m.input) == 0 {
+			return 0x00
+		}
+		b := m.input[0]
+		m.input = m.input[1:]
+		return b
+	case Random:
+		return byte(m.rnd.Intn(0x100))
+	case ArgLen:
+		return m.argLen
+	}
+	return 0x00
+}
+
+func (m *machine) Write(l, h, b byte) {
+	if h != ioPage {
+		m.mem[uint16(h)<<8|uint16(l)] = b
+		return
+	}
+	switch uint16(h)<<8 | uint16(l) {
+	case ConOut:
+		m.output = append(m.output, b)
+	case Exit:
+		m.exit, m.exited = b, true
+	}
+}
@@ -0,0 +1,80 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package sandbox
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dtgorski/m6502"
+	"github.com/dtgorski/m6502/prog"
+)
+
+func TestRunEcho(t *testing.T) {
+	// Echoes the input in upper case, exits with the number of bytes.
+	p := prog.New(Origin).LDXImm(0x00).
+		Label("loop").LDAAbs(ConIn).BEQ("done").
+		Byte(0x29, 0xDF). // AND #$DF
+		STAAbs(ConOut).INX().JMP("loop").
+		Label("done").STXAbs(Exit)
+
+	image, err := p.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := Run(image, Config{Input: []byte("abc")})
+
+	if res.Err != nil || !res.Exited || res.ExitCode != 3 || string(res.Output) != "ABC" {
+		t.Fatalf("unexpected, got %+v", res)
+	}
+}
+
+func TestRunRandom(t *testing.T) {
+	p := prog.New(Origin).
+		LDAAbs(Random).STAAbs(ConOut).
+		LDAAbs(Random).STAAbs(ConOut).
+		STAAbs(Exit)
+	image, _ := p.Bytes()
+
+	a, b := Run(image, Config{Seed: 1}), Run(image, Config{Seed: 1})
+	if string(a.Output) != string(b.Output) || a.Cycles != b.Cycles {
+		t.Fatalf("unexpected, got %X and %X", a.Output, b.Output)
+	}
+}
+
+func TestRunLimit(t *testing.T) {
+	image, _ := prog.New(Origin).Label("loop").JMP("loop").Bytes()
+	res := Run(image, Config{MaxCycles: 300})
+
+	var e *m6502.LimitError
+	if !errors.As(res.Err, &e) || e.Kind != m6502.LimitCycles || res.Cycles != 300 || res.Exited ||
+		res.Instructions != 100 || e.Instructions != 100 {
+		t.Fatalf("unexpected, got %+v", res)
+	}
+	if res = Run(make([]byte, 0x10000), Config{}); res.Err == nil {
+		t.Fatal("unexpected, got nil")
+	}
+}
+
+func TestRunImageWithArgs(t *testing.T) {
+	// Exits with the sum of the arguments.
+	p := prog.New(Origin).LDAAbs(ArgLen).TAX().LDAImm(0x00).
+		Label("loop").CPXImm(0x00).BEQ("done").DEX().CLC().
+		Byte(0x7D, Args&0xFF, Args>>8). // ADC Args,X
+		JMP("loop").
+		Label("done").STAAbs(Exit)
+
+	image, err := p.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := RunImageWithArgs(image, []byte{1, 2, 3, 4}); res.Err != nil || res.ExitCode != 10 {
+		t.Fatalf("unexpected, got %+v", res)
+	}
+	if res := RunImageWithArgs(image, nil); res.Err != nil || res.ExitCode != 0 {
+		t.Fatalf("unexpected, got %+v", res)
+	}
+	if res := RunImageWithArgs(image, make([]byte, MaxArgs+1)); res.Err == nil {
+		t.Fatal("unexpected")
+	}
+}
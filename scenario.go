@@ -0,0 +1,222 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Scenario describes a regression test as pure data: an initial state,
+	// a program, the number of steps to perform and the expected final state.
+	// Scenarios are read from JSON by LoadScenarios(), e.g.:
+	//
+	//	[{
+	//	  "name": "ADC decimal",
+	//	  "program": "F8 18 69 01",
+	//	  "steps": 3,
+	//	  "initial": {"a": "0x09"},
+	//	  "final": {"a": "0x10", "p": "0x28", "memory": {"0x0203": "01"}},
+	//	  "cycles": 6
+	//	}]
+	//
+	// Numbers may be given as JSON numbers or as strings with "0x" or "$"
+	// prefix, byte sequences as strings of hexadecimal pairs.
+	Scenario struct {
+		Name    string `json:"name"`
+		Variant string `json:"variant"` // "6502" (default), "65C02", "2A03", "65CE02" or "HuC6280"
+		Origin  *Hex   `json:"origin"`  // Load address of the program, 0x0200 by default
+		Program Bytes  `json:"program"` // Loaded at Origin, where PC starts by default
+		Steps   int    `json:"steps"`   // Number of Step() calls, 1 by default
+		Initial State  `json:"initial"`
+		Final   State  `json:"final"`
+		Cycles  *uint  `json:"cycles"` // Expected total cycles, unchecked when absent
+	}
+
+	// State describes the CPU registers and memory of a Scenario. Absent
+	// registers are left unchanged, respectively are not checked. The status
+	// register is compared without the B and unused bits.
+	State struct {
+		PC     *Hex          `json:"pc"`
+		A      *Hex          `json:"a"`
+		X      *Hex          `json:"x"`
+		Y      *Hex          `json:"y"`
+		S      *Hex          `json:"s"`
+		P      *Hex          `json:"p"`
+		Memory map[Hex]Bytes `json:"memory"` // Byte sequences by start address
+	}
+
+	// Hex is a number of a Scenario, see there.
+	Hex uint16
+
+	// Bytes is a byte sequence of a Scenario, see there.
+	Bytes []byte
+)
+
+// LoadScenarios reads a JSON array of Scenarios.
+func LoadScenarios(r io.Reader) ([]Scenario, error) {
+	list := []Scenario{}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&list); err != nil {
+		return nil, fmt.Errorf("m6502: scenario: %w", err)
+	}
+	return list, nil
+}
+
+// Run performs the Scenario on a fresh CPU with flat 64K RAM. It returns
+// nil when the final state matches, and the joined mismatches otherwise.
+func (sc Scenario) Run() error {
+	mem := NewRAM(0x10000)
+	cpu := New(Adapt16(mem))
+
+	switch sc.Variant {
+	case "", "6502":
+	case "65C02":
+		cpu.SetVariant(CMOS65C02)
+	case "2A03":
+		cpu.SetVariant(Ricoh2A03)
+	case "65CE02":
+		cpu.SetVariant(CSG65CE02)
+	case "HuC6280":
+		cpu.SetVariant(HuC6280)
+	default:
+		return fmt.Errorf("m6502: %s: unknown variant %q", sc.Name, sc.Variant)
+	}
+
+	origin := uint16(0x0200)
+	if sc.Origin != nil {
+		origin = uint16(*sc.Origin)
+	}
+	mem.Load(origin, sc.Program)
+	cpu.PC(byte(origin), byte(origin>>8))
+	sc.Initial.apply(cpu, mem)
+
+	steps, total := sc.Steps, uint(0)
+	if steps == 0 {
+		steps = 1
+	}
+	for i := 0; i < steps; i++ {
+		cycles, err := cpu.Step()
+		if err != nil {
+			return fmt.Errorf("m6502: %s: step %d: %w", sc.Name, i+1, err)
+		}
+		total += cycles
+	}
+
+	errs := sc.Final.check(cpu, mem)
+	if sc.Cycles != nil && *sc.Cycles != total {
+		errs = append(errs, fmt.Errorf("cycles: want %d, got %d", *sc.Cycles, total))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("m6502: %s: %w", sc.Name, errors.Join(errs...))
+	}
+	return nil
+}
+
+func (s State) apply(cpu *CPU, mem *RAM) {
+	if s.PC != nil {
+		cpu.PC(byte(*s.PC), byte(*s.PC>>8))
+	}
+	for _, r := range []struct {
+		v   *Hex
+		reg *byte
+	}{{s.A, &cpu.a}, {s.X, &cpu.x}, {s.Y, &cpu.y}, {s.S, &cpu.s}} {
+		if r.v != nil {
+			*r.reg = byte(*r.v)
+		}
+	}
+	if s.P != nil {
+		cpu.p = flag(*s.P) &^ (flagU | flagB)
+	}
+	for addr, b := range s.Memory {
+		mem.Load(uint16(addr), b)
+	}
+}
+
+func (s State) check(cpu *CPU, mem *RAM) []error {
+	errs := []error{}
+	if s.PC != nil && uint16(*s.PC) != cpu.pc() {
+		errs = append(errs, fmt.Errorf("pc: want %04X, got %04X", uint16(*s.PC), cpu.pc()))
+	}
+	for _, r := range []struct {
+		name string
+		v    *Hex
+		got  byte
+	}{
+		{"a", s.A, cpu.a}, {"x", s.X, cpu.x}, {"y", s.Y, cpu.y}, {"s", s.S, cpu.s},
+		{"p", s.P, byte(cpu.p | flagU)},
+	} {
+		if r.v == nil {
+			continue
+		}
+		want := byte(*r.v)
+		if r.name == "p" {
+			want = want&^byte(flagB) | byte(flagU)
+		}
+		if want != r.got {
+			errs = append(errs, fmt.Errorf("%s: want %02X, got %02X", r.name, want, r.got))
+		}
+	}
+	for _, addr := range s.addrs() {
+		for i, want := range s.Memory[addr] {
+			if a := uint16(addr) + uint16(i); mem.Read(a) != want {
+				errs = append(errs, fmt.Errorf("memory %04X: want %02X, got %02X", a, want, mem.Read(a)))
+			}
+		}
+	}
+	return errs
+}
+
+// addrs returns the start addresses of the memory in ascending order.
+func (s State) addrs() []Hex {
+	list := make([]Hex, 0, len(s.Memory))
+	for addr := range s.Memory {
+		list = append(list, addr)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	return list
+}
+
+// UnmarshalJSON accepts a JSON number or a string, see UnmarshalText().
+func (h *Hex) UnmarshalJSON(b []byte) error {
+	if s, err := strconv.Unquote(string(b)); err == nil {
+		return h.UnmarshalText([]byte(s))
+	}
+	return h.UnmarshalText(b)
+}
+
+// UnmarshalText accepts a decimal number or a hexadecimal
+// number with "0x" or "$" prefix, up to 0xFFFF.
+func (h *Hex) UnmarshalText(b []byte) error {
+	s, base := string(b), 10
+	for _, p := range []string{"0x", "0X", "$"} {
+		if strings.HasPrefix(s, p) {
+			s, base = s[len(p):], 16
+			break
+		}
+	}
+	v, err := strconv.ParseUint(s, base, 16)
+	if err != nil {
+		return fmt.Errorf("invalid number %q", b)
+	}
+	*h = Hex(v)
+	return nil
+}
+
+// UnmarshalText accepts hexadecimal pairs, optionally separated by blanks.
+func (bs *Bytes) UnmarshalText(b []byte) error {
+	v, err := hex.DecodeString(strings.Join(strings.Fields(string(b)), ""))
+	if err != nil {
+		return fmt.Errorf("invalid bytes %q", b)
+	}
+	*bs = v
+	return nil
+}
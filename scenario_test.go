@@ -0,0 +1,59 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestScenarios(t *testing.T) {
+	f, err := os.Open("testdata/scenarios.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	list, err := LoadScenarios(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sc := range list {
+		if err := sc.Run(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestScenarioMismatch(t *testing.T) {
+	list, err := LoadScenarios(strings.NewReader(`[{
+		"name": "wrong",
+		"program": "A9 01",
+		"final": {"a": 2, "x": "$00", "memory": {"0x0201": "02"}},
+		"cycles": 3
+	}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "m6502: wrong: a: want 02, got 01\nmemory 0201: want 02, got 01\ncycles: want 3, got 2"
+	if err = list[0].Run(); err == nil || err.Error() != want {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
+
+func TestLoadScenariosInvalid(t *testing.T) {
+	for _, s := range []string{
+		`[{"name": "x", "program": "A9 0"}]`,
+		`[{"name": "x", "initial": {"a": "0x10000"}}]`,
+		`[{"name": "x", "unknown": 1}]`,
+	} {
+		if _, err := LoadScenarios(strings.NewReader(s)); err == nil {
+			t.Errorf("unexpected, got nil for %s", s)
+		}
+	}
+	list, _ := LoadScenarios(strings.NewReader(`[{"name": "x", "variant": "Z80"}]`))
+	if err := list[0].Run(); err == nil {
+		t.Fatal("unexpected, got nil")
+	}
+}
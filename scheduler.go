@@ -0,0 +1,81 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "time"
+
+// Scheduler interleaves the execution of several CPUs clocked at possibly
+// different frequencies, e.g. a C64 and its 1541 disk drive. It always
+// steps the CPU lagging behind the most in emulated time, so each CPU
+// sees the bus effects of the others with instruction granularity. The
+// CPUs may share a bus or communicate via bridging devices on their buses.
+type Scheduler struct {
+	units []*unit
+}
+
+// unit is a CPU run by a Scheduler.
+type unit struct {
+	cpu  *CPU
+	ns   *Ratio        // Cycles to nanoseconds
+	time time.Duration // Emulated time of the CPU
+}
+
+// NewScheduler creates an empty Scheduler.
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Add adds a CPU clocked at hz cycles per second. The CPU starts at the
+// current emulated time of the Scheduler.
+func (s *Scheduler) Add(cpu *CPU, hz uint) {
+	if hz == 0 {
+		panic("m6502: invalid clock frequency 0")
+	}
+	ns := NewRatio(uint64(time.Second), uint64(hz), RoundDown)
+	s.units = append(s.units, &unit{cpu: cpu, ns: ns, time: s.Elapsed()})
+}
+
+// Elapsed returns the emulated time reached by all CPUs.
+func (s *Scheduler) Elapsed() time.Duration {
+	if u := s.next(); u != nil {
+		return u.time
+	}
+	return 0
+}
+
+// Step steps the CPU lagging behind the most and returns it together with
+// the results of its Step(). On equal time, CPUs added earlier go first.
+func (s *Scheduler) Step() (cpu *CPU, cycles uint, err error) {
+	u := s.next()
+	if u == nil {
+		return nil, 0, nil
+	}
+	if cycles, err = u.cpu.Step(); err != nil {
+		return u.cpu, cycles, err
+	}
+	u.time += time.Duration(u.ns.Convert(uint64(cycles)))
+	return u.cpu, cycles, nil
+}
+
+// Run steps the CPUs until all of them advanced by the emulated time d,
+// or until a CPU returns an error.
+func (s *Scheduler) Run(d time.Duration) error {
+	until := s.Elapsed() + d
+	for u := s.next(); u != nil && u.time < until; u = s.next() {
+		if _, _, err := s.Step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// next returns the unit lagging behind the most.
+func (s *Scheduler) next() *unit {
+	var next *unit
+	for _, u := range s.units {
+		if next == nil || u.time < next.time {
+			next = u
+		}
+	}
+	return next
+}
@@ -0,0 +1,58 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduler(t *testing.T) {
+	slow, _ := newTestCPU(NMOS6502, nopJmp...)
+	fast, _ := newTestCPU(NMOS6502, nopJmp...)
+
+	s := NewScheduler()
+	s.Add(slow, 1_000_000)
+	s.Add(fast, 2_000_000)
+
+	count := map[*CPU]uint{}
+	for s.Elapsed() < time.Millisecond {
+		cpu, cycles, err := s.Step()
+		if err != nil {
+			t.Fatal(err)
+		}
+		count[cpu] += cycles
+	}
+	if c := count[slow]; c < 1000 || c > 1003 {
+		t.Fatalf("unexpected, got %d", c)
+	}
+	if c := count[fast]; c < 2000 || c > 2003 {
+		t.Fatalf("unexpected, got %d", c)
+	}
+}
+
+func TestSchedulerRun(t *testing.T) {
+	s := NewScheduler()
+	if err := s.Run(time.Second); err != nil || s.Elapsed() != 0 {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	for _, hz := range []uint{ClockC64PAL, 1_000_000} {
+		cpu, _ := newTestCPU(NMOS6502, nopJmp...)
+		s.Add(cpu, hz)
+	}
+
+	if err := s.Run(10 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if e := s.Elapsed(); e < 10*time.Millisecond || e > 10*time.Millisecond+5*time.Microsecond {
+		t.Fatalf("unexpected, got %s", e)
+	}
+
+	bad := New(&memoryBus{}) // JAM
+	bad.PC(0x00, 0x02)
+	bad.bus.Write(0x00, 0x02, 0x02)
+	s.Add(bad, 1_000_000)
+	if err := s.Run(time.Millisecond); err == nil {
+		t.Fatal("unexpected")
+	}
+}
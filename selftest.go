@@ -0,0 +1,156 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"errors"
+	"fmt"
+)
+
+type (
+	// Suite describes a self-checking test program, e.g. one of the
+	// Klaus Dormann 6502 tests. A suite signals its result by trapping,
+	// i.e. by jumping or branching onto itself. The suite passed, when
+	// the trap address equals the Success address. A suite with a Done
+	// address ends there instead and passed, when its Result byte is 0.
+	Suite struct {
+		Name    string // Name for error reporting
+		Image   []byte // Program image, loaded at Origin
+		Origin  uint16 // Load address of the image
+		Start   uint16 // Entry point of the program
+		Success uint16 // Trap address signaling success
+		Done    uint16 // End of the program, 0 when trapping
+		Result  uint16 // Address of the result byte at Done, 0 on success
+
+		// Port is the address of a feedback register, 0 for none. Writing
+		// bit 0 drives the IRQ line, bit 1 the NMI line.
+		Port uint16
+
+		// MaxCycles limits the run time of the suite, unlimited when 0.
+		MaxCycles uint64
+	}
+
+	// feedback is the Bus16 of a suite with feedback register.
+	feedback struct {
+		ram  *RAM
+		cpu  *CPU
+		port uint16
+	}
+)
+
+// The suites of Klaus Dormann's 6502 tests as assembled with the default
+// configuration. The tests are licensed under the GPL, so their images are
+// not embedded in this package; they have to be supplied by the caller,
+// e.g. by testrom:
+//
+//	suite := m6502.FunctionalTest
+//	suite.Image, _ = testrom.Load(testrom.FunctionalTest)
+//	err := m6502.Verify(suite)
+var (
+	FunctionalTest = Suite{
+		Name:    "6502_functional_test",
+		Origin:  0x0000,
+		Start:   0x0400,
+		Success: 0x3469,
+	}
+	DecimalTest = Suite{
+		Name:   "6502_decimal_test",
+		Origin: 0x0200,
+		Start:  0x0200,
+		Done:   0x024B,
+		Result: 0x000B,
+	}
+	InterruptTest = Suite{
+		Name:    "6502_interrupt_test",
+		Origin:  0x0000,
+		Start:   0x0400,
+		Success: 0x06F5,
+		Port:    0xBFFC,
+	}
+)
+
+// Verify runs the suite on a fresh CPU with flat 64K RAM. It returns nil
+// when the suite passed and an error otherwise.
+func Verify(s Suite) error {
+	if len(s.Image) == 0 {
+		return fmt.Errorf("m6502: %s: missing image", s.Name)
+	}
+	if int(s.Origin)+len(s.Image) > 0x10000 {
+		return fmt.Errorf("m6502: %s: image exceeds address space", s.Name)
+	}
+	ram := NewRAM(0x10000)
+	ram.Load(s.Origin, s.Image)
+
+	fb := &feedback{ram: ram, port: s.Port}
+	bus := Adapt16(ram)
+	if s.Port != 0 {
+		bus = Adapt16(fb)
+	}
+	cpu := New(bus)
+	cpu.PC(byte(s.Start), byte(s.Start>>8))
+	fb.cpu = cpu
+
+	total := uint64(0)
+	for {
+		pc := cpu.pc()
+		if s.Done != 0 && pc == s.Done {
+			if b := ram.Read(s.Result); b != 0 {
+				return fmt.Errorf("m6502: %s: failed, result %02X", s.Name, b)
+			}
+			return nil
+		}
+		cycles, err := cpu.Step()
+		if err != nil {
+			return fmt.Errorf("m6502: %s: %w", s.Name, err)
+		}
+		if total += uint64(cycles); s.MaxCycles > 0 && total > s.MaxCycles {
+			return fmt.Errorf("m6502: %s: cycle limit exceeded at %04X", s.Name, cpu.pc())
+		}
+		if cpu.pc() != pc {
+			continue
+		}
+		if s.Done != 0 || pc != s.Success {
+			return fmt.Errorf("m6502: %s: failed, trapped at %04X", s.Name, pc)
+		}
+		return nil
+	}
+}
+
+// SelfTest verifies all given suites and returns the joined errors
+// of the failed suites, or nil when all suites passed. Without suites,
+// it verifies FunctionalTest, DecimalTest and InterruptTest, failing
+// for each one without an Image.
+func SelfTest(suites ...Suite) error {
+	if len(suites) == 0 {
+		suites = []Suite{FunctionalTest, DecimalTest, InterruptTest}
+	}
+	errs := make([]error, 0, len(suites))
+	for _, s := range suites {
+		errs = append(errs, Verify(s))
+	}
+	return errors.Join(errs...)
+}
+
+func (cpu *CPU) pc() uint16 {
+	return uint16(cpu.pch)<<8 | uint16(cpu.pcl)
+}
+
+func (f *feedback) Read(addr uint16) byte {
+	return f.ram.Read(addr)
+}
+
+func (f *feedback) Write(addr uint16, db byte) {
+	if f.ram.Write(addr, db); addr != f.port {
+		return
+	}
+	if db&0x01 != 0 {
+		f.cpu.AssertIRQ()
+	} else {
+		f.cpu.ReleaseIRQ()
+	}
+	if db&0x02 != 0 {
+		f.cpu.AssertNMI()
+	} else {
+		f.cpu.ReleaseNMI()
+	}
+}
@@ -0,0 +1,131 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dtgorski/m6502/testrom"
+)
+
+func TestVerify(t *testing.T) {
+	image := []byte{
+		0xA2, 0x05, //       LDX #$05
+		0xCA,       // loop: DEX
+		0xD0, 0xFD, //       BNE loop
+		0xE0, 0x00, //       CPX #$00
+		0xD0, 0xFE, // fail: BNE fail
+		0x4C, 0x09, 0x02, // JMP * (success)
+	}
+	suite := Suite{Name: "test", Image: image, Origin: 0x0200, Start: 0x0200, Success: 0x0209}
+
+	if err := Verify(suite); err != nil {
+		t.Fatalf("unexpected, got %s", err)
+	}
+
+	suite.Success = 0x0000
+	if err := Verify(suite); err == nil || !strings.Contains(err.Error(), "trapped at 0209") {
+		t.Fatalf("unexpected, got %v", err)
+	}
+
+	suite.MaxCycles = 10
+	if err := Verify(suite); err == nil || !strings.Contains(err.Error(), "cycle limit") {
+		t.Fatalf("unexpected, got %v", err)
+	}
+
+	if err := Verify(Suite{Name: "empty"}); err == nil {
+		t.Fatal("unexpected")
+	}
+	if err := Verify(Suite{Image: []byte{0xEA, 0xEA}, Origin: 0xFFFF}); err == nil {
+		t.Fatal("unexpected")
+	}
+}
+
+func TestVerifyDone(t *testing.T) {
+	image := []byte{
+		0xA9, 0x00, // LDA #$00
+		0x85, 0x0B, // STA $0B
+		0x00, //       BRK (done)
+	}
+	suite := Suite{Name: "test", Image: image, Origin: 0x0200, Start: 0x0200, Done: 0x0204, Result: 0x000B}
+
+	if err := Verify(suite); err != nil {
+		t.Fatalf("unexpected, got %s", err)
+	}
+
+	image[1] = 0x01
+	if err := Verify(suite); err == nil || !strings.Contains(err.Error(), "result 01") {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
+
+func TestVerifyPort(t *testing.T) {
+	image := make([]byte, 0x10000)
+	copy(image[0x0200:], []byte{
+		0x58,       // CLI
+		0xA9, 0x01, //       LDA #$01
+		0x8D, 0xFC, 0xBF, // STA $BFFC
+		0x4C, 0x06, 0x02, // JMP * (fail)
+	})
+	copy(image[0x0300:], []byte{
+		0xA9, 0x00, //       LDA #$00
+		0x8D, 0xFC, 0xBF, // STA $BFFC
+		0x4C, 0x05, 0x03, // JMP * (success)
+	})
+	image[0xFFFE], image[0xFFFF] = 0x00, 0x03 // IRQ
+	suite := Suite{Name: "test", Image: image, Start: 0x0200, Success: 0x0305, Port: 0xBFFC}
+
+	if err := Verify(suite); err != nil {
+		t.Fatalf("unexpected, got %s", err)
+	}
+
+	suite.Port = 0x0000
+	if err := Verify(suite); err == nil || !strings.Contains(err.Error(), "trapped at 0206") {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
+
+func TestSelfTest(t *testing.T) {
+	pass := Suite{Name: "pass", Image: []byte{0x4C, 0x00, 0x04}, Origin: 0x0400, Start: 0x0400, Success: 0x0400}
+	fail := Suite{Name: "fail", Image: []byte{0x02}, Origin: 0x0400, Start: 0x0400}
+
+	if err := SelfTest(pass, pass); err != nil {
+		t.Fatalf("unexpected, got %s", err)
+	}
+	if err := SelfTest(pass, fail); err == nil || !strings.Contains(err.Error(), "fail") {
+		t.Fatalf("unexpected, got %v", err)
+	}
+
+	// Defaults to the Dormann suites, which have no image.
+	err := SelfTest()
+	for _, s := range []Suite{FunctionalTest, DecimalTest, InterruptTest} {
+		if err == nil || !strings.Contains(err.Error(), s.Name+": missing image") {
+			t.Fatalf("unexpected, got %v", err)
+		}
+	}
+}
+
+func TestSuites(t *testing.T) {
+	for _, tt := range []struct {
+		suite Suite
+		rom   testrom.ROM
+	}{
+		{FunctionalTest, testrom.FunctionalTest},
+		{DecimalTest, testrom.DecimalTest},
+		{InterruptTest, testrom.InterruptTest},
+	} {
+		t.Run(tt.suite.Name, func(t *testing.T) {
+			image, err := testrom.Locate(tt.rom)
+			if err != nil {
+				t.Skip(err)
+			}
+			suite := tt.suite
+			suite.Image = image
+
+			if err := Verify(suite); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "fmt"
+
+type (
+	// SentinelError is returned by Step() when the CPU is about to fetch an
+	// instruction from the sentinel address, see SetSentinel().
+	SentinelError struct {
+		PC uint16 // Sentinel address
+		S  byte   // Stack pointer, e.g. to match nested returns
+	}
+
+	sentinel struct {
+		addr uint16
+		on   bool
+	}
+)
+
+// DefaultSentinel is the sentinel address used by Call(), unless the
+// CPU has a sentinel set.
+const DefaultSentinel = 0xFFFF
+
+// SetSentinel reserves the address as sentinel: instead of fetching an
+// instruction from there, Step() returns a *SentinelError and leaves the
+// CPU untouched, pending interrupts included. Push the sentinel address
+// minus one as return address to regain control after the RTS of an
+// emulated routine, e.g. from an HLE hook. The CPU is not halted: Step()
+// returns the error until the PC has been changed. Passing false for on
+// removes the sentinel.
+func (cpu *CPU) SetSentinel(addr uint16, on bool) {
+	cpu.sentinel = sentinel{addr, on}
+}
+
+func (e *SentinelError) Error() string {
+	return fmt.Sprintf("m6502: %04X: sentinel reached", e.PC)
+}
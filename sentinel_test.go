@@ -0,0 +1,38 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSentinel(t *testing.T) {
+	bus := &memoryBus{}
+	bus.mem[0x0200] = 0x60 // RTS
+	bus.mem[0x01FE], bus.mem[0x01FF] = 0xFF, 0xEF
+
+	cpu := New(bus)
+	cpu.PC(0x00, 0x02)
+	cpu.s = 0xFD
+	cpu.SetSentinel(0xF000, true)
+
+	if _, err := cpu.Step(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		_, err := cpu.Step()
+		e := &SentinelError{}
+		if !errors.As(err, &e) || e.PC != 0xF000 || e.S != 0xFF {
+			t.Fatalf("unexpected, got %v", err)
+		}
+	}
+	if err := (&SentinelError{PC: 0xF000}); err.Error() != "m6502: F000: sentinel reached" {
+		t.Fatalf("unexpected, got %s", err)
+	}
+
+	cpu.SetSentinel(0xF000, false)
+	if _, err := cpu.Step(); err != nil {
+		t.Fatal(err)
+	}
+}
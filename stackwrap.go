@@ -0,0 +1,47 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "fmt"
+
+// StackWrap selects the handling of the stack pointer wrapping around.
+type StackWrap byte
+
+const (
+	// StackWrapIgnore lets the stack pointer wrap silently, the default.
+	StackWrapIgnore StackWrap = iota
+
+	// StackWrapReport reports a push with S at 0x00 (overflow) and a pull
+	// with S at 0xFF (underflow) as DiagStackWrap Diagnostic.
+	StackWrapReport
+
+	// StackWrapBreak reports like StackWrapReport and additionally returns
+	// the Diagnostic as error from Step(), after the instruction completed.
+	StackWrapBreak
+)
+
+// SetStackWrap selects the handling of the stack pointer wrapping past
+// 0x00 or 0xFF during pushes and pulls, incl. interrupt sequences. TXS is
+// not considered. Defaults to StackWrapIgnore.
+func (cpu *CPU) SetStackWrap(w StackWrap) {
+	cpu.stackWrap = w
+}
+
+// wrapped reports the stack pointer wrapping by a push or a pull of
+// the instruction at pc, or of the interrupt sequence at pc.
+func (cpu *CPU) wrapped(pc uint16, push bool) {
+	what, s := "underflow", cpu.s+1
+	if push {
+		what, s = "overflow", cpu.s-1
+	}
+	d := Diagnostic{
+		Kind: DiagStackWrap, PC: pc,
+		Text: fmt.Sprintf("stack %s: S wrapped from %02X to %02X", what, cpu.s, s),
+	}
+	if cpu.diagnostics != nil {
+		cpu.diagnostics(d)
+	}
+	if cpu.stackWrap == StackWrapBreak && cpu.stop == nil {
+		cpu.stop = d
+	}
+}
@@ -0,0 +1,71 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStackWrap(t *testing.T) {
+	// LDX #$01, TXS, PHA, PHA, PLA, PLA
+	prog := []byte{0xA2, 0x01, 0x9A, 0x48, 0x48, 0x68, 0x68}
+
+	for _, w := range []StackWrap{StackWrapIgnore, StackWrapReport, StackWrapBreak} {
+		bus := &memoryBus{}
+		copy(bus.mem[0x0200:], prog)
+		cpu := New(bus)
+		cpu.PC(0x00, 0x02)
+		cpu.SetStackWrap(w)
+
+		var diags []Diagnostic
+		var errs []error
+		cpu.SetDiagnostics(func(d Diagnostic) { diags = append(diags, d) })
+		for i := 0; i < 6; i++ {
+			if _, err := cpu.Step(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if cpu.s != 0x01 || cpu.pc() != 0x0207 {
+			t.Fatalf("unexpected, got %s", cpu)
+		}
+		if w == StackWrapIgnore {
+			if len(diags) != 0 || len(errs) != 0 {
+				t.Fatalf("unexpected, got %v %v", diags, errs)
+			}
+			continue
+		}
+		want := []Diagnostic{
+			{Kind: DiagStackWrap, PC: 0x0204, Text: "stack overflow: S wrapped from 00 to FF"},
+			{Kind: DiagStackWrap, PC: 0x0205, Text: "stack underflow: S wrapped from FF to 00"},
+		}
+		if len(diags) != 2 || diags[0] != want[0] || diags[1] != want[1] {
+			t.Fatalf("unexpected, got %+v", diags)
+		}
+		d := Diagnostic{}
+		if w == StackWrapBreak && (len(errs) != 2 || !errors.As(errs[0], &d) || d != want[0]) {
+			t.Fatalf("unexpected, got %v", errs)
+		}
+		if w == StackWrapReport && len(errs) != 0 {
+			t.Fatalf("unexpected, got %v", errs)
+		}
+	}
+}
+
+func TestStackWrapInterrupt(t *testing.T) {
+	cpu, _ := newTestCPU(NMOS6502, 0xEA)
+	cpu.SetStackWrap(StackWrapBreak)
+	cpu.s = 0x01
+
+	cpu.AssertNMI()
+	n, err := cpu.Step()
+	d := Diagnostic{}
+	if n != 0 || !errors.As(err, &d) || d.PC != 0x0200 || cpu.pc() != 0x8000 || cpu.s != 0xFE {
+		t.Fatalf("unexpected, got %d %v %s", n, err, cpu)
+	}
+
+	cpu.s = 0x00
+	if n, err := cpu.NMI(); n != 0 || !errors.As(err, &d) || d.PC != 0x8000 {
+		t.Fatalf("unexpected, got %d %v", n, err)
+	}
+}
@@ -0,0 +1,132 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+// Package symbols maps addresses to names, e.g. to show labels instead
+// of addresses in the output of the m6502 Disassembler. Tables are read
+// from and written to VICE label files ("ll"/"sl" monitor commands):
+//
+//	al C:0810 .start
+//	al C:D020 .border
+//
+// A Table is used by assigning it to Disassembler.Labels:
+//
+//	tab, err := symbols.ParseVICE(file)
+//	d := m6502.Disassembler{Labels: tab}
+package symbols
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Table maps addresses to names and vice versa. An address may have
+// several names, the name added first is its label.
+type Table struct {
+	names map[uint16][]string
+	addrs map[string]uint16
+}
+
+// New creates an empty Table.
+func New() *Table {
+	return &Table{names: map[uint16][]string{}, addrs: map[string]uint16{}}
+}
+
+// Add adds the name for the address. A name already present is moved.
+func (t *Table) Add(addr uint16, name string) {
+	if old, ok := t.addrs[name]; ok {
+		t.remove(old, name)
+	}
+	t.addrs[name] = addr
+	t.names[addr] = append(t.names[addr], name)
+}
+
+// Label returns the first name of the address.
+func (t *Table) Label(addr uint16) (string, bool) {
+	if names := t.names[addr]; len(names) > 0 {
+		return names[0], true
+	}
+	return "", false
+}
+
+// Names returns all names of the address, in order of addition.
+func (t *Table) Names(addr uint16) []string {
+	return append([]string(nil), t.names[addr]...)
+}
+
+// Addr returns the address of the name.
+func (t *Table) Addr(name string) (uint16, bool) {
+	addr, ok := t.addrs[name]
+	return addr, ok
+}
+
+// Len returns the number of names.
+func (t *Table) Len() int {
+	return len(t.addrs)
+}
+
+// ParseVICE reads a VICE label file. Empty lines and comments starting
+// with ";" are skipped, other commands than "al" (add label) are errors.
+// The memory space prefix of an address, e.g. "C:", is ignored.
+func ParseVICE(r io.Reader) (*Table, error) {
+	t := New()
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+		f := strings.Fields(line)
+		if len(f) != 3 || f[0] != "al" {
+			return nil, fmt.Errorf("symbols: line %d: invalid label %q", n, line)
+		}
+		hex := f[1]
+		if i := strings.IndexByte(hex, ':'); i >= 0 {
+			hex = hex[i+1:]
+		}
+		addr, err := strconv.ParseUint(strings.TrimPrefix(hex, "$"), 16, 16)
+		if err != nil {
+			return nil, fmt.Errorf("symbols: line %d: invalid address %q", n, f[1])
+		}
+		name := strings.TrimPrefix(f[2], ".")
+		if name == "" {
+			return nil, fmt.Errorf("symbols: line %d: empty name", n)
+		}
+		t.Add(uint16(addr), name)
+	}
+	return t, sc.Err()
+}
+
+// WriteVICE writes the Table as VICE label file, ordered by address.
+func (t *Table) WriteVICE(w io.Writer) error {
+	addrs := make([]int, 0, len(t.names))
+	for addr := range t.names {
+		addrs = append(addrs, int(addr))
+	}
+	sort.Ints(addrs)
+
+	bw := bufio.NewWriter(w)
+	for _, addr := range addrs {
+		for _, name := range t.names[uint16(addr)] {
+			fmt.Fprintf(bw, "al C:%04X .%s\n", addr, name)
+		}
+	}
+	return bw.Flush()
+}
+
+func (t *Table) remove(addr uint16, name string) {
+	names := t.names[addr]
+	for i, n := range names {
+		if n == name {
+			names = append(names[:i:i], names[i+1:]...)
+			break
+		}
+	}
+	if len(names) == 0 {
+		delete(t.names, addr)
+		return
+	}
+	t.names[addr] = names
+}
@@ -0,0 +1,85 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package symbols
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dtgorski/m6502"
+)
+
+const labels = `; generated
+al C:0810 .start
+al C:d020 .border
+al 0810 .entry
+
+al $00FB .ptr
+`
+
+func TestParseVICE(t *testing.T) {
+	tab, err := ParseVICE(strings.NewReader(labels))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tab.Len() != 4 {
+		t.Fatalf("unexpected, got %d", tab.Len())
+	}
+	if name, ok := tab.Label(0x0810); !ok || name != "start" {
+		t.Fatalf("unexpected, got %s", name)
+	}
+	if names := tab.Names(0x0810); len(names) != 2 || names[1] != "entry" {
+		t.Fatalf("unexpected, got %v", names)
+	}
+	if addr, ok := tab.Addr("border"); !ok || addr != 0xD020 {
+		t.Fatalf("unexpected, got %04X", addr)
+	}
+
+	for _, bad := range []string{"break 1000", "al C:XYZ .a", "al 1000 .", "al 1000"} {
+		if _, err := ParseVICE(strings.NewReader(bad)); err == nil || !strings.Contains(err.Error(), "line 1") {
+			t.Fatalf("unexpected, got %v", err)
+		}
+	}
+}
+
+func TestWriteVICE(t *testing.T) {
+	tab, _ := ParseVICE(strings.NewReader(labels))
+	tab.Add(0x1000, "ptr") // Moved
+
+	sb := &strings.Builder{}
+	if err := tab.WriteVICE(sb); err != nil {
+		t.Fatal(err)
+	}
+	want := "al C:0810 .start\nal C:0810 .entry\nal C:1000 .ptr\nal C:D020 .border\n"
+	if sb.String() != want {
+		t.Fatalf("unexpected, got\n%s", sb)
+	}
+
+	again, err := ParseVICE(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb2 := &strings.Builder{}
+	_ = again.WriteVICE(sb2)
+	if sb2.String() != want {
+		t.Fatalf("unexpected, got\n%s", sb2)
+	}
+}
+
+func TestDisassembler(t *testing.T) {
+	tab, _ := ParseVICE(strings.NewReader(labels))
+	d := m6502.Disassembler{Labels: tab}
+
+	code := []byte{
+		0xA5, 0xFB, //       LDA $FB
+		0x8D, 0x20, 0xD0, // STA $D020
+		0xD0, 0xF9, //       BNE $0810
+	}
+	want := "start:\n" +
+		"0810  A5 FB     LDA ptr\n" +
+		"0812  8D 20 D0  STA border\n" +
+		"0815  D0 F9     BNE start\n"
+	if s := d.Listing(0x0810, code); s != want {
+		t.Fatalf("unexpected, got\n%s", s)
+	}
+}
@@ -0,0 +1,163 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+// Package testrom locates, and if necessary downloads, the standard 6502
+// test binaries for benchmarks and self-tests. Binaries are searched in:
+//
+//  1. the directory named by the M6502_TESTROM_DIR environment variable,
+//  2. ./dev and ./testdata relative to the working directory,
+//  3. the user cache directory, e.g. ~/.cache/m6502/testrom.
+//
+// Load downloads a missing binary into the cache directory, unless the
+// M6502_TESTROM_OFFLINE environment variable is set. Only binaries with a
+// pinned checksum are downloaded, the download and the binaries found have
+// to match it. The cache directory is the only directory written to.
+//
+// Klaus Dormann's tests are licensed under the GPL, their binaries are not
+// part of this module and have to be supplied by the user or downloaded.
+package testrom
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ROM describes a test binary.
+type ROM struct {
+	Name   string // File name
+	URL    string // Download location
+	SHA256 string // Pinned checksum, hex encoded, required for downloads
+}
+
+// FunctionalTest is Klaus Dormann's 6502 functional test, assembled with
+// the default configuration, see m6502.FunctionalTest for its entry points.
+// No checksum is pinned yet, the binary has to be supplied locally.
+var FunctionalTest = ROM{
+	Name: "6502_functional_test.bin",
+}
+
+// InterruptTest is Klaus Dormann's 6502 interrupt test, assembled with
+// the default configuration, see m6502.InterruptTest. No checksum is
+// pinned yet, the binary has to be supplied locally.
+var InterruptTest = ROM{
+	Name: "6502_interrupt_test.bin",
+}
+
+// DecimalTest is Klaus Dormann's 6502 decimal test, assembled from
+// 6502_decimal_test.a65 with the default configuration, see
+// m6502.DecimalTest. There is no binary to download.
+var DecimalTest = ROM{
+	Name: "6502_decimal_test.bin",
+}
+
+var (
+	// ErrNotFound is returned by Locate() when the binary is not available locally.
+	ErrNotFound = errors.New("testrom: not found")
+
+	// ErrUnpinned is returned by Load() instead of downloading a binary
+	// without pinned checksum.
+	ErrUnpinned = errors.New("testrom: no pinned checksum")
+)
+
+var client = &http.Client{Timeout: time.Minute}
+
+// Locate returns the content of the locally available binary, without
+// downloading. It returns ErrNotFound when the binary is not available.
+func Locate(rom ROM) ([]byte, error) {
+	for _, dir := range dirs() {
+		b, err := os.ReadFile(filepath.Join(dir, rom.Name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("testrom: %w", err)
+		}
+		return b, verify(rom, b)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, rom.Name)
+}
+
+// Load returns the content of the binary, downloaded into
+// the cache directory when not available locally.
+func Load(rom ROM) ([]byte, error) {
+	b, err := Locate(rom)
+	if !errors.Is(err, ErrNotFound) || os.Getenv("M6502_TESTROM_OFFLINE") != "" {
+		return b, err
+	}
+	if rom.SHA256 == "" {
+		return nil, fmt.Errorf("%w: %s", ErrUnpinned, rom.Name)
+	}
+	dir, err := cacheDir()
+	if err != nil {
+		return nil, err
+	}
+	if b, err = download(rom.URL); err != nil {
+		return nil, err
+	}
+	if err = verify(rom, b); err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(dir, 0o755); err == nil {
+		err = os.WriteFile(filepath.Join(dir, rom.Name), b, 0o644)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("testrom: %w", err)
+	}
+	return b, nil
+}
+
+// verify checks b against the pinned checksum of the ROM, if any.
+func verify(rom ROM, b []byte) error {
+	if rom.SHA256 == "" {
+		return nil
+	}
+	sum := sha256.Sum256(b)
+	if got, want := hex.EncodeToString(sum[:]), strings.ToLower(rom.SHA256); got != want {
+		return fmt.Errorf("testrom: %s: checksum mismatch, want %s, got %s", rom.Name, want, got)
+	}
+	return nil
+}
+
+func download(url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("testrom: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("testrom: %s: %s", url, resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("testrom: %w", err)
+	}
+	return b, nil
+}
+
+func dirs() []string {
+	list := []string{}
+	if dir := os.Getenv("M6502_TESTROM_DIR"); dir != "" {
+		list = append(list, dir)
+	}
+	list = append(list, "dev", "testdata")
+	if dir, err := cacheDir(); err == nil {
+		list = append(list, dir)
+	}
+	return list
+}
+
+func cacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("testrom: %w", err)
+	}
+	return filepath.Join(dir, "m6502", "testrom"), nil
+}
@@ -0,0 +1,118 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package testrom
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func isolate(t *testing.T) string {
+	dir := t.TempDir()
+	t.Setenv("M6502_TESTROM_DIR", "")
+	t.Setenv("M6502_TESTROM_OFFLINE", "")
+	t.Setenv("XDG_CACHE_HOME", dir)
+	t.Setenv("HOME", dir)
+	return dir
+}
+
+func TestLocate(t *testing.T) {
+	isolate(t)
+	dir := t.TempDir()
+	t.Setenv("M6502_TESTROM_DIR", dir)
+
+	rom := ROM{Name: "x.bin"}
+	if _, err := Locate(rom); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "x.bin"), []byte{0x4C}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := Locate(rom); err != nil || len(b) != 1 {
+		t.Fatalf("unexpected, got %v", err)
+	}
+
+	// Pinned checksum, the directory is left as is.
+	rom.SHA256 = "00"
+	if _, err := Locate(rom); err == nil {
+		t.Fatal("unexpected, got nil")
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 1 {
+		t.Fatalf("unexpected, got %d files", len(files))
+	}
+}
+
+func TestLoad(t *testing.T) {
+	isolate(t)
+	data := []byte{0xA9, 0x00}
+	sum := sha256.Sum256(data)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(data)
+	}))
+	defer srv.Close()
+
+	rom := ROM{Name: "y.bin", URL: srv.URL, SHA256: "00"}
+	if _, err := Load(rom); err == nil {
+		t.Fatal("unexpected, got nil")
+	}
+	rom.SHA256 = hex.EncodeToString(sum[:])
+	if b, err := Load(rom); err != nil || string(b) != string(data) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+
+	// Cached, the server is not needed anymore.
+	srv.Close()
+	if b, err := Load(rom); err != nil || string(b) != string(data) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
+
+func TestLoadOffline(t *testing.T) {
+	isolate(t)
+	t.Setenv("M6502_TESTROM_OFFLINE", "1")
+
+	if _, err := Load(ROM{Name: "z.bin", URL: "http://invalid"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	t.Setenv("M6502_TESTROM_OFFLINE", "")
+	if _, err := Load(ROM{Name: "z.bin", URL: srv.URL}); !errors.Is(err, ErrUnpinned) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	_, err := Load(ROM{Name: "z.bin", URL: srv.URL, SHA256: "00"})
+	if err == nil || err.Error() != fmt.Sprintf("testrom: %s: 404 Not Found", srv.URL) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
+
+// pinned matches a download location referring to a fixed commit, not to
+// a branch, so that the pinned checksum stays valid.
+var pinned = regexp.MustCompile(`^https://github\.com/[^/]+/[^/]+/raw/[0-9a-f]{40}/`)
+
+func TestPinned(t *testing.T) {
+	for _, rom := range []ROM{FunctionalTest, InterruptTest, DecimalTest} {
+		if rom.URL == "" {
+			continue
+		}
+		if b, err := hex.DecodeString(rom.SHA256); err != nil || len(b) != sha256.Size {
+			t.Errorf("unexpected, got checksum %q for %s", rom.SHA256, rom.Name)
+		}
+		if !pinned.MatchString(rom.URL) {
+			t.Errorf("unexpected, got URL %s for %s", rom.URL, rom.Name)
+		}
+	}
+	if pinned.MatchString("https://github.com/Klaus2m5/6502_65C02_functional_tests/raw/master/x.bin") {
+		t.Fatal("unexpected")
+	}
+}
@@ -0,0 +1,144 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+)
+
+type (
+	// Trace is a snapshot of the CPU state taken after the op code
+	// of an instruction has been fetched, but before it is performed.
+	Trace struct {
+		CPU    string // Name of the CPU, see SetName()
+		PC     uint16 // Address of the op code
+		Opcode byte   // Op code of the instruction
+		Op     Op     // Instruction identifier of the op code
+		A      byte   // Accumulator
+		X      byte   // X register
+		Y      byte   // Y register
+		S      byte   // Stack pointer
+		P      byte   // Processor flags
+		Cycles uint64 // Cycles performed since Reset(), incl. its own 7
+	}
+
+	// Tracer receives a Trace for each performed instruction.
+	Tracer func(Trace)
+
+	// Reservoir keeps a uniformly distributed random sample of fixed
+	// size from a trace stream of unknown length (reservoir sampling).
+	// This yields representative profiles of arbitrarily long runs.
+	Reservoir struct {
+		size    int
+		seen    uint64
+		rand    *rand.Rand
+		samples []Trace
+	}
+)
+
+// SetTracer registers a Tracer receiving a Trace for each instruction. The
+// Tracer is a Hook with PriorityTracer, replacing a previously set Tracer.
+// Passing nil removes the Tracer.
+func (cpu *CPU) SetTracer(t Tracer) {
+	if cpu.untrace != nil {
+		cpu.untrace()
+		cpu.untrace = nil
+	}
+	if t != nil {
+		cpu.untrace = cpu.AddHook(PriorityTracer, func(_ *CPU, tr Trace) bool {
+			t(tr)
+			return false
+		})
+	}
+}
+
+// EveryN returns a Tracer forwarding every n-th Trace to next.
+func EveryN(n uint64, next Tracer) Tracer {
+	i := uint64(0)
+	return func(t Trace) {
+		if i++; i >= n {
+			i = 0
+			next(t)
+		}
+	}
+}
+
+// PerSecond returns a Tracer forwarding approximately k traces per
+// second of wall time to next, evenly spaced, dropping the others.
+func PerSecond(k int, next Tracer) Tracer {
+	return perSecond(k, next, time.Now)
+}
+
+func perSecond(k int, next Tracer, now func() time.Time) Tracer {
+	interval := time.Second / time.Duration(max(k, 1))
+	due := time.Time{}
+	return func(t Trace) {
+		if n := now(); !n.Before(due) {
+			due = n.Add(interval)
+			next(t)
+		}
+	}
+}
+
+// Nintendulator returns a Tracer writing a line per instruction to w in the
+// format of Nintendulator, also emitted by Mesen and the nestest.log, to diff
+// runs against established emulators. Undocumented op codes are marked by
+// "*". The operand bytes are read from the bus of the CPU. Errors of w are
+// ignored, consider a buffered writer.
+//
+//	C000  4C F5 C5  JMP $C5F5                       A:00 X:00 Y:00 P:24 SP:FD CYC:7
+func Nintendulator(cpu *CPU, w io.Writer) Tracer {
+	d := cpu.Disassembler()
+	d.Illegal = true
+	return func(t Trace) {
+		i := DecodeVariant(d.Variant, t.Opcode)
+		code := []byte{t.Opcode, 0, 0}[:max(i.Size(), 1)]
+		for n := range code[1:] {
+			addr := t.PC + uint16(n) + 1
+			code[n+1] = cpu.bus.Read(byte(addr), byte(addr>>8))
+		}
+		asm, n := d.Decode(t.PC, code)
+		mark := ' '
+		if i.Illegal && i.Op != OpInvalid {
+			mark = '*'
+		}
+		fmt.Fprintf(w, "%04X  %-8s %c%-31s A:%02X X:%02X Y:%02X P:%02X SP:%02X CYC:%d\n",
+			t.PC, fmt.Sprintf("% X", code[:n]), mark, asm, t.A, t.X, t.Y, t.P, t.S, t.Cycles,
+		)
+	}
+}
+
+// NewReservoir creates a Reservoir keeping at most size traces. The seed
+// makes the choice of samples reproducible.
+func NewReservoir(size int, seed int64) *Reservoir {
+	return &Reservoir{
+		size:    size,
+		rand:    rand.New(rand.NewSource(seed)),
+		samples: make([]Trace, 0, size),
+	}
+}
+
+// Trace offers a trace to the Reservoir. The method value can be
+// used as a Tracer, e.g. cpu.SetTracer(r.Trace).
+func (r *Reservoir) Trace(t Trace) {
+	if r.seen++; len(r.samples) < r.size {
+		r.samples = append(r.samples, t)
+		return
+	}
+	if i := r.rand.Int63n(int64(r.seen)); i < int64(r.size) {
+		r.samples[i] = t
+	}
+}
+
+// Seen returns the number of traces offered to the Reservoir.
+func (r *Reservoir) Seen() uint64 {
+	return r.seen
+}
+
+// Samples returns the traces currently kept in the Reservoir.
+func (r *Reservoir) Samples() []Trace {
+	return r.samples
+}
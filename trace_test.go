@@ -0,0 +1,132 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetTracer(t *testing.T) {
+	bus := &memoryBus{}
+	bus.mem[0x0000] = 0xA9 // LDA #$80
+	bus.mem[0x0001] = 0x80
+	bus.mem[0x0002] = 0xEA // NOP
+
+	cpu := New(bus)
+
+	traces := []Trace{}
+	cpu.SetTracer(func(t Trace) { traces = append(traces, t) })
+
+	_, _ = cpu.Step()
+	_, _ = cpu.Step()
+
+	if len(traces) != 2 {
+		t.Fatalf("unexpected, got %d", len(traces))
+	}
+	if traces[0].PC != 0x0000 || traces[0].Opcode != 0xA9 || traces[0].A != 0x00 || traces[0].S != 0xFF {
+		t.Errorf("unexpected, got %+v", traces[0])
+	}
+	if traces[1].PC != 0x0002 || traces[1].Opcode != 0xEA || traces[1].A != 0x80 || traces[1].P != 0xA0 {
+		t.Errorf("unexpected, got %+v", traces[1])
+	}
+
+	cpu.SetTracer(nil)
+	_, _ = cpu.Step()
+
+	if len(traces) != 2 {
+		t.Fatalf("unexpected, got %d", len(traces))
+	}
+}
+
+func TestEveryN(t *testing.T) {
+	pcs := []uint16{}
+	tracer := EveryN(3, func(t Trace) { pcs = append(pcs, t.PC) })
+
+	for pc := uint16(1); pc <= 10; pc++ {
+		tracer(Trace{PC: pc})
+	}
+	if len(pcs) != 3 || pcs[0] != 3 || pcs[1] != 6 || pcs[2] != 9 {
+		t.Fatalf("unexpected, got %v", pcs)
+	}
+}
+
+func TestPerSecond(t *testing.T) {
+	now := time.Unix(0, 0)
+	clock := func() time.Time { return now }
+
+	n := 0
+	tracer := perSecond(4, func(Trace) { n++ }, clock)
+
+	for i := 0; i < 100; i++ {
+		tracer(Trace{})
+		now = now.Add(10 * time.Millisecond) // 1 second total
+	}
+	if n != 4 {
+		t.Fatalf("unexpected, got %d", n)
+	}
+}
+
+func TestReservoir(t *testing.T) {
+	r := NewReservoir(10, 1)
+
+	for pc := uint16(0); pc < 1000; pc++ {
+		r.Trace(Trace{PC: pc})
+	}
+	if r.Seen() != 1000 || len(r.Samples()) != 10 {
+		t.Fatalf("unexpected, got %d/%d", r.Seen(), len(r.Samples()))
+	}
+
+	late := 0
+	for _, s := range r.Samples() {
+		if s.PC >= 10 {
+			late++
+		}
+	}
+	if late == 0 {
+		t.Fatal("unexpected, reservoir never replaced a sample")
+	}
+
+	q := NewReservoir(10, 1)
+	for pc := uint16(0); pc < 1000; pc++ {
+		q.Trace(Trace{PC: pc})
+	}
+	for i := range q.Samples() {
+		if q.Samples()[i] != r.Samples()[i] {
+			t.Fatal("unexpected, same seed must yield same samples")
+		}
+	}
+}
+
+func TestNintendulator(t *testing.T) {
+	bus := &memoryBus{}
+	bus.mem[0xFFFC], bus.mem[0xFFFD] = 0x00, 0xC0
+	copy(bus.mem[0xC000:], []byte{
+		0x4C, 0xF5, 0xC5, // JMP $C5F5
+	})
+	copy(bus.mem[0xC5F5:], []byte{
+		0xA2, 0x00, // LDX #$00
+		0x1A,       // NOP (undocumented)
+		0x86, 0x00, // STX $00
+	})
+	cpu := New(bus)
+	cpu.s = 0xFD
+	cpu.p = flagI
+
+	sb := &strings.Builder{}
+	cpu.SetTracer(Nintendulator(cpu, sb))
+	for i := 0; i < 4; i++ {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := "" +
+		"C000  4C F5 C5  JMP $C5F5                       A:00 X:00 Y:00 P:24 SP:FD CYC:7\n" +
+		"C5F5  A2 00     LDX #$00                        A:00 X:00 Y:00 P:24 SP:FD CYC:10\n" +
+		"C5F7  1A       *NOP                             A:00 X:00 Y:00 P:26 SP:FD CYC:12\n" +
+		"C5F8  86 00     STX $00                         A:00 X:00 Y:00 P:26 SP:FD CYC:14\n"
+	if sb.String() != want {
+		t.Fatalf("unexpected, got\n%s", sb)
+	}
+}
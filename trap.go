@@ -0,0 +1,151 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// TrapHandler serves an op code or an address as host service, see
+	// SetTrap() and SetAddrTrap(). A returned error ends the Step() with
+	// a *TrapError.
+	TrapHandler func(t *Trap) error
+
+	// Trap is the state passed to a TrapHandler. The registers and the PC
+	// may be modified, they are written back when the handler returns.
+	Trap struct {
+		RegisterSet
+		PC     uint16 // Address to continue at, past the op code when trapped by op code
+		At     uint16 // Address of the op code respectively the trapped address
+		Opcode byte   // Trapped op code, 0x00 when trapped by address
+		Cycles byte   // Cycles of the trap: 2 by op code, incl. its fetch, 0 by address
+
+		cpu *CPU
+	}
+
+	// TrapError is returned by Step() when a TrapHandler failed. The
+	// CPU is not halted, the registers are updated by the handler.
+	TrapError struct {
+		PC     uint16 // Address of the op code respectively the trapped address
+		Opcode byte   // Trapped op code
+		Addr   bool   // Trapped by address
+		Err    error  // Error of the handler
+	}
+)
+
+// SetTrap maps the op code to a Go handler, replacing its instruction,
+// e.g. an otherwise unused or JAM op code like 0x02, to let emulated
+// programs call host services: print a character, read a file, exit.
+// The handler replaces a previously set one, passing nil removes it.
+//
+//	cpu.SetTrap(0x02, func(t *m6502.Trap) error {
+//		fmt.Printf("%c", t.A)
+//		return nil
+//	})
+func (cpu *CPU) SetTrap(op byte, h TrapHandler) {
+	if cpu.traps == nil {
+		cpu.traps = &[0x100]TrapHandler{}
+	}
+	cpu.traps[op] = h
+}
+
+// SetAddrTrap calls the handler when the execution reaches the address,
+// instead of performing the instruction located there, e.g. to stub out
+// KERNAL or BIOS routines without emulating the ROM. Pending interrupts
+// are serviced first. The handler replaces a previously set one, passing
+// nil removes it.
+//
+//	cpu.SetAddrTrap(0xFFD2, func(t *m6502.Trap) error { // CHROUT
+//		fmt.Printf("%c", t.A)
+//		t.RTS()
+//		return nil
+//	})
+func (cpu *CPU) SetAddrTrap(addr uint16, h TrapHandler) {
+	if h == nil {
+		delete(cpu.addrTraps, addr)
+		return
+	}
+	if cpu.addrTraps == nil {
+		cpu.addrTraps = map[uint16]TrapHandler{}
+	}
+	cpu.addrTraps[addr] = h
+}
+
+// RTS returns from the trapped subroutine as RTS does: the return address
+// is pulled from the stack, the PC set past it, and the Cycles set to 6.
+func (t *Trap) RTS() {
+	t.S++
+	l := t.Read(0x0100 | uint16(t.S))
+	t.S++
+	h := t.Read(0x0100 | uint16(t.S))
+	t.PC, t.Cycles = (uint16(h)<<8|uint16(l))+1, 6
+}
+
+// Read reads a byte from the bus, without costing cycles.
+func (t *Trap) Read(addr uint16) byte {
+	return t.cpu.bus.Read(byte(addr), byte(addr>>8))
+}
+
+// Write writes a byte to the bus, without costing cycles.
+func (t *Trap) Write(addr uint16, db byte) {
+	t.cpu.bus.Write(byte(addr), byte(addr>>8), db)
+}
+
+// ReadString reads a zero-terminated string of at most 0x100 bytes at addr.
+func (t *Trap) ReadString(addr uint16) string {
+	sb := strings.Builder{}
+	for i := uint16(0); i < 0x100; i++ {
+		b := t.Read(addr + i)
+		if b == 0x00 {
+			break
+		}
+		sb.WriteByte(b)
+	}
+	return sb.String()
+}
+
+// trapAddr performs the trap of the address of the instruction.
+func (cpu *CPU) trapAddr(h TrapHandler) error {
+	cpu.kind = 0
+	if cpu.trap(h, true); cpu.fail != nil {
+		return cpu.fail
+	}
+	if cpu.acc == AccuracyCycle {
+		cpu.poll(max(cpu.cycles, 1), cpu.p.has(flagI))
+	}
+	return nil
+}
+
+// trap calls the handler, addr when trapped by address.
+func (cpu *CPU) trap(h TrapHandler, addr bool) {
+	t := &Trap{
+		RegisterSet: cpu.Registers(), PC: cpu.pc(), At: cpu.at,
+		Opcode: cpu.op, Cycles: when(addr, 0, 2), cpu: cpu,
+	}
+	err := h(t)
+
+	cpu.a, cpu.x, cpu.y, cpu.s = t.A, t.X, t.Y, t.S
+	cpu.p = flag(t.P) & ^(flagU | flagB)
+	cpu.setPC(byte(t.PC), byte(t.PC>>8))
+	cpu.cost(max(t.Cycles, byte(cpu.cycles)) - byte(cpu.cycles))
+	if cpu.calls.on {
+		cpu.calls.unwind(cpu.s)
+	}
+
+	if err != nil {
+		cpu.fail = &TrapError{PC: cpu.at, Opcode: cpu.op, Addr: addr, Err: err}
+	}
+}
+
+func (e *TrapError) Error() string {
+	if e.Addr {
+		return fmt.Sprintf("m6502: %04X: trap: %v", e.PC, e.Err)
+	}
+	return fmt.Sprintf("m6502: %04X: trap %02X: %v", e.PC, e.Opcode, e.Err)
+}
+
+func (e *TrapError) Unwrap() error {
+	return e.Err
+}
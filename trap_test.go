@@ -0,0 +1,132 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type exitCode byte
+
+func (e exitCode) Error() string { return "exit" }
+
+func TestTrap(t *testing.T) {
+	bus := &memoryBus{}
+	copy(bus.mem[0x0200:], []byte{
+		0xA9, 0x41, // LDA #$41
+		0x02,       // putchar
+		0xA2, 0x00, // LDX #$00
+		0xA0, 0x03, // LDY #$03
+		0x12,       // puts ($0300)
+		0xA9, 0x07, // LDA #$07
+		0x22, // exit
+		0xEA, // NOP
+	})
+	copy(bus.mem[0x0300:], "bc\x00")
+
+	cpu := New(bus)
+	cpu.PC(0x00, 0x02)
+
+	out := &strings.Builder{}
+	cpu.SetTrap(0x02, func(t *Trap) error {
+		out.WriteByte(t.A)
+		return nil
+	})
+	cpu.SetTrap(0x12, func(t *Trap) error {
+		out.WriteString(t.ReadString(uint16(t.Y)<<8 | uint16(t.X)))
+		t.Write(0x0010, 0xFF)
+		t.A, t.P, t.Cycles = byte(out.Len()), 0xFF, 10
+		return nil
+	})
+	cpu.SetTrap(0x22, func(t *Trap) error {
+		return exitCode(t.A)
+	})
+
+	cycles := []uint{2, 2, 2, 2, 10, 2}
+	for i, c := range cycles {
+		if n, err := cpu.Step(); err != nil || n != c {
+			t.Fatalf("unexpected, got %d %v in step %d", n, err, i)
+		}
+	}
+	if out.String() != "Abc" || cpu.a != 0x07 || bus.mem[0x0010] != 0xFF || cpu.p != flagV|flagD|flagI|flagC {
+		t.Fatalf("unexpected, got %q %s", out, cpu)
+	}
+
+	_, err := cpu.Step()
+	var code exitCode
+	e := &TrapError{}
+	if !errors.As(err, &code) || code != 0x07 || !errors.As(err, &e) || e.PC != 0x020A {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	if err.Error() != "m6502: 020A: trap 22: exit" || cpu.pc() != 0x020B || cpu.Halted() {
+		t.Fatalf("unexpected, got %q at %04X", err, cpu.pc())
+	}
+
+	cpu.SetTrap(0x22, func(t *Trap) error {
+		t.PC = t.At
+		return nil
+	})
+	cpu.PC(0x0A, 0x02)
+	if _, err := cpu.Step(); err != nil || cpu.pc() != 0x020A {
+		t.Fatalf("unexpected, got %v at %04X", err, cpu.pc())
+	}
+	cpu.SetTrap(0x22, nil)
+	if _, err := cpu.Step(); !errors.Is(err, ErrHalted) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
+
+func TestAddrTrap(t *testing.T) {
+	bus := &memoryBus{}
+	copy(bus.mem[0x0200:], []byte{
+		0xA9, 0x41, // LDA #$41
+		0x20, 0xD2, 0xFF, // JSR $FFD2
+		0x20, 0xE4, 0xFF, // JSR $FFE4
+		0x02, // HLT
+	})
+	cpu := New(bus)
+	cpu.PC(0x00, 0x02)
+	cpu.SetStrictReturns(true)
+
+	out := &strings.Builder{}
+	cpu.SetAddrTrap(0xFFD2, func(t *Trap) error { // CHROUT
+		out.WriteByte(t.A)
+		t.RTS()
+		return nil
+	})
+	cpu.SetAddrTrap(0xFFE4, func(t *Trap) error { // GETIN
+		return errors.New("no input")
+	})
+
+	cycles := []uint{2, 6, 6}
+	for i, c := range cycles {
+		if n, err := cpu.Step(); err != nil || n != c {
+			t.Fatalf("unexpected, got %d %v in step %d", n, err, i)
+		}
+	}
+	if out.String() != "A" || cpu.pc() != 0x0205 || cpu.s != 0xFF || len(cpu.CallStack()) != 0 {
+		t.Fatalf("unexpected, got %q %s", out, cpu)
+	}
+
+	_, _ = cpu.Step()
+	n, err := cpu.Step()
+	e := &TrapError{}
+	if n != 0 || !errors.As(err, &e) || !e.Addr || err.Error() != "m6502: FFE4: trap: no input" {
+		t.Fatalf("unexpected, got %d %v", n, err)
+	}
+
+	cpu.SetAddrTrap(0xFFE4, func(t *Trap) error {
+		t.A, t.PC = 0x0D, 0x0208
+		return nil
+	})
+	if n, err := cpu.Step(); err != nil || n != 0 || cpu.a != 0x0D {
+		t.Fatalf("unexpected, got %d %v", n, err)
+	}
+	cpu.SetAddrTrap(0xFFE4, nil)
+	cpu.SetAddrTrap(0x0208, nil)
+	if _, err := cpu.Step(); !errors.Is(err, ErrHalted) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+// DefaultMagic is the constant of the unstable NMOS instructions ANE and
+// LXA used by New(), the value found on most chips.
+const DefaultMagic = 0xEE
+
+// SetMagic sets the constant of the unstable NMOS instructions ANE ($8B)
+// and LXA ($AB), which is ORed into A before the operation:
+//
+//	ANE  A = (A | magic) & X & oper
+//	LXA  A, X = (A | magic) & oper
+//
+// The constant depends on the chip and its temperature, commonly 0xEE,
+// 0xEF, 0xFE or 0xFF. Pick the one a specific program expects.
+func (cpu *CPU) SetMagic(c byte) {
+	cpu.magic = c
+}
+
+// Magic returns the constant of ANE and LXA, see SetMagic().
+func (cpu *CPU) Magic() byte {
+	return cpu.magic
+}
+
+// SetStableStores simplifies the unstable NMOS stores SHA ($93, $9F),
+// SHX ($9E), SHY ($9C) and TAS ($9B). These store a register value ANDed
+// with the high byte of the base address plus one, TAS sets S = A & X:
+//
+//	SHA  A & X & (H+1)   SHX  X & (H+1)   SHY  Y & (H+1)   TAS  S & (H+1)
+//
+// When the indexing crosses a page, the real chips replace the high byte
+// of the target address by the value stored, which some programs rely on.
+// Stable stores write to the target address regardless. Defaults to false.
+func (cpu *CPU) SetStableStores(on bool) {
+	cpu.stable = on
+}
@@ -0,0 +1,116 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "testing"
+
+func TestMagic(t *testing.T) {
+	tests := []struct {
+		variant Variant
+		op      byte
+		magic   byte
+		a, x    byte
+		oper    byte
+		wantA   byte
+		wantX   byte
+		flags   flag
+	}{
+		{NMOS6502, 0x8B, 0xEE, 0x01, 0xFF, 0xFF, 0xEF, 0xFF, flagN},
+		{NMOS6502, 0x8B, 0xFF, 0x00, 0x0F, 0x3C, 0x0C, 0x0F, 0},
+		{NMOS6502, 0x8B, 0xEE, 0x00, 0x11, 0x11, 0x00, 0x11, flagZ},
+		{Ricoh2A03, 0x8B, 0xEF, 0x00, 0xFF, 0x10, 0x00, 0xFF, flagZ},
+		{NMOS6502, 0xAB, 0xEE, 0x00, 0x55, 0xFF, 0xEE, 0xEE, flagN},
+		{NMOS6502, 0xAB, 0xFF, 0x00, 0x55, 0x0F, 0x0F, 0x0F, 0},
+		{NMOS6502, 0xAB, 0x00, 0x00, 0x55, 0xFF, 0x00, 0x00, flagZ},
+	}
+	for i, tt := range tests {
+		bus := &memoryBus{}
+		copy(bus.mem[0x0200:], []byte{tt.op, tt.oper})
+		cpu := New(bus)
+		cpu.SetVariant(tt.variant)
+		cpu.SetMagic(tt.magic)
+		cpu.PC(0x00, 0x02)
+		cpu.a, cpu.x, cpu.p = tt.a, tt.x, 0
+
+		cycles, err := cpu.Step()
+		if err != nil || cycles != 2 || cpu.pc() != 0x0202 {
+			t.Fatalf("unexpected, got %d %v in test %d", cycles, err, i)
+		}
+		if cpu.a != tt.wantA || cpu.x != tt.wantX || cpu.p != tt.flags {
+			t.Errorf("unexpected, got %s in test %d", cpu, i)
+		}
+	}
+}
+
+func TestUnstableCMOS(t *testing.T) {
+	if cpu := New(&memoryBus{}); cpu.Magic() != DefaultMagic {
+		t.Fatalf("unexpected, got %02X", cpu.Magic())
+	}
+	// Reserved NOPs of one byte, STZ instead of SHY and SHX.
+	for _, op := range []byte{0x8B, 0xAB, 0x93, 0x9B, 0x9C, 0x9E} {
+		bus := &memoryBus{}
+		copy(bus.mem[0x0200:], []byte{op, 0x10, 0x00})
+		bus.mem[0x0010] = 0xFF
+		cpu := New(bus, WithVariant(CMOS65C02))
+		cpu.PC(0x00, 0x02)
+		cpu.a, cpu.x = 0xFF, 0x00
+
+		cycles, err := cpu.Step()
+		if err != nil || cpu.a != 0xFF || cpu.x != 0x00 {
+			t.Fatalf("unexpected, got %v %s for %02X", err, cpu, op)
+		}
+		if i := DecodeVariant(CMOS65C02, op); cpu.pc() != 0x0200+uint16(i.Size()) || cycles != uint(i.Cycles) {
+			t.Fatalf("unexpected, got %04X %d for %02X", cpu.pc(), cycles, op)
+		}
+		if want := when(op&0x0F == 0x0C || op&0x0F == 0x0E, 0x00, 0xFF); bus.mem[0x0010] != want {
+			t.Fatalf("unexpected, got %02X for %02X", bus.mem[0x0010], op)
+		}
+	}
+}
+
+func TestUnstableStores(t *testing.T) {
+	tests := []struct {
+		code   []byte
+		stable bool
+		addr   uint16
+		want   byte
+		cycles uint
+	}{
+		{[]byte{0x9F, 0x10, 0x12}, false, 0x1211, 0x12, 5}, // SHA oper,Y
+		{[]byte{0x9F, 0xFF, 0x12}, false, 0x1200, 0x12, 5}, // Corrupted
+		{[]byte{0x9F, 0xFF, 0x12}, true, 0x1300, 0x12, 5},
+		{[]byte{0x93, 0x80}, false, 0x1211, 0x12, 6}, // SHA (oper),Y
+		{[]byte{0x93, 0x82}, false, 0x1200, 0x12, 6},
+		{[]byte{0x9E, 0x10, 0x34}, false, 0x3411, 0x34, 5}, // SHX oper,Y
+		{[]byte{0x9E, 0xFF, 0x34}, false, 0x3400, 0x34, 5},
+		{[]byte{0x9C, 0x10, 0x56}, false, 0x5612, 0x51, 5}, // SHY oper,X
+		{[]byte{0x9C, 0xFE, 0x56}, false, 0x5100, 0x51, 5},
+		{[]byte{0x9C, 0xFE, 0x56}, true, 0x5700, 0x51, 5},
+		{[]byte{0x9B, 0x10, 0x12}, false, 0x1211, 0x12, 5}, // TAS oper,Y
+		{[]byte{0x9B, 0xFF, 0x12}, false, 0x1200, 0x12, 5},
+	}
+	for i, tt := range tests {
+		bus := &memoryBus{}
+		copy(bus.mem[0x0200:], tt.code)
+		copy(bus.mem[0x0080:], []byte{0x10, 0x12, 0xFF, 0x12})
+
+		cpu := New(bus)
+		cpu.SetStableStores(tt.stable)
+		cpu.PC(0x00, 0x02)
+		cpu.a, cpu.x, cpu.y = 0x1E, 0x36, 0x01
+		if tt.code[0] == 0x9C {
+			cpu.x, cpu.y = 0x02, 0xF1
+		}
+
+		cycles, err := cpu.Step()
+		if err != nil || cycles != tt.cycles {
+			t.Fatalf("unexpected, got %d %v in test %d", cycles, err, i)
+		}
+		if bus.mem[tt.addr] != tt.want {
+			t.Errorf("unexpected, got %02X at %04X in test %d", bus.mem[tt.addr], tt.addr, i)
+		}
+		if tt.code[0] == 0x9B && cpu.s != 0x16 {
+			t.Errorf("unexpected, got S=%02X in test %d", cpu.s, i)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+// Variant selects the processor model to emulate. The variant determines
+// behavioral differences between the models, e.g. the NMOS JMP (indirect)
+// page wrap bug, which has been fixed in the CMOS processors.
+type Variant byte
+
+// Processor models.
+const (
+	NMOS6502  Variant = iota // Original MOS 6502, default
+	CMOS65C02                // WDC/Rockwell 65C02
+	Ricoh2A03                // NES CPU, NMOS without decimal mode
+	CSG65CE02                // Commodore 65 CPU, 65C02 with Z and B register
+	HuC6280                  // PC Engine CPU, 65C02 with block transfers and MMU
+)
+
+// SetVariant selects the processor model to emulate, its Quirks and Vectors.
+// Defaults to NMOS6502.
+func (cpu *CPU) SetVariant(v Variant) {
+	cpu.variant, cpu.quirks, cpu.vecs = v, v.Quirks(), v.Vectors()
+	cpu.table()
+	cpu.pages()
+}
+
+// table selects the dispatch table of the variant and the quirks.
+func (cpu *CPU) table() {
+	cpu.ext = nil
+	switch cpu.variant {
+	case CMOS65C02:
+		cpu.ext = &cmosops
+	case CSG65CE02:
+		cpu.ext = &ce02ops
+	case HuC6280:
+		cpu.ext = &hucops
+	default:
+		if !cpu.quirks.Illegal {
+			cpu.ext = &documented
+		}
+	}
+}
+
+// Variant returns the processor model emulated.
+func (cpu *CPU) Variant() Variant {
+	return cpu.variant
+}
+
+// String returns the name of the processor model.
+func (v Variant) String() string {
+	switch v {
+	case NMOS6502:
+		return "6502"
+	case CMOS65C02:
+		return "65C02"
+	case Ricoh2A03:
+		return "2A03"
+	case CSG65CE02:
+		return "65CE02"
+	case HuC6280:
+		return "HuC6280"
+	}
+	return "unknown"
+}
+
+// pages locates the zero page and the stack of the variant.
+func (cpu *CPU) pages() {
+	cpu.sph, cpu.ce = 0x01, ce02{}
+	if cpu.variant == HuC6280 {
+		cpu.sph, cpu.ce.b = 0x21, 0x20
+	}
+}
@@ -0,0 +1,199 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVariantString(t *testing.T) {
+	if NMOS6502.String() != "6502" || CMOS65C02.String() != "65C02" || Ricoh2A03.String() != "2A03" {
+		t.Error("unexpected")
+	}
+	if Variant(0xFF).String() != "unknown" {
+		t.Error("unexpected")
+	}
+	if cpu := New(&memoryBus{}); cpu.Variant() != NMOS6502 {
+		t.Error("unexpected")
+	}
+}
+
+func TestVariantJMPIndirect(t *testing.T) {
+	tests := []struct {
+		variant Variant
+		vector  byte // operand low byte
+		pc      uint16
+		cycles  uint
+	}{
+		{NMOS6502, 0x80, 0x1234, 5},
+		{NMOS6502, 0xFF, 0x5634, 5},
+		{Ricoh2A03, 0xFF, 0x5634, 5},
+		{CMOS65C02, 0x80, 0x1234, 6},
+		{CMOS65C02, 0xFF, 0x7834, 6},
+	}
+	for _, tt := range tests {
+		bus := &memoryBus{}
+		copy(bus.mem[0x0200:], []byte{0x6C, tt.vector, 0x03})
+		copy(bus.mem[0x0380:], []byte{0x34, 0x12})
+		bus.mem[0x03FF], bus.mem[0x0300], bus.mem[0x0400] = 0x34, 0x56, 0x78
+
+		cpu := New(bus)
+		cpu.SetVariant(tt.variant)
+		cpu.PC(0x00, 0x02)
+
+		cycles, err := cpu.Step()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cpu.pc() != tt.pc || cycles != tt.cycles {
+			t.Errorf("unexpected, got %04X %d for %s $03%02X", cpu.pc(), cycles, tt.variant, tt.vector)
+		}
+	}
+}
+
+func TestVariantRMWAbsoluteX(t *testing.T) {
+	tests := []struct {
+		opcode byte
+		cross  bool
+		nmos   uint
+		cmos   uint
+	}{
+		{0x1E, false, 7, 6}, // ASL
+		{0x1E, true, 7, 7},
+		{0x3E, false, 7, 6}, // ROL
+		{0x3E, true, 7, 7},
+		{0x5E, false, 7, 6}, // LSR
+		{0x5E, true, 7, 7},
+		{0x7E, false, 7, 6}, // ROR
+		{0x7E, true, 7, 7},
+		{0xDE, false, 7, 7}, // DEC
+		{0xDE, true, 7, 7},
+		{0xFE, false, 7, 7}, // INC
+		{0xFE, true, 7, 7},
+	}
+	for _, tt := range tests {
+		for _, v := range []Variant{NMOS6502, CMOS65C02} {
+			bus := &memoryBus{}
+			copy(bus.mem[0x0200:], []byte{tt.opcode, 0x10, 0x30})
+
+			cpu := New(bus)
+			cpu.SetVariant(v)
+			cpu.PC(0x00, 0x02)
+			cpu.x = 0x01
+			if tt.cross {
+				cpu.x = 0xF0
+			}
+
+			want := map[Variant]uint{NMOS6502: tt.nmos, CMOS65C02: tt.cmos}[v]
+			if cycles, _ := cpu.Step(); cycles != want {
+				t.Errorf("unexpected, want %d, got %d for %s %02X cross=%t", want, cycles, v, tt.opcode, tt.cross)
+			}
+		}
+	}
+}
+
+func TestVariantDecimal(t *testing.T) {
+	tests := []struct {
+		variant Variant
+		op, a   byte
+		b       byte
+		decimal bool
+		want    byte
+		cycles  uint
+	}{
+		{NMOS6502, 0x69, 0x19, 0x01, true, 0x20, 2},
+		{CMOS65C02, 0x69, 0x19, 0x01, true, 0x20, 3},
+		{CMOS65C02, 0x69, 0x19, 0x01, false, 0x1A, 2},
+		{NMOS6502, 0xE9, 0x20, 0x01, true, 0x19, 2},
+		{CMOS65C02, 0xE9, 0x20, 0x01, true, 0x19, 3},
+		{CMOS65C02, 0xE9, 0x20, 0x01, false, 0x1F, 2},
+		{NMOS6502, 0xE9, 0x00, 0x0F, true, 0x9B, 2}, // Invalid BCD
+		{CMOS65C02, 0xE9, 0x00, 0x0F, true, 0x8B, 3},
+	}
+	for i, tt := range tests {
+		bus := &memoryBus{}
+		copy(bus.mem[0x0200:], []byte{tt.op, tt.b})
+		cpu := New(bus)
+		cpu.SetVariant(tt.variant)
+		cpu.PC(0x00, 0x02)
+		cpu.p.set(tt.decimal, flagD)
+		cpu.p.set(tt.op == 0xE9, flagC)
+		cpu.a = tt.a
+
+		if cycles, _ := cpu.Step(); cpu.a != tt.want || cycles != tt.cycles {
+			t.Errorf("unexpected, got 0x%02X in %d cycles in test %d", cpu.a, cycles, i)
+		}
+	}
+}
+
+func TestVariantRicoh2A03(t *testing.T) {
+	// SED, LDA #$09, CLC, ADC #$01, SEC, SBC #$01
+	prog := []byte{0xF8, 0xA9, 0x09, 0x18, 0x69, 0x01, 0x38, 0xE9, 0x01}
+
+	for _, tt := range []struct {
+		variant Variant
+		add     byte
+		sub     byte
+	}{
+		{NMOS6502, 0x10, 0x09},
+		{Ricoh2A03, 0x0A, 0x0F},
+	} {
+		bus := &memoryBus{}
+		copy(bus.mem[0x0200:], prog)
+		cpu := New(bus)
+		cpu.SetVariant(tt.variant)
+		cpu.PC(0x00, 0x02)
+
+		for i := 0; i < 4; i++ {
+			_, _ = cpu.Step()
+		}
+		if cpu.a != tt.add || !cpu.p.has(flagD) {
+			t.Errorf("unexpected, got %s for %s", cpu, tt.variant)
+		}
+		cpu.a = 0x10
+		for i := 0; i < 2; i++ {
+			_, _ = cpu.Step()
+		}
+		if cpu.a != tt.sub {
+			t.Errorf("unexpected, got %s for %s", cpu, tt.variant)
+		}
+	}
+}
+
+func TestVariantBitInstructions(t *testing.T) {
+	bus := &memoryBus{}
+	// SMB3 $10, BBS3 $10,$0207, RMB3 $10, BBR3 $10,$01FC, BBS3 $10,$01FF
+	copy(bus.mem[0x0200:], []byte{0xB7, 0x10, 0xBF, 0x10, 0x02, 0x00, 0x00, 0x37, 0x10, 0x3F, 0x10, 0xF0})
+	copy(bus.mem[0x01FC:], []byte{0xBF, 0x10, 0x00})
+	cpu := New(bus)
+	cpu.SetVariant(CMOS65C02)
+	cpu.PC(0x00, 0x02)
+
+	steps := []struct {
+		pc     uint16
+		mem    byte
+		cycles uint
+	}{
+		{0x0202, 0x08, 5},
+		{0x0207, 0x08, 6},
+		{0x0209, 0x00, 5},
+		{0x01FC, 0x00, 7},
+		{0x01FF, 0x00, 5},
+	}
+	for i, s := range steps {
+		if cycles, _ := cpu.Step(); cpu.pc() != s.pc || bus.mem[0x10] != s.mem || cycles != s.cycles {
+			t.Fatalf("unexpected, got %04X %02X %d in step %d", cpu.pc(), bus.mem[0x10], cycles, i)
+		}
+	}
+
+	for _, op := range []byte{0x07, 0x0F, 0x87, 0x8F} {
+		bus := &memoryBus{}
+		bus.mem[0x0200] = op
+		cpu := New(bus)
+		cpu.PC(0x00, 0x02)
+		if _, err := cpu.Step(); !errors.As(err, new(*OpcodeError)) {
+			t.Errorf("unexpected, got %v for %02X", err, op)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import "fmt"
+
+// VectorWatch selects the handling of writes to the interrupt vectors.
+type VectorWatch byte
+
+const (
+	// VectorIgnore does not watch the vectors, the default.
+	VectorIgnore VectorWatch = iota
+
+	// VectorReport reports each write to the Vectors, 0xFFFA-0xFFFF by default,
+	// as DiagVectorWrite Diagnostic, with the old and the new value of the byte.
+	VectorReport
+
+	// VectorBreak reports like VectorReport and additionally returns the
+	// Diagnostic as error from Step(), after the instruction completed.
+	VectorBreak
+)
+
+// SetVectorWatch selects the handling of writes to the interrupt vectors,
+// a common RAM-vector setup but also a frequent bug. The old value is read
+// from the bus right before the write. Defaults to VectorIgnore.
+func (cpu *CPU) SetVectorWatch(w VectorWatch) {
+	cpu.vectors = w
+}
+
+// vectorWrite reports the write of b to the vector byte at a by the
+// instruction at pc. It returns the Diagnostic, when the execution should break.
+func (cpu *CPU) vectorWrite(pc, a uint16, b byte) error {
+	old := cpu.bus.Read(byte(a), byte(a>>8))
+	d := Diagnostic{
+		Kind: DiagVectorWrite, PC: pc,
+		Text: fmt.Sprintf("%s vector %04X written: %02X -> %02X", cpu.vecs.name(a), a, old, b),
+		Addr: a, Old: old, New: b,
+	}
+	if cpu.diagnostics != nil {
+		cpu.diagnostics(d)
+	}
+	if cpu.vectors == VectorBreak {
+		return d
+	}
+	return nil
+}
+
+// Vectors are the addresses of the low bytes of the interrupt vectors.
+type Vectors struct {
+	NMI   uint16
+	Reset uint16
+	IRQ   uint16
+	BRK   uint16 // Used by the HuC6280 only, the other models share the IRQ vector
+}
+
+// Vectors returns the vector addresses of the processor model.
+func (v Variant) Vectors() Vectors {
+	if v == HuC6280 {
+		return Vectors{NMI: 0xFFFC, Reset: 0xFFFE, IRQ: 0xFFF8, BRK: 0xFFF6}
+	}
+	return Vectors{NMI: VectorNMI, Reset: VectorReset, IRQ: VectorIRQ, BRK: VectorIRQ}
+}
+
+// SetVectors replaces the vector addresses selected by SetVariant(), e.g.
+// for a tiny memory map without the top page. Reset() reads the new Reset
+// vector, SetVectorWatch() watches the new addresses.
+func (cpu *CPU) SetVectors(v Vectors) {
+	cpu.vecs = v
+}
+
+// Vectors returns the vector addresses in effect.
+func (cpu *CPU) Vectors() Vectors {
+	return cpu.vecs
+}
+
+// vector returns the address of the vector 0xFF<v> of the 6502,
+// as relocated by the variant or SetVectors().
+func (cpu *CPU) vector(v byte) uint16 {
+	switch v {
+	case 0xFA:
+		return cpu.vecs.NMI
+	case 0xFC:
+		return cpu.vecs.Reset
+	}
+	return cpu.vecs.IRQ
+}
+
+// has reports whether a is a byte of one of the vectors.
+func (v Vectors) has(a uint16) bool {
+	return v.name(a) != ""
+}
+
+func (v Vectors) name(a uint16) string {
+	switch {
+	case a-v.NMI < 2:
+		return "NMI"
+	case a-v.Reset < 2:
+		return "RESET"
+	case a-v.IRQ < 2:
+		return "IRQ"
+	case a-v.BRK < 2:
+		return "BRK"
+	}
+	return ""
+}
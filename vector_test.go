@@ -0,0 +1,94 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package m6502
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVectorWatch(t *testing.T) {
+	// LDA #$12, STA $FFFF, STA $FFFA
+	prog := []byte{0xA9, 0x12, 0x8D, 0xFF, 0xFF, 0x8D, 0xFA, 0xFF}
+
+	cpu, _ := newTestCPU(NMOS6502, prog...)
+	diags := []Diagnostic{}
+	cpu.SetDiagnostics(func(d Diagnostic) { diags = append(diags, d) })
+
+	for i := 0; i < 3; i++ {
+		_ = stepPC(t, cpu)
+	}
+	if len(diags) != 0 {
+		t.Fatal("unexpected")
+	}
+
+	cpu, bus := newTestCPU(NMOS6502, prog...)
+	cpu.SetDiagnostics(func(d Diagnostic) { diags = append(diags, d) })
+	cpu.SetVectorWatch(VectorReport)
+
+	for i := 0; i < 3; i++ {
+		_ = stepPC(t, cpu)
+	}
+	if len(diags) != 2 || bus.mem[0xFFFF] != 0x12 {
+		t.Fatalf("unexpected, got %v", diags)
+	}
+	d := diags[0]
+	if d.Kind != DiagVectorWrite || d.PC != 0x0202 || d.Addr != 0xFFFF || d.Old != 0x90 || d.New != 0x12 {
+		t.Fatalf("unexpected, got %+v", d)
+	}
+	if d.Error() != "m6502: 0202: IRQ vector FFFF written: 90 -> 12" {
+		t.Fatalf("unexpected, got %s", d)
+	}
+	if diags[1].Text != "NMI vector FFFA written: 00 -> 12" {
+		t.Fatalf("unexpected, got %s", diags[1].Text)
+	}
+}
+
+func TestVectorWatchBreak(t *testing.T) {
+	cpu, bus := newTestCPU(NMOS6502, 0x8D, 0xFC, 0xFF, 0xEA)
+	cpu.SetVectorWatch(VectorBreak)
+
+	_, err := cpu.Step()
+
+	var d Diagnostic
+	if !errors.As(err, &d) || d.Addr != 0xFFFC || bus.mem[0xFFFC] != 0x00 || cpu.pc() != 0x0203 {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	if pc := stepPC(t, cpu); pc != 0x0204 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+}
+
+func TestVectors(t *testing.T) {
+	if v := HuC6280.Vectors(); v.Reset != 0xFFFE || v.BRK != 0xFFF6 {
+		t.Fatalf("unexpected, got %+v", v)
+	}
+	// Tiny fixture of 1K with the vectors at 0x03F0.
+	bus := &memoryBus{}
+	bus.mem[0x03F2], bus.mem[0x03F3] = 0x00, 0x02 // RESET
+	bus.mem[0x03F0], bus.mem[0x03F1] = 0x00, 0x01 // NMI
+	bus.mem[0x03F4], bus.mem[0x03F5] = 0x80, 0x01 // IRQ
+	copy(bus.mem[0x0200:], []byte{0x00, 0x00, 0x8D, 0xF4, 0x03})
+
+	v := Vectors{NMI: 0x03F0, Reset: 0x03F2, IRQ: 0x03F4, BRK: 0x03F4}
+	cpu := New(bus, WithVectors(v))
+	if cpu.pc() != 0x0200 || cpu.Vectors() != v {
+		t.Fatalf("unexpected, got %04X %+v", cpu.pc(), cpu.Vectors())
+	}
+	if pc := stepPC(t, cpu); pc != 0x0180 {
+		t.Fatalf("unexpected, got %04X", pc)
+	}
+	if _, _ = cpu.NMI(); cpu.pc() != 0x0100 {
+		t.Fatalf("unexpected, got %04X", cpu.pc())
+	}
+
+	cpu.PC(0x02, 0x02)
+	cpu.SetVectorWatch(VectorBreak)
+	var d Diagnostic
+	if _, err := cpu.Step(); !errors.As(err, &d) || d.Text != "IRQ vector 03F4 written: 80 -> 00" {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	if cpu.SetVariant(CMOS65C02); cpu.Vectors() != CMOS65C02.Vectors() {
+		t.Fatalf("unexpected, got %+v", cpu.Vectors())
+	}
+}
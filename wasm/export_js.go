@@ -0,0 +1,107 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+//go:build js && wasm
+
+package wasm
+
+import (
+	"syscall/js"
+
+	"github.com/dtgorski/m6502"
+)
+
+// Export returns a JavaScript object controlling the Machine:
+//
+//	reset()                     Resets the CPU
+//	step()                      {cycles, error}
+//	run(steps)                  {steps, cycles, error}
+//	read(addr)                  Byte at addr
+//	write(addr, byte)           Writes byte to addr
+//	load(addr, Uint8Array)      Copies the array to addr
+//	setPC(addr)                 Sets the program counter
+//	registers()                 {pc, a, x, y, s, p, cycles}
+//	disassemble(addr)           {asm, size}
+//	onTrace(fn | null)          Calls fn({pc, opcode, a, x, y, s, p, cycles, text}) per instruction
+//
+// Errors are reported as strings, null when there is none.
+func Export(m *Machine) js.Value {
+	obj := js.Global().Get("Object").New()
+
+	fn := func(name string, f func(args []js.Value) any) {
+		obj.Set(name, js.FuncOf(func(_ js.Value, args []js.Value) any {
+			return f(args)
+		}))
+	}
+	fn("reset", func([]js.Value) any {
+		m.Reset()
+		return nil
+	})
+	fn("step", func([]js.Value) any {
+		cycles, err := m.Step()
+		return map[string]any{"cycles": cycles, "error": jsError(err)}
+	})
+	fn("run", func(args []js.Value) any {
+		n, cycles, err := m.Run(arg(args, 0))
+		return map[string]any{"steps": n, "cycles": cycles, "error": jsError(err)}
+	})
+	fn("read", func(args []js.Value) any {
+		return m.Read(uint16(arg(args, 0)))
+	})
+	fn("write", func(args []js.Value) any {
+		m.Write(uint16(arg(args, 0)), byte(arg(args, 1)))
+		return nil
+	})
+	fn("load", func(args []js.Value) any {
+		if len(args) < 2 {
+			return nil
+		}
+		data := make([]byte, args[1].Length())
+		js.CopyBytesToGo(data, args[1])
+		m.Load(uint16(arg(args, 0)), data)
+		return nil
+	})
+	fn("setPC", func(args []js.Value) any {
+		m.SetPC(uint16(arg(args, 0)))
+		return nil
+	})
+	fn("registers", func([]js.Value) any {
+		r := m.Registers()
+		return map[string]any{
+			"pc": r.PC, "a": r.A, "x": r.X, "y": r.Y, "s": r.S, "p": r.P,
+			"cycles": r.Cycles,
+		}
+	})
+	fn("disassemble", func(args []js.Value) any {
+		asm, size := m.Disassemble(uint16(arg(args, 0)))
+		return map[string]any{"asm": asm, "size": size}
+	})
+	fn("onTrace", func(args []js.Value) any {
+		if len(args) == 0 || args[0].Type() != js.TypeFunction {
+			m.SetTracer(nil)
+			return nil
+		}
+		f := args[0]
+		m.SetTracer(func(t m6502.Trace) {
+			f.Invoke(map[string]any{
+				"pc": t.PC, "opcode": t.Opcode, "a": t.A, "x": t.X, "y": t.Y,
+				"s": t.S, "p": t.P, "cycles": t.Cycles, "text": m6502.DefaultLayout.Render(t),
+			})
+		})
+		return nil
+	})
+	return obj
+}
+
+func arg(args []js.Value, i int) int {
+	if i >= len(args) || args[i].Type() != js.TypeNumber {
+		return 0
+	}
+	return args[i].Int()
+}
+
+func jsError(err error) any {
+	if err == nil {
+		return nil
+	}
+	return err.Error()
+}
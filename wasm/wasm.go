@@ -0,0 +1,114 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+// Package wasm is a facade of a 6502 machine with 64K RAM for JavaScript
+// front-ends, e.g. browser-based playgrounds. Build cmd/m6502-wasm for the
+// js/wasm target, see "make wasm", to export it as global object "m6502":
+//
+//	m6502.load(0x0200, new Uint8Array([0xE8, 0x4C, 0x00, 0x02]))
+//	m6502.setPC(0x0200)
+//	m6502.onTrace(t => console.log(t.text))
+//	m6502.run(1000)   // {steps: 1000, cycles: 4000, error: null}
+//	m6502.registers() // {pc: 512, a: 0, x: 244, y: 0, s: 253, p: 164, cycles: 4007}
+package wasm
+
+import "github.com/dtgorski/m6502"
+
+type (
+	// Machine is a CPU with 64K RAM.
+	Machine struct {
+		cpu *m6502.CPU
+		mem *memory
+	}
+
+	// Registers is the state of the CPU.
+	Registers struct {
+		PC      uint16
+		A, X, Y byte
+		S, P    byte
+		Cycles  uint64 // Cycles since Reset()
+	}
+
+	memory [0x10000]byte
+)
+
+// New creates a Machine with zeroed RAM, the CPU reset.
+func New() *Machine {
+	mem := &memory{}
+	return &Machine{cpu: m6502.New(mem), mem: mem}
+}
+
+// CPU returns the CPU of the Machine for further configuration.
+func (m *Machine) CPU() *m6502.CPU {
+	return m.cpu
+}
+
+// Reset resets the CPU, the RAM is left unchanged.
+func (m *Machine) Reset() {
+	m.cpu.Reset()
+}
+
+// Step performs an instruction, see m6502.CPU.Step().
+func (m *Machine) Step() (uint, error) {
+	return m.cpu.Step()
+}
+
+// Run performs up to steps instructions, stopping at the first error.
+// It returns the number of instructions performed and their cycles.
+func (m *Machine) Run(steps int) (n int, cycles uint64, err error) {
+	for ; n < steps; n++ {
+		c, err := m.cpu.Step()
+		if err != nil {
+			return n, cycles, err
+		}
+		cycles += uint64(c)
+	}
+	return n, cycles, nil
+}
+
+// Read reads a byte from the RAM.
+func (m *Machine) Read(addr uint16) byte {
+	return m.mem[addr]
+}
+
+// Write writes a byte to the RAM.
+func (m *Machine) Write(addr uint16, db byte) {
+	m.mem[addr] = db
+}
+
+// Load copies data into the RAM at addr, wrapping at the end.
+func (m *Machine) Load(addr uint16, data []byte) {
+	for i, b := range data {
+		m.mem[addr+uint16(i)] = b
+	}
+}
+
+// SetPC sets the program counter.
+func (m *Machine) SetPC(addr uint16) {
+	m.cpu.PC(byte(addr), byte(addr>>8))
+}
+
+// Registers returns the state of the CPU.
+func (m *Machine) Registers() Registers {
+	r := m.cpu.Registers()
+	return Registers{
+		PC: uint16(m.cpu.PCH())<<8 | uint16(m.cpu.PCL()),
+		A:  r.A, X: r.X, Y: r.Y, S: r.S, P: r.P,
+		Cycles: m.cpu.TotalCycles(),
+	}
+}
+
+// SetTracer streams a Trace per instruction to t, nil stops the stream.
+func (m *Machine) SetTracer(t m6502.Tracer) {
+	m.cpu.SetTracer(t)
+}
+
+// Disassemble returns the instruction at addr in assembler notation and
+// its size in bytes.
+func (m *Machine) Disassemble(addr uint16) (string, int) {
+	code := []byte{m.mem[addr], m.mem[addr+1], m.mem[addr+2]}
+	return m.cpu.Disassembler().Decode(addr, code)
+}
+
+func (m *memory) Read(l, h byte) byte { return m[uint16(h)<<8|uint16(l)] }
+func (m *memory) Write(l, h, db byte) { m[uint16(h)<<8|uint16(l)] = db }
+func (m *memory) MemSlice() []byte    { return m[:] }
@@ -0,0 +1,61 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package wasm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dtgorski/m6502"
+)
+
+func TestMachine(t *testing.T) {
+	m := New()
+	// INX, JMP $0200
+	m.Load(0x0200, []byte{0xE8, 0x4C, 0x00, 0x02})
+	m.SetPC(0x0200)
+
+	traces := 0
+	m.SetTracer(func(m6502.Trace) { traces++ })
+
+	n, cycles, err := m.Run(10)
+	if err != nil || n != 10 || cycles != 25 || traces != 10 {
+		t.Fatalf("unexpected, got %d %d %d %v", n, cycles, traces, err)
+	}
+	if r := m.Registers(); r.PC != 0x0200 || r.X != 5 || r.Cycles != 32 {
+		t.Fatalf("unexpected, got %+v", r)
+	}
+	if asm, size := m.Disassemble(0x0201); asm != "JMP $0200" || size != 3 {
+		t.Fatalf("unexpected, got %q %d", asm, size)
+	}
+
+	m.SetTracer(nil)
+	if _, _, _ = m.Run(1); traces != 10 {
+		t.Fatalf("unexpected, got %d", traces)
+	}
+}
+
+func TestMachineMemory(t *testing.T) {
+	m := New()
+	m.Write(0xFFFF, 0x12)
+	m.Load(0xFFFF, []byte{0x34, 0x56})
+	if m.Read(0xFFFF) != 0x34 || m.Read(0x0000) != 0x56 {
+		t.Fatalf("unexpected, got %02X %02X", m.Read(0xFFFF), m.Read(0x0000))
+	}
+}
+
+func TestMachineError(t *testing.T) {
+	m := New()
+	m.Write(0x0200, 0x02) // KIL
+	m.SetPC(0x0200)
+
+	n, _, err := m.Run(10)
+	var e *m6502.OpcodeError
+	if n != 0 || !errors.As(err, &e) && !errors.Is(err, m6502.ErrHalted) {
+		t.Fatalf("unexpected, got %d %v", n, err)
+	}
+	m.Reset()
+	if m.CPU() == nil || m.Registers().PC != 0x0000 {
+		t.Fatalf("unexpected, got %+v", m.Registers())
+	}
+}
@@ -0,0 +1,114 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+// Package xex reads Atari 8-bit DOS executables (.xex, .com): a sequence of
+// segments, each loaded to an address range. A segment writing the INITAD
+// vector requests a call of the init routine right after loading it, the
+// RUNAD vector holds the start address of the program.
+//
+//	f, err := xex.Read(r)
+//	run, ok, err := f.Load(bus, func(addr uint16) error {
+//	    _, err := cpu.Call(addr, cpu.Registers(), 1_000_000)
+//	    return err
+//	})
+package xex
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dtgorski/m6502"
+)
+
+type (
+	// File is a parsed executable.
+	File struct {
+		Segments []Segment
+	}
+
+	// Segment is a block of data loaded to Start-End (inclusive).
+	Segment struct {
+		Start uint16
+		End   uint16
+		Data  []byte
+	}
+)
+
+// Addresses of the vectors evaluated by the loader.
+const (
+	RUNAD  uint16 = 0x02E0 // Run address, called after loading
+	INITAD uint16 = 0x02E2 // Init address, called after a segment
+)
+
+// Parse parses the content of an executable. The file must start with the
+// 0xFFFF marker, which is optional in front of the following segments.
+func Parse(b []byte) (*File, error) {
+	if len(b) < 2 || b[0] != 0xFF || b[1] != 0xFF {
+		return nil, errors.New("xex: missing header")
+	}
+	f := &File{}
+	for at := 0; at < len(b); {
+		if at+1 < len(b) && b[at] == 0xFF && b[at+1] == 0xFF {
+			at += 2
+		}
+		if at+4 > len(b) {
+			return nil, fmt.Errorf("xex: truncated segment header at offset %d", at)
+		}
+		s := Segment{
+			Start: uint16(b[at]) | uint16(b[at+1])<<8,
+			End:   uint16(b[at+2]) | uint16(b[at+3])<<8,
+		}
+		if s.End < s.Start {
+			return nil, fmt.Errorf("xex: invalid segment %04X-%04X at offset %d", s.Start, s.End, at)
+		}
+		at += 4
+		n := int(s.End) - int(s.Start) + 1
+		if at+n > len(b) {
+			return nil, fmt.Errorf("xex: truncated segment %04X-%04X at offset %d", s.Start, s.End, at)
+		}
+		s.Data, at = b[at:at+n], at+n
+		f.Segments = append(f.Segments, s)
+	}
+	return f, nil
+}
+
+// Read reads and parses an executable.
+func Read(r io.Reader) (*File, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("xex: %w", err)
+	}
+	return Parse(b)
+}
+
+// Covers reports whether the segment writes to the address.
+func (s Segment) Covers(addr uint16) bool {
+	return addr >= s.Start && addr <= s.End
+}
+
+// Load writes the segments to the bus in order, calling init with the
+// address of INITAD after each segment writing it, unless init is nil.
+// An error of init aborts the loading. Load returns the address of RUNAD
+// when a segment has written it.
+func (f *File) Load(bus m6502.Bus, init func(addr uint16) error) (run uint16, ok bool, err error) {
+	for _, s := range f.Segments {
+		for i, b := range s.Data {
+			addr := s.Start + uint16(i)
+			bus.Write(byte(addr), byte(addr>>8), b)
+		}
+		if init != nil && (s.Covers(INITAD) || s.Covers(INITAD+1)) {
+			if err = init(vector(bus, INITAD)); err != nil {
+				return 0, false, err
+			}
+		}
+		ok = ok || s.Covers(RUNAD) || s.Covers(RUNAD+1)
+	}
+	if ok {
+		run = vector(bus, RUNAD)
+	}
+	return run, ok, nil
+}
+
+func vector(bus m6502.Bus, addr uint16) uint16 {
+	return uint16(bus.Read(byte(addr), byte(addr>>8))) | uint16(bus.Read(byte(addr+1), byte((addr+1)>>8)))<<8
+}
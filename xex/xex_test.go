@@ -0,0 +1,83 @@
+// MIT License · Daniel T. Gorski · dtg [at] lengo [dot] org · 09/2023
+
+package xex
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/dtgorski/m6502"
+)
+
+type memoryBus struct{ mem [0x10000]byte }
+
+func (m *memoryBus) Read(l, h byte) byte     { return m.mem[uint16(h)<<8|uint16(l)] }
+func (m *memoryBus) Write(l, h byte, b byte) { m.mem[uint16(h)<<8|uint16(l)] = b }
+
+var exe = []byte{
+	0xFF, 0xFF, 0x00, 0x30, 0x05, 0x30, // Init routine at $3000
+	0xA9, 0x42, 0x8D, 0x00, 0x06, 0x60, // LDA #$42, STA $0600, RTS
+	0xE2, 0x02, 0xE3, 0x02, 0x00, 0x30, // INITAD
+	0xFF, 0xFF, 0x00, 0x20, 0x00, 0x20, 0xEA, // Program at $2000
+	0xE0, 0x02, 0xE1, 0x02, 0x00, 0x20, // RUNAD
+}
+
+func TestLoad(t *testing.T) {
+	f, err := Read(bytes.NewReader(exe))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Segments) != 4 || f.Segments[2].Start != 0x2000 || !bytes.Equal(f.Segments[2].Data, []byte{0xEA}) {
+		t.Fatalf("unexpected, got %+v", f.Segments)
+	}
+
+	bus := &memoryBus{}
+	cpu := m6502.New(bus)
+	inits := []uint16{}
+
+	run, ok, err := f.Load(bus, func(addr uint16) error {
+		inits = append(inits, addr)
+		_, err := cpu.Call(addr, m6502.RegisterSet{S: 0xFF}, 100)
+		return err
+	})
+	if err != nil || !ok || run != 0x2000 {
+		t.Fatalf("unexpected, got %04X %t %v", run, ok, err)
+	}
+	if len(inits) != 1 || inits[0] != 0x3000 || bus.mem[0x0600] != 0x42 || bus.mem[0x2000] != 0xEA {
+		t.Fatalf("unexpected, got %04X", inits)
+	}
+
+	errFoo := errors.New("foo")
+	if _, _, err = f.Load(bus, func(uint16) error { return errFoo }); err != errFoo {
+		t.Fatalf("unexpected, got %v", err)
+	}
+	if _, ok, err = (&File{Segments: f.Segments[:1]}).Load(bus, nil); ok || err != nil {
+		t.Fatalf("unexpected, got %t %v", ok, err)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for _, tt := range []struct {
+		b   []byte
+		err string
+	}{
+		{[]byte{0x00, 0x30}, "xex: missing header"},
+		{[]byte{0xFF, 0xFF}, "xex: truncated segment header at offset 2"},
+		{[]byte{0xFF, 0xFF, 0x00, 0x30, 0x01}, "xex: truncated segment header at offset 2"},
+		{[]byte{0xFF, 0xFF, 0x01, 0x30, 0x00, 0x30}, "xex: invalid segment 3001-3000 at offset 2"},
+		{[]byte{0xFF, 0xFF, 0x00, 0x30, 0x01, 0x30, 0xEA}, "xex: truncated segment 3000-3001 at offset 6"},
+	} {
+		if _, err := Parse(tt.b); err == nil || err.Error() != tt.err {
+			t.Fatalf("unexpected, got %v", err)
+		}
+	}
+	errFoo := errors.New("foo")
+	if _, err := Read(failingReader{errFoo}); !errors.Is(err, errFoo) {
+		t.Fatalf("unexpected, got %v", err)
+	}
+}
+
+type failingReader struct{ err error }
+
+func (r failingReader) Read([]byte) (int, error) { return 0, r.err }